@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	grovelogging "github.com/grovetools/core/logging"
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultBatchConcurrency is how many batch prompts run at once unless --concurrency is given
+const DefaultBatchConcurrency = 4
+
+var (
+	batchModel       string
+	batchWorkDir     string
+	batchUseCache    string
+	batchNoCache     bool
+	batchOutputDir   string
+	batchConcurrency int
+	batchTimeout     time.Duration
+)
+
+func newBatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch <prompts-file>",
+		Short: "Run many prompts concurrently against the same cached context",
+		Long: `Run every prompt in a file as a separate request against the same context,
+a few at a time, and write each response to its own file.
+
+The prompts file is either plain text with one prompt per non-blank line, or a
+JSON array of objects with a "prompt" and an optional "output" path:
+
+  [
+    {"prompt": "Summarize the API package", "output": "api.md"},
+    {"prompt": "List the TODOs in the codebase"}
+  ]
+
+Responses without an output path are written to prompt-001.md, prompt-002.md,
+... in --output-dir, which defaults to <prompts-file>-responses next to the
+prompts file. Relative output paths are resolved against --output-dir too.
+
+All prompts share one cache. With --use-cache every prompt reuses that cache;
+otherwise the first prompt runs alone to create or reuse the automatic cache
+and the rest reuse it. A failed prompt doesn't stop the others. Token usage
+and cost are totalled at the end, and the command fails if any prompt did.
+
+Examples:
+  # Run a list of questions against an existing cache, 8 at a time
+  grove-gemini batch questions.txt --use-cache my-repo --concurrency 8
+
+  # Write responses to chosen files
+  grove-gemini batch tasks.json --output-dir reviews`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBatch,
+	}
+
+	cmd.Flags().StringVarP(&batchModel, "model", "m", config.DefaultRequestModel, "Gemini model to use (defaults to gemini.default_model in grove.yml if set)")
+	cmd.Flags().StringVarP(&batchWorkDir, "workdir", "w", "", "Working directory (defaults to current)")
+	cmd.Flags().StringVar(&batchUseCache, "use-cache", "", "Cache name, display name or short hash every prompt reuses")
+	cmd.Flags().BoolVar(&batchNoCache, "no-cache", false, "Send the cold context with every prompt instead of caching it")
+	cmd.Flags().StringVarP(&batchOutputDir, "output-dir", "o", "", "Directory for responses (default <prompts-file>-responses)")
+	cmd.Flags().IntVar(&batchConcurrency, "concurrency", DefaultBatchConcurrency, "Maximum number of prompts running at once")
+	cmd.Flags().DurationVar(&batchTimeout, "timeout", 0, "Cancel a prompt if no response arrives within this duration (default from gemini.timeout, otherwise none)")
+
+	return cmd
+}
+
+// batchPrompt is one entry of a batch prompts file
+type batchPrompt struct {
+	Prompt string `json:"prompt"`
+	Output string `json:"output,omitempty"`
+}
+
+// batchOutcome holds what happened to a single batch prompt
+type batchOutcome struct {
+	output string
+	result *gemini.GenerateResult
+	err    error
+}
+
+// parseBatchPrompts reads prompts from a JSON array of batchPrompt objects or,
+// failing that, one prompt per non-blank line. Entries without an output path
+// are numbered, and relative paths are resolved against outputDir.
+func parseBatchPrompts(content []byte, outputDir string) ([]batchPrompt, error) {
+	var prompts []batchPrompt
+	if trimmed := strings.TrimSpace(string(content)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &prompts); err != nil {
+			return nil, fmt.Errorf("parsing JSON prompts file: %w", err)
+		}
+	} else {
+		for _, line := range strings.Split(trimmed, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				prompts = append(prompts, batchPrompt{Prompt: line})
+			}
+		}
+	}
+
+	seen := make(map[string]int, len(prompts))
+	for i := range prompts {
+		p := &prompts[i]
+		if strings.TrimSpace(p.Prompt) == "" {
+			return nil, fmt.Errorf("prompt %d is empty", i+1)
+		}
+		if p.Output == "" {
+			p.Output = fmt.Sprintf("prompt-%03d.md", i+1)
+		}
+		if !filepath.IsAbs(p.Output) {
+			p.Output = filepath.Join(outputDir, p.Output)
+		}
+		if prev, ok := seen[p.Output]; ok {
+			return nil, fmt.Errorf("prompts %d and %d both write to %s", prev, i+1, p.Output)
+		}
+		seen[p.Output] = i + 1
+	}
+	return prompts, nil
+}
+
+// defaultBatchOutputDir returns <prompts-file>-responses next to the prompts file
+func defaultBatchOutputDir(promptsFile string) string {
+	return strings.TrimSuffix(promptsFile, filepath.Ext(promptsFile)) + "-responses"
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	promptsFile := args[0]
+
+	if batchConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if batchUseCache != "" && batchNoCache {
+		return fmt.Errorf("--use-cache and --no-cache cannot be used together")
+	}
+
+	content, err := os.ReadFile(promptsFile) //nolint:gosec // promptsFile is user-provided path
+	if err != nil {
+		return fmt.Errorf("reading prompts file: %w", err)
+	}
+	outputDir := batchOutputDir
+	if outputDir == "" {
+		outputDir = defaultBatchOutputDir(promptsFile)
+	}
+	prompts, err := parseBatchPrompts(content, outputDir)
+	if err != nil {
+		return err
+	}
+	if len(prompts) == 0 {
+		return fmt.Errorf("no prompts found in %s", promptsFile)
+	}
+
+	model, _ := config.ResolveModel(batchWorkDir, batchModel, cmd.Flags().Changed("model"))
+	timeout := batchTimeout
+	if cmd.Flags().Changed("timeout") {
+		if timeout <= 0 {
+			return fmt.Errorf("--timeout must be positive")
+		}
+	} else if timeout, err = config.ResolveTimeout(batchWorkDir); err != nil {
+		return err
+	}
+	options := gemini.RequestOptions{
+		Model:            model,
+		WorkDir:          batchWorkDir,
+		UseCache:         batchUseCache,
+		NoCache:          batchNoCache,
+		SkipConfirmation: true, // prompts run concurrently, so nothing can stop to ask
+		ShowCostOnly:     true, // one line per prompt instead of interleaved usage boxes
+		Timeout:          timeout,
+		Caller:           "grove-gemini-batch",
+	}
+
+	// Progress goes where the pretty logs go, so --quiet silences it; failures stay on stderr
+	progress := grovelogging.GetGlobalOutput()
+	runner := gemini.NewRequestRunner()
+	outcomes := make([]batchOutcome, len(prompts))
+	run := func(i int) {
+		p := prompts[i]
+		opts := options
+		opts.Prompt = p.Prompt
+		outcomes[i] = batchOutcome{output: p.Output}
+		result, err := runner.RunWithResult(ctx, opts)
+		if err == nil {
+			err = writeBatchResponse(p.Output, result.Text)
+		}
+		outcomes[i].result, outcomes[i].err = result, err
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Prompt %d failed: %v\n", theme.IconWarning, i+1, err)
+			return
+		}
+		fmt.Fprintf(progress, "%s [%d/%d] Wrote %s\n", theme.IconSuccess, i+1, len(prompts), p.Output)
+	}
+
+	// Without a named cache, let the first prompt create or find the automatic
+	// cache alone so the concurrent prompts reuse it instead of each building one
+	start := 0
+	if batchUseCache == "" && !batchNoCache {
+		run(0)
+		start = 1
+	}
+	g := new(errgroup.Group)
+	g.SetLimit(batchConcurrency)
+	for i := start; i < len(prompts); i++ {
+		g.Go(func() error {
+			run(i)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	fmt.Fprintln(progress)
+	fmt.Fprintln(progress, renderBatchTable(outcomes))
+	fmt.Fprintln(progress, batchSummary(outcomes))
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d prompt(s) failed", failed, len(prompts))
+	}
+	return nil
+}
+
+// writeBatchResponse writes a response to path, creating its directory
+func writeBatchResponse(path, response string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // output dir needs to be traversable
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(response), 0o600); err != nil { //nolint:gosec // output file
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// renderBatchTable lists each prompt's output file, status, tokens and cost
+func renderBatchTable(outcomes []batchOutcome) string {
+	rows := make([][]string, 0, len(outcomes))
+	for i, o := range outcomes {
+		if o.err != nil {
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", i+1), o.output, theme.IconError + " Failed", "-", "-", truncatePreview(o.err.Error(), 60),
+			})
+			continue
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1),
+			o.output,
+			theme.IconSuccess + " OK",
+			pretty.FormatTokens(o.result.TotalTokens),
+			pretty.FormatCost(o.result.EstimatedCost),
+			"",
+		})
+	}
+	return tablecomponent.NewStyledTable().
+		Headers("#", "OUTPUT", "STATUS", "TOKENS", "COST", "ERROR").
+		Rows(rows...).
+		String()
+}
+
+// batchSummary totals token usage and cost across the prompts that succeeded
+func batchSummary(outcomes []batchOutcome) string {
+	var succeeded int
+	var cached, prompt, completion int32
+	var cost, creation float64
+	for _, o := range outcomes {
+		if o.err != nil || o.result == nil {
+			continue
+		}
+		succeeded++
+		cached += o.result.CachedTokens
+		prompt += o.result.PromptTokens
+		completion += o.result.CompletionTokens
+		cost += o.result.EstimatedCost
+		creation += o.result.CacheCreationCost
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Completed %d of %d prompts.\n", succeeded, len(outcomes))
+	fmt.Fprintf(&b, "%s Tokens: %s prompt (%s cached), %s completion\n", theme.IconChart,
+		pretty.FormatTokens(prompt), pretty.FormatTokens(cached), pretty.FormatTokens(completion))
+	fmt.Fprintf(&b, "%s Total cost: %s", theme.IconChart, pretty.FormatCost(cost))
+	if creation > 0 {
+		fmt.Fprintf(&b, " (plus %s to create the cache)", pretty.FormatCost(creation))
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+)
+
+func TestParseBatchPrompts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []batchPrompt
+		wantErr string
+	}{
+		{
+			name:    "one prompt per line",
+			content: "Summarize the API\n\n  List the TODOs  \n",
+			want: []batchPrompt{
+				{Prompt: "Summarize the API", Output: filepath.Join("out", "prompt-001.md")},
+				{Prompt: "List the TODOs", Output: filepath.Join("out", "prompt-002.md")},
+			},
+		},
+		{
+			name:    "JSON with output paths",
+			content: `[{"prompt": "Review auth", "output": "auth.md"}, {"prompt": "Review db", "output": "/tmp/db.md"}, {"prompt": "Other"}]`,
+			want: []batchPrompt{
+				{Prompt: "Review auth", Output: filepath.Join("out", "auth.md")},
+				{Prompt: "Review db", Output: "/tmp/db.md"},
+				{Prompt: "Other", Output: filepath.Join("out", "prompt-003.md")},
+			},
+		},
+		{
+			name:    "empty JSON prompt",
+			content: `[{"prompt": " "}]`,
+			wantErr: "prompt 1 is empty",
+		},
+		{
+			name:    "duplicate outputs",
+			content: `[{"prompt": "a", "output": "x.md"}, {"prompt": "b", "output": "x.md"}]`,
+			wantErr: "prompts 1 and 2 both write to",
+		},
+		{
+			name:    "invalid JSON",
+			content: `[{"prompt": }]`,
+			wantErr: "parsing JSON prompts file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBatchPrompts([]byte(tt.content), "out")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseBatchPrompts() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBatchPrompts() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseBatchPrompts() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("prompt %d = %+v, want %+v", i+1, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBatchSummary(t *testing.T) {
+	outcomes := []batchOutcome{
+		{output: "a.md", result: &gemini.GenerateResult{CachedTokens: 1000, PromptTokens: 1200, CompletionTokens: 50, EstimatedCost: 0.01, CacheCreationCost: 0.5}},
+		{output: "b.md", result: &gemini.GenerateResult{CachedTokens: 1000, PromptTokens: 1100, CompletionTokens: 30, EstimatedCost: 0.02}},
+		{output: "c.md", err: errors.New("boom")},
+	}
+	summary := batchSummary(outcomes)
+	for _, want := range []string{"Completed 2 of 3 prompts", "2,300 prompt (2,000 cached), 80 completion", "$0.0300", "plus $0.5000 to create the cache"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("batchSummary() = %q, missing %q", summary, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchModels       []string
+	benchPrompt       string
+	benchPromptFile   string
+	benchWorkDir      string
+	benchContextFiles []string
+	benchPreviewLen   int
+)
+
+func newBenchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Compare models by running the same prompt against each",
+		Long: `Run the same prompt and context against several models sequentially and
+print a comparison of latency, token usage, cost and a response preview.
+
+Context is gathered the same way as 'request'. Gemini caches are bound to the
+model that created them, so caching is disabled and the cold context is sent as
+dynamic context for every model to keep the comparison fair.
+
+Examples:
+  # Compare three models on a prompt file
+  grove-gemini bench --models gemini-2.5-pro,gemini-2.5-flash,gemini-2.0-flash -f prompt.md
+
+  # Inline prompt with extra context
+  grove-gemini bench --models gemini-2.5-pro,gemini-2.5-flash -p "Summarize the API" --context api.md`,
+		RunE: runBench,
+	}
+
+	cmd.Flags().StringSliceVar(&benchModels, "models", nil, "Comma-separated list of models to compare")
+	cmd.Flags().StringVarP(&benchPrompt, "prompt", "p", "", "Prompt text")
+	cmd.Flags().StringVarP(&benchPromptFile, "file", "f", "", "Read prompt from file")
+	cmd.Flags().StringVarP(&benchWorkDir, "workdir", "w", "", "Working directory (defaults to current)")
+	cmd.Flags().StringSliceVar(&benchContextFiles, "context", nil, "Additional context files to include")
+	cmd.Flags().IntVar(&benchPreviewLen, "preview", 60, "Number of response characters to show in the preview column")
+	_ = cmd.MarkFlagRequired("models")
+
+	return cmd
+}
+
+// benchRun holds the outcome of running the prompt against a single model
+type benchRun struct {
+	model  string
+	result *gemini.GenerateResult
+	err    error
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if len(benchModels) < 2 {
+		return fmt.Errorf("--models must list at least two models to compare")
+	}
+
+	promptText := benchPrompt
+	var promptFiles []string
+	if benchPromptFile != "" {
+		content, err := os.ReadFile(benchPromptFile) //nolint:gosec // benchPromptFile is user-provided path
+		if err != nil {
+			return fmt.Errorf("reading prompt file: %w", err)
+		}
+		promptText = string(content)
+		promptFiles = []string{benchPromptFile}
+	} else if promptText == "" && len(args) > 0 {
+		promptText = strings.Join(args, " ")
+	}
+	if promptText == "" {
+		return fmt.Errorf("must provide prompt via -p, -f, or as argument")
+	}
+
+	runner := gemini.NewRequestRunner()
+	runs := make([]benchRun, 0, len(benchModels))
+	for _, model := range benchModels {
+		model = strings.TrimSpace(model)
+		if model == "" {
+			continue
+		}
+
+		ulog.Info("Running benchmark").
+			Field("model", model).
+			Pretty(fmt.Sprintf("%s Running prompt against %s...", theme.IconRobot, model)).
+			PrettyOnly().
+			Log(ctx)
+
+		result, err := runner.RunWithResult(ctx, gemini.RequestOptions{
+			Model:            model,
+			Prompt:           promptText,
+			PromptFiles:      promptFiles,
+			WorkDir:          benchWorkDir,
+			NoCache:          true,
+			ContextFiles:     benchContextFiles,
+			SkipConfirmation: true,
+			Caller:           "grove-gemini-bench",
+		})
+		runs = append(runs, benchRun{model: model, result: result, err: err})
+	}
+
+	fmt.Println()
+	fmt.Println(renderBenchTable(runs, benchPreviewLen))
+
+	return nil
+}
+
+// renderBenchTable builds the comparison table for a set of benchmark runs
+func renderBenchTable(runs []benchRun, previewLen int) string {
+	rows := make([][]string, 0, len(runs))
+	for _, run := range runs {
+		if run.err != nil {
+			rows = append(rows, []string{
+				run.model, theme.IconError + " Failed", "-", "-", "-", "-", truncatePreview(run.err.Error(), previewLen),
+			})
+			continue
+		}
+
+		r := run.result
+		rows = append(rows, []string{
+			run.model,
+			fmt.Sprintf("%.2fs", r.Duration.Seconds()),
+			fmt.Sprintf("%d", r.PromptTokens),
+			fmt.Sprintf("%d", r.CompletionTokens),
+			fmt.Sprintf("%d", r.TotalTokens),
+			pretty.FormatCost(r.EstimatedCost),
+			truncatePreview(r.Text, previewLen),
+		})
+	}
+
+	return tablecomponent.NewStyledTable().
+		Headers("MODEL", "LATENCY", "PROMPT", "COMPLETION", "TOTAL", "COST", "PREVIEW").
+		Rows(rows...).
+		String()
+}
+
+// truncatePreview collapses whitespace and shortens text for single-line display
+func truncatePreview(text string, maxLen int) string {
+	preview := strings.Join(strings.Fields(text), " ")
+	runes := []rune(preview)
+	if maxLen > 3 && len(runes) > maxLen {
+		return string(runes[:maxLen-3]) + "..."
+	}
+	return preview
+}
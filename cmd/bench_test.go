@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+func TestNewBenchCmd(t *testing.T) {
+	cmd := newBenchCmd()
+
+	if cmd.Use != "bench" {
+		t.Errorf("Expected Use to be 'bench', got %q", cmd.Use)
+	}
+	if cmd.Flags().Lookup("models") == nil {
+		t.Fatal("Expected 'models' flag to be registered")
+	}
+}
+
+func TestTruncatePreview(t *testing.T) {
+	tests := []struct {
+		text   string
+		maxLen int
+		want   string
+	}{
+		{text: "short", maxLen: 10, want: "short"},
+		{text: "line one\n\nline   two", maxLen: 40, want: "line one line two"},
+		{text: "abcdefghijkl", maxLen: 8, want: "abcde..."},
+	}
+
+	for _, tt := range tests {
+		if got := truncatePreview(tt.text, tt.maxLen); got != tt.want {
+			t.Errorf("truncatePreview(%q, %d) = %q, want %q", tt.text, tt.maxLen, got, tt.want)
+		}
+	}
+}
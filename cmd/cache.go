@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +18,8 @@ import (
 	tablecomponent "github.com/grovetools/core/tui/components/table"
 	"github.com/grovetools/core/tui/theme"
 	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 	"github.com/spf13/cobra"
 )
 
@@ -33,9 +40,18 @@ func newCacheCmd() *cobra.Command {
 	}
 	cmd.AddCommand(tuiCmd)
 	cmd.AddCommand(newCacheListCmd())
+	cmd.AddCommand(newCacheCreateCmd())
 	cmd.AddCommand(newCacheClearCmd())
 	cmd.AddCommand(newCachePruneCmd())
+	cmd.AddCommand(newCacheGCCmd())
 	cmd.AddCommand(newCacheInspectCmd())
+	cmd.AddCommand(newCachePinCmd())
+	cmd.AddCommand(newCacheUnpinCmd())
+	cmd.AddCommand(newCacheStatsCmd())
+	cmd.AddCommand(newCacheLeaderboardCmd())
+	cmd.AddCommand(newCacheMigrateCmd())
+	cmd.AddCommand(newCacheRestoreCmd())
+	cmd.AddCommand(newCacheCleanUploadsCmd())
 
 	return cmd
 }
@@ -74,7 +90,7 @@ Use --local-only or --api-only to filter the view.`,
 }
 
 func newCacheClearCmd() *cobra.Command {
-	var withLocal, preserveLocal bool
+	var withLocal, preserveLocal, force, yes, trash bool
 
 	cmd := &cobra.Command{
 		Use:   "clear [cache-name...] | --all",
@@ -82,7 +98,12 @@ func newCacheClearCmd() *cobra.Command {
 		Long: `Clears caches from Google's servers and updates local tracking.
 By default, only clears the remote cache and marks the local file as cleared.
 Use --with-local to also remove the local cache file.
-Use --preserve-local to skip updating the local cache file.`,
+Use --trash to move local cache files to .trash/ instead; recover them with 'cache restore'.
+Use --preserve-local to skip updating the local cache file.
+Pinned caches are skipped by --all unless --force is given.
+A shared cache that other projects still use stays on the server; only this
+project's reference to it is dropped.
+--all asks for confirmation first unless --yes is given.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			all, _ := cmd.Flags().GetBool("all")
 			if !all && len(args) == 0 {
@@ -92,6 +113,9 @@ Use --preserve-local to skip updating the local cache file.`,
 			if withLocal && preserveLocal {
 				return fmt.Errorf("cannot use both --with-local and --preserve-local flags")
 			}
+			if trash && preserveLocal {
+				return fmt.Errorf("cannot use both --trash and --preserve-local flags")
+			}
 
 			ctx := context.Background()
 			workDir, err := os.Getwd()
@@ -100,11 +124,12 @@ Use --preserve-local to skip updating the local cache file.`,
 			}
 			cacheDir := gemini.ResolveGeminiCacheDir(workDir)
 
-			// Always create client since we default to clearing remote
-			client, err := gemini.NewClient(ctx, "")
-			if err != nil {
-				return fmt.Errorf("creating client: %w", err)
+			type clearTarget struct {
+				name string
+				path string
+				info *gemini.CacheInfo
 			}
+			var targets []clearTarget
 
 			if all {
 				files, err := os.ReadDir(cacheDir)
@@ -116,76 +141,59 @@ Use --preserve-local to skip updating the local cache file.`,
 					return fmt.Errorf("reading cache directory: %w", err)
 				}
 
-				clearedCount := 0
-				apiDeletedCount := 0
 				for _, file := range files {
-					if strings.HasSuffix(file.Name(), ".json") && strings.HasPrefix(file.Name(), "hybrid_") {
-						path := filepath.Join(cacheDir, file.Name())
+					if !strings.HasSuffix(file.Name(), ".json") || !strings.HasPrefix(file.Name(), "hybrid_") {
+						continue
+					}
+					path := filepath.Join(cacheDir, file.Name())
 
-						// Load cache info to get the cache ID
-						info, err := gemini.LoadCacheInfo(path)
-						if err != nil {
-							fmt.Fprintf(os.Stderr, "Warning: could not read cache info for %s: %v\n", file.Name(), err)
-							continue
-						}
+					// Load cache info to get the cache ID
+					info, err := gemini.LoadCacheInfo(path)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: could not read cache info for %s: %v\n", file.Name(), err)
+						continue
+					}
 
-						// Skip if already cleared
-						if info.ClearedAt != nil {
-							continue
-						}
+					// Skip if already cleared
+					if info.ClearedAt != nil {
+						continue
+					}
 
-						// Delete from API
-						if client != nil {
-							if err := client.DeleteCache(ctx, info.CacheID); err != nil {
-								fmt.Fprintf(os.Stderr, "Warning: failed to delete cache from API: %v\n", err)
-							} else {
-								apiDeletedCount++
-								fmt.Printf("Deleted from API: %s\n", info.CacheName)
-							}
-						}
+					if cleanupExempt(info, force) {
+						fmt.Printf("Skipping pinned cache: %s\n", info.CacheName)
+						continue
+					}
 
-						// Update or remove local file based on flags
-						if withLocal {
-							// Remove the local file entirely
-							if err := os.Remove(path); err != nil {
-								fmt.Fprintf(os.Stderr, "Failed to remove local file %s: %v\n", path, err)
-							} else {
-								fmt.Printf("Removed local cache: %s\n", info.CacheName)
-								clearedCount++
-							}
-						} else if !preserveLocal {
-							// Update the cache info with clear reason
-							now := time.Now()
-							info.ClearReason = "user-cleared"
-							info.ClearedAt = &now
+					targets = append(targets, clearTarget{name: info.CacheName, path: path, info: info})
+				}
 
-							data, _ := json.MarshalIndent(info, "", "  ")
-							if err := os.WriteFile(path, data, 0o600); err != nil { //nolint:gosec // cache info file
-								fmt.Fprintf(os.Stderr, "Failed to update cache info: %v\n", err)
-							} else {
-								fmt.Printf("Marked as cleared: %s\n", info.CacheName)
-								clearedCount++
-							}
-						}
-					}
+				if len(targets) == 0 {
+					fmt.Println("No active caches to clear.")
+					return nil
 				}
 
-				if withLocal {
-					fmt.Printf("\nDeleted %d cache(s) from API and removed %d local file(s).\n", apiDeletedCount, clearedCount)
-				} else if preserveLocal {
-					fmt.Printf("\nDeleted %d cache(s) from API (local files unchanged).\n", apiDeletedCount)
-				} else {
-					fmt.Printf("\nDeleted %d cache(s) from API and marked %d local cache(s) as cleared.\n", apiDeletedCount, clearedCount)
+				if !yes {
+					question := fmt.Sprintf("Clear %d cache(s) from Google's servers?", len(targets))
+					if withLocal && !trash {
+						question = fmt.Sprintf("Clear %d cache(s) and permanently delete their local records?", len(targets))
+					}
+					if !confirm(os.Stdin, os.Stdout, question) {
+						fmt.Println("Aborted. No caches were cleared.")
+						return nil
+					}
 				}
 			} else {
 				for _, cacheName := range args {
-					fileName := "hybrid_" + cacheName + ".json"
-					path := filepath.Join(cacheDir, fileName)
+					if cacheName, err = gemini.ResolveCacheName(cacheDir, cacheName); err != nil {
+						fmt.Fprintf(os.Stderr, "%v\n", err)
+						continue
+					}
+					path := filepath.Join(cacheDir, "hybrid_"+cacheName+".json")
 
 					// Load cache info to get the cache ID
 					info, err := gemini.LoadCacheInfo(path)
 					if err != nil {
-						if os.IsNotExist(err) {
+						if errors.Is(err, os.ErrNotExist) {
 							fmt.Fprintf(os.Stderr, "Cache '%s' not found locally.\n", cacheName)
 						} else {
 							fmt.Fprintf(os.Stderr, "Failed to read cache '%s': %v\n", cacheName, err)
@@ -199,57 +207,142 @@ Use --preserve-local to skip updating the local cache file.`,
 						continue
 					}
 
-					// Delete from API
-					if client != nil {
-						if err := client.DeleteCache(ctx, info.CacheID); err != nil {
-							fmt.Fprintf(os.Stderr, "Warning: failed to delete cache from API: %v\n", err)
-						} else {
-							fmt.Printf("Deleted from API: %s\n", cacheName)
-						}
-					}
+					targets = append(targets, clearTarget{name: cacheName, path: path, info: info})
+				}
+				if len(targets) == 0 {
+					return nil
+				}
+			}
 
-					// Update or remove local file based on flags
-					if withLocal {
-						// Remove the local file entirely
-						if err := os.Remove(path); err != nil {
-							fmt.Fprintf(os.Stderr, "Failed to remove local cache '%s': %v\n", cacheName, err)
-						} else {
-							fmt.Printf("Removed local cache: %s\n", cacheName)
-						}
-					} else if !preserveLocal {
-						// Update the cache info with clear reason
+			// Always create client since we default to clearing remote
+			client, err := gemini.NewClient(ctx, "")
+			if err != nil {
+				return fmt.Errorf("creating client: %w", err)
+			}
+
+			clearedCount := 0
+			apiDeletedCount := 0
+			for _, target := range targets {
+				info := target.info
+
+				// Delete from API, unless other projects still use a shared cache
+				apiDeleted := false
+				if stillUsedBy, err := gemini.ReleaseCache(ctx, client, gemini.SharedCacheDir(), workDir, info); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to delete cache from API: %v\n", err)
+				} else if len(stillUsedBy) > 0 {
+					fmt.Printf("Kept shared cache on API, still used by %d other project(s): %s\n", len(stillUsedBy), target.name)
+				} else {
+					apiDeleted = true
+					apiDeletedCount++
+					fmt.Printf("Deleted from API: %s\n", target.name)
+				}
+
+				// Update, trash or remove local file based on flags
+				switch {
+				case trash:
+					// Only stamp the record when the server cache is really gone, so a
+					// restored record still points at a live cache if deletion failed
+					if apiDeleted {
 						now := time.Now()
 						info.ClearReason = "user-cleared"
 						info.ClearedAt = &now
-
-						data, _ := json.MarshalIndent(info, "", "  ")
-						if err := os.WriteFile(path, data, 0o600); err != nil { //nolint:gosec // cache info file
+						if err := gemini.SaveCacheInfo(target.path, info); err != nil {
 							fmt.Fprintf(os.Stderr, "Failed to update cache info: %v\n", err)
-						} else {
-							fmt.Printf("Marked as cleared: %s\n", cacheName)
 						}
 					}
+					if err := gemini.TrashCacheRecord(cacheDir, target.path); err != nil {
+						fmt.Fprintf(os.Stderr, "Failed to move local cache '%s' to trash: %v\n", target.name, err)
+					} else {
+						fmt.Printf("Moved local cache to trash: %s\n", target.name)
+						clearedCount++
+					}
+				case withLocal:
+					// Remove the local file entirely
+					if err := os.Remove(target.path); err != nil {
+						fmt.Fprintf(os.Stderr, "Failed to remove local cache '%s': %v\n", target.name, err)
+					} else {
+						fmt.Printf("Removed local cache: %s\n", target.name)
+						clearedCount++
+					}
+				case !preserveLocal:
+					// Update the cache info with clear reason
+					now := time.Now()
+					info.ClearReason = "user-cleared"
+					info.ClearedAt = &now
+
+					data, _ := json.MarshalIndent(info, "", "  ")
+					if err := os.WriteFile(target.path, data, 0o600); err != nil { //nolint:gosec // cache info file
+						fmt.Fprintf(os.Stderr, "Failed to update cache info: %v\n", err)
+					} else {
+						fmt.Printf("Marked as cleared: %s\n", target.name)
+						clearedCount++
+					}
 				}
 			}
+
+			if trash {
+				if _, err := gemini.PurgeTrash(cacheDir, gemini.TrashRetention); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to purge old trash: %v\n", err)
+				}
+			}
+
+			if !all {
+				return nil
+			}
+			switch {
+			case trash:
+				fmt.Printf("\nDeleted %d cache(s) from API and moved %d local file(s) to trash.\n", apiDeletedCount, clearedCount)
+				fmt.Println("Run 'grove-gemini cache restore' to recover them.")
+			case withLocal:
+				fmt.Printf("\nDeleted %d cache(s) from API and removed %d local file(s).\n", apiDeletedCount, clearedCount)
+			case preserveLocal:
+				fmt.Printf("\nDeleted %d cache(s) from API (local files unchanged).\n", apiDeletedCount)
+			default:
+				fmt.Printf("\nDeleted %d cache(s) from API and marked %d local cache(s) as cleared.\n", apiDeletedCount, clearedCount)
+			}
 			return nil
 		},
 	}
 	cmd.Flags().Bool("all", false, "Clear all caches in the current project")
 	cmd.Flags().BoolVar(&withLocal, "with-local", false, "Also remove local cache files (default: mark as cleared)")
+	cmd.Flags().BoolVar(&trash, "trash", false, "Move local cache files to .trash/ so 'cache restore' can recover them")
 	cmd.Flags().BoolVar(&preserveLocal, "preserve-local", false, "Don't update local cache files at all")
+	cmd.Flags().BoolVar(&force, "force", false, "Also clear pinned caches when using --all")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the --all confirmation prompt")
 
 	return cmd
 }
 
+// confirm asks a yes/no question and reports whether the answer was yes.
+// Anything other than "y" or "yes", including EOF, counts as no. In quiet
+// mode the question can't be seen, so it is declined without reading input.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	if pretty.IsQuiet() {
+		return false
+	}
+	_, _ = fmt.Fprintf(out, "%s %s [y/N]: ", theme.IconHelp, question)
+	response, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && response == "" {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
 func newCachePruneCmd() *cobra.Command {
-	var removeLocal bool
+	var removeLocal, force bool
 
 	cmd := &cobra.Command{
 		Use:   "prune",
 		Short: "Mark expired caches as cleared and optionally clean up",
 		Long: `Marks expired cache records as cleared and removes them from Google's API.
+Caches marked as orphaned by 'cache migrate' are pruned as well, even if they
+have not expired yet.
 By default, updates local files to mark them as expired.
-Use --remove-local to also remove the local cache files.`,
+Use --remove-local to also remove the local cache files.
+Pinned caches are never pruned unless --force is given.
+A shared cache that other projects still use stays on the server; only this
+project's reference to it is dropped.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			workDir, err := os.Getwd()
@@ -290,33 +383,40 @@ Use --remove-local to also remove the local cache files.`,
 						continue
 					}
 
-					if time.Now().After(info.ExpiresAt) {
-						// Try to delete from API (it might already be gone)
-						if client != nil {
-							if err := client.DeleteCache(ctx, info.CacheID); err == nil {
-								apiDeletedCount++
-							}
+					if reason := pruneReason(info, time.Now()); reason != "" {
+						if cleanupExempt(info, force) {
+							fmt.Printf("Skipping pinned cache: %s\n", info.CacheName)
+							continue
+						}
+
+						// Try to delete from API (it might already be gone); a shared
+						// cache other projects still use only loses this project's reference
+						if stillUsedBy, err := gemini.ReleaseCache(ctx, client, gemini.SharedCacheDir(), workDir, info); err == nil && len(stillUsedBy) == 0 {
+							apiDeletedCount++
+						} else if err != nil && info.Shared {
+							fmt.Fprintf(os.Stderr, "Skipping shared cache %s: %v\n", info.CacheName, err)
+							continue
 						}
 
 						if removeLocal {
 							// Remove the file
 							if err := os.Remove(path); err != nil {
-								fmt.Fprintf(os.Stderr, "Failed to remove expired cache %s: %v\n", file.Name(), err)
+								fmt.Fprintf(os.Stderr, "Failed to remove %s cache %s: %v\n", reason, file.Name(), err)
 							} else {
-								fmt.Printf("Removed expired cache: %s\n", info.CacheName)
+								fmt.Printf("Removed %s cache: %s\n", reason, info.CacheName)
 								prunedCount++
 							}
 						} else {
 							// Mark as expired
 							now := time.Now()
-							info.ClearReason = "expired"
+							info.ClearReason = reason
 							info.ClearedAt = &now
 
 							data, _ := json.MarshalIndent(info, "", "  ")
 							if err := os.WriteFile(path, data, 0o600); err != nil { //nolint:gosec // cache info file
 								fmt.Fprintf(os.Stderr, "Failed to update cache info: %v\n", err)
 							} else {
-								fmt.Printf("Marked as expired: %s\n", info.CacheName)
+								fmt.Printf("Marked as %s: %s\n", reason, info.CacheName)
 								prunedCount++
 							}
 						}
@@ -339,15 +439,21 @@ Use --remove-local to also remove the local cache files.`,
 	}
 
 	cmd.Flags().BoolVar(&removeLocal, "remove-local", false, "Remove local cache files instead of marking them")
+	cmd.Flags().BoolVar(&force, "force", false, "Also prune pinned caches")
 
 	return cmd
 }
 
 func newCacheInspectCmd() *cobra.Command {
-	return &cobra.Command{
+	var previewLines int
+
+	cmd := &cobra.Command{
 		Use:   "inspect [cache-name]",
 		Short: "Show detailed information about a specific cache",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show detailed information about a specific cache.
+Use --preview to print the first lines of each cached file as it exists locally,
+noting any file whose content has changed since the cache was created.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cacheName := args[0]
 
@@ -357,6 +463,9 @@ func newCacheInspectCmd() *cobra.Command {
 			}
 
 			cacheDir := gemini.ResolveGeminiCacheDir(workDir)
+			if cacheName, err = gemini.ResolveCacheName(cacheDir, cacheName); err != nil {
+				return err
+			}
 			cacheFile := filepath.Join(cacheDir, "hybrid_"+cacheName+".json")
 
 			// Load cache info
@@ -381,8 +490,14 @@ func newCacheInspectCmd() *cobra.Command {
 
 			// Print basic info
 			fmt.Printf("│ Server Cache ID: %-46s │\n", info.CacheID)
+			if info.DisplayName != "" {
+				fmt.Printf("│ Name:            %-46s │\n", info.DisplayName)
+			}
 			fmt.Printf("│ Model:           %-46s │\n", info.Model)
 			fmt.Printf("│ Status:          %-46s │\n", status)
+			if info.Pinned {
+				fmt.Printf("│ Pinned:          %-46s │\n", "yes")
+			}
 			fmt.Printf("│ Created:         %-46s │\n", info.CreatedAt.Local().Format("2006-01-02 15:04:05 MST"))
 			fmt.Printf("│ Expires:         %-46s │\n", info.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST"))
 
@@ -401,7 +516,8 @@ func newCacheInspectCmd() *cobra.Command {
 			if len(info.CachedFileHashes) > 0 {
 				fmt.Println("├─────────────────────────────────────────────────────────────────┤")
 				fmt.Println("│ Cached Files:                                                   │")
-				for file, hash := range info.CachedFileHashes {
+				for _, file := range info.CachedFiles() {
+					hash := info.CachedFileHashes[file]
 					// Truncate long file paths
 					displayFile := file
 					if len(displayFile) > 60 {
@@ -414,9 +530,159 @@ func newCacheInspectCmd() *cobra.Command {
 
 			fmt.Println("╰─────────────────────────────────────────────────────────────────╯")
 
+			if previewLines > 0 {
+				printCachedFilePreviews(info, previewLines)
+			}
+
 			return nil
 		},
 	}
+
+	cmd.Flags().IntVar(&previewLines, "preview", 0, "Show the first N lines of each cached file (default 10 when given without a value)")
+	cmd.Flags().Lookup("preview").NoOptDefVal = "10"
+
+	return cmd
+}
+
+// printCachedFilePreviews prints the first lines of each locally cached file,
+// warning when the file no longer matches the hash recorded at cache creation.
+func printCachedFilePreviews(info *gemini.CacheInfo, lines int) {
+	for _, file := range info.CachedFiles() {
+		fmt.Printf("\n%s %s\n", theme.IconFile, file)
+
+		content, err := os.ReadFile(file) //nolint:gosec // file is recorded in the local cache info
+		if err != nil {
+			fmt.Printf("  %s Could not read file: %v\n", theme.IconWarning, err)
+			continue
+		}
+
+		hash := sha256.Sum256(content)
+		if hex.EncodeToString(hash[:]) != info.CachedFileHashes[file] {
+			fmt.Printf("  %s File has changed since the cache was created; the preview may not match the cached content\n", theme.IconWarning)
+		}
+
+		fmt.Println(previewLinesOf(string(content), lines))
+	}
+}
+
+// previewLinesOf returns the first n lines of content, indented and with long lines shortened
+func previewLinesOf(content string, n int) string {
+	all := strings.Split(content, "\n")
+	shown := all
+	if len(all) > n {
+		shown = all[:n]
+	}
+
+	var b strings.Builder
+	for i, line := range shown {
+		if runes := []rune(line); len(runes) > 120 {
+			line = string(runes[:117]) + "..."
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("  │ " + line)
+	}
+	if len(all) > n {
+		b.WriteString(fmt.Sprintf("\n  … %d more lines", len(all)-n))
+	}
+	return b.String()
+}
+
+func newCachePinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pin [cache-name]",
+		Short: "Pin a cache so it is skipped by prune and clear --all",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setCachePinned(args[0], true)
+		},
+	}
+}
+
+func newCacheUnpinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unpin [cache-name]",
+		Short: "Unpin a cache so bulk cleanup can remove it again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setCachePinned(args[0], false)
+		},
+	}
+}
+
+// cleanupExempt reports whether bulk cleanup (clear --all, prune) must leave
+// a cache record alone: pinned caches are kept unless --force is given.
+func cleanupExempt(info *gemini.CacheInfo, force bool) bool {
+	return info.Pinned && !force
+}
+
+// pruneReason returns why 'cache prune' would remove a record, "orphaned" or
+// "expired", or "" if the cache is still live at now.
+func pruneReason(info *gemini.CacheInfo, now time.Time) string {
+	switch {
+	case info.Orphaned:
+		return "orphaned"
+	case now.After(info.ExpiresAt):
+		return "expired"
+	}
+	return ""
+}
+
+// setCachePinned updates the pinned flag on a local cache record.
+func setCachePinned(cacheName string, pinned bool) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	cacheDir := gemini.ResolveGeminiCacheDir(workDir)
+	if cacheName, err = gemini.ResolveCacheName(cacheDir, cacheName); err != nil {
+		return err
+	}
+	path := filepath.Join(cacheDir, "hybrid_"+cacheName+".json")
+
+	info, err := gemini.LoadCacheInfo(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("cache '%s' not found", cacheName)
+		}
+		return fmt.Errorf("loading cache info: %w", err)
+	}
+
+	if info.Pinned == pinned {
+		if pinned {
+			fmt.Printf("Cache '%s' is already pinned.\n", cacheName)
+		} else {
+			fmt.Printf("Cache '%s' is not pinned.\n", cacheName)
+		}
+		return nil
+	}
+
+	info.Pinned = pinned
+	if err := gemini.SaveCacheInfo(path, info); err != nil {
+		return fmt.Errorf("saving cache info: %w", err)
+	}
+
+	if pinned {
+		fmt.Printf("Pinned cache: %s\n", cacheName)
+	} else {
+		fmt.Printf("Unpinned cache: %s\n", cacheName)
+	}
+	return nil
+}
+
+// pinnedCacheName returns the cache's display name, falling back to its hash,
+// decorated with a pin marker when pinned.
+func pinnedCacheName(info *gemini.CacheInfo) string {
+	name := info.CacheName
+	if info.DisplayName != "" {
+		name = info.DisplayName
+	}
+	if info.Pinned {
+		return name + " " + theme.IconFileLock
+	}
+	return name
 }
 
 func formatDuration(d time.Duration) string {
@@ -437,44 +703,21 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
-// calculateCacheCost calculates the cost of storing cached content
-// Based on Gemini pricing: https://ai.google.dev/pricing
-// Cached content costs $1.00 per million tokens per hour for Gemini 1.5 Pro/Flash
-// For Gemini 2.0, pricing may differ
+// calculateCacheCost formats the storage cost of keeping tokenCount tokens
+// cached for duration, using the model's storage rate per token-hour
+// (see logging.EstimateCacheStorageCost)
 func calculateCacheCost(tokenCount int32, duration time.Duration, model string) string {
 	if tokenCount <= 0 || duration <= 0 {
 		return "-"
 	}
 
-	// Cost per million tokens per hour in USD
-	var costPerMillionTokensPerHour float64
-
-	// Set pricing based on model
-	switch {
-	case strings.Contains(model, "gemini-2.0"):
-		// Gemini 2.0 models - using same rate for now, update when pricing is announced
-		costPerMillionTokensPerHour = 1.00
-	case strings.Contains(model, "gemini-1.5-pro"), strings.Contains(model, "gemini-1.5-flash"):
-		// Gemini 1.5 Pro and Flash
-		costPerMillionTokensPerHour = 1.00
-	default:
-		// Default pricing
-		costPerMillionTokensPerHour = 1.00
-	}
-
-	// Calculate cost
-	tokens := float64(tokenCount)
-	hours := duration.Hours()
-	cost := (tokens / 1_000_000) * hours * costPerMillionTokensPerHour
+	cost := logging.EstimateCacheStorageCost(model, tokenCount, duration)
 
 	// Format cost
 	if cost < 0.01 {
 		return "<$0.01"
-	} else if cost < 1.00 {
-		return fmt.Sprintf("$%.2f", cost)
-	} else {
-		return fmt.Sprintf("$%.2f", cost)
 	}
+	return fmt.Sprintf("$%.2f", cost)
 }
 
 // cacheRow holds data for a cache row with sorting metadata
@@ -517,9 +760,12 @@ func listCachesCombined() error {
 	fmt.Println("Querying caches from Google API...")
 	apiCaches, err := client.ListCachesFromAPI(ctx)
 	if err != nil {
-		// If API fails, fall back to local only
-		fmt.Fprintf(os.Stderr, "Warning: Could not query API (%v), showing local caches only\n", err)
-		return listLocalCachesOnly()
+		if len(apiCaches) == 0 {
+			// If API fails, fall back to local only
+			fmt.Fprintf(os.Stderr, "Warning: Could not query API (%v), showing local caches only\n", err)
+			return listLocalCachesOnly()
+		}
+		fmt.Fprintf(os.Stderr, "Warning: API listing was incomplete (%v), showing partial results\n", err)
 	}
 
 	// Create a map of API caches for easy lookup
@@ -636,7 +882,7 @@ func listCachesCombined() error {
 
 		cacheRows = append(cacheRows, cacheRow{
 			data: []string{
-				localInfo.CacheName,
+				pinnedCacheName(localInfo),
 				repoName,
 				localInfo.Model,
 				status,
@@ -662,6 +908,9 @@ func listCachesCombined() error {
 			if len(cacheName) > 16 {
 				cacheName = cacheName[:16]
 			}
+			if name := gemini.UserCacheDisplayName(apiCache.DisplayName); name != "" {
+				cacheName = name
+			}
 
 			// These are API-only, so no local file
 			var status string
@@ -831,7 +1080,7 @@ func listLocalCachesOnly() error {
 
 			cacheRows = append(cacheRows, cacheRow{
 				data: []string{
-					info.CacheName,
+					pinnedCacheName(info),
 					repoName,
 					info.Model,
 					status,
@@ -892,7 +1141,10 @@ func listCachesFromAPI() error {
 	fmt.Println("Querying caches from Google API...")
 	caches, err := client.ListCachesFromAPI(ctx)
 	if err != nil {
-		return fmt.Errorf("listing caches from API: %w", err)
+		if len(caches) == 0 {
+			return fmt.Errorf("listing caches from API: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: API listing was incomplete (%v), showing partial results\n", err)
 	}
 
 	if len(caches) == 0 {
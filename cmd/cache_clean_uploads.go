@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCleanUploadsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean-uploads",
+		Short: "Forget earlier file uploads so the next request uploads fresh copies",
+		Long: `Delete the local index of uploaded context files.
+
+Requests reuse an earlier upload when a context file's content hasn't changed.
+Cleaning the index forces every file to be uploaded again, which helps when a
+stale upload causes unexpected results. The uploaded files themselves expire
+on the server on their own.
+
+To skip reuse for a single request, pass --no-upload-cache to 'request'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+
+			removed, err := gemini.ClearUploadIndex(gemini.ResolveGeminiCacheDir(workDir))
+			if err != nil {
+				return err
+			}
+			if removed == 0 {
+				fmt.Println("No uploads recorded. Nothing to clean.")
+				return nil
+			}
+			fmt.Printf("%s Forgot %d uploaded file(s)\n", theme.IconSuccess, removed)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/tui/theme"
+	grovecontext "github.com/grovetools/cx/pkg/context"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCreateCmd() *cobra.Command {
+	var model, ttlStr, cacheName string
+	var minCacheTokens int
+	var force, yes, countTokens bool
+
+	cmd := &cobra.Command{
+		Use:   "create [file|url...]",
+		Short: "Create a cache from the cold context or arbitrary files or URLs",
+		Long: `Upload one or more files and create a single Gemini cache from them.
+The cache is recorded locally like any other cache, and the printed name can be
+passed to 'request --use-cache'.
+
+Without arguments the project's cold context is cached, generating it from
+.grove/rules first if it doesn't exist yet. This warms the cache ahead of time,
+so the first real request doesn't pay for the upload, e.g. in a CI setup step.
+
+If a valid cache already exists for the same file contents it is reused
+unless --force is given.
+
+--cache-name gives the cache a human-readable name that 'cache list', the TUI
+and --use-cache accept in place of its hash. A name belongs to one cache at a
+time; giving it to a new cache takes it from the old one.
+
+An http(s) URL is fetched first; it must serve a text document.
+
+Examples:
+  # Warm the cache for the current cold context
+  grove-gemini cache create --ttl 2h --yes
+
+  # Cache a large spec for an hour
+  grove-gemini cache create docs/spec.md --ttl 1h
+
+  # Cache a document published on the web
+  grove-gemini cache create https://example.com/spec.md
+
+  # Cache several files together
+  grove-gemini cache create docs/spec.md docs/api.md
+
+  # Name the cache so it can be used without its hash
+  grove-gemini cache create --cache-name main-context
+  grove-gemini request --use-cache main-context -p "Summarize the architecture"
+
+  # Use it in a request
+  grove-gemini request --use-cache <name> -p "Summarize section 4"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+
+			if len(args) == 0 {
+				coldContextFile, err := resolveColdContextFile(workDir)
+				if err != nil {
+					return err
+				}
+				args = []string{coldContextFile}
+			}
+
+			filePaths := make([]string, 0, len(args))
+			for _, arg := range args {
+				source := arg
+				if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+					fetched, err := gemini.FetchContextURL(ctx, source, gemini.DefaultContextURLTimeout, gemini.MaxContextURLBytes)
+					if err != nil {
+						return err
+					}
+					defer func() { _ = os.Remove(fetched) }()
+					source = fetched
+				}
+
+				filePath, err := filepath.Abs(source)
+				if err != nil {
+					return fmt.Errorf("resolving file path: %w", err)
+				}
+				info, err := os.Stat(filePath)
+				if err != nil {
+					return fmt.Errorf("reading file: %w", err)
+				}
+				if info.IsDir() {
+					return fmt.Errorf("%s is a directory, not a file", arg)
+				}
+				filePaths = append(filePaths, filePath)
+			}
+
+			ttl, err := time.ParseDuration(ttlStr)
+			if err != nil {
+				return fmt.Errorf("parsing cache TTL: %w", err)
+			}
+
+			// Fall back to the repo's configured default model when --model isn't passed
+			model, _ = config.ResolveModel(workDir, model, cmd.Flags().Changed("model"))
+
+			client, err := gemini.NewClient(ctx, "")
+			if err != nil {
+				return fmt.Errorf("creating Gemini client: %w", err)
+			}
+
+			var estimator gemini.TokenEstimator = gemini.HeuristicEstimator{}
+			if countTokens {
+				estimator = gemini.APITokenEstimator{Client: client}
+			}
+
+			cacheManager := gemini.NewCacheManager(workDir)
+			if !cmd.Flags().Changed("min-cache-tokens") {
+				minCacheTokens = config.ResolveMinCacheTokens(workDir)
+			}
+			cacheManager.SetMinCacheTokens(minCacheTokens)
+			cacheManager.SetDisplayName(cacheName)
+			if config.ResolveSharedCache(workDir) {
+				cacheManager.SetSharedCacheDir(gemini.SharedCacheDir())
+			}
+			cacheInfo, created, err := cacheManager.GetOrCreateCache(ctx, client, model, filePaths, ttl, false, false, force, yes, estimator)
+			if err != nil {
+				return err
+			}
+			if cacheInfo == nil {
+				return fmt.Errorf("no cache was created for %s", strings.Join(args, ", "))
+			}
+
+			// A reused cache takes the name too, and the name moves off any other cache
+			useName := cacheInfo.CacheName
+			if cacheName != "" {
+				if err := gemini.AssignCacheDisplayName(ctx, gemini.ResolveGeminiCacheDir(workDir), cacheInfo.CacheName, cacheName); err != nil {
+					return fmt.Errorf("naming cache: %w", err)
+				}
+				useName = cacheName
+			}
+
+			if created {
+				fmt.Printf("%s Created cache %s\n", theme.IconSuccess, describeCacheName(cacheInfo.CacheName, cacheName))
+			} else {
+				fmt.Printf("%s Reusing existing cache %s\n", theme.IconInfo, describeCacheName(cacheInfo.CacheName, cacheName))
+			}
+			fmt.Printf("Use it with: grove-gemini request --use-cache %s\n", useName)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&model, "model", "m", config.DefaultRequestModel, "Gemini model to create the cache for (defaults to gemini.default_model in grove.yml if set)")
+	cmd.Flags().StringVar(&ttlStr, "ttl", "1h", "Cache TTL (e.g., 1h, 30m, 24h)")
+	cmd.Flags().StringVar(&cacheName, "cache-name", "", "Human-readable name for the cache, usable in place of its hash")
+	cmd.Flags().BoolVar(&force, "force", false, "Create a new cache even if a valid one exists for this file")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip cache creation confirmation prompt")
+	cmd.Flags().IntVar(&minCacheTokens, "min-cache-tokens", config.DefaultMinCacheTokens, "Smallest file set in tokens worth caching (defaults to gemini.min_cache_tokens in grove.yml if set)")
+	cmd.Flags().BoolVar(&countTokens, "count-tokens", false, "Count tokens with the API before checking the caching minimum (costs a round trip)")
+
+	return cmd
+}
+
+// describeCacheName shows a cache's hash with its display name, if it has one
+func describeCacheName(hash, displayName string) string {
+	if displayName == "" {
+		return hash
+	}
+	return fmt.Sprintf("%s (%s)", displayName, hash)
+}
+
+// resolveColdContextFile returns the project's cold context file, generating
+// the context from .grove/rules when the file doesn't exist yet
+func resolveColdContextFile(workDir string) (string, error) {
+	ctxMgr := grovecontext.NewManager(workDir)
+	coldContextFile := ctxMgr.ResolveCachedContextPath()
+
+	if _, err := os.Stat(coldContextFile); os.IsNotExist(err) {
+		if _, err := os.Stat(ctxMgr.ResolveRulesPath()); err != nil {
+			return "", fmt.Errorf("no cold context found at %s and no rules file to generate it from; pass files to cache explicitly", coldContextFile)
+		}
+		if err := ctxMgr.UpdateFromRules(); err != nil {
+			return "", fmt.Errorf("updating context from rules: %w", err)
+		}
+		if err := ctxMgr.GenerateContext(true); err != nil {
+			return "", fmt.Errorf("generating context: %w", err)
+		}
+	}
+
+	info, err := os.Stat(coldContextFile)
+	if err != nil {
+		return "", fmt.Errorf("reading cold context: %w", err)
+	}
+	if info.Size() == 0 {
+		return "", fmt.Errorf("cold context %s is empty; nothing to cache", coldContextFile)
+	}
+	return coldContextFile, nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	grovecontext "github.com/grovetools/cx/pkg/context"
+)
+
+func TestResolveColdContextFile(t *testing.T) {
+	workDir := t.TempDir()
+
+	if _, err := resolveColdContextFile(workDir); err == nil || !strings.Contains(err.Error(), "no cold context found") {
+		t.Fatalf("expected missing cold context error, got %v", err)
+	}
+
+	coldContextFile := grovecontext.NewManager(workDir).ResolveCachedContextPath()
+	if err := os.MkdirAll(filepath.Dir(coldContextFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(coldContextFile, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveColdContextFile(workDir); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Fatalf("expected empty cold context error, got %v", err)
+	}
+
+	if err := os.WriteFile(coldContextFile, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := resolveColdContextFile(workDir)
+	if err != nil {
+		t.Fatalf("resolveColdContextFile: %v", err)
+	}
+	if got != coldContextFile {
+		t.Errorf("got %s, want %s", got, coldContextFile)
+	}
+}
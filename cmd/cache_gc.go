@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+)
+
+func newCacheGCCmd() *cobra.Command {
+	var (
+		olderThan   time.Duration
+		dryRun      bool
+		deleteFound bool
+		allProjects bool
+		yes         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete server caches that have no local record",
+		Long: `Find caches on Google's servers that no local record refers to and, with
+--delete, delete them. These are the caches 'cache list' shows as API-only,
+typically left behind when a project's .grove/gemini-cache was wiped. They keep
+accruing storage charges until they expire.
+
+A server cache counts as known when the current project has a record for it
+(including records in the trash) or it is listed in the shared cache index.
+
+grove-gemini tags the caches it creates with the project they belong to, and
+by default gc only considers caches tagged for the current project. Caches
+from other projects and other tools using the same API key may still be in
+use, so they are only included with --all-projects, which always asks for
+confirmation. --older-than keeps recently created caches out of reach either way.
+
+Without --delete, gc only lists what it would delete. With --delete it asks
+for confirmation unless --yes is given.`,
+		Example: `  # See what would be deleted
+  grove-gemini cache gc
+
+  # Delete this project's API-only caches created more than a week ago
+  grove-gemini cache gc --older-than 168h --delete --yes
+
+  # Include caches created by other projects and tools
+  grove-gemini cache gc --all-projects --delete`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if dryRun && deleteFound {
+				return fmt.Errorf("--dry-run and --delete cannot be used together")
+			}
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+
+			cacheDir := gemini.ResolveGeminiCacheDir(workDir)
+			known, err := gemini.KnownCacheIDs(cacheDir, gemini.SharedCacheDir())
+			if err != nil {
+				return fmt.Errorf("reading local cache records: %w", err)
+			}
+
+			client, err := gemini.NewClient(ctx, "")
+			if err != nil {
+				return fmt.Errorf("creating client: %w", err)
+			}
+			apiCaches, err := client.ListCachesFromAPI(ctx)
+			if err != nil {
+				switch {
+				case gemini.IsPermissionError(err):
+					// Work with whatever the listing returned, as the TUI does
+					fmt.Fprintf(os.Stderr, "Warning: no permission to list some caches: %v\n", err)
+				case len(apiCaches) > 0:
+					fmt.Fprintf(os.Stderr, "Warning: using partial API results: %v\n", err)
+				default:
+					return fmt.Errorf("could not query API: %w", err)
+				}
+			}
+
+			projectTag := gemini.ProjectCacheTag(cacheDir)
+			orphaned := gemini.FindOrphanedCaches(apiCaches, known, projectTag, olderThan, time.Now())
+			if allProjects {
+				orphaned = gemini.FindOrphanedCaches(apiCaches, known, "", olderThan, time.Now())
+			} else if skipped := len(gemini.FindOrphanedCaches(apiCaches, known, "", olderThan, time.Now())) - len(orphaned); skipped > 0 {
+				defer fmt.Printf("Skipped %d cache(s) created by other projects or tools; use --all-projects to include them.\n", skipped)
+			}
+			if len(orphaned) == 0 {
+				fmt.Printf("No server caches without a local record older than %s.\n", formatDuration(olderThan))
+				return nil
+			}
+
+			fmt.Println(formatOrphanedCaches(orphaned, projectTag))
+			if !deleteFound {
+				fmt.Printf("\nDry run: %d cache(s) would be deleted. Run with --delete to delete them.\n", len(orphaned))
+				return nil
+			}
+
+			// Caches from elsewhere may be in use, so --yes doesn't skip this prompt
+			question := fmt.Sprintf("Delete %d cache(s) from Google's servers?", len(orphaned))
+			if allProjects {
+				question = fmt.Sprintf("Delete %d cache(s) from Google's servers, including caches other projects or tools may still use?", len(orphaned))
+			}
+			if (allProjects || !yes) && !confirm(os.Stdin, os.Stdout, question) {
+				fmt.Println("Aborted. No caches were deleted.")
+				return nil
+			}
+
+			deleted := 0
+			for _, cache := range orphaned {
+				if err := client.DeleteCache(ctx, cache.Name); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to delete %s: %v\n", cache.Name, err)
+					continue
+				}
+				fmt.Printf("Deleted from API: %s\n", cache.Name)
+				deleted++
+			}
+			fmt.Printf("\nDeleted %d of %d cache(s) without a local record.\n", deleted, len(orphaned))
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 24*time.Hour, "Only delete caches created longer ago than this")
+	cmd.Flags().BoolVar(&deleteFound, "delete", false, "Delete the caches found instead of only listing them")
+	cmd.Flags().BoolVar(&allProjects, "all-projects", false, "Include caches created by other projects and tools (always asks for confirmation)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would be deleted without deleting anything (the default)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt for this project's caches")
+
+	return cmd
+}
+
+// formatOrphanedCaches renders the caches gc would delete as a table, naming
+// which project or tool created each relative to projectTag
+func formatOrphanedCaches(caches []gemini.CachedContentInfo, projectTag string) string {
+	rows := make([][]string, 0, len(caches))
+	for _, c := range caches {
+		tokens := "-"
+		if c.TokenCount > 0 {
+			tokens = pretty.FormatTokens(c.TokenCount)
+		}
+		source := "other tool"
+		if tag, ok := gemini.CacheOwnerTag(c.DisplayName); ok {
+			source = "other project"
+			if tag == projectTag {
+				source = "this project"
+			}
+		}
+		rows = append(rows, []string{
+			strings.TrimPrefix(c.Name, "cachedContents/"),
+			source,
+			c.Model,
+			c.CreateTime.Local().Format("2006-01-02 15:04"),
+			c.ExpireTime.Local().Format("2006-01-02 15:04"),
+			tokens,
+		})
+	}
+	return tablecomponent.NewStyledTable().
+		Headers("CACHE", "CREATED BY", "MODEL", "CREATED", "EXPIRES", "TOKENS").
+		Rows(rows...).
+		String()
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+)
+
+func newCacheLeaderboardCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "leaderboard",
+		Short: "Rank caches by how much they have saved",
+		Long: `Rank the project's local caches by estimated dollar savings, so it's
+clear which cached contexts pay for themselves.
+
+Caches that were created but never used by a query are listed separately as
+candidates for deletion. Records already cleared from the server are left out
+of that list.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+
+			infos, err := loadLocalCacheInfos(gemini.ResolveGeminiCacheDir(workDir))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if len(infos) == 0 {
+				fmt.Println("No caches found in this project.")
+				return nil
+			}
+
+			ranked, neverReused := rankCacheSavings(infos)
+
+			if len(ranked) > 0 {
+				rows := make([][]string, 0, len(ranked))
+				for i, r := range ranked {
+					rows = append(rows, []string{
+						fmt.Sprintf("%d", i+1),
+						pinnedCacheName(r.Info),
+						r.Info.Model,
+						fmt.Sprintf("%d", r.Info.UsageStats.TotalQueries),
+						pretty.FormatTokens(r.Info.UsageStats.TotalTokensSaved),
+						pretty.FormatCost(r.Savings),
+					})
+				}
+				fmt.Println(tablecomponent.NewStyledTable().
+					Headers("#", "CACHE NAME", "MODEL", "QUERIES", "TOKENS SAVED", "SAVED").
+					Rows(rows...))
+			} else {
+				fmt.Println("No cache has been used by a query yet.")
+			}
+
+			if len(neverReused) > 0 {
+				fmt.Printf("\n%s Never reused (candidates for deletion):\n", theme.IconWarning)
+				for _, info := range neverReused {
+					fmt.Printf("  %s  %s  created %s ago\n", pinnedCacheName(info), info.Model, formatDuration(time.Since(info.CreatedAt)))
+				}
+				fmt.Println("\nDelete one with: grove-gemini cache clear <cache-name>")
+			}
+
+			return nil
+		},
+	}
+}
+
+// cacheSavings is a cache with its estimated savings
+type cacheSavings struct {
+	Info    *gemini.CacheInfo
+	Savings float64
+}
+
+// rankCacheSavings sorts the caches that have served queries by estimated
+// savings, highest first, and returns the ones that were never queried
+// (and haven't been cleared) separately, oldest first.
+func rankCacheSavings(infos []*gemini.CacheInfo) ([]cacheSavings, []*gemini.CacheInfo) {
+	var ranked []cacheSavings
+	var neverReused []*gemini.CacheInfo
+	for _, info := range infos {
+		if info.UsageStats == nil || info.UsageStats.TotalQueries == 0 {
+			if info.ClearedAt == nil {
+				neverReused = append(neverReused, info)
+			}
+			continue
+		}
+		ranked = append(ranked, cacheSavings{Info: info, Savings: gemini.CalculateCacheAnalytics(info).TotalSavings})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Savings != ranked[j].Savings {
+			return ranked[i].Savings > ranked[j].Savings
+		}
+		return ranked[i].Info.UsageStats.TotalTokensSaved > ranked[j].Info.UsageStats.TotalTokensSaved
+	})
+	sort.SliceStable(neverReused, func(i, j int) bool {
+		return neverReused[i].CreatedAt.Before(neverReused[j].CreatedAt)
+	})
+	return ranked, neverReused
+}
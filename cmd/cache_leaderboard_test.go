@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+)
+
+func TestRankCacheSavings(t *testing.T) {
+	now := time.Now()
+	cleared := now.Add(-time.Hour)
+	usage := func(queries int, cached int64) *gemini.CacheUsageStats {
+		return &gemini.CacheUsageStats{TotalQueries: queries, TotalCacheHits: cached, TotalTokensSaved: cached}
+	}
+	infos := []*gemini.CacheInfo{
+		{CacheName: "small", Model: "gemini-2.0-flash", CreatedAt: now, UsageStats: usage(2, 10_000)},
+		{CacheName: "unused-new", Model: "gemini-2.0-flash", CreatedAt: now},
+		{CacheName: "big", Model: "gemini-2.0-flash", CreatedAt: now, UsageStats: usage(5, 2_000_000)},
+		{CacheName: "unused-old", Model: "gemini-2.0-flash", CreatedAt: now.Add(-48 * time.Hour), UsageStats: usage(0, 0)},
+		{CacheName: "unused-cleared", Model: "gemini-2.0-flash", CreatedAt: now, ClearedAt: &cleared},
+	}
+
+	ranked, neverReused := rankCacheSavings(infos)
+	if len(ranked) != 2 || ranked[0].Info.CacheName != "big" || ranked[1].Info.CacheName != "small" {
+		t.Fatalf("expected big then small, got %+v", ranked)
+	}
+	if ranked[0].Savings <= ranked[1].Savings {
+		t.Errorf("expected descending savings, got %.4f then %.4f", ranked[0].Savings, ranked[1].Savings)
+	}
+	if len(neverReused) != 2 || neverReused[0].CacheName != "unused-old" || neverReused[1].CacheName != "unused-new" {
+		t.Errorf("expected uncleared unused caches oldest first, got %v", neverReused)
+	}
+}
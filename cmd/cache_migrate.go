@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/spf13/cobra"
+)
+
+func newCacheMigrateCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade cache records written by older cache key schemes",
+		Long: fmt.Sprintf(`Find local cache records written by an older cache key scheme and bring
+them up to the current one (v%d).
+
+Records whose source file is unchanged are re-keyed so requests keep finding
+and reusing the paid-for cache. Records whose sources changed are kept under
+their old key, pinned or not, and expire as usual. Only an unpinned record that
+duplicates one already stored under its new key is marked as orphaned; 'cache
+prune' then deletes it from Google's API so it doesn't linger until expiry.
+
+Examples:
+  # Show what would change
+  grove-gemini cache migrate --dry-run
+
+  # Migrate, then clean up duplicates that couldn't be re-keyed
+  grove-gemini cache migrate && grove-gemini cache prune`, gemini.CacheKeyVersion),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+
+			migrations, err := gemini.NewCacheManager(workDir).MigrateCacheKeys(dryRun)
+			if err != nil {
+				return fmt.Errorf("migrating caches: %w", err)
+			}
+
+			counts := make(map[gemini.CacheMigrationAction]int)
+			for _, m := range migrations {
+				counts[m.Action]++
+				if m.Action == gemini.MigrationCurrent {
+					continue
+				}
+
+				var msg string
+				switch m.Action {
+				case gemini.MigrationStamped:
+					msg = fmt.Sprintf("%s Recorded key version for %s", theme.IconSuccess, m.CacheName)
+					if m.Reason != "" {
+						msg += fmt.Sprintf(" (kept under its old key: %s)", m.Reason)
+					}
+				case gemini.MigrationRekeyed:
+					msg = fmt.Sprintf("%s Re-keyed %s -> %s", theme.IconSuccess, m.CacheName, m.NewCacheName)
+				case gemini.MigrationOrphaned:
+					msg = fmt.Sprintf("%s Marked %s for cleanup: %s", theme.IconWarning, m.CacheName, m.Reason)
+				}
+				ulog.Info("Cache migration").
+					Field("file", m.File).
+					Field("cache_name", m.CacheName).
+					Field("new_cache_name", m.NewCacheName).
+					Field("action", string(m.Action)).
+					Field("reason", m.Reason).
+					Field("dry_run", dryRun).
+					Pretty(msg).
+					Log(ctx)
+			}
+
+			if len(migrations) == 0 {
+				fmt.Println("No cache records found. Nothing to migrate.")
+				return nil
+			}
+
+			prefix := ""
+			if dryRun {
+				prefix = "Dry run: "
+			}
+			fmt.Printf("\n%s%d current, %d version recorded, %d re-keyed, %d marked for cleanup.\n", prefix,
+				counts[gemini.MigrationCurrent], counts[gemini.MigrationStamped],
+				counts[gemini.MigrationRekeyed], counts[gemini.MigrationOrphaned])
+			if counts[gemini.MigrationOrphaned] > 0 && !dryRun {
+				fmt.Println("Run 'grove-gemini cache prune' to delete orphaned caches from the API.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show planned actions without changing any cache records")
+
+	return cmd
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/spf13/cobra"
+)
+
+func newCacheRestoreCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "restore [cache-name...] | --all",
+		Short: "Recover cache records moved to the trash by 'cache clear --trash'",
+		Long: fmt.Sprintf(`Moves cache records from the trash back into the cache directory.
+Without arguments, lists the records currently in the trash.
+
+Trashed records are kept for %d days. A record whose server cache could not be
+deleted when it was cleared is restored as active and can be reused right away.
+
+Examples:
+  # See what can be restored
+  grove-gemini cache restore
+
+  # Restore a single record
+  grove-gemini cache restore 1a2b3c4d5e6f7a8b
+
+  # Restore everything in the trash
+  grove-gemini cache restore --all`, int(gemini.TrashRetention.Hours()/24)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+			cacheDir := gemini.ResolveGeminiCacheDir(workDir)
+
+			trashed, err := gemini.ListTrashedCaches(cacheDir)
+			if err != nil {
+				return err
+			}
+
+			if !all && len(args) == 0 {
+				if len(trashed) == 0 {
+					fmt.Println("Trash is empty. Nothing to restore.")
+					return nil
+				}
+				rows := make([][]string, 0, len(trashed))
+				for _, t := range trashed {
+					status := "active"
+					if t.Info.ClearedAt != nil {
+						status = "cleared"
+					} else if time.Now().After(t.Info.ExpiresAt) {
+						status = "expired"
+					}
+					rows = append(rows, []string{
+						t.Info.CacheName,
+						t.Info.Model,
+						status,
+						t.TrashedAt.Format("2006-01-02 15:04"),
+					})
+				}
+				table := tablecomponent.NewStyledTable().
+					Headers("CACHE NAME", "MODEL", "STATUS", "TRASHED").
+					Rows(rows...)
+				fmt.Println(table)
+				return nil
+			}
+
+			names := args
+			if all {
+				names = nil
+				for _, t := range trashed {
+					names = append(names, t.Info.CacheName)
+				}
+			}
+
+			restored := 0
+			for _, name := range names {
+				if err := gemini.RestoreCacheRecord(cacheDir, name); err != nil {
+					fmt.Fprintf(os.Stderr, "%s %v\n", theme.IconWarning, err)
+					continue
+				}
+				fmt.Printf("%s Restored %s\n", theme.IconSuccess, name)
+				restored++
+			}
+			if all {
+				fmt.Printf("\nRestored %d of %d trashed cache(s).\n", restored, len(names))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Restore every record in the trash")
+
+	return cmd
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+)
+
+func newCacheStatsCmd() *cobra.Command {
+	var minHitRate float64
+	var minQueries int
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "stats [cache-name] | --all",
+		Short: "Show cache usage statistics and recommendations",
+		Long: `Show per-cache usage statistics for the current project and recommend
+disabling caching for contexts whose average hit rate stays below a threshold.
+
+Thresholds default to gemini.cache_advice in grove.yml (min_hit_rate, min_queries)
+and can be overridden with flags.
+
+Pass a cache name to see its analytics: efficiency score, total and per-query
+savings, peak usage and an hourly usage histogram. --all shows the same
+analytics aggregated across every local cache in the project.
+
+Examples:
+  # Recommendations for every cache
+  grove-gemini cache stats
+
+  # Analytics for one cache
+  grove-gemini cache stats 1a2b3c4d5e6f7a8b
+
+  # Analytics across all caches
+  grove-gemini cache stats --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && len(args) > 0 {
+				return fmt.Errorf("pass either a cache name or --all, not both")
+			}
+
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+			cacheDir := gemini.ResolveGeminiCacheDir(workDir)
+
+			if len(args) == 1 {
+				info, err := gemini.LoadCacheInfo(filepath.Join(cacheDir, "hybrid_"+args[0]+".json"))
+				if err != nil {
+					if os.IsNotExist(err) {
+						return fmt.Errorf("cache '%s' not found", args[0])
+					}
+					return fmt.Errorf("loading cache info: %w", err)
+				}
+				printCacheStatsReport(os.Stdout, "Cache "+info.CacheName, aggregateCacheStats([]*gemini.CacheInfo{info}))
+				return nil
+			}
+
+			advice := config.ResolveCacheAdvice(workDir)
+			if cmd.Flags().Changed("min-hit-rate") {
+				advice.MinHitRate = minHitRate
+			}
+			if cmd.Flags().Changed("min-queries") {
+				advice.MinQueries = minQueries
+			}
+
+			infos, err := loadLocalCacheInfos(cacheDir)
+			if err != nil {
+				if os.IsNotExist(err) {
+					fmt.Println("No cache directory found. No statistics to show.")
+					return nil
+				}
+				return err
+			}
+
+			if len(infos) == 0 {
+				fmt.Println("No caches found in this project.")
+				return nil
+			}
+
+			if all {
+				printCacheStatsReport(os.Stdout, fmt.Sprintf("All caches (%d)", len(infos)), aggregateCacheStats(infos))
+				return nil
+			}
+
+			// Newest first
+			sort.Slice(infos, func(i, j int) bool {
+				return infos[i].CreatedAt.After(infos[j].CreatedAt)
+			})
+
+			var recommendations []string
+			rows := make([][]string, 0, len(infos))
+			for _, info := range infos {
+				queries, hitRate, saved := "0", "-", "-"
+				if info.UsageStats != nil && info.UsageStats.TotalQueries > 0 {
+					queries = fmt.Sprintf("%d", info.UsageStats.TotalQueries)
+					hitRate = fmt.Sprintf("%.1f%%", info.UsageStats.AverageHitRate*100)
+					saved = fmt.Sprintf("$%.2f", gemini.CalculateCacheAnalytics(info).TotalSavings)
+				}
+
+				rec := gemini.RecommendCacheUsage(info, advice.MinHitRate, advice.MinQueries)
+				advise := theme.IconSuccess + " Keep"
+				if rec.DisableCaching {
+					advise = theme.IconWarning + " Disable"
+					recommendations = append(recommendations, fmt.Sprintf("%s: %s", info.CacheName, rec.Reason))
+				} else if info.UsageStats == nil || info.UsageStats.TotalQueries < advice.MinQueries {
+					advise = theme.IconInfo + " Too early"
+				}
+
+				rows = append(rows, []string{
+					pinnedCacheName(info),
+					info.Model,
+					queries,
+					hitRate,
+					saved,
+					advise,
+				})
+			}
+
+			t := tablecomponent.NewStyledTable().
+				Headers("CACHE NAME", "MODEL", "QUERIES", "HIT RATE", "SAVED", "ADVICE").
+				Rows(rows...)
+
+			fmt.Println(t)
+			fmt.Printf("\nThreshold: %.0f%% average hit rate after %d queries\n", advice.MinHitRate*100, advice.MinQueries)
+
+			if len(recommendations) > 0 {
+				fmt.Println("\nRecommendations:")
+				for _, r := range recommendations {
+					fmt.Printf("  %s %s\n", theme.IconLightbulb, r)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Float64Var(&minHitRate, "min-hit-rate", config.DefaultCacheMinHitRate, "Average hit rate (0-1) below which disabling caching is recommended")
+	cmd.Flags().IntVar(&minQueries, "min-queries", config.DefaultCacheMinQueries, "Minimum number of queries before making a recommendation")
+	cmd.Flags().BoolVar(&all, "all", false, "Show analytics aggregated across every local cache in the project")
+
+	return cmd
+}
+
+// loadLocalCacheInfos reads every cache record in cacheDir, warning about and
+// skipping records that can't be read. A missing directory returns an
+// os.IsNotExist error.
+func loadLocalCacheInfos(cacheDir string) ([]*gemini.CacheInfo, error) {
+	files, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	var infos []*gemini.CacheInfo
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".json") && strings.HasPrefix(file.Name(), "hybrid_") {
+			info, err := gemini.LoadCacheInfo(filepath.Join(cacheDir, file.Name()))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not read cache info for %s: %v\n", file.Name(), err)
+				continue
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+// cacheStatsReport is the analytics for one cache or the aggregate of several
+type cacheStatsReport struct {
+	Caches          int // Caches that have served at least one query
+	Queries         int
+	CachedTokens    int64
+	EfficiencyScore float64 // Average across caches with usage
+	TotalSavings    float64
+	UsageByHour     [24]int
+	UsageByDay      map[string]int
+	HitRateTrend    []float64
+}
+
+// aggregateCacheStats combines CalculateCacheAnalytics across caches.
+// Caches that have never been queried don't count toward the efficiency average.
+func aggregateCacheStats(infos []*gemini.CacheInfo) cacheStatsReport {
+	report := cacheStatsReport{UsageByDay: make(map[string]int)}
+	for _, info := range infos {
+		if info.UsageStats == nil || info.UsageStats.TotalQueries == 0 {
+			continue
+		}
+		analytics := gemini.CalculateCacheAnalytics(info)
+		report.Caches++
+		report.Queries += info.UsageStats.TotalQueries
+		report.CachedTokens += info.UsageStats.TotalCacheHits
+		report.EfficiencyScore += analytics.EfficiencyScore
+		report.TotalSavings += analytics.TotalSavings
+		for hour, count := range analytics.UsageByHour {
+			report.UsageByHour[hour] += count
+		}
+		for day, count := range analytics.UsageByDay {
+			report.UsageByDay[day] += count
+		}
+		report.HitRateTrend = append(report.HitRateTrend, analytics.HitRateTrend...)
+	}
+	if report.Caches > 0 {
+		report.EfficiencyScore /= float64(report.Caches)
+	}
+	return report
+}
+
+// printCacheStatsReport writes the analytics with an hourly usage histogram
+func printCacheStatsReport(w io.Writer, title string, report cacheStatsReport) {
+	_, _ = fmt.Fprintf(w, "%s\n\n", title)
+	if report.Queries == 0 {
+		_, _ = fmt.Fprintln(w, "No queries have used this cache yet.")
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Efficiency Score:  %.1f/100\n", report.EfficiencyScore)
+	_, _ = fmt.Fprintf(w, "Queries:           %d\n", report.Queries)
+	_, _ = fmt.Fprintf(w, "Cached Tokens:     %s\n", pretty.FormatTokensCompact(report.CachedTokens))
+	_, _ = fmt.Fprintf(w, "Total Savings:     %s\n", pretty.FormatCost(report.TotalSavings))
+	_, _ = fmt.Fprintf(w, "Savings per Query: %s\n", pretty.FormatCost(report.TotalSavings/float64(report.Queries)))
+
+	peakHour, peakHourCount := 0, 0
+	for hour, count := range report.UsageByHour {
+		if count > peakHourCount {
+			peakHour, peakHourCount = hour, count
+		}
+	}
+	if peakHourCount > 0 {
+		_, _ = fmt.Fprintf(w, "Peak Hour:         %02d:00 (%d queries)\n", peakHour, peakHourCount)
+	}
+
+	days := make([]string, 0, len(report.UsageByDay))
+	for day := range report.UsageByDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	peakDay, peakDayCount := "", 0
+	for _, day := range days {
+		if report.UsageByDay[day] > peakDayCount {
+			peakDay, peakDayCount = day, report.UsageByDay[day]
+		}
+	}
+	if peakDay != "" {
+		_, _ = fmt.Fprintf(w, "Peak Day:          %s (%d queries)\n", peakDay, peakDayCount)
+	}
+
+	if len(report.HitRateTrend) > 0 {
+		_, _ = fmt.Fprintf(w, "Recent Hit Rates:  %s\n", generateSparkline(report.HitRateTrend))
+	}
+
+	if peakHourCount == 0 {
+		return
+	}
+	const barWidth = 40
+	_, _ = fmt.Fprintln(w, "\nUsage by Hour:")
+	for hour, count := range report.UsageByHour {
+		width := count * barWidth / peakHourCount
+		bar := strings.Repeat("█", width)
+		if count > 0 && width == 0 {
+			bar, width = "▏", 1
+		}
+		_, _ = fmt.Fprintf(w, "  %02d:00 %s%s %d\n", hour, bar, strings.Repeat(" ", barWidth-width), count)
+	}
+}
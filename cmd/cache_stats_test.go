@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+)
+
+func TestAggregateCacheStats(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2025, 3, 3, hour, 0, 0, 0, time.Local) // a Monday
+	}
+	used := &gemini.CacheInfo{
+		Model:     "gemini-2.0-flash",
+		CreatedAt: time.Now().Add(-time.Hour),
+		UsageStats: &gemini.CacheUsageStats{
+			TotalQueries:   3,
+			TotalCacheHits: 300_000,
+			AverageHitRate: 0.8,
+			QueryHistory: []gemini.CacheQueryStats{
+				{Timestamp: at(9), CacheHitRate: 0.7},
+				{Timestamp: at(9), CacheHitRate: 0.8},
+				{Timestamp: at(14), CacheHitRate: 0.9},
+			},
+		},
+	}
+	other := &gemini.CacheInfo{
+		Model:     "gemini-2.0-flash",
+		CreatedAt: time.Now().Add(-time.Hour),
+		UsageStats: &gemini.CacheUsageStats{
+			TotalQueries:   1,
+			TotalCacheHits: 100_000,
+			AverageHitRate: 0.5,
+			QueryHistory:   []gemini.CacheQueryStats{{Timestamp: at(14), CacheHitRate: 0.5}},
+		},
+	}
+	unused := &gemini.CacheInfo{Model: "gemini-2.0-flash"}
+
+	report := aggregateCacheStats([]*gemini.CacheInfo{used, other, unused})
+	if report.Caches != 2 || report.Queries != 4 || report.CachedTokens != 400_000 {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	if report.UsageByHour[9] != 2 || report.UsageByHour[14] != 2 || report.UsageByDay["Monday"] != 4 {
+		t.Errorf("unexpected usage pattern: hours %v, days %v", report.UsageByHour, report.UsageByDay)
+	}
+	want := (gemini.CalculateCacheAnalytics(used).EfficiencyScore + gemini.CalculateCacheAnalytics(other).EfficiencyScore) / 2
+	if report.EfficiencyScore != want {
+		t.Errorf("efficiency = %.2f, want the average %.2f", report.EfficiencyScore, want)
+	}
+
+	var buf bytes.Buffer
+	printCacheStatsReport(&buf, "All caches (3)", report)
+	out := buf.String()
+	for _, want := range []string{"Efficiency Score:", "Savings per Query:", "Peak Hour:         09:00 (2 queries)", "Peak Day:          Monday (4 queries)", "Usage by Hour:", "  14:00 █"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintCacheStatsReportNoUsage(t *testing.T) {
+	var buf bytes.Buffer
+	printCacheStatsReport(&buf, "Cache abc", aggregateCacheStats([]*gemini.CacheInfo{{Model: "gemini-2.0-flash"}}))
+	if !strings.Contains(buf.String(), "No queries have used this cache yet.") {
+		t.Errorf("expected a no-usage note, got:\n%s", buf.String())
+	}
+}
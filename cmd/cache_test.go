@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+)
+
+func TestPreviewLinesOf(t *testing.T) {
+	content := "line1\nline2\nline3\nline4"
+
+	got := previewLinesOf(content, 2)
+	if !strings.Contains(got, "│ line1") || !strings.Contains(got, "│ line2") {
+		t.Errorf("Expected first two lines in preview, got:\n%s", got)
+	}
+	if strings.Contains(got, "line3") {
+		t.Errorf("Expected preview to stop after two lines, got:\n%s", got)
+	}
+	if !strings.Contains(got, "2 more lines") {
+		t.Errorf("Expected remaining line count, got:\n%s", got)
+	}
+
+	if got := previewLinesOf(content, 10); strings.Contains(got, "more lines") {
+		t.Errorf("Did not expect a truncation note for short content, got:\n%s", got)
+	}
+
+	long := strings.Repeat("x", 200)
+	if got := previewLinesOf(long, 1); !strings.HasSuffix(got, "...") || len([]rune(got)) > 130 {
+		t.Errorf("Expected long line to be shortened, got %d chars", len([]rune(got)))
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"  yes  \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"y", true},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		if got := confirm(strings.NewReader(tt.input), &out, "Clear 2 cache(s)?"); got != tt.want {
+			t.Errorf("confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.Contains(out.String(), "Clear 2 cache(s)? [y/N]") {
+			t.Errorf("Expected question in prompt, got %q", out.String())
+		}
+	}
+}
+
+func TestPinnedCacheCleanup(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Chdir(dir)
+	now := time.Now()
+
+	cacheDir := gemini.ResolveGeminiCacheDir(dir)
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(cacheDir, "hybrid_pinned.json")
+	if err := gemini.SaveCacheInfo(path, &gemini.CacheInfo{CacheName: "pinned", Pinned: true, ExpiresAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		info       gemini.CacheInfo
+		force      bool
+		wantReason string
+		wantExempt bool
+	}{
+		{"pinned and live", gemini.CacheInfo{Pinned: true, ExpiresAt: now.Add(time.Hour)}, false, "", true},
+		{"pinned and expired", gemini.CacheInfo{Pinned: true, ExpiresAt: now.Add(-time.Hour)}, false, "expired", true},
+		{"pinned and orphaned", gemini.CacheInfo{Pinned: true, Orphaned: true, ExpiresAt: now.Add(time.Hour)}, false, "orphaned", true},
+		{"pinned with --force", gemini.CacheInfo{Pinned: true, ExpiresAt: now.Add(-time.Hour)}, true, "expired", false},
+		{"unpinned and expired", gemini.CacheInfo{ExpiresAt: now.Add(-time.Hour)}, false, "expired", false},
+		{"unpinned and live", gemini.CacheInfo{ExpiresAt: now.Add(time.Hour)}, false, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pruneReason(&tt.info, now); got != tt.wantReason {
+				t.Errorf("pruneReason() = %q, want %q", got, tt.wantReason)
+			}
+			if got := cleanupExempt(&tt.info, tt.force); got != tt.wantExempt {
+				t.Errorf("cleanupExempt(force=%v) = %v, want %v", tt.force, got, tt.wantExempt)
+			}
+		})
+	}
+
+	// Unpinning makes the expired record eligible for prune and clear --all again
+	if err := setCachePinned("pinned", false); err != nil {
+		t.Fatalf("setCachePinned: %v", err)
+	}
+	info, err := gemini.LoadCacheInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cleanupExempt(info, false) || pruneReason(info, now) != "expired" {
+		t.Errorf("unpinned record = %+v, want it pruned as expired", info)
+	}
+	if err := setCachePinned("pinned", true); err != nil {
+		t.Fatalf("setCachePinned: %v", err)
+	}
+	if info, err = gemini.LoadCacheInfo(path); err != nil || !cleanupExempt(info, false) {
+		t.Errorf("re-pinned record = %+v (err %v), want it exempt from cleanup", info, err)
+	}
+}
+
+func TestCacheClearResolvesDisplayName(t *testing.T) {
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, ".config"))
+	t.Setenv("GOOGLE_GEMINI_BASE_URL", srv.URL)
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("GROVE_GEMINI_BACKEND", "")
+	t.Chdir(dir)
+
+	cacheDir := gemini.ResolveGeminiCacheDir(dir)
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(cacheDir, "hybrid_abc123.json")
+	if err := gemini.SaveCacheInfo(path, &gemini.CacheInfo{
+		CacheID:     "cachedContents/xyz",
+		CacheName:   "abc123",
+		DisplayName: "my-context",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newCacheClearCmd()
+	cmd.SetArgs([]string{"my-context"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cache clear: %v", err)
+	}
+
+	if len(deleted) != 1 || !strings.HasSuffix(deleted[0], "cachedContents/xyz") {
+		t.Errorf("DELETE requests = %v, want one for cachedContents/xyz", deleted)
+	}
+	info, err := gemini.LoadCacheInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ClearedAt == nil {
+		t.Error("record for display name my-context was not marked as cleared")
+	}
+}
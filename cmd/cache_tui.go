@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -20,6 +22,8 @@ import (
 	"github.com/grovetools/core/tui/keymap"
 	"github.com/grovetools/core/tui/theme"
 	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 )
 
 type viewState int
@@ -43,6 +47,106 @@ type combinedCacheInfo struct {
 	CreateTime time.Time
 }
 
+// tokenCount returns the cached token count, preferring the API's figure
+func (c combinedCacheInfo) tokenCount() int32 {
+	if c.APIInfo != nil && c.APIInfo.TokenCount > 0 {
+		return c.APIInfo.TokenCount
+	}
+	if c.LocalInfo != nil {
+		return int32(c.LocalInfo.TokenCount) //nolint:gosec // TokenCount is bounded by API limits
+	}
+	return 0
+}
+
+// expireTime returns when the cache expires, preferring the API's figure
+func (c combinedCacheInfo) expireTime() time.Time {
+	if c.APIInfo != nil {
+		return c.APIInfo.ExpireTime
+	}
+	if c.LocalInfo != nil {
+		return c.LocalInfo.ExpiresAt
+	}
+	return time.Time{}
+}
+
+// uses returns the number of queries served from the cache
+func (c combinedCacheInfo) uses() int {
+	if c.LocalInfo != nil && c.LocalInfo.UsageStats != nil {
+		return c.LocalInfo.UsageStats.TotalQueries
+	}
+	return 0
+}
+
+// storageCost estimates the storage cost of the cache over its whole
+// lifetime, as 'cache list' does
+func (c combinedCacheInfo) storageCost() float64 {
+	model := ""
+	if c.LocalInfo != nil {
+		model = c.LocalInfo.Model
+	} else if c.APIInfo != nil {
+		model = c.APIInfo.Model
+	}
+	return logging.EstimateCacheStorageCost(model, c.tokenCount(), c.expireTime().Sub(c.CreateTime))
+}
+
+// cacheSortField is the column the cache list is ordered by
+type cacheSortField int
+
+const (
+	cacheSortCreated cacheSortField = iota
+	cacheSortCost
+	cacheSortTokens
+	cacheSortUses
+	cacheSortExpiry
+	numCacheSortFields
+)
+
+func (f cacheSortField) String() string {
+	switch f {
+	case cacheSortCost:
+		return "cost"
+	case cacheSortTokens:
+		return "tokens"
+	case cacheSortUses:
+		return "uses"
+	case cacheSortExpiry:
+		return "expiry"
+	default:
+		return "created"
+	}
+}
+
+// sortCaches orders caches by field, largest cost/tokens/uses, soonest expiry
+// and newest creation first. With activeFirst, active caches are grouped ahead
+// of the rest and the field orders within each group.
+func sortCaches(caches []combinedCacheInfo, field cacheSortField, activeFirst bool) {
+	sort.SliceStable(caches, func(i, j int) bool {
+		a, b := caches[i], caches[j]
+		if activeFirst && a.IsActive != b.IsActive {
+			return a.IsActive
+		}
+		switch field {
+		case cacheSortCost:
+			if ca, cb := a.storageCost(), b.storageCost(); ca != cb {
+				return ca > cb
+			}
+		case cacheSortTokens:
+			if ta, tb := a.tokenCount(), b.tokenCount(); ta != tb {
+				return ta > tb
+			}
+		case cacheSortUses:
+			if ua, ub := a.uses(), b.uses(); ua != ub {
+				return ua > ub
+			}
+		case cacheSortExpiry:
+			if ea, eb := a.expireTime(), b.expireTime(); !ea.Equal(eb) {
+				return ea.Before(eb)
+			}
+		}
+		return a.CreateTime.After(b.CreateTime)
+	})
+}
+
 // cacheTUIModel represents the state of the TUI
 type cacheTUIModel struct {
 	client           *gemini.Client
@@ -59,16 +163,38 @@ type cacheTUIModel struct {
 	width, height    int
 	confirmingDelete bool
 	confirmingWipe   bool
+	selected         map[string]bool // Multi-selected caches by cacheSelectionKey
+	sortField        cacheSortField
+	activeFirst      bool // Group active caches ahead of the rest when sorting
+	warning          string
+	status           string // Transient footer message, e.g. after copying a cache name
 	workDir          string
 }
 
 // Messages
 type (
-	cachesLoadedMsg struct{ caches []combinedCacheInfo }
-	cacheDeletedMsg struct{}
-	cacheWipedMsg   struct{}
-	errMsg          struct{ err error }
-	tickMsg         time.Time
+	cachesLoadedMsg struct {
+		caches  []combinedCacheInfo
+		warning string
+	}
+	// cacheDeletedMsg and cacheWipedMsg report a single or bulk operation;
+	// err joins the failures of items that couldn't be processed
+	cacheDeletedMsg struct {
+		count int
+		err   error
+	}
+	cacheWipedMsg struct {
+		count int
+		err   error
+	}
+	cachePinnedMsg struct{}
+	cacheCopiedMsg struct {
+		name string
+		err  error
+	}
+	clearStatusMsg struct{}
+	errMsg         struct{ err error }
+	tickMsg        time.Time
 )
 
 // cacheKeyMap extends keymap.Base with cache-specific bindings
@@ -78,7 +204,13 @@ type cacheKeyMap struct {
 	Analytics key.Binding
 	Delete    key.Binding
 	Wipe      key.Binding
+	Pin       key.Binding
+	Copy      key.Binding
 	Refresh   key.Binding
+	Select    key.Binding
+	SelectAll key.Binding
+	Sort      key.Binding
+	Group     key.Binding
 }
 
 func newCacheKeyMap(cfg *config.Config) cacheKeyMap {
@@ -100,10 +232,34 @@ func newCacheKeyMap(cfg *config.Config) cacheKeyMap {
 			key.WithKeys("w"),
 			key.WithHelp("w", "wipe local"),
 		),
+		Pin: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pin/unpin"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy name"),
+		),
 		Refresh: key.NewBinding(
 			key.WithKeys("ctrl+r"),
 			key.WithHelp("ctrl+r", "refresh"),
 		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select for bulk delete/wipe"),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("ctrl+a"),
+			key.WithHelp("ctrl+a", "select/deselect all shown"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort by created/cost/tokens/uses/expiry"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "toggle active-first grouping"),
+		),
 	}
 
 	// Apply TUI-specific overrides from config
@@ -115,7 +271,13 @@ func newCacheKeyMap(cfg *config.Config) cacheKeyMap {
 func (k cacheKeyMap) Sections() []keymap.Section {
 	return append(k.Base.Sections(),
 		keymap.NewSectionWithIcon("Cache Actions", theme.IconArchive,
-			k.Inspect, k.Analytics, k.Delete, k.Wipe, k.Refresh,
+			k.Inspect, k.Analytics, k.Delete, k.Wipe, k.Pin, k.Copy, k.Refresh,
+		),
+		keymap.NewSectionWithIcon("Selection", theme.IconSelectAll,
+			k.Select, k.SelectAll,
+		),
+		keymap.NewSectionWithIcon("Sorting", theme.IconFilter,
+			k.Sort, k.Group,
 		),
 	)
 }
@@ -172,6 +334,7 @@ func newCacheTUIModel() (*cacheTUIModel, error) {
 		{Title: "REGEN", Width: 6},
 		{Title: "EFF", Width: 5},
 		{Title: "TOKENS", Width: 8},
+		{Title: "COST", Width: 8},
 		{Title: "TTL", Width: 10},
 		{Title: "EXPIRES", Width: 8},
 		{Title: "SAVED", Width: 8},
@@ -217,6 +380,8 @@ func newCacheTUIModel() (*cacheTUIModel, error) {
 		isLoading:       true,
 		workDir:         workDir,
 		currentView:     listView,
+		selected:        make(map[string]bool),
+		activeFirst:     true,
 	}, nil
 }
 
@@ -240,13 +405,17 @@ func fetchCachesCmd(client *gemini.Client, workDir string) tea.Cmd {
 			}
 		}
 
+		var warning string
 		apiCaches, err := client.ListCachesFromAPI(ctx)
 		if err != nil {
-			// Handle permission errors gracefully
-			if gemini.IsPermissionError(err) {
-				// Continue with just local caches, no API data
-				apiCaches = []gemini.CachedContentInfo{}
-			} else {
+			switch {
+			case gemini.IsPermissionError(err):
+				// Handle permission errors gracefully: continue with whatever
+				// API data was read alongside the local caches
+			case len(apiCaches) > 0:
+				// Partial listing: show what we got with a warning
+				warning = fmt.Sprintf("Showing partial API results: %v", err)
+			default:
 				// For other errors, return an error message
 				return errMsg{fmt.Errorf("could not query API: %w", err)}
 			}
@@ -326,74 +495,133 @@ func fetchCachesCmd(client *gemini.Client, workDir string) tea.Cmd {
 			return combined[i].CreateTime.After(combined[j].CreateTime)
 		})
 
-		return cachesLoadedMsg{caches: combined}
+		return cachesLoadedMsg{caches: combined, warning: warning}
 	}
 }
 
-func deleteCacheCmd(client *gemini.Client, cache combinedCacheInfo) tea.Cmd {
+// deleteCachesCmd deletes the caches from the API and marks their local
+// records as cleared. Failures don't stop the rest; they are reported together
+// in a single message so the list is refreshed once.
+func deleteCachesCmd(client *gemini.Client, caches []combinedCacheInfo, workDir string) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
-
-		// Check if cache is already cleared or missing
-		if cache.Status == theme.IconError+" Cleared" {
-			return cacheDeletedMsg{} // Already cleared, nothing to do
+		var msg cacheDeletedMsg
+		var errs []error
+		for _, cache := range caches {
+			if err := deleteCache(ctx, client, cache, workDir); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", cache.Name, err))
+				continue
+			}
+			msg.count++
 		}
+		msg.err = errors.Join(errs...)
+		return msg
+	}
+}
 
-		cacheIDToDelete := ""
-		if cache.APIInfo != nil {
-			cacheIDToDelete = cache.APIInfo.Name
-		} else if cache.LocalInfo != nil {
-			cacheIDToDelete = cache.LocalInfo.CacheID
-		}
+func deleteCache(ctx context.Context, client *gemini.Client, cache combinedCacheInfo, workDir string) error {
+	// Check if cache is already cleared or missing
+	if cache.Status == theme.IconError+" Cleared" {
+		return nil // Already cleared, nothing to do
+	}
 
-		if cacheIDToDelete == "" {
-			return errMsg{fmt.Errorf("cannot delete cache, missing ID")}
-		}
+	cacheIDToDelete := ""
+	if cache.APIInfo != nil {
+		cacheIDToDelete = cache.APIInfo.Name
+	} else if cache.LocalInfo != nil {
+		cacheIDToDelete = cache.LocalInfo.CacheID
+	}
+
+	if cacheIDToDelete == "" {
+		return fmt.Errorf("cannot delete cache, missing ID")
+	}
 
-		// Only try to delete from API if the cache is active or expired (not missing/cleared)
-		if cache.Status == theme.IconSuccess+" Active" || cache.Status == theme.IconWarning+" Expired" {
-			// Delete from API
-			if err := client.DeleteCache(ctx, cacheIDToDelete); err != nil {
-				return errMsg{fmt.Errorf("failed to delete from API: %w", err)}
+	// Only try to delete from API if the cache is active or expired (not missing/cleared)
+	if cache.Status == theme.IconSuccess+" Active" || cache.Status == theme.IconWarning+" Expired" {
+		// Delete from API; a shared cache other projects still use only
+		// loses this project's reference
+		if cache.LocalInfo != nil && cache.LocalInfo.CacheID == cacheIDToDelete {
+			if _, err := gemini.ReleaseCache(ctx, client, gemini.SharedCacheDir(), workDir, cache.LocalInfo); err != nil {
+				return fmt.Errorf("failed to delete from API: %w", err)
 			}
+		} else if err := client.DeleteCache(ctx, cacheIDToDelete); err != nil {
+			return fmt.Errorf("failed to delete from API: %w", err)
 		}
+	}
 
-		// Update local file if it exists
-		if cache.LocalInfo != nil {
-			workDir, _ := os.Getwd()
-			cacheDir := gemini.ResolveGeminiCacheDir(workDir)
-			path := filepath.Join(cacheDir, "hybrid_"+cache.LocalInfo.CacheName+".json")
+	// Update local file if it exists
+	if cache.LocalInfo != nil {
+		cacheDir := gemini.ResolveGeminiCacheDir(workDir)
+		path := filepath.Join(cacheDir, "hybrid_"+cache.LocalInfo.CacheName+".json")
+
+		now := time.Now()
+		cache.LocalInfo.ClearReason = "user-deleted"
+		cache.LocalInfo.ClearedAt = &now
+		if err := gemini.SaveCacheInfo(path, cache.LocalInfo); err != nil {
+			return fmt.Errorf("failed to update local cache file: %w", err)
+		}
+	}
+	return nil
+}
 
-			now := time.Now()
-			cache.LocalInfo.ClearReason = "user-deleted"
-			cache.LocalInfo.ClearedAt = &now
-			if err := gemini.SaveCacheInfo(path, cache.LocalInfo); err != nil {
-				return errMsg{fmt.Errorf("failed to update local cache file: %w", err)}
+// wipeCachesCmd removes the local records of the caches, reporting failures
+// together in a single message like deleteCachesCmd
+func wipeCachesCmd(caches []combinedCacheInfo, workDir string) tea.Cmd {
+	return func() tea.Msg {
+		cacheDir := gemini.ResolveGeminiCacheDir(workDir)
+		var msg cacheWipedMsg
+		var errs []error
+		for _, cache := range caches {
+			if cache.LocalInfo == nil {
+				continue // No local file to wipe
 			}
+			path := filepath.Join(cacheDir, "hybrid_"+cache.LocalInfo.CacheName+".json")
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("%s: failed to wipe local cache file: %w", cache.Name, err))
+				continue
+			}
+			msg.count++
 		}
-		return cacheDeletedMsg{}
+		msg.err = errors.Join(errs...)
+		return msg
 	}
 }
 
-func wipeCacheCmd(cache combinedCacheInfo, workDir string) tea.Cmd {
+func togglePinCmd(cache combinedCacheInfo, workDir string) tea.Cmd {
 	return func() tea.Msg {
 		if cache.LocalInfo == nil {
-			return cacheWipedMsg{} // No local file to wipe
+			return errMsg{fmt.Errorf("cannot pin cache '%s': no local cache file", cache.Name)}
 		}
 
 		cacheDir := gemini.ResolveGeminiCacheDir(workDir)
 		path := filepath.Join(cacheDir, "hybrid_"+cache.LocalInfo.CacheName+".json")
 
-		if err := os.Remove(path); err != nil {
-			if !os.IsNotExist(err) {
-				return errMsg{fmt.Errorf("failed to wipe local cache file: %w", err)}
-			}
+		cache.LocalInfo.Pinned = !cache.LocalInfo.Pinned
+		if err := gemini.SaveCacheInfo(path, cache.LocalInfo); err != nil {
+			return errMsg{fmt.Errorf("failed to update local cache file: %w", err)}
 		}
 
-		return cacheWipedMsg{}
+		return cachePinnedMsg{}
 	}
 }
 
+// copyCacheNameCmd copies a cache name to the system clipboard
+func copyCacheNameCmd(name string) tea.Cmd {
+	return func() tea.Msg {
+		if clipboard.Unsupported {
+			return cacheCopiedMsg{name: name, err: fmt.Errorf("no clipboard available")}
+		}
+		return cacheCopiedMsg{name: name, err: clipboard.WriteAll(name)}
+	}
+}
+
+// clearStatusCmd clears the transient footer message after a short delay
+func clearStatusCmd() tea.Cmd {
+	return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+		return clearStatusMsg{}
+	})
+}
+
 func tickCmd() tea.Cmd {
 	return tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -418,7 +646,7 @@ func (m *cacheTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.table.SetWidth(m.width - 2)
-		m.table.SetHeight(m.height - 8)
+		m.table.SetHeight(m.height - 9)
 		m.inspectViewport.Width = m.width - 4
 		m.inspectViewport.Height = m.height - 8
 		m.filterInput.Width = m.width / 2
@@ -428,19 +656,39 @@ func (m *cacheTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case cachesLoadedMsg:
 		m.isLoading = false
 		m.allCaches = msg.caches
+		m.warning = msg.warning
+		m.pruneSelection()
 		m.updateFilteredCaches()
 		return m, nil
 
 	case cacheDeletedMsg:
 		m.confirmingDelete = false
-		// Refresh the list
-		return m, fetchCachesCmd(m.client, m.workDir)
+		m.finishBulkAction("Deleted", msg.count, msg.err)
+		// Refresh the list once for the whole batch
+		return m, tea.Batch(fetchCachesCmd(m.client, m.workDir), clearStatusCmd())
 
 	case cacheWipedMsg:
 		m.confirmingWipe = false
-		// Refresh the list
+		m.finishBulkAction("Wiped", msg.count, msg.err)
+		// Refresh the list once for the whole batch
+		return m, tea.Batch(fetchCachesCmd(m.client, m.workDir), clearStatusCmd())
+
+	case cachePinnedMsg:
 		return m, fetchCachesCmd(m.client, m.workDir)
 
+	case cacheCopiedMsg:
+		if msg.err != nil {
+			// Headless sessions (e.g. over SSH) have no clipboard, so show the name to copy by hand
+			m.status = fmt.Sprintf("%s Could not copy (%v): %s", theme.IconWarning, msg.err, msg.name)
+		} else {
+			m.status = fmt.Sprintf("%s Copied %s", theme.IconSuccess, msg.name)
+		}
+		return m, clearStatusCmd()
+
+	case clearStatusMsg:
+		m.status = ""
+		return m, nil
+
 	case errMsg:
 		m.err = msg.err
 		m.isLoading = false
@@ -463,9 +711,8 @@ func (m *cacheTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.confirmingDelete {
 			switch {
 			case key.Matches(msg, m.keys.Confirm):
-				if len(m.filteredCaches) > 0 {
-					selectedCache := m.filteredCaches[m.table.Cursor()]
-					return m, deleteCacheCmd(m.client, selectedCache)
+				if targets := m.actionTargets(); len(targets) > 0 {
+					return m, deleteCachesCmd(m.client, targets, m.workDir)
 				}
 				return m, nil
 			case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Back):
@@ -477,9 +724,8 @@ func (m *cacheTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.confirmingWipe {
 			switch {
 			case key.Matches(msg, m.keys.Confirm):
-				if len(m.filteredCaches) > 0 {
-					selectedCache := m.filteredCaches[m.table.Cursor()]
-					return m, wipeCacheCmd(selectedCache, m.workDir)
+				if targets := m.actionTargets(); len(targets) > 0 {
+					return m, wipeCachesCmd(targets, m.workDir)
 				}
 				return m, nil
 			case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Back):
@@ -516,6 +762,39 @@ func (m *cacheTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.confirmingWipe = true
 				}
 				return m, nil
+			case key.Matches(msg, m.keys.Copy):
+				if len(m.filteredCaches) > 0 {
+					return m, copyCacheNameCmd(m.filteredCaches[m.table.Cursor()].Name)
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.Select):
+				if len(m.filteredCaches) > 0 {
+					k := cacheSelectionKey(m.filteredCaches[m.table.Cursor()])
+					if m.selected[k] {
+						delete(m.selected, k)
+					} else {
+						m.selected[k] = true
+					}
+					m.updateTableRows()
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.SelectAll):
+				m.toggleSelectAll()
+				return m, nil
+			case key.Matches(msg, m.keys.Sort):
+				m.sortField = (m.sortField + 1) % numCacheSortFields
+				m.updateFilteredCaches()
+				return m, nil
+			case key.Matches(msg, m.keys.Group):
+				m.activeFirst = !m.activeFirst
+				m.updateFilteredCaches()
+				return m, nil
+			case key.Matches(msg, m.keys.Pin):
+				if len(m.filteredCaches) > 0 {
+					selectedCache := m.filteredCaches[m.table.Cursor()]
+					return m, togglePinCmd(selectedCache, m.workDir)
+				}
+				return m, nil
 			case key.Matches(msg, m.keys.Refresh):
 				m.isLoading = true
 				return m, fetchCachesCmd(m.client, m.workDir)
@@ -530,6 +809,11 @@ func (m *cacheTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
 				m.currentView = listView
 				return m, nil
+			case key.Matches(msg, m.keys.Copy):
+				if len(m.filteredCaches) > 0 {
+					return m, copyCacheNameCmd(m.filteredCaches[m.table.Cursor()].Name)
+				}
+				return m, nil
 			}
 
 		case helpView:
@@ -634,27 +918,154 @@ func (m *cacheTUIModel) renderTableWithArrow() string {
 
 func (m *cacheTUIModel) footerView() string {
 	if m.confirmingDelete {
-		if len(m.filteredCaches) > 0 {
-			selectedCache := m.filteredCaches[m.table.Cursor()]
-			return theme.DefaultTheme.Warning.Render(fmt.Sprintf("Delete cache '%s' from GCP? (y/n)", selectedCache.Name))
+		if targets := m.actionTargets(); len(targets) == 1 && len(m.selected) == 0 {
+			return theme.DefaultTheme.Warning.Render(fmt.Sprintf("Delete cache '%s' from GCP? (y/n)", targets[0].Name))
+		} else if len(targets) > 0 {
+			return theme.DefaultTheme.Warning.Render(fmt.Sprintf("Delete %d caches from GCP? %s (y/n)", len(targets), summarizeCacheStatuses(targets)))
 		}
 	}
 
 	if m.confirmingWipe {
-		if len(m.filteredCaches) > 0 {
-			selectedCache := m.filteredCaches[m.table.Cursor()]
-			return theme.DefaultTheme.Error.Render(fmt.Sprintf("%s  Wipe local file for '%s'? This cannot be undone! (y/n)", theme.IconWarning, selectedCache.Name))
+		if targets := m.actionTargets(); len(targets) == 1 && len(m.selected) == 0 {
+			return theme.DefaultTheme.Error.Render(fmt.Sprintf("%s  Wipe local file for '%s'? This cannot be undone! (y/n)", theme.IconWarning, targets[0].Name))
+		} else if len(targets) > 0 {
+			return theme.DefaultTheme.Error.Render(fmt.Sprintf("%s  Wipe local files for %d caches? %s This cannot be undone! (y/n)", theme.IconWarning, len(targets), summarizeCacheStatuses(targets)))
 		}
 	}
 
+	if m.status != "" {
+		return m.status
+	}
+
+	if m.warning != "" && m.currentView == listView {
+		return theme.DefaultTheme.Warning.Render(fmt.Sprintf("%s %s", theme.IconWarning, m.warning))
+	}
+
 	switch m.currentView {
 	case inspectView:
 		return theme.DefaultTheme.Muted.Render("Press ? for help")
 	default: // listView
-		return theme.DefaultTheme.Muted.Render("Press ? for help")
+		summary := m.costSummary()
+		if len(m.selected) > 0 {
+			return summary + "\n" + theme.DefaultTheme.Muted.Render(fmt.Sprintf("%d selected · d delete · w wipe · space toggle · Press ? for help", len(m.selected)))
+		}
+		return summary + "\n" + theme.DefaultTheme.Muted.Render("Press ? for help")
 	}
 }
 
+// costSummary totals the estimated storage cost of all active caches and
+// notes the current sort order
+func (m *cacheTUIModel) costSummary() string {
+	var total float64
+	active := 0
+	for _, cache := range m.allCaches {
+		if cache.IsActive {
+			total += cache.storageCost()
+			active++
+		}
+	}
+	order := "sort: " + m.sortField.String()
+	if m.activeFirst {
+		order += ", active first"
+	}
+	return fmt.Sprintf("Active caches: %d · Est. storage cost: %s · %s",
+		active, pretty.FormatCost(total), theme.DefaultTheme.Muted.Render(order))
+}
+
+// cacheSelectionKey identifies a cache across refreshes
+func cacheSelectionKey(cache combinedCacheInfo) string {
+	switch {
+	case cache.APIInfo != nil:
+		return cache.APIInfo.Name
+	case cache.LocalInfo != nil && cache.LocalInfo.CacheID != "":
+		return cache.LocalInfo.CacheID
+	default:
+		return cache.Name
+	}
+}
+
+// actionTargets returns the caches a delete or wipe applies to: the selected
+// caches if any are selected, otherwise the one under the cursor
+func (m *cacheTUIModel) actionTargets() []combinedCacheInfo {
+	if len(m.selected) == 0 {
+		if len(m.filteredCaches) == 0 {
+			return nil
+		}
+		return []combinedCacheInfo{m.filteredCaches[m.table.Cursor()]}
+	}
+	var targets []combinedCacheInfo
+	for _, cache := range m.allCaches {
+		if m.selected[cacheSelectionKey(cache)] {
+			targets = append(targets, cache)
+		}
+	}
+	return targets
+}
+
+// toggleSelectAll selects every shown cache, or clears the selection if they all are
+func (m *cacheTUIModel) toggleSelectAll() {
+	allSelected := len(m.filteredCaches) > 0
+	for _, cache := range m.filteredCaches {
+		if !m.selected[cacheSelectionKey(cache)] {
+			allSelected = false
+			break
+		}
+	}
+	for _, cache := range m.filteredCaches {
+		if allSelected {
+			delete(m.selected, cacheSelectionKey(cache))
+		} else {
+			m.selected[cacheSelectionKey(cache)] = true
+		}
+	}
+	m.updateTableRows()
+}
+
+// pruneSelection drops selected caches that are no longer listed
+func (m *cacheTUIModel) pruneSelection() {
+	listed := make(map[string]bool, len(m.allCaches))
+	for _, cache := range m.allCaches {
+		listed[cacheSelectionKey(cache)] = true
+	}
+	for k := range m.selected {
+		if !listed[k] {
+			delete(m.selected, k)
+		}
+	}
+}
+
+// finishBulkAction clears the selection after a delete or wipe and reports the outcome
+func (m *cacheTUIModel) finishBulkAction(verb string, count int, err error) {
+	m.selected = make(map[string]bool)
+	if err != nil {
+		m.status = theme.DefaultTheme.Warning.Render(fmt.Sprintf("%s %s %d cache(s); failed: %v", theme.IconWarning, verb, count, err))
+		return
+	}
+	m.status = fmt.Sprintf("%s %s %d cache(s)", theme.IconSuccess, verb, count)
+}
+
+// summarizeCacheStatuses counts caches by status, e.g. "(2 Active, 9 Expired)"
+func summarizeCacheStatuses(caches []combinedCacheInfo) string {
+	counts := make(map[string]int)
+	for _, cache := range caches {
+		label := cache.Status
+		if _, rest, ok := strings.Cut(label, " "); ok {
+			label = rest
+		}
+		counts[label]++
+	}
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[label], label))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
 func (m *cacheTUIModel) prepareInspectView() {
 	if len(m.filteredCaches) == 0 {
 		m.inspectViewport.SetContent("No cache selected.")
@@ -674,6 +1085,9 @@ func (m *cacheTUIModel) prepareInspectView() {
 		b.WriteString(fmt.Sprintf("\nCreated: %s", cache.LocalInfo.CreatedAt.Local().Format(time.RFC1123)))
 		b.WriteString(fmt.Sprintf("\nExpires: %s", cache.LocalInfo.ExpiresAt.Local().Format(time.RFC1123)))
 		b.WriteString(fmt.Sprintf("\nToken Count: %d", cache.LocalInfo.TokenCount))
+		if cache.LocalInfo.Pinned {
+			b.WriteString(fmt.Sprintf("\nPinned: %s yes", theme.IconFileLock))
+		}
 		if cache.LocalInfo.ClearedAt != nil {
 			b.WriteString(fmt.Sprintf("\nCleared: %s (%s)", cache.LocalInfo.ClearedAt.Local().Format(time.RFC1123), cache.LocalInfo.ClearReason))
 		}
@@ -689,7 +1103,8 @@ func (m *cacheTUIModel) prepareInspectView() {
 
 		if len(cache.LocalInfo.CachedFileHashes) > 0 {
 			b.WriteString("\n\nCached Files:")
-			for file, hash := range cache.LocalInfo.CachedFileHashes {
+			for _, file := range cache.LocalInfo.CachedFiles() {
+				hash := cache.LocalInfo.CachedFileHashes[file]
 				b.WriteString(fmt.Sprintf("\n  %s", file))
 				b.WriteString(fmt.Sprintf("\n    SHA256: %s...", hash[:16]))
 			}
@@ -718,12 +1133,15 @@ func (m *cacheTUIModel) updateFilteredCaches() {
 
 	var filtered []combinedCacheInfo
 	if filter == "" {
-		filtered = m.allCaches
+		filtered = append(filtered, m.allCaches...)
 	} else {
 		for _, cache := range m.allCaches {
-			repo := ""
+			repo, displayName := "", ""
 			if cache.LocalInfo != nil {
 				repo = cache.LocalInfo.RepoName
+				displayName = cache.LocalInfo.DisplayName
+			} else if cache.APIInfo != nil {
+				displayName = gemini.UserCacheDisplayName(cache.APIInfo.DisplayName)
 			}
 			model := ""
 			if cache.LocalInfo != nil {
@@ -733,6 +1151,7 @@ func (m *cacheTUIModel) updateFilteredCaches() {
 			}
 
 			if strings.Contains(strings.ToLower(cache.Name), filter) ||
+				strings.Contains(strings.ToLower(displayName), filter) ||
 				strings.Contains(strings.ToLower(repo), filter) ||
 				strings.Contains(strings.ToLower(model), filter) {
 				filtered = append(filtered, cache)
@@ -740,6 +1159,7 @@ func (m *cacheTUIModel) updateFilteredCaches() {
 		}
 	}
 
+	sortCaches(filtered, m.sortField, m.activeFirst)
 	m.filteredCaches = filtered
 
 	// If the cursor is now out of bounds, reset it.
@@ -762,8 +1182,11 @@ func (m *cacheTUIModel) updateTableRows() {
 		ttl := "-"
 		expires := "-"
 		saved := "-"
+		cost := "-"
+		name := cache.Name
 
 		if cache.LocalInfo != nil {
+			name = pinnedCacheName(cache.LocalInfo)
 			repo = cache.LocalInfo.RepoName
 			if len(repo) > 15 {
 				repo = repo[:12] + "..."
@@ -781,13 +1204,16 @@ func (m *cacheTUIModel) updateTableRows() {
 				analytics := gemini.CalculateCacheAnalytics(cache.LocalInfo)
 				efficiency = fmt.Sprintf("%.0f", analytics.EfficiencyScore)
 				if analytics.TotalSavings > 0.01 {
-					saved = fmt.Sprintf("$%.2f", analytics.TotalSavings)
+					saved = pretty.FormatCost(analytics.TotalSavings)
 				} else {
 					saved = "$0.00"
 				}
 			}
 		} else if cache.APIInfo != nil {
 			model = cache.APIInfo.Model
+			if displayName := gemini.UserCacheDisplayName(cache.APIInfo.DisplayName); displayName != "" {
+				name = displayName
+			}
 		}
 
 		if cache.IsActive {
@@ -803,23 +1229,29 @@ func (m *cacheTUIModel) updateTableRows() {
 			}
 
 			if tokenCount > 0 {
-				tokens = fmt.Sprintf("%dk", tokenCount/1000)
+				tokens = pretty.FormatTokensCompact(tokenCount)
+				cost = calculateCacheCost(tokenCount, expireTime.Sub(cache.CreateTime), model)
 			}
 			ttl = formatDuration(time.Until(expireTime))
 			expires = expireTime.Local().Format("15:04")
 		}
 
+		if m.selected[cacheSelectionKey(cache)] {
+			name = theme.IconSuccess + " " + name
+		}
+
 		statusStyle := getStatusStyle(cache.Status)
 
 		rows[i] = table.Row{
 			statusStyle.Render(cache.Status),
-			cache.Name,
+			name,
 			repo,
 			model,
 			uses,
 			regen,
 			efficiency,
 			tokens,
+			cost,
 			ttl,
 			expires,
 			saved,
@@ -869,9 +1301,9 @@ func (m *cacheTUIModel) prepareAnalyticsView() {
 
 	// Overall Statistics
 	b.WriteString(theme.DefaultTheme.Header.Underline(false).MarginBottom(0).Render(theme.IconChart + " Overall Statistics"))
-	b.WriteString(fmt.Sprintf("\n\nTotal Cost Savings: $%.2f", totalSavings))
+	b.WriteString(fmt.Sprintf("\n\nTotal Cost Savings: %s", pretty.FormatCost(totalSavings)))
 	b.WriteString(fmt.Sprintf("\nTotal Queries: %d", totalQueries))
-	b.WriteString(fmt.Sprintf("\nTotal Cached Tokens: %s", formatTokenCount(totalCachedTokens)))
+	b.WriteString(fmt.Sprintf("\nTotal Cached Tokens: %s", pretty.FormatTokensCompact(totalCachedTokens)))
 	b.WriteString(fmt.Sprintf("\nAverage Efficiency Score: %.1f/100", avgEfficiency))
 	b.WriteString(fmt.Sprintf("\nActive Caches: %d", cacheCount))
 
@@ -907,8 +1339,8 @@ func (m *cacheTUIModel) prepareAnalyticsView() {
 	// Show top 5
 	for i := 0; i < len(scoredCaches) && i < 5; i++ {
 		sc := scoredCaches[i]
-		b.WriteString(fmt.Sprintf("%d. %s (Score: %.1f, Saved: $%.2f)\n",
-			i+1, sc.cache.Name, sc.score, sc.savings))
+		b.WriteString(fmt.Sprintf("%d. %s (Score: %.1f, Saved: %s)\n",
+			i+1, sc.cache.Name, sc.score, pretty.FormatCost(sc.savings)))
 	}
 
 	// Usage Patterns
@@ -1029,16 +1461,6 @@ func generateSparkline(data []float64) string {
 	return result.String()
 }
 
-// formatTokenCount formats large token counts for display
-func formatTokenCount(tokens int64) string {
-	if tokens >= 1_000_000 {
-		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
-	} else if tokens >= 1_000 {
-		return fmt.Sprintf("%.1fK", float64(tokens)/1_000)
-	}
-	return fmt.Sprintf("%d", tokens)
-}
-
 // runCacheTUI runs the interactive TUI for cache management
 func runCacheTUI() error {
 	model, err := newCacheTUIModel()
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+)
+
+func TestCacheTUIBulkTargets(t *testing.T) {
+	active := combinedCacheInfo{Name: "a", Status: theme.IconSuccess + " Active", APIInfo: &gemini.CachedContentInfo{Name: "cachedContents/a"}}
+	expired1 := combinedCacheInfo{Name: "b", Status: theme.IconWarning + " Expired", LocalInfo: &gemini.CacheInfo{CacheID: "cachedContents/b"}}
+	expired2 := combinedCacheInfo{Name: "c", Status: theme.IconWarning + " Expired", LocalInfo: &gemini.CacheInfo{CacheID: "cachedContents/c"}}
+
+	columns := make([]table.Column, 12)
+	for i := range columns {
+		columns[i] = table.Column{Width: 8}
+	}
+	m := &cacheTUIModel{
+		table:          table.New(table.WithColumns(columns)),
+		allCaches:      []combinedCacheInfo{active, expired1, expired2},
+		filteredCaches: []combinedCacheInfo{active, expired1, expired2},
+		selected:       make(map[string]bool),
+	}
+
+	// Without a selection the action applies to the cursor row
+	if targets := m.actionTargets(); len(targets) != 1 || targets[0].Name != "a" {
+		t.Fatalf("expected cursor row as the only target, got %+v", targets)
+	}
+
+	m.toggleSelectAll()
+	if len(m.selected) != 3 {
+		t.Fatalf("expected all 3 caches selected, got %d", len(m.selected))
+	}
+	if got, want := summarizeCacheStatuses(m.actionTargets()), "(1 Active, 2 Expired)"; got != want {
+		t.Errorf("summarizeCacheStatuses = %q, want %q", got, want)
+	}
+
+	// After a bulk delete the reloaded list no longer has the deleted caches
+	m.allCaches = []combinedCacheInfo{expired2}
+	m.pruneSelection()
+	if len(m.selected) != 1 || !m.selected["cachedContents/c"] {
+		t.Errorf("expected only the remaining cache to stay selected, got %v", m.selected)
+	}
+
+	m.filteredCaches = m.allCaches
+	m.toggleSelectAll() // everything shown is selected, so this clears it
+	if len(m.selected) != 0 {
+		t.Errorf("expected selection cleared, got %v", m.selected)
+	}
+}
+
+func TestSortCaches(t *testing.T) {
+	now := time.Now()
+	cache := func(name string, active bool, tokens int32, uses int, lifetime time.Duration) combinedCacheInfo {
+		return combinedCacheInfo{
+			Name:       name,
+			IsActive:   active,
+			CreateTime: now.Add(-time.Hour),
+			APIInfo:    &gemini.CachedContentInfo{TokenCount: tokens, ExpireTime: now.Add(-time.Hour + lifetime)},
+			LocalInfo:  &gemini.CacheInfo{UsageStats: &gemini.CacheUsageStats{TotalQueries: uses}},
+		}
+	}
+	caches := []combinedCacheInfo{
+		cache("small", true, 100_000, 9, 2*time.Hour),
+		cache("expired-big", false, 2_000_000, 1, time.Hour),
+		cache("big", true, 1_000_000, 3, 3*time.Hour),
+	}
+	names := func() []string {
+		out := make([]string, len(caches))
+		for i, c := range caches {
+			out[i] = c.Name
+		}
+		return out
+	}
+
+	tests := []struct {
+		field       cacheSortField
+		activeFirst bool
+		want        []string
+	}{
+		{cacheSortCost, true, []string{"big", "small", "expired-big"}},
+		{cacheSortCost, false, []string{"big", "expired-big", "small"}},
+		{cacheSortTokens, false, []string{"expired-big", "big", "small"}},
+		{cacheSortUses, true, []string{"small", "big", "expired-big"}},
+		{cacheSortExpiry, false, []string{"expired-big", "small", "big"}},
+	}
+	for _, tt := range tests {
+		sortCaches(caches, tt.field, tt.activeFirst)
+		got := names()
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("sort by %s (activeFirst=%v) = %v, want %v", tt.field, tt.activeFirst, got, tt.want)
+				break
+			}
+		}
+	}
+
+	// Only active caches count toward the total: 1M tokens for 3h plus 100k for 2h
+	m := &cacheTUIModel{allCaches: caches, sortField: cacheSortCost}
+	if summary := m.costSummary(); !strings.Contains(summary, "Active caches: 2") || !strings.Contains(summary, "$3.20") {
+		t.Errorf("costSummary = %q, want 2 active caches costing $3.20", summary)
+	}
+}
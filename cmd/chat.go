@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+)
+
+func newChatCmd() *cobra.Command {
+	var (
+		model     string
+		workDir   string
+		cacheTTL  time.Duration
+		noCache   bool
+		useCache  string
+		system    string
+		contextFs []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "chat",
+		Short: "Chat with Gemini in an interactive terminal UI",
+		Long: `Open an interactive chat with Gemini.
+
+Each turn is sent with the repository context set up the same way as
+'request': the cold context is cached (without a confirmation prompt) and
+reused across turns, and the conversation so far is sent with every prompt.
+Responses stream into the scrollback, followed by the turn's token usage.
+
+Commands typed at the prompt:
+  /save [path]  Write the transcript as markdown (defaults to chat-<time>.md)
+  /clear        Start a new conversation
+
+Press ctrl+c to quit.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			model, _ = config.ResolveModel(workDir, model, cmd.Flags().Changed("model"))
+			return runChatTUI(gemini.RequestOptions{
+				Model:             model,
+				WorkDir:           workDir,
+				CacheTTL:          cacheTTL,
+				NoCache:           noCache,
+				UseCache:          useCache,
+				ContextFiles:      contextFs,
+				SystemInstruction: system,
+				SkipConfirmation:  true, // the TUI owns the terminal, so there's no one to answer
+				Caller:            "grove-gemini-chat",
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&model, "model", "m", config.DefaultRequestModel, "Gemini model to use (defaults to gemini.default_model in grove.yml if set)")
+	cmd.Flags().StringVarP(&workDir, "workdir", "w", "", "Working directory (defaults to current)")
+	cmd.Flags().DurationVar(&cacheTTL, "cache-ttl", 5*time.Minute, "Cache TTL (e.g., 1h, 30m, 24h)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Disable context caching")
+	cmd.Flags().StringVar(&useCache, "use-cache", "", "Specify a cache by short hash or the name given with cache create --cache-name for every turn, bypassing automatic selection")
+	cmd.Flags().StringVar(&system, "system", "", "System instruction sent separately from each prompt")
+	cmd.Flags().StringSliceVar(&contextFs, "context", nil, "Additional context files to include")
+
+	return cmd
+}
+
+// chatKeyMap holds the chat bindings. The base keymap isn't used because its
+// single-letter bindings would swallow typed text.
+type chatKeyMap struct {
+	Quit       key.Binding
+	Send       key.Binding
+	ScrollUp   key.Binding
+	ScrollDown key.Binding
+}
+
+func newChatKeyMap() chatKeyMap {
+	return chatKeyMap{
+		Quit: key.NewBinding(
+			key.WithKeys("ctrl+c"),
+			key.WithHelp("ctrl+c", "quit"),
+		),
+		Send: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "send"),
+		),
+		ScrollUp: key.NewBinding(
+			key.WithKeys("pgup", "ctrl+u"),
+			key.WithHelp("pgup", "scroll up"),
+		),
+		ScrollDown: key.NewBinding(
+			key.WithKeys("pgdown", "ctrl+d"),
+			key.WithHelp("pgdown", "scroll down"),
+		),
+	}
+}
+
+// chatTurn is one prompt and its response as shown in the scrollback
+type chatTurn struct {
+	Prompt   string
+	Response string
+	Usage    string // Rendered token usage box, empty until the turn completes
+	Err      error
+}
+
+// chatChunkMsg carries streamed response text
+type chatChunkMsg string
+
+// chatResponseMsg is sent when a turn's request finishes
+type chatResponseMsg struct {
+	result *gemini.GenerateResult
+	err    error
+}
+
+// chatStreamWriter forwards streamed text to the running program
+type chatStreamWriter struct {
+	send func(tea.Msg)
+}
+
+func (w *chatStreamWriter) Write(p []byte) (int, error) {
+	if w.send != nil {
+		w.send(chatChunkMsg(p))
+	}
+	return len(p), nil
+}
+
+type chatModel struct {
+	options  gemini.RequestOptions
+	runner   *gemini.RequestRunner
+	usageBox *pretty.Logger
+
+	// history is the conversation sent with each prompt; only completed turns are added
+	history *gemini.Session
+	turns   []chatTurn
+	waiting bool
+	status  string
+
+	keys     chatKeyMap
+	viewport viewport.Model
+	input    textinput.Model
+	width    int
+	height   int
+}
+
+func newChatModel(options gemini.RequestOptions, stream *chatStreamWriter) chatModel {
+	options.StreamWriter = stream
+
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.Placeholder = "Ask Gemini, or /save, /clear..."
+	ti.Focus()
+
+	return chatModel{
+		options:  options,
+		runner:   gemini.NewRequestRunner(),
+		usageBox: pretty.NewWithWriter(io.Discard),
+		history:  &gemini.Session{},
+		keys:     newChatKeyMap(),
+		viewport: viewport.New(80, 20),
+		input:    ti,
+	}
+}
+
+func (m chatModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m chatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.ScrollUp), key.Matches(msg, m.keys.ScrollDown):
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		case key.Matches(msg, m.keys.Send):
+			return m.submit()
+		}
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.input.Width = m.width - 4
+		m.viewport.Width = m.width
+		m.viewport.Height = m.height - 4 // title, input and status lines
+		m.refresh()
+		return m, nil
+	case chatChunkMsg:
+		if n := len(m.turns); n > 0 && m.waiting {
+			m.turns[n-1].Response += string(msg)
+			m.refresh()
+		}
+		return m, nil
+	case chatResponseMsg:
+		m.waiting = false
+		turn := &m.turns[len(m.turns)-1]
+		if msg.err != nil {
+			turn.Err = msg.err
+			m.status = "Request failed"
+		} else {
+			r := msg.result
+			turn.Response = r.Text
+			turn.Usage = m.usageBox.TokenUsageBox(int(r.CachedTokens), int(r.PromptTokens-r.CachedTokens), int(r.CompletionTokens), int(r.UserPromptTokens), r.UploadDuration, r.Duration, r.CacheCreationCost > 0)
+			m.history.AppendExchange(turn.Prompt, r.Text)
+			m.status = fmt.Sprintf("%s · %s", r.Model, pretty.FormatCost(r.EstimatedCost))
+		}
+		m.refresh()
+		return m, nil
+	}
+
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// submit handles the entered line: a slash command or a prompt to send
+func (m chatModel) submit() (tea.Model, tea.Cmd) {
+	line := strings.TrimSpace(m.input.Value())
+	if line == "" {
+		return m, nil
+	}
+
+	if name, arg, ok := parseChatCommand(line); ok {
+		m.input.SetValue("")
+		switch name {
+		case "save":
+			path := arg
+			if path == "" {
+				path = fmt.Sprintf("chat-%s.md", time.Now().Format("20060102-150405"))
+			}
+			if err := saveChatTranscript(path, m.turns); err != nil {
+				m.status = fmt.Sprintf("%s %v", theme.IconWarning, err)
+			} else {
+				m.status = fmt.Sprintf("%s Saved transcript to %s", theme.IconSuccess, path)
+			}
+		case "clear":
+			if m.waiting {
+				m.status = "Wait for the response before clearing"
+				return m, nil
+			}
+			m.turns = nil
+			m.history = &gemini.Session{}
+			m.status = "Started a new conversation"
+			m.refresh()
+		default:
+			m.status = fmt.Sprintf("Unknown command /%s (try /save or /clear)", name)
+		}
+		return m, nil
+	}
+
+	if m.waiting {
+		m.status = "Still waiting for the last response"
+		return m, nil
+	}
+
+	m.input.SetValue("")
+	m.turns = append(m.turns, chatTurn{Prompt: line})
+	m.waiting = true
+	m.status = "Waiting for Gemini..."
+	m.refresh()
+
+	options := m.options
+	options.Prompt = line
+	options.ConversationHistory = slices.Clone(m.history.Turns)
+	runner := m.runner
+	return m, func() tea.Msg {
+		result, err := runner.RunWithResult(context.Background(), options)
+		return chatResponseMsg{result: result, err: err}
+	}
+}
+
+// refresh re-renders the scrollback and keeps it pinned to the latest output
+func (m *chatModel) refresh() {
+	m.viewport.SetContent(renderChatTurns(m.turns, m.width))
+	m.viewport.GotoBottom()
+}
+
+func (m chatModel) View() string {
+	title := theme.DefaultTheme.Title.Render(fmt.Sprintf("Gemini Chat · %s", m.options.Model))
+	status := m.status
+	if status == "" {
+		status = "enter send · pgup/pgdown scroll · ctrl+c quit"
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		m.viewport.View(),
+		m.input.View(),
+		theme.DefaultTheme.Muted.Render(status),
+	)
+}
+
+// parseChatCommand splits a "/name arg" line. ok is false for ordinary prompts.
+func parseChatCommand(line string) (name, arg string, ok bool) {
+	if !strings.HasPrefix(line, "/") {
+		return "", "", false
+	}
+	name, arg, _ = strings.Cut(strings.TrimPrefix(line, "/"), " ")
+	return strings.ToLower(name), strings.TrimSpace(arg), true
+}
+
+// renderChatTurns renders the scrollback, wrapping text to width when it is known
+func renderChatTurns(turns []chatTurn, width int) string {
+	wrap := lipgloss.NewStyle()
+	if width > 0 {
+		wrap = wrap.Width(width)
+	}
+	var b strings.Builder
+	for i, turn := range turns {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(theme.DefaultTheme.Bold.Render("You"))
+		b.WriteString("\n")
+		b.WriteString(wrap.Render(turn.Prompt))
+		b.WriteString("\n\n")
+		b.WriteString(theme.DefaultTheme.Success.Render("Gemini"))
+		b.WriteString("\n")
+		if turn.Response != "" {
+			b.WriteString(wrap.Render(turn.Response))
+			b.WriteString("\n")
+		}
+		if turn.Err != nil {
+			b.WriteString(theme.DefaultTheme.Error.Render(fmt.Sprintf("%s %v", theme.IconWarning, turn.Err)))
+			b.WriteString("\n")
+		}
+		if turn.Usage != "" {
+			b.WriteString(turn.Usage)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// formatChatTranscript renders the completed turns as markdown
+func formatChatTranscript(turns []chatTurn) string {
+	var b strings.Builder
+	for _, turn := range turns {
+		fmt.Fprintf(&b, "## You\n\n%s\n\n## Gemini\n\n", strings.TrimSpace(turn.Prompt))
+		if turn.Err != nil {
+			fmt.Fprintf(&b, "_Error: %v_\n\n", turn.Err)
+		} else {
+			fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(turn.Response))
+		}
+	}
+	return b.String()
+}
+
+// saveChatTranscript writes the transcript to path as markdown
+func saveChatTranscript(path string, turns []chatTurn) error {
+	if len(turns) == 0 {
+		return fmt.Errorf("nothing to save yet")
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // transcript dir is user-chosen
+			return fmt.Errorf("creating transcript directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(formatChatTranscript(turns)), 0o644); err != nil { //nolint:gosec // transcripts are meant to be shared
+		return fmt.Errorf("writing transcript: %w", err)
+	}
+	return nil
+}
+
+func runChatTUI(options gemini.RequestOptions) error {
+	// Progress output from the runner would draw over the TUI
+	previous := grovelogging.GetGlobalOutput()
+	grovelogging.SetGlobalOutput(io.Discard)
+	defer grovelogging.SetGlobalOutput(previous)
+
+	stream := &chatStreamWriter{}
+	p := tea.NewProgram(newChatModel(options, stream), tea.WithAltScreen())
+	stream.send = p.Send
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+	return nil
+}
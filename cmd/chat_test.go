@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+)
+
+func TestParseChatCommand(t *testing.T) {
+	tests := []struct {
+		line      string
+		name, arg string
+		ok        bool
+	}{
+		{"/save", "save", "", true},
+		{"/save  notes/chat.md ", "save", "notes/chat.md", true},
+		{"/CLEAR", "clear", "", true},
+		{"what does /save do?", "", "", false},
+	}
+	for _, tt := range tests {
+		name, arg, ok := parseChatCommand(strings.TrimSpace(tt.line))
+		if name != tt.name || arg != tt.arg || ok != tt.ok {
+			t.Errorf("parseChatCommand(%q) = %q, %q, %v; want %q, %q, %v", tt.line, name, arg, ok, tt.name, tt.arg, tt.ok)
+		}
+	}
+}
+
+func TestChatModelStreamsAndKeepsHistory(t *testing.T) {
+	m := newChatModel(gemini.RequestOptions{Model: "gemini-2.0-flash"}, &chatStreamWriter{})
+	m.turns = []chatTurn{{Prompt: "hi"}}
+	m.waiting = true
+
+	model, _ := m.Update(chatChunkMsg("Hel"))
+	model, _ = model.Update(chatChunkMsg("lo"))
+	m = model.(chatModel)
+	if got := m.turns[0].Response; got != "Hello" {
+		t.Fatalf("streamed response = %q, want %q", got, "Hello")
+	}
+
+	model, _ = m.Update(chatResponseMsg{result: &gemini.GenerateResult{
+		Text:             "Hello!",
+		Model:            "gemini-2.0-flash",
+		PromptTokens:     120,
+		CachedTokens:     100,
+		CompletionTokens: 5,
+		Duration:         time.Second,
+	}})
+	m = model.(chatModel)
+	if m.waiting || m.turns[0].Response != "Hello!" || !strings.Contains(m.turns[0].Usage, "Cache Hit Rate") {
+		t.Fatalf("unexpected turn after response: %+v", m.turns[0])
+	}
+	if len(m.history.Turns) != 2 {
+		t.Errorf("history has %d turns, want the prompt and reply", len(m.history.Turns))
+	}
+
+	// A failed turn is shown but not sent as history
+	m.turns = append(m.turns, chatTurn{Prompt: "again"})
+	m.waiting = true
+	model, _ = m.Update(chatResponseMsg{err: errors.New("quota exceeded")})
+	m = model.(chatModel)
+	if m.turns[1].Err == nil || len(m.history.Turns) != 2 {
+		t.Errorf("failed turn should keep its error and stay out of the history: %+v, %d turns", m.turns[1], len(m.history.Turns))
+	}
+}
+
+func TestSaveChatTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "chat.md")
+	if err := saveChatTranscript(path, nil); err == nil {
+		t.Fatal("expected an error for an empty transcript")
+	}
+
+	turns := []chatTurn{
+		{Prompt: "What is a cache?", Response: "A place to keep things.\n"},
+		{Prompt: "And now?", Err: errors.New("quota exceeded")},
+	}
+	if err := saveChatTranscript(path, turns); err != nil {
+		t.Fatalf("saveChatTranscript: %v", err)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // test path
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## You\n\nWhat is a cache?\n\n## Gemini\n\nA place to keep things.\n\n## You\n\nAnd now?\n\n## Gemini\n\n_Error: quota exceeded_\n\n"
+	if string(data) != want {
+		t.Errorf("transcript =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	tablecomponent "github.com/grovetools/core/tui/components/table"
 	"github.com/grovetools/grove-gemini/pkg/config"
 	"github.com/spf13/cobra"
 )
@@ -13,21 +14,136 @@ func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage grove-gemini configuration",
-		Long:  `Configure default settings for grove-gemini, such as the default GCP project.`,
+		Long: `Configure default settings for grove-gemini, such as the default GCP project.
+
+Settings in the gemini section of grove.yml can be read and written by key:
+  grove-gemini config list
+  grove-gemini config get default_model
+  grove-gemini config set default_model gemini-2.5-pro
+  grove-gemini config set pricing_overrides.gemini-2.5-pro.input 1.25 --global`,
 	}
 
 	cmd.AddCommand(newConfigSetCmd())
 	cmd.AddCommand(newConfigGetCmd())
+	cmd.AddCommand(newConfigListCmd())
+	cmd.AddCommand(newConfigEffectiveCmd())
 
 	return cmd
 }
 
+func newConfigEffectiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "effective",
+		Short: "Show the resolved gemini configuration and where each value comes from",
+		Long: `Show every gemini setting as it resolves for the current directory, along with
+its source: an environment variable, a grove.yml layer (global, ecosystem,
+project or override, with the file path) or the built-in default.
+Secret values such as the API key are masked.
+
+Command-line flags such as --model still take precedence over these values
+for the command they are passed to.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := config.ResolveEffectiveConfig("")
+			if err != nil {
+				return err
+			}
+
+			rows := make([][]string, 0, len(settings))
+			for _, s := range settings {
+				value := s.Value
+				if value == "" {
+					value = "(not set)"
+				}
+				rows = append(rows, []string{s.Key, value, s.Source})
+			}
+
+			t := tablecomponent.NewStyledTable().
+				Headers("KEY", "VALUE", "SOURCE").
+				Rows(rows...)
+			fmt.Println(t)
+
+			return nil
+		},
+	}
+}
+
+func newConfigListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the gemini settings that can be read and set by key",
+		Long: `List every gemini setting that 'config get' and 'config set' accept, with its type
+and description. <model> stands for a model name or pattern you choose.
+
+Use 'config effective' to see the values that currently apply.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keys := config.ConfigKeys()
+			rows := make([][]string, 0, len(keys))
+			for _, k := range keys {
+				rows = append(rows, []string{k.Key, k.Type, k.Description})
+			}
+
+			t := tablecomponent.NewStyledTable().
+				Headers("KEY", "TYPE", "DESCRIPTION").
+				Rows(rows...)
+			fmt.Println(t)
+
+			return nil
+		},
+	}
+}
+
 func newConfigSetCmd() *cobra.Command {
+	var global, project bool
+
 	cmd := &cobra.Command{
-		Use:   "set",
+		Use:   "set <key> <value>",
 		Short: "Set configuration values",
+		Long: `Set a gemini setting in grove.yml. The key is checked against the config schema
+(see 'config list') and the value is parsed as the type the key expects. The
+file is rewritten atomically, keeping its other settings and comments.
+
+Sensitive settings such as api_key, and settings that belong in the global
+layer such as backend, cache_advice and pricing_overrides, are written to the
+global grove.yml so they don't end up in a committed project file. Everything
+else goes to the project grove.yml that applies to the current directory. Pass
+--global or --project to choose the file explicitly.
+
+The project and billing subcommands set the saved GCP defaults instead.`,
+		Example: `  grove-gemini config set default_model gemini-2.5-pro
+  grove-gemini config set cache_advice.min_hit_rate 0.4
+  grove-gemini config set timeout 5m
+  grove-gemini config set project my-gcp-project`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			key, value := args[0], args[1]
+
+			if !global && !project {
+				global = config.DefaultsToGlobal(key)
+			}
+			path, err := config.ConfigFileForScope("", global)
+			if err != nil {
+				return err
+			}
+			if err := config.SetGeminiValue(path, key, value); err != nil {
+				return err
+			}
+
+			ulog.Success("Configuration updated").
+				Field("key", key).
+				Field("config_path", path).
+				Pretty(fmt.Sprintf("Set gemini.%s in %s", key, path)).
+				PrettyOnly().
+				Log(ctx)
+			return nil
+		},
 	}
 
+	cmd.Flags().BoolVar(&global, "global", false, "Write to the global grove.yml")
+	cmd.Flags().BoolVar(&project, "project", false, "Write to the project grove.yml, even for sensitive or global settings")
+	cmd.MarkFlagsMutuallyExclusive("global", "project")
+
 	cmd.AddCommand(newConfigSetProjectCmd())
 	cmd.AddCommand(newConfigSetBillingCmd())
 
@@ -36,8 +152,25 @@ func newConfigSetCmd() *cobra.Command {
 
 func newConfigGetCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "get",
+		Use:   "get <key>",
 		Short: "Get configuration values",
+		Long: `Print the value of a gemini setting as it resolves for the current directory,
+along with where it comes from. See 'config list' for the valid keys.
+
+The project and billing subcommands show the saved GCP defaults instead.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			setting, err := config.GetGeminiValue("", args[0])
+			if err != nil {
+				return err
+			}
+			value := setting.Value
+			if value == "" {
+				value = "(not set)"
+			}
+			fmt.Printf("%s\t(%s)\n", value, setting.Source)
+			return nil
+		},
 	}
 
 	cmd.AddCommand(newConfigGetProjectCmd())
@@ -8,32 +8,47 @@ import (
 	"os"
 	"strings"
 
+	"github.com/grovetools/grove-gemini/pkg/config"
 	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 	"github.com/spf13/cobra"
 	"google.golang.org/genai"
 )
 
-var countTokensModel string
+var (
+	countTokensModel        string
+	countTokensAllowUnknown bool
+	countTokensFiles        []string
+)
 
 func newCountTokensCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "count-tokens [text...]",
-		Short: "Count tokens for a given text using Gemini API",
-		Long: `Count the number of tokens in a piece of text using the Gemini API.
+		Short: "Count tokens for text or files using Gemini API",
+		Long: `Count the number of tokens in a piece of text or a set of files using the Gemini API.
 
-You can provide text in three ways:
+You can provide input in these ways:
 1. As command line arguments: grove-gemini count-tokens "Your text here"
 2. Via standard input: echo "Your text" | grove-gemini count-tokens
 3. From a file: cat file.txt | grove-gemini count-tokens
+4. As files, including images and PDFs: grove-gemini count-tokens -f context.md -f diagram.png
+
+Files are uploaded and counted individually, then totalled. No cache or
+.grove/rules setup is needed, so this is a quick way to check whether a set of
+cold-context files fits a model before creating a cache.
 
 This is useful for:
 - Checking if your prompt fits within model limits
 - Estimating costs before making API calls
-- Understanding token usage for different types of content`,
+- Understanding token usage for different types of content
+- Sizing a cold-context set before caching it`,
 		RunE: runCountTokens,
 	}
 
-	cmd.Flags().StringVarP(&countTokensModel, "model", "m", "gemini-1.5-flash-latest", "Model to use for token counting")
+	cmd.Flags().StringVarP(&countTokensModel, "model", "m", config.DefaultRequestModel, "Model to use for token counting (defaults to gemini.default_model in grove.yml if set)")
+	cmd.Flags().BoolVar(&countTokensAllowUnknown, "allow-unknown-model", false, "Count with --model even if it isn't a known model (for newly released models)")
+	cmd.Flags().StringArrayVarP(&countTokensFiles, "file", "f", nil, "Count the tokens in this file; text, images and PDFs are supported (repeatable)")
 
 	return cmd
 }
@@ -41,12 +56,22 @@ This is useful for:
 func runCountTokens(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	if cmd.Flags().Changed("model") {
+		if err := validateModelFlag(countTokensModel, countTokensAllowUnknown); err != nil {
+			return err
+		}
+	}
+
+	// Fall back to the repo's configured default model when --model isn't passed
+	var modelSource string
+	countTokensModel, modelSource = config.ResolveModel("", countTokensModel, cmd.Flags().Changed("model"))
+
 	// Get text to count
 	var text string
 	if len(args) > 0 {
 		// Text provided as command line arguments
 		text = strings.Join(args, " ")
-	} else {
+	} else if len(countTokensFiles) == 0 {
 		// Read from stdin
 		reader := bufio.NewReader(os.Stdin)
 		var builder strings.Builder
@@ -78,9 +103,14 @@ func runCountTokens(cmd *cobra.Command, args []string) error {
 		text = builder.String()
 	}
 
-	if strings.TrimSpace(text) == "" {
+	if strings.TrimSpace(text) == "" && len(countTokensFiles) == 0 {
 		return fmt.Errorf("no text provided to count")
 	}
+	for _, path := range countTokensFiles {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("file not found: %s", path)
+		}
+	}
 
 	// Create client
 	client, err := gemini.NewClient(ctx, "")
@@ -94,30 +124,55 @@ func runCountTokens(cmd *cobra.Command, args []string) error {
 	// Count tokens
 	ulog.Info("Counting tokens").
 		Field("model", countTokensModel).
-		Pretty(fmt.Sprintf("Counting tokens using model: %s", countTokensModel)).
+		Field("model_source", modelSource).
+		Pretty(fmt.Sprintf("Counting tokens using model: %s (%s)", countTokensModel, modelSource)).
 		PrettyOnly().
 		Log(ctx)
 
-	tokenResp, err := genaiClient.Models.CountTokens(ctx,
-		countTokensModel,
-		[]*genai.Content{{Parts: []*genai.Part{{Text: text}}}},
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to count tokens: %w", err)
+	var totalTokens int32
+	if text != "" {
+		tokenResp, err := genaiClient.Models.CountTokens(ctx,
+			countTokensModel,
+			[]*genai.Content{{Parts: []*genai.Part{{Text: text}}}},
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to count tokens: %w", err)
+		}
+		totalTokens = tokenResp.TotalTokens
+	}
+
+	var fileCounts []gemini.FileTokenCount
+	if len(countTokensFiles) > 0 {
+		fileCounts, err = client.CountFileTokens(ctx, countTokensModel, countTokensFiles)
+		if err != nil {
+			return fmt.Errorf("failed to count file tokens: %w", err)
+		}
+		for _, fc := range fileCounts {
+			totalTokens += int32(fc.Tokens) //nolint:gosec // token counts are bounded by API limits
+		}
 	}
 
 	// Display results
 	var output strings.Builder
 	output.WriteString("=== Token Count ===\n")
 	output.WriteString(fmt.Sprintf("Model: %s\n", countTokensModel))
-	output.WriteString(fmt.Sprintf("Total Tokens: %d\n", tokenResp.TotalTokens))
+	if len(fileCounts) > 0 {
+		output.WriteString(formatFileTokenCounts(fileCounts))
+	}
+	output.WriteString(fmt.Sprintf("Total Tokens: %d\n", totalTokens))
 
 	// Calculate estimated costs based on current Gemini pricing
 	// These are prompt token prices
 	var pricePerMillion float64
 	modelLower := strings.ToLower(countTokensModel)
+	pricing, hasOverride := logging.PricingOverrideFor(countTokensModel)
 	switch {
+	case hasOverride:
+		pricePerMillion = pricing.InputPrice
+		if pricing.LongContextThreshold > 0 && totalTokens > pricing.LongContextThreshold && pricing.LongInputPrice > 0 {
+			pricePerMillion = pricing.LongInputPrice
+		}
 	case strings.Contains(modelLower, "gemini-2.5-pro"):
 		pricePerMillion = 1.25 // $1.25 per million input tokens (<=200k)
 	case strings.Contains(modelLower, "gemini-2.5-flash") && strings.Contains(modelLower, "lite"):
@@ -132,15 +187,17 @@ func runCountTokens(cmd *cobra.Command, args []string) error {
 		pricePerMillion = 0.10 // Default to 2.0 flash pricing
 	}
 
-	estimatedCost := float64(tokenResp.TotalTokens) / 1_000_000 * pricePerMillion
+	estimatedCost := float64(totalTokens) / 1_000_000 * pricePerMillion
 	output.WriteString(fmt.Sprintf("\nEstimated Input Cost: $%.6f\n", estimatedCost))
 
 	// Show text preview if not too long
-	if len(text) <= 200 {
-		output.WriteString(fmt.Sprintf("\nText: %q\n", text))
-	} else {
-		output.WriteString(fmt.Sprintf("\nText Preview: %q...\n", text[:200]))
-		output.WriteString(fmt.Sprintf("(Total length: %d characters)\n", len(text)))
+	if text != "" {
+		if len(text) <= 200 {
+			output.WriteString(fmt.Sprintf("\nText: %q\n", text))
+		} else {
+			output.WriteString(fmt.Sprintf("\nText Preview: %q...\n", text[:200]))
+			output.WriteString(fmt.Sprintf("(Total length: %d characters)\n", len(text)))
+		}
 	}
 
 	// Model limits information
@@ -148,22 +205,41 @@ func runCountTokens(cmd *cobra.Command, args []string) error {
 	switch {
 	case strings.Contains(countTokensModel, "flash"):
 		output.WriteString("Context Window: 1,048,576 tokens\n")
-		output.WriteString(fmt.Sprintf("Usage: %.2f%% of context window\n", float64(tokenResp.TotalTokens)/1_048_576*100))
+		output.WriteString(fmt.Sprintf("Usage: %.2f%% of context window\n", float64(totalTokens)/1_048_576*100))
 	case strings.Contains(countTokensModel, "pro"):
 		output.WriteString("Context Window: 2,097,152 tokens\n")
-		output.WriteString(fmt.Sprintf("Usage: %.2f%% of context window\n", float64(tokenResp.TotalTokens)/2_097_152*100))
+		output.WriteString(fmt.Sprintf("Usage: %.2f%% of context window\n", float64(totalTokens)/2_097_152*100))
 	default:
 		output.WriteString("Context Window: Model-specific (check documentation)\n")
 	}
 
 	ulog.Info("Token count results").
 		Field("model", countTokensModel).
-		Field("total_tokens", tokenResp.TotalTokens).
+		Field("total_tokens", totalTokens).
 		Field("estimated_cost", estimatedCost).
 		Field("text_length", len(text)).
+		Field("file_count", len(fileCounts)).
 		Pretty(output.String()).
 		PrettyOnly().
 		Log(ctx)
 
 	return nil
 }
+
+// formatFileTokenCounts lists the token count of each file, noting non-text files' types
+func formatFileTokenCounts(counts []gemini.FileTokenCount) string {
+	width := 0
+	for _, fc := range counts {
+		width = max(width, len(fc.Path))
+	}
+	var b strings.Builder
+	b.WriteString("Files:\n")
+	for _, fc := range counts {
+		line := fmt.Sprintf("  %-*s  %s tokens", width, fc.Path, pretty.FormatTokens(fc.Tokens))
+		if fc.MIMEType != "" && !strings.HasPrefix(fc.MIMEType, "text/") {
+			line += fmt.Sprintf(" (%s)", fc.MIMEType)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/gcp"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/genai"
+)
+
+// doctorRemoteTimeout bounds each check that calls a Google API
+const doctorRemoteTimeout = 15 * time.Second
+
+var doctorOffline bool
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the API key, GCP project, billing export and query logs are set up",
+		Long: `Run a checklist of the setup grove-gemini depends on and report each item as
+pass, warn or fail, with a hint for anything that needs fixing:
+
+  - which backend requests go to (Gemini Developer API or Vertex AI)
+  - API key resolution (GEMINI_API_KEY, then gemini.api_key_command, then gemini.api_key)
+  - a trivial authenticated API call
+  - a default GCP project for the billing and metrics commands
+  - the BigQuery billing export dataset and table, and that a query against the
+    table can run (a dry run, so nothing is billed)
+  - whether the local query log directory is writable
+
+Warnings are optional features that aren't configured; the command fails only
+if a check fails. Use --offline to skip the checks that call Google APIs.`,
+		Args: cobra.NoArgs,
+		RunE: runDoctor,
+	}
+
+	cmd.Flags().BoolVar(&doctorOffline, "offline", false, "Skip the checks that call the Gemini API and BigQuery")
+
+	return cmd
+}
+
+// doctorCheck is the outcome of one doctor check
+type doctorCheck struct {
+	name   string
+	status pretty.CheckStatus
+	detail string
+	hint   string
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	// A failed check isn't a usage mistake, so don't follow it with the usage text
+	cmd.SilenceUsage = true
+
+	checks := []doctorCheck{checkBackend()}
+	keyCheck := checkAPIKey()
+	checks = append(checks, keyCheck, checkAPIAccess(ctx, keyCheck.status == pretty.CheckFail, doctorOffline))
+	project := config.GetDefaultProject("")
+	checks = append(checks,
+		checkDefaultProject(project),
+		checkBillingExport(ctx, project, config.GetBillingDatasetID(""), config.GetBillingTableID(""), doctorOffline),
+		checkLogDir(logging.GetLogger().Dir()),
+	)
+
+	logger := pretty.New()
+	failed := 0
+	for _, c := range checks {
+		logger.CheckCtx(ctx, c.status, c.name, c.detail, c.hint)
+		if c.status == pretty.CheckFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// checkBackend reports which API backend requests go to
+func checkBackend() doctorCheck {
+	c := doctorCheck{name: "Backend"}
+	backend, err := config.ResolveBackend("")
+	switch {
+	case err != nil:
+		c.status, c.detail = pretty.CheckFail, err.Error()
+		c.hint = fmt.Sprintf("Set %s or gemini.backend to %q or %q", config.BackendEnvVar, config.BackendGeminiAPI, config.BackendVertexAI)
+	case backend.IsVertex():
+		c.status = pretty.CheckPass
+		c.detail = fmt.Sprintf("Vertex AI (project %s, location %s)", backend.Project, backend.Location)
+	default:
+		c.status, c.detail = pretty.CheckPass, "Gemini Developer API"
+	}
+	return c
+}
+
+// checkAPIKey resolves the API key the way requests do and reports where it came from
+func checkAPIKey() doctorCheck {
+	c := doctorCheck{name: "API key"}
+	if backend, err := config.ResolveBackend(""); err == nil && backend.IsVertex() {
+		c.status, c.detail = pretty.CheckPass, "not needed: Vertex AI uses Application Default Credentials"
+		return c
+	}
+
+	key, err := config.ResolveAPIKey()
+	if err != nil {
+		c.status, c.detail = pretty.CheckFail, "no API key found"
+		c.hint = "Set GEMINI_API_KEY, or add gemini.api_key_command or gemini.api_key to grove.yml (https://aistudio.google.com/apikey)"
+		if geminiCfg, cfgErr := config.LoadGeminiConfig(""); cfgErr == nil && geminiCfg.APIKeyCommand != "" {
+			c.detail = err.Error()
+			c.hint = "Fix gemini.api_key_command so it prints the key"
+		}
+		return c
+	}
+
+	source := "gemini.api_key"
+	if os.Getenv("GEMINI_API_KEY") != "" {
+		source = "GEMINI_API_KEY"
+	} else if geminiCfg, err := config.LoadGeminiConfig(""); err == nil && geminiCfg.APIKeyCommand != "" {
+		source = "gemini.api_key_command"
+	}
+	c.status = pretty.CheckPass
+	c.detail = fmt.Sprintf("%s from %s", config.MaskSecret(key), source)
+	return c
+}
+
+// checkAPIAccess makes a trivial authenticated call (listing one model)
+func checkAPIAccess(ctx context.Context, noKey, offline bool) doctorCheck {
+	c := doctorCheck{name: "API access"}
+	switch {
+	case offline:
+		c.status, c.detail = pretty.CheckWarn, "skipped (--offline)"
+		return c
+	case noKey:
+		c.status, c.detail = pretty.CheckWarn, "skipped: no API key"
+		return c
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorRemoteTimeout)
+	defer cancel()
+	client, err := gemini.NewClient(ctx, "")
+	if err == nil {
+		_, err = client.GetClient().Models.List(ctx, &genai.ListModelsConfig{PageSize: 1})
+	}
+	if err != nil {
+		c.status, c.detail = pretty.CheckFail, err.Error()
+		switch {
+		case gemini.IsAPIKeyError(err):
+			c.hint = "The API rejected the key; create a new one at https://aistudio.google.com/apikey"
+		case gemini.IsPermissionError(err):
+			c.hint = "The credentials lack access to the Generative Language or Vertex AI API"
+		default:
+			c.hint = "Check network access to the Gemini API"
+		}
+		return c
+	}
+	c.status, c.detail = pretty.CheckPass, "authenticated request succeeded"
+	return c
+}
+
+// checkDefaultProject reports the GCP project used by the billing and metrics commands
+func checkDefaultProject(project string) doctorCheck {
+	c := doctorCheck{name: "Default project"}
+	if project == "" {
+		c.status, c.detail = pretty.CheckWarn, "not set (needed for query billing, dashboard and metrics)"
+		c.hint = "Run 'grove-gemini config set project PROJECT_ID' or set GCP_PROJECT_ID"
+		return c
+	}
+	c.status, c.detail = pretty.CheckPass, project
+	return c
+}
+
+// checkBillingExport reports whether the BigQuery billing export is configured
+// and, unless offline, whether a query against its table can run. A dry-run
+// query is used rather than reading table metadata because billing queries also
+// need permission to create jobs (bigquery.jobs.create) in the project.
+func checkBillingExport(ctx context.Context, project, dataset, table string, offline bool) doctorCheck {
+	c := doctorCheck{name: "Billing export"}
+	if dataset == "" || table == "" {
+		c.status, c.detail = pretty.CheckWarn, "dataset and table not configured (needed for query billing and dashboard)"
+		c.hint = "Enable billing export to BigQuery, then run 'grove-gemini config set billing DATASET_ID TABLE_ID'"
+		return c
+	}
+	ref := fmt.Sprintf("%s.%s", dataset, table)
+	switch {
+	case offline:
+		c.status, c.detail = pretty.CheckWarn, ref+" configured; query skipped (--offline)"
+		return c
+	case project == "":
+		c.status, c.detail = pretty.CheckWarn, ref+" configured but there is no default project to query it in"
+		c.hint = "Run 'grove-gemini config set project PROJECT_ID'"
+		return c
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorRemoteTimeout)
+	defer cancel()
+	client, err := gcp.NewBigQueryClient(ctx, project)
+	if err == nil {
+		defer func() { _ = client.Close() }()
+		q := client.Query(fmt.Sprintf("SELECT 1 FROM `%s.%s` LIMIT 0", project, ref))
+		q.DryRun = true
+		_, err = q.Run(ctx)
+	}
+	if err != nil {
+		c.status, c.detail = pretty.CheckFail, fmt.Sprintf("%s.%s: %v", project, ref, err)
+		c.hint = billingExportHint(err, project)
+		return c
+	}
+	c.status, c.detail = pretty.CheckPass, fmt.Sprintf("%s.%s can be queried", project, ref)
+	return c
+}
+
+// billingExportHint suggests a fix for a failed billing export dry run
+func billingExportHint(err error, project string) string {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case strings.Contains(apiErr.Message, "bigquery.jobs.create"):
+			return fmt.Sprintf("The credentials can't run queries in %s; grant roles/bigquery.jobUser (bigquery.jobs.create) on the project", project)
+		case apiErr.Code == http.StatusForbidden:
+			return "The credentials can't read the table; grant roles/bigquery.dataViewer on the dataset and roles/bigquery.jobUser on the project"
+		case apiErr.Code == http.StatusNotFound:
+			return "Check the dataset and table IDs with 'grove-gemini config set billing DATASET_ID TABLE_ID'"
+		}
+	}
+	return "Run 'gcloud auth application-default login' and check the dataset and table IDs"
+}
+
+// checkLogDir reports whether query logs can be written to dir
+func checkLogDir(dir string) doctorCheck {
+	c := doctorCheck{name: "Query log dir"}
+	hint := fmt.Sprintf("Set %s or gemini.log_dir to a writable directory", config.LogDirEnvVar)
+	if dir == "" {
+		c.status, c.detail, c.hint = pretty.CheckFail, "could not determine or create the query log directory", hint
+		return c
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		c.status, c.detail, c.hint = pretty.CheckFail, fmt.Sprintf("%s is not writable: %v", dir, err), hint
+		return c
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	c.status, c.detail = pretty.CheckPass, dir
+	return c
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"google.golang.org/api/googleapi"
+)
+
+func TestCheckLogDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "not-a-dir")
+	if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want pretty.CheckStatus
+	}{
+		{"writable directory", dir, pretty.CheckPass},
+		{"unknown directory", "", pretty.CheckFail},
+		{"path is a file", file, pretty.CheckFail},
+		{"missing directory", filepath.Join(dir, "missing"), pretty.CheckFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkLogDir(tt.dir)
+			if got.status != tt.want {
+				t.Errorf("checkLogDir(%q) status = %s, want %s (%s)", tt.dir, got.status, tt.want, got.detail)
+			}
+			if got.status == pretty.CheckFail && got.hint == "" {
+				t.Errorf("checkLogDir(%q) failed without a hint", tt.dir)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("checkLogDir left %d entries behind, want only the fixture file", len(entries)-1)
+	}
+}
+
+func TestCheckBillingExportWithoutNetwork(t *testing.T) {
+	tests := []struct {
+		name           string
+		project        string
+		dataset, table string
+		offline        bool
+	}{
+		{"not configured", "proj", "", "", false},
+		{"table missing", "proj", "billing", "", false},
+		{"offline", "proj", "billing", "export", true},
+		{"no project", "", "billing", "export", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkBillingExport(context.Background(), tt.project, tt.dataset, tt.table, tt.offline)
+			if got.status != pretty.CheckWarn {
+				t.Errorf("status = %s, want %s (%s)", got.status, pretty.CheckWarn, got.detail)
+			}
+		})
+	}
+}
+
+func TestBillingExportHint(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"jobs.create denied", &googleapi.Error{Code: 403, Message: "Access Denied: Project proj: User does not have bigquery.jobs.create permission in project proj."}, "bigquery.jobs.create"},
+		{"table access denied", &googleapi.Error{Code: 403, Message: "Access Denied: Table proj:billing.export"}, "dataViewer"},
+		{"table not found", &googleapi.Error{Code: 404, Message: "Not found: Table proj:billing.export"}, "config set billing"},
+		{"no credentials", errors.New("could not find default credentials"), "gcloud auth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := billingExportHint(tt.err, "proj"); !strings.Contains(got, tt.want) {
+				t.Errorf("billingExportHint() = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDefaultProject(t *testing.T) {
+	if got := checkDefaultProject(""); got.status != pretty.CheckWarn || got.hint == "" {
+		t.Errorf("empty project: got %s with hint %q, want warn with a hint", got.status, got.hint)
+	}
+	if got := checkDefaultProject("my-project"); got.status != pretty.CheckPass || got.detail != "my-project" {
+		t.Errorf("set project: got %s %q, want pass showing the project", got.status, got.detail)
+	}
+}
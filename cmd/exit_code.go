@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"google.golang.org/genai"
+)
+
+// Exit codes for failures scripts may want to tell apart. Anything else exits 1.
+const (
+	ExitCodeError        = 1
+	ExitCodeConfig       = 2 // missing or rejected API key, permission denied
+	ExitCodeRateLimit    = 3 // quota exhausted (429)
+	ExitCodeInvalidInput = 4 // bad flags, unknown or expired cache, oversized context, rejected request
+	ExitCodeServer       = 5 // Gemini server error, model unavailable or request timed out
+)
+
+// exitCodesHelp documents the exit codes in the root command's help
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  other error
+  2  configuration or authentication error (missing or invalid API key)
+  3  rate limit or quota exhausted
+  4  invalid input (bad flags, unknown or expired cache, context too large)
+  5  Gemini server error, model unavailable or request timed out`
+
+// ExitError is returned by Execute and carries the process exit code for the error it wraps
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// invalidInputError marks command line usage errors such as unknown flags
+type invalidInputError struct {
+	err error
+}
+
+func (e *invalidInputError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidInputError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode maps an error returned by a command to the process exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	var usageErr *invalidInputError
+	var streamErr *gemini.StreamInterruptedError
+	var apiErr genai.APIError
+	isAPIErr := errors.As(err, &apiErr)
+	switch {
+	case gemini.IsAPIKeyError(err), gemini.IsPermissionError(err), isAPIErr && apiErr.Code == 401:
+		return ExitCodeConfig
+	case gemini.IsQuotaError(err):
+		return ExitCodeRateLimit
+	case gemini.IsUnavailableError(err), errors.As(err, &streamErr), errors.Is(err, gemini.ErrRequestTimeout), isAPIErr && apiErr.Code >= 500:
+		return ExitCodeServer
+	case errors.As(err, &usageErr),
+		errors.Is(err, gemini.ErrCacheNotFound),
+		errors.Is(err, gemini.ErrCacheExpired),
+		errors.Is(err, gemini.ErrCacheModelMismatch),
+		errors.Is(err, gemini.ErrContextTooLarge),
+		isAPIErr && apiErr.Code == 400:
+		return ExitCodeInvalidInput
+	default:
+		return ExitCodeError
+	}
+}
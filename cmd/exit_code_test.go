@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"google.golang.org/genai"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"generic", errors.New("boom"), ExitCodeError},
+		{"already classified", &ExitError{Code: 42, Err: errors.New("boom")}, 42},
+		{"missing api key", fmt.Errorf("creating Gemini client: %w", &gemini.APIKeyError{Err: errors.New("no key")}), ExitCodeConfig},
+		{"invalid api key", genai.APIError{Code: 400, Message: "API key not valid. Please pass a valid API key."}, ExitCodeConfig},
+		{"permission denied", genai.APIError{Code: 403}, ExitCodeConfig},
+		{"quota", &gemini.QuotaError{Model: "m", Err: errors.New("429")}, ExitCodeRateLimit},
+		{"raw 429", genai.APIError{Code: 429}, ExitCodeRateLimit},
+		{"overloaded", fmt.Errorf("Gemini API request failed: %w", genai.APIError{Code: 503}), ExitCodeServer},
+		{"internal", genai.APIError{Code: 500}, ExitCodeServer},
+		{"stream interrupted", &gemini.StreamInterruptedError{Bytes: 10, Err: errors.New("reset")}, ExitCodeServer},
+		{"unknown flag", &invalidInputError{err: errors.New("unknown flag: --nope")}, ExitCodeInvalidInput},
+		{"cache not found", fmt.Errorf("using specified cache: %w", gemini.ErrCacheNotFound), ExitCodeInvalidInput},
+		{"cache expired", fmt.Errorf("using specified cache: %w", gemini.ErrCacheExpired), ExitCodeInvalidInput},
+		{"request timeout", fmt.Errorf("failed to generate content: %w", gemini.ErrRequestTimeout), ExitCodeServer},
+		{"cache model mismatch", fmt.Errorf("using specified cache: %w", gemini.ErrCacheModelMismatch), ExitCodeInvalidInput},
+		{"cache too large", fmt.Errorf("managing cache: %w", &gemini.CacheTooLargeError{Model: "m", Tokens: 2, Limit: 1}), ExitCodeInvalidInput},
+		{"bad request", genai.APIError{Code: 400, Message: "Invalid JSON payload"}, ExitCodeInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
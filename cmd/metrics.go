@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/analytics"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsExportHours  int
+	metricsExportOutput string
+)
+
+func newMetricsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Export local cache and usage metrics",
+		Long:  `Export metrics derived from local cache records and query logs. Unlike 'query metrics', which queries Cloud Monitoring, these commands only read local data.`,
+	}
+
+	cmd.AddCommand(newMetricsExportCmd())
+
+	return cmd
+}
+
+func newMetricsExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export cache analytics and request usage in OpenMetrics format",
+		Long: `Emit OpenMetrics text with per-cache hit rate, token count, query count and savings
+for the current project, plus aggregate request counts, tokens and cost from the local query logs.
+
+Examples:
+  # Print metrics to stdout
+  grove-gemini metrics export
+
+  # Write to a node-exporter textfile collector directory
+  grove-gemini metrics export -o /var/lib/node_exporter/textfile/grove_gemini.prom`,
+		RunE: runMetricsExport,
+	}
+
+	cmd.Flags().IntVarP(&metricsExportHours, "hours", "H", 24, "Number of hours of query logs to aggregate")
+	cmd.Flags().StringVarP(&metricsExportOutput, "output", "o", "", "Write metrics to file instead of stdout (written atomically)")
+
+	return cmd
+}
+
+func runMetricsExport(cmd *cobra.Command, args []string) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	caches, err := collectCacheMetrics(gemini.ResolveGeminiCacheDir(workDir))
+	if err != nil {
+		return err
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(metricsExportHours) * time.Hour)
+	logs, err := logging.GetLogger().ReadLogs(startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := analytics.WriteOpenMetrics(&buf, caches, logs); err != nil {
+		return fmt.Errorf("writing metrics: %w", err)
+	}
+
+	if metricsExportOutput == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	// Write to a temporary file first so scrapers never see a partial file
+	tempFile := metricsExportOutput + ".tmp"
+	if err := os.WriteFile(tempFile, buf.Bytes(), 0o644); err != nil { //nolint:gosec // metrics files need to be readable by exporters
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	if err := os.Rename(tempFile, metricsExportOutput); err != nil {
+		_ = os.Remove(tempFile) // best-effort cleanup
+		return fmt.Errorf("renaming metrics file: %w", err)
+	}
+
+	return nil
+}
+
+// collectCacheMetrics reads local cache records and converts active ones to metrics.
+func collectCacheMetrics(cacheDir string) ([]analytics.CacheMetrics, error) {
+	files, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	var caches []analytics.CacheMetrics
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") || !strings.HasPrefix(file.Name(), "hybrid_") {
+			continue
+		}
+		info, err := gemini.LoadCacheInfo(filepath.Join(cacheDir, file.Name()))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read cache info for %s: %v\n", file.Name(), err)
+			continue
+		}
+		if info.ClearedAt != nil {
+			continue
+		}
+
+		m := analytics.CacheMetrics{
+			Name:       info.CacheName,
+			Repo:       info.RepoName,
+			Model:      info.Model,
+			TokenCount: int64(info.TokenCount),
+		}
+		if info.UsageStats != nil {
+			m.HitRate = info.UsageStats.AverageHitRate
+			m.Queries = int64(info.UsageStats.TotalQueries)
+			m.SavingsUSD = gemini.CalculateCacheAnalytics(info).TotalSavings
+		}
+		caches = append(caches, m)
+	}
+
+	return caches, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/grove-gemini/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+func newModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "Show the Gemini models grove-gemini knows about",
+	}
+
+	cmd.AddCommand(newModelsListCmd())
+
+	return cmd
+}
+
+// modelListing is a model as printed by 'models list --json'. Prices are USD
+// per million tokens; the long context prices apply above long_context_threshold
+// prompt tokens and are omitted for single-tier models.
+type modelListing struct {
+	ID                   string  `json:"id"`
+	Provider             string  `json:"provider"`
+	InputPrice           float64 `json:"input_price"`
+	OutputPrice          float64 `json:"output_price"`
+	InputPriceLong       float64 `json:"input_price_long,omitempty"`
+	OutputPriceLong      float64 `json:"output_price_long,omitempty"`
+	LongContextThreshold int32   `json:"long_context_threshold,omitempty"`
+	InputTokenLimit      int32   `json:"input_token_limit"`
+	Legacy               bool    `json:"legacy"`
+	Note                 string  `json:"note"`
+}
+
+func newModelsListCmd() *cobra.Command {
+	var legacy, jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List available models with pricing",
+		Long: `Lists the models that can be passed to -m/--model, with their input and
+output prices in USD per million tokens. Models with a long context tier show
+the higher price that applies above the threshold.
+
+Legacy models are hidden unless --legacy is given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all := models.CurrentModels()
+			if legacy {
+				all = models.Models()
+			}
+
+			if jsonOutput {
+				listings := make([]modelListing, 0, len(all))
+				for _, m := range all {
+					listing := modelListing{
+						ID:              m.ID,
+						Provider:        m.Provider,
+						InputPrice:      m.Input,
+						OutputPrice:     m.Output,
+						InputPriceLong:  m.InputLong,
+						OutputPriceLong: m.OutputLong,
+						InputTokenLimit: m.InputTokenLimit,
+						Legacy:          m.Legacy,
+						Note:            m.Note,
+					}
+					if m.InputLong > 0 || m.OutputLong > 0 {
+						listing.LongContextThreshold = models.LongContextThreshold
+					}
+					listings = append(listings, listing)
+				}
+				data, err := json.MarshalIndent(listings, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal models to JSON: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			rows := make([][]string, 0, len(all))
+			for _, m := range all {
+				note := m.Note
+				if m.Legacy {
+					note += " [legacy]"
+				}
+				rows = append(rows, []string{
+					m.ID,
+					m.Provider,
+					formatTieredPrice(m.Input, m.InputLong),
+					formatTieredPrice(m.Output, m.OutputLong),
+					note,
+				})
+			}
+
+			t := tablecomponent.NewStyledTable().
+				Headers("MODEL", "PROVIDER", "INPUT $/M", "OUTPUT $/M", "NOTE").
+				Rows(rows...)
+			fmt.Println(t)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&legacy, "legacy", false, "Include legacy models")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the models as JSON")
+
+	return cmd
+}
+
+// formatTieredPrice formats a per-million price, adding the long context price when there is one
+func formatTieredPrice(price, long float64) string {
+	s := formatPrice(price)
+	if long > 0 {
+		s += fmt.Sprintf(" (%s >%dk)", formatPrice(long), models.LongContextThreshold/1000)
+	}
+	return s
+}
+
+// formatPrice formats a dollar amount with at least two decimals, keeping
+// sub-cent precision such as $0.075
+func formatPrice(price float64) string {
+	if cents := price * 100; math.Abs(cents-math.Round(cents)) < 1e-9 {
+		return fmt.Sprintf("$%.2f", price)
+	}
+	return "$" + strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// validateModelFlag checks a model passed with -m against the known models so
+// typos fail before any network call. allowUnknown skips the check for models
+// released after this list was last updated.
+func validateModelFlag(model string, allowUnknown bool) error {
+	if allowUnknown {
+		return nil
+	}
+	if _, err := models.ResolveModel(model); err != nil {
+		return fmt.Errorf("%w\nUse --allow-unknown-model to send it anyway", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import "testing"
+
+func TestFormatTieredPrice(t *testing.T) {
+	tests := []struct {
+		price, long float64
+		want        string
+	}{
+		{1.25, 0, "$1.25"},
+		{10, 0, "$10.00"},
+		{0.075, 0, "$0.075"},
+		{1.25, 2.50, "$1.25 ($2.50 >200k)"},
+	}
+	for _, tt := range tests {
+		if got := formatTieredPrice(tt.price, tt.long); got != tt.want {
+			t.Errorf("formatTieredPrice(%v, %v) = %q, want %q", tt.price, tt.long, got, tt.want)
+		}
+	}
+}
+
+func TestValidateModelFlag(t *testing.T) {
+	tests := []struct {
+		model        string
+		allowUnknown bool
+		wantErr      bool
+	}{
+		{"gemini-2.5-pro", false, false},
+		{"gemini-2.5-prro", false, true},
+		{"gemini-2.5-prro", true, false},
+		{"gemini-9-ultra", true, false},
+	}
+	for _, tt := range tests {
+		err := validateModelFlag(tt.model, tt.allowUnknown)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateModelFlag(%q, %v) error = %v, wantErr %v", tt.model, tt.allowUnknown, err, tt.wantErr)
+		}
+	}
+}
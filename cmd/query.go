@@ -27,6 +27,10 @@ func newQueryCmd() *cobra.Command {
 	cmd.AddCommand(newQueryRequestsCmd())
 	cmd.AddCommand(newQueryExploreCmd())
 	cmd.AddCommand(newQueryLocalCmd())
+	cmd.AddCommand(newQueryCompareCmd())
+	cmd.AddCommand(newQueryErrorsCmd())
+	cmd.AddCommand(newQueryExportCmd())
+	cmd.AddCommand(newQueryMetricsExportCmd())
 
 	return cmd
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/analytics"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	comparePeriod string
+	compareOffset int
+)
+
+func newQueryCompareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare local usage between two periods",
+		Long: `Compares cost, tokens, requests and error rate for the current rolling period
+against an earlier period of the same length, using local logs.
+
+Examples:
+  # This week vs. last week
+  grove-gemini query compare --period week
+
+  # Today vs. the same day last week
+  grove-gemini query compare --period day --offset 7`,
+		RunE: runQueryCompare,
+	}
+
+	cmd.Flags().StringVar(&comparePeriod, "period", "week", "Period length: day, week or month (30 days)")
+	cmd.Flags().IntVar(&compareOffset, "offset", 1, "How many periods back the comparison period starts")
+
+	return cmd
+}
+
+func runQueryCompare(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	period, err := analytics.ParsePeriod(comparePeriod)
+	if err != nil {
+		return err
+	}
+	if compareOffset < 1 {
+		return fmt.Errorf("--offset must be at least 1")
+	}
+
+	now := time.Now()
+	earliest := now.Add(-time.Duration(compareOffset+1) * period)
+	logs, err := logging.GetLogger().ReadLogs(earliest, now)
+	if err != nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	c := analytics.ComparePeriods(logs, period, compareOffset, now)
+
+	ulog.Info("Period comparison").
+		Field("period", comparePeriod).
+		Field("offset", compareOffset).
+		Field("current_cost", c.Current.TotalCost).
+		Field("previous_cost", c.Previous.TotalCost).
+		Pretty(fmt.Sprintf("%s Comparing %s to %s",
+			theme.IconChart,
+			formatCompareRange(c.CurrentStart, c.CurrentEnd),
+			formatCompareRange(c.PreviousStart, c.PreviousEnd))).
+		PrettyOnly().
+		Log(ctx)
+
+	rows := [][]string{
+		{"Cost", pretty.FormatCost(c.Previous.TotalCost), pretty.FormatCost(c.Current.TotalCost), formatPercentChange(c.Previous.TotalCost, c.Current.TotalCost)},
+		{"Tokens", fmt.Sprintf("%d", c.Previous.TotalTokens), fmt.Sprintf("%d", c.Current.TotalTokens), formatPercentChange(float64(c.Previous.TotalTokens), float64(c.Current.TotalTokens))},
+		{"Requests", fmt.Sprintf("%d", c.Previous.TotalRequests), fmt.Sprintf("%d", c.Current.TotalRequests), formatPercentChange(float64(c.Previous.TotalRequests), float64(c.Current.TotalRequests))},
+		// Error rate is already a percentage, so show the change in percentage points
+		{"Error rate", fmt.Sprintf("%.1f%%", c.Previous.ErrorRate), fmt.Sprintf("%.1f%%", c.Current.ErrorRate), fmt.Sprintf("%+.1f pp", c.Current.ErrorRate-c.Previous.ErrorRate)},
+	}
+
+	t := tablecomponent.NewStyledTable().
+		Headers("METRIC", "PREVIOUS", "CURRENT", "CHANGE").
+		Rows(rows...)
+	fmt.Println(t)
+
+	return nil
+}
+
+// formatCompareRange renders a period's date range for display
+func formatCompareRange(start, end time.Time) string {
+	return fmt.Sprintf("%s – %s", start.Local().Format("Jan 02 15:04"), end.Local().Format("Jan 02 15:04"))
+}
+
+// formatPercentChange renders the relative change with an up/down arrow
+func formatPercentChange(previous, current float64) string {
+	change, ok := analytics.PercentChange(previous, current)
+	switch {
+	case !ok && current == 0:
+		return "-"
+	case !ok:
+		return "new"
+	case change > 0:
+		return fmt.Sprintf("↑ %.1f%%", change)
+	case change < 0:
+		return fmt.Sprintf("↓ %.1f%%", -change)
+	default:
+		return "0.0%"
+	}
+}
@@ -64,11 +64,16 @@ func runQueryDashboard(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no billing table specified. Use --table-id flag or set a default with 'grove-gemini config set billing DATASET_ID TABLE_ID'")
 	}
 
+	// Start in the view selected last time unless --days was given
+	model := newDashboardModel(billingProjectID, billingDatasetID, billingTableID, dashboardDays)
+	if !cmd.Flags().Changed("days") {
+		if frame, ok := timeFrameFromName(loadTUIState().DashboardTimeFrame); ok {
+			model.timeFrame = frame
+		}
+	}
+
 	// Initialize and run the TUI
-	p := tea.NewProgram(
-		newDashboardModel(billingProjectID, billingDatasetID, billingTableID, dashboardDays),
-		tea.WithAltScreen(),
-	)
+	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running dashboard: %w", err)
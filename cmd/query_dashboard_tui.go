@@ -14,6 +14,7 @@ import (
 	"github.com/grovetools/core/tui/keymap"
 	"github.com/grovetools/core/tui/theme"
 	"github.com/grovetools/grove-gemini/pkg/analytics"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 )
 
 // dashboardKeyMap extends the base keymap with custom keybindings
@@ -183,6 +184,13 @@ func newDashboardModel(projectID, datasetID, tableID string, days int) dashboard
 	}
 }
 
+// saveView remembers the current time frame for the next run
+func (m dashboardModel) saveView() {
+	saveTUIState(func(s *tuiState) {
+		s.DashboardTimeFrame = timeFrameNames[m.timeFrame]
+	})
+}
+
 func (m dashboardModel) Init() tea.Cmd {
 	return loadBillingDataCmd(m.projectID, m.datasetID, m.tableID, m.timeFrame, m.timeOffset)
 }
@@ -216,26 +224,31 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.timeFrame = 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadBillingDataCmd(m.projectID, m.datasetID, m.tableID, m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.WeeklyView):
 			m.timeFrame = 7 * 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadBillingDataCmd(m.projectID, m.datasetID, m.tableID, m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.MonthlyView):
 			m.timeFrame = 30 * 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadBillingDataCmd(m.projectID, m.datasetID, m.tableID, m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.QuarterlyView):
 			m.timeFrame = 90 * 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadBillingDataCmd(m.projectID, m.datasetID, m.tableID, m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.YearlyView):
 			m.timeFrame = 365 * 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadBillingDataCmd(m.projectID, m.datasetID, m.tableID, m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.PrevPeriod):
 			m.timeOffset++
@@ -317,7 +330,7 @@ func (m dashboardModel) renderSummaryView() string {
 	for _, sku := range m.billingData.SKUBreakdown {
 		totalTokens += int64(sku.TotalUsage)
 	}
-	tokens := fmt.Sprintf("%s %dK", titleStyle.Render("Tokens:"), totalTokens/1000)
+	tokens := fmt.Sprintf("%s %s", titleStyle.Render("Tokens:"), pretty.FormatTokensCompact(totalTokens))
 
 	// Calculate requests (estimate based on token averages)
 	requests := fmt.Sprintf("%s %d", titleStyle.Render("Requests:"), len(m.billingData.SKUBreakdown))
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tablecomponent "github.com/grovetools/core/tui/components/table"
+	"github.com/grovetools/grove-gemini/pkg/analytics"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errorsHours     int
+	errorsThreshold float64
+)
+
+func newQueryErrorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "errors",
+		Short: "Check the local error rate and list distinct errors",
+		Long: `Computes the error rate of locally logged requests and lists each distinct
+error message with its count.
+
+With --threshold, the command exits non-zero when the error rate (in percent)
+exceeds the threshold, so it can be used from cron or monitoring to alert.
+
+Examples:
+  # Alert if more than 5% of requests failed in the last hour
+  grove-gemini query errors --hours 1 --threshold 5`,
+		RunE: runQueryErrors,
+	}
+
+	cmd.Flags().IntVarP(&errorsHours, "hours", "H", 24, "Number of hours to look back")
+	cmd.Flags().Float64Var(&errorsThreshold, "threshold", -1, "Exit non-zero if the error rate (percent) exceeds this value")
+
+	return cmd
+}
+
+func runQueryErrors(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(errorsHours) * time.Hour)
+
+	logs, err := logging.GetLogger().ReadLogs(startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	summary := analytics.SummarizeErrors(logs)
+
+	ulog.Info("Error rate").
+		Field("hours", errorsHours).
+		Field("total_requests", summary.TotalRequests).
+		Field("failed_requests", summary.FailedRequests).
+		Field("error_rate", summary.ErrorRate).
+		Pretty(fmt.Sprintf("Error rate over the last %d hour(s): %.1f%% (%d of %d requests failed)",
+			errorsHours, summary.ErrorRate, summary.FailedRequests, summary.TotalRequests)).
+		PrettyOnly().
+		Log(ctx)
+
+	if len(summary.Messages) > 0 {
+		rows := make([][]string, 0, len(summary.Messages))
+		for _, m := range summary.Messages {
+			rows = append(rows, []string{fmt.Sprintf("%d", m.Count), truncatePreview(m.Message, 100)})
+		}
+		t := tablecomponent.NewStyledTable().
+			Headers("COUNT", "ERROR").
+			Rows(rows...)
+		fmt.Println()
+		fmt.Println(t)
+	}
+
+	if errorsThreshold >= 0 && summary.ErrorRate > errorsThreshold {
+		return fmt.Errorf("error rate %.1f%% exceeds threshold %.1f%%", summary.ErrorRate, errorsThreshold)
+	}
+
+	return nil
+}
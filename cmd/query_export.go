@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportHours  int
+	exportFrom   string
+	exportTo     string
+	exportOutput string
+)
+
+// exportColumns is the CSV header written by 'query export'
+var exportColumns = []string{
+	"timestamp", "model", "caller",
+	"prompt_tokens", "completion_tokens", "cached_tokens", "total_tokens",
+	"cache_hit_rate", "estimated_cost_usd", "response_time_seconds",
+	"git_repo", "git_branch", "success", "error",
+}
+
+func newQueryExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export local query logs as CSV",
+		Long: `Writes locally logged Gemini API requests as CSV, one row per request in
+chronological order, for use in spreadsheets and reporting tools.
+
+The time range is either the last --hours, or --from/--to. Both accept a date
+(2006-01-02), a date and time (2006-01-02 15:04) or an RFC 3339 timestamp; a
+date given to --to includes that whole day. --to defaults to now.
+
+Examples:
+  # Last week's requests
+  grove-gemini query export --hours 168 -o week.csv
+
+  # A calendar month
+  grove-gemini query export --from 2025-06-01 --to 2025-06-30 > june.csv`,
+		RunE: runQueryExport,
+	}
+
+	cmd.Flags().IntVarP(&exportHours, "hours", "H", 24, "Number of hours to look back (ignored with --from)")
+	cmd.Flags().StringVar(&exportFrom, "from", "", "Start of the time range")
+	cmd.Flags().StringVar(&exportTo, "to", "", "End of the time range (requires --from)")
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write CSV to this file instead of stdout")
+
+	return cmd
+}
+
+func runQueryExport(cmd *cobra.Command, args []string) error {
+	startTime, endTime, err := resolveQueryRange(exportHours, exportFrom, exportTo, time.Now())
+	if err != nil {
+		return err
+	}
+
+	logs, err := logging.GetLogger().ReadLogs(startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].Timestamp.Before(logs[j].Timestamp)
+	})
+
+	out := io.Writer(os.Stdout)
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput) //nolint:gosec // exportOutput is a user-provided path
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	if err := writeQueryLogsCSV(out, logs); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	if exportOutput != "" {
+		ulog.Info("Exported query logs").
+			Field("rows", len(logs)).
+			Field("file", exportOutput).
+			Pretty(fmt.Sprintf("Exported %d request(s) to %s", len(logs), exportOutput)).
+			PrettyOnly().
+			Log(context.Background())
+	}
+	return nil
+}
+
+// writeQueryLogsCSV writes logs as CSV with a header row
+func writeQueryLogsCSV(w io.Writer, logs []logging.QueryLog) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, log := range logs {
+		record := []string{
+			log.Timestamp.Format(time.RFC3339),
+			log.Model,
+			log.Caller,
+			strconv.FormatInt(int64(log.PromptTokens), 10),
+			strconv.FormatInt(int64(log.CompletionTokens), 10),
+			strconv.FormatInt(int64(log.CachedTokens), 10),
+			strconv.FormatInt(int64(log.TotalTokens), 10),
+			strconv.FormatFloat(log.CacheHitRate, 'f', 4, 64),
+			strconv.FormatFloat(log.EstimatedCost, 'f', 6, 64),
+			strconv.FormatFloat(log.ResponseTime, 'f', 3, 64),
+			log.GitRepo,
+			log.GitBranch,
+			strconv.FormatBool(log.Success),
+			log.Error,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestWriteQueryLogsCSV(t *testing.T) {
+	ts := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	logs := []logging.QueryLog{
+		{Timestamp: ts, Model: "gemini-2.0-flash", Caller: "grove-flow", PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120, EstimatedCost: 0.0012, ResponseTime: 1.5, Success: true},
+		{Timestamp: ts, Model: "gemini-2.5-pro", Success: false, Error: `bad request: "prompt", too long`},
+	}
+
+	var out strings.Builder
+	if err := writeQueryLogsCSV(&out, logs); err != nil {
+		t.Fatalf("writeQueryLogsCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d records", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(exportColumns, ",") {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	if records[1][0] != "2025-06-01T12:30:00Z" || records[1][3] != "100" || records[1][12] != "true" {
+		t.Errorf("unexpected first row: %v", records[1])
+	}
+	if got := records[2][len(exportColumns)-1]; got != `bad request: "prompt", too long` {
+		t.Errorf("error field did not round-trip: %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,28 +10,52 @@ import (
 
 	"github.com/grovetools/core/tui/theme"
 	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 	"github.com/spf13/cobra"
 )
 
 var (
 	localHours  int
+	localFrom   string
+	localTo     string
 	localLimit  int
 	localModel  string
+	localCaller string
 	localErrors bool
+	localLabels []string
+	localJSON   bool
+	localGroup  string
 )
 
+// costGroupings are the --group-by values and the labels shown in the summary
+var costGroupings = map[string]string{
+	"repo":   "Repository",
+	"model":  "Model",
+	"caller": "Caller",
+}
+
 func newQueryLocalCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "local",
 		Short: "Query local Gemini API logs",
-		Long:  `Displays locally logged Gemini API requests with token usage, costs, and performance metrics.`,
-		RunE:  runQueryLocal,
+		Long: `Displays locally logged Gemini API requests with token usage, costs, and performance metrics.
+
+The time range is either the last --hours, or --from/--to. Both accept a date
+(2006-01-02), a date and time (2006-01-02 15:04) or an RFC 3339 timestamp; a
+date given to --to includes that whole day. --to defaults to now.`,
+		RunE: runQueryLocal,
 	}
 
-	cmd.Flags().IntVarP(&localHours, "hours", "H", 24, "Number of hours to look back")
+	cmd.Flags().IntVarP(&localHours, "hours", "H", 24, "Number of hours to look back (ignored with --from)")
+	cmd.Flags().StringVar(&localFrom, "from", "", "Start of the time range")
+	cmd.Flags().StringVar(&localTo, "to", "", "End of the time range (requires --from)")
 	cmd.Flags().IntVarP(&localLimit, "limit", "l", 100, "Maximum number of requests to display")
 	cmd.Flags().StringVarP(&localModel, "model", "m", "", "Filter by model name")
+	cmd.Flags().StringVar(&localCaller, "caller", "", "Filter by caller (e.g. grove-flow); matches exactly or as a substring")
 	cmd.Flags().BoolVar(&localErrors, "errors", false, "Show only failed requests")
+	cmd.Flags().StringArrayVar(&localLabels, "label", nil, "Filter by request label key=value (repeatable)")
+	cmd.Flags().BoolVar(&localJSON, "json", false, "Print the matching requests and summary as a single JSON document")
+	cmd.Flags().StringVar(&localGroup, "group-by", "repo", "Break the summary cost down by repo, model or caller")
 
 	return cmd
 }
@@ -39,25 +64,38 @@ func runQueryLocal(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	logger := logging.GetLogger()
 
-	endTime := time.Now()
-	startTime := endTime.Add(-time.Duration(localHours) * time.Hour)
+	labelFilter, err := parseLabels(localLabels)
+	if err != nil {
+		return err
+	}
+	if _, ok := costGroupings[localGroup]; !ok {
+		return fmt.Errorf("invalid --group-by %q: use repo, model or caller", localGroup)
+	}
 
-	ulog.Info("Fetching local Gemini API logs").
-		Field("hours", localHours).
-		Field("start_time", startTime).
-		Field("end_time", endTime).
-		Pretty(fmt.Sprintf("Fetching local Gemini API logs for the last %d hour(s)...\n", localHours)).
-		PrettyOnly().
-		Log(ctx)
+	startTime, endTime, err := resolveQueryRange(localHours, localFrom, localTo, time.Now())
+	if err != nil {
+		return err
+	}
+	hours := queryRangeHours(startTime, endTime)
+
+	if !localJSON {
+		ulog.Info("Fetching local Gemini API logs").
+			Field("hours", hours).
+			Field("start_time", startTime).
+			Field("end_time", endTime).
+			Pretty(fmt.Sprintf("Fetching local Gemini API logs for %s...\n", describeQueryRange(localHours, localFrom, startTime, endTime))).
+			PrettyOnly().
+			Log(ctx)
+	}
 
 	logs, err := logger.ReadLogs(startTime, endTime)
 	if err != nil {
 		return fmt.Errorf("failed to read logs: %w", err)
 	}
 
-	if len(logs) == 0 {
+	if len(logs) == 0 && !localJSON {
 		ulog.Info("No logs found").
-			Field("time_range_hours", localHours).
+			Field("time_range_hours", hours).
 			Pretty("No logs found for the specified time range.").
 			PrettyOnly().
 			Log(ctx)
@@ -72,11 +110,21 @@ func runQueryLocal(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Filter by caller if specified
+		if !matchesCaller(log.Caller, localCaller) {
+			continue
+		}
+
 		// Filter by errors if specified
 		if localErrors && log.Success {
 			continue
 		}
 
+		// Filter by labels if specified
+		if !matchesLabels(log.Labels, labelFilter) {
+			continue
+		}
+
 		filteredLogs = append(filteredLogs, log)
 	}
 
@@ -90,17 +138,51 @@ func runQueryLocal(cmd *cobra.Command, args []string) error {
 		filteredLogs = filteredLogs[:localLimit]
 	}
 
+	if localJSON {
+		report := localLogsReport{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Hours:     hours,
+			Requests:  filteredLogs,
+			Summary:   summarizeLocalLogs(filteredLogs, hours),
+			GroupBy:   localGroup,
+			Groups:    groupLocalLogs(filteredLogs, localGroup),
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal logs to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
 	// Display table
 	displayLocalLogsTable(ctx, filteredLogs)
 
 	// Summary
 	if len(filteredLogs) > 10 {
-		displaySummary(ctx, filteredLogs)
+		displaySummary(ctx, filteredLogs, hours, localGroup)
 	}
 
 	return nil
 }
 
+// matchesCaller reports whether caller matches filter, case-insensitively and
+// as a substring, so "flow" selects "grove-flow". An empty filter matches everything.
+func matchesCaller(caller, filter string) bool {
+	return filter == "" || strings.Contains(strings.ToLower(caller), strings.ToLower(filter))
+}
+
+// matchesLabels reports whether labels contain every key=value pair in filter
+func matchesLabels(labels, filter map[string]string) bool {
+	for k, v := range filter {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
 func displayLocalLogsTable(ctx context.Context, logs []logging.QueryLog) {
 	var output strings.Builder
 
@@ -124,19 +206,19 @@ func displayLocalLogsTable(ctx context.Context, logs []logging.QueryLog) {
 
 		cachedStr := "-"
 		if log.CachedTokens > 0 {
-			cachedStr = fmt.Sprintf("%d", log.CachedTokens)
+			cachedStr = pretty.FormatTokens(log.CachedTokens)
 		}
 
-		promptStr := fmt.Sprintf("%d", log.PromptTokens)
-		completionStr := fmt.Sprintf("%d", log.CompletionTokens)
-		totalStr := fmt.Sprintf("%d", log.TotalTokens)
+		promptStr := pretty.FormatTokens(log.PromptTokens)
+		completionStr := pretty.FormatTokens(log.CompletionTokens)
+		totalStr := pretty.FormatTokens(log.TotalTokens)
 
 		cacheRateStr := "-"
 		if log.CacheHitRate > 0 {
 			cacheRateStr = fmt.Sprintf("%.1f%%", log.CacheHitRate*100)
 		}
 
-		costStr := fmt.Sprintf("$%.6f", log.EstimatedCost)
+		costStr := pretty.FormatCost(log.EstimatedCost)
 		timeStr := fmt.Sprintf("%.2fs", log.ResponseTime)
 
 		// Format repo/branch info
@@ -188,37 +270,127 @@ func displayLocalLogsTable(ctx context.Context, logs []logging.QueryLog) {
 		Log(ctx)
 }
 
-func displaySummary(ctx context.Context, logs []logging.QueryLog) {
-	var output strings.Builder
-	output.WriteString(fmt.Sprintf("\n=== Summary (showing %d requests) ===\n", len(logs)))
+// localLogsReport is the document printed by 'query local --json'
+type localLogsReport struct {
+	StartTime time.Time          `json:"start_time"`
+	EndTime   time.Time          `json:"end_time"`
+	Hours     int                `json:"hours"`
+	Requests  []logging.QueryLog `json:"requests"`
+	Summary   localSummary       `json:"summary"`
+	GroupBy   string             `json:"group_by"`
+	Groups    []costGroup        `json:"groups"`
+}
 
-	var totalCost float64
-	var totalPromptTokens, totalCompletionTokens, totalCachedTokens, totalUserPromptTokens int64
-	var totalResponseTime float64
-	var errorCount int
-	var cacheHits int
-	var requestsWithUserPrompt int
+// localSummary aggregates a set of local query logs. Costs are in USD and
+// rates are fractions (0-1).
+type localSummary struct {
+	Requests          int     `json:"requests"`
+	TotalCost         float64 `json:"total_cost_usd"`
+	QueryCost         float64 `json:"query_cost_usd"`
+	CacheCreationCost float64 `json:"cache_creation_cost_usd"`
+	NewCaches         int     `json:"new_caches"`
+
+	PromptTokens           int64 `json:"prompt_tokens"`
+	CompletionTokens       int64 `json:"completion_tokens"`
+	CachedTokens           int64 `json:"cached_tokens"`
+	TotalTokens            int64 `json:"total_tokens"`
+	UserPromptTokens       int64 `json:"user_prompt_tokens"`
+	RequestsWithUserPrompt int   `json:"requests_with_user_prompt"`
+
+	Errors          int                  `json:"errors"`
+	ErrorRate       float64              `json:"error_rate"`
+	CacheHits       int                  `json:"cache_hits"`
+	CacheHitRate    float64              `json:"cache_hit_rate"`
+	AvgCacheRate    float64              `json:"avg_cache_rate"`
+	CacheSavings    float64              `json:"cache_savings_usd"`
+	AvgResponseTime float64              `json:"avg_response_time_seconds"`
+	CostByModel     map[string]modelCost `json:"cost_by_model"`
+
+	ProjectedHourlyCost  float64 `json:"projected_hourly_cost_usd"`
+	ProjectedDailyCost   float64 `json:"projected_daily_cost_usd"`
+	ProjectedMonthlyCost float64 `json:"projected_monthly_cost_usd"`
+}
 
-	modelCosts := make(map[string]float64)
-	modelCounts := make(map[string]int)
+// modelCost is the query cost and request count for one model family
+type modelCost struct {
+	Cost     float64 `json:"cost_usd"`
+	Requests int     `json:"requests"`
+}
+
+// costGroup is the query cost, tokens and request count for one --group-by value
+type costGroup struct {
+	Key      string  `json:"key"`
+	Cost     float64 `json:"cost_usd"`
+	Tokens   int64   `json:"total_tokens"`
+	Requests int     `json:"requests"`
+}
 
+// groupLocalLogs totals logs by repo, model or caller, most expensive first.
+// Logs without a value are grouped under a placeholder such as "(no repo)".
+func groupLocalLogs(logs []logging.QueryLog, by string) []costGroup {
+	index := make(map[string]int)
+	var groups []costGroup
 	for _, log := range logs {
-		totalCost += log.EstimatedCost
-		totalPromptTokens += int64(log.PromptTokens)
-		totalCompletionTokens += int64(log.CompletionTokens)
-		totalCachedTokens += int64(log.CachedTokens)
+		var k string
+		switch by {
+		case "model":
+			k = log.Model
+		case "caller":
+			k = log.Caller
+		default:
+			k = log.GitRepo
+		}
+		if k == "" {
+			k = "(no " + by + ")"
+		}
+
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, costGroup{Key: k})
+		}
+		groups[i].Cost += log.EstimatedCost + log.CacheCreationCost
+		groups[i].Tokens += int64(log.TotalTokens)
+		groups[i].Requests++
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Cost != groups[j].Cost {
+			return groups[i].Cost > groups[j].Cost
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// summarizeLocalLogs computes the aggregates shown by displaySummary over a
+// window of the given number of hours
+func summarizeLocalLogs(logs []logging.QueryLog, hours int) localSummary {
+	summary := localSummary{Requests: len(logs), CostByModel: make(map[string]modelCost)}
+	var totalResponseTime float64
+
+	for _, log := range logs {
+		summary.QueryCost += log.EstimatedCost
+		if log.IsNewCache {
+			summary.CacheCreationCost += log.CacheCreationCost
+			summary.NewCaches++
+		}
+		summary.PromptTokens += int64(log.PromptTokens)
+		summary.CompletionTokens += int64(log.CompletionTokens)
+		summary.CachedTokens += int64(log.CachedTokens)
 		totalResponseTime += log.ResponseTime
 
 		if log.UserPromptTokens > 0 {
-			totalUserPromptTokens += int64(log.UserPromptTokens)
-			requestsWithUserPrompt++
+			summary.UserPromptTokens += int64(log.UserPromptTokens)
+			summary.RequestsWithUserPrompt++
 		}
 
 		if !log.Success {
-			errorCount++
+			summary.Errors++
 		}
 		if log.CachedTokens > 0 {
-			cacheHits++
+			summary.CacheHits++
 		}
 
 		// Group by model
@@ -228,57 +400,93 @@ func displaySummary(ctx context.Context, logs []logging.QueryLog) {
 		} else if strings.Contains(modelKey, "pro") {
 			modelKey = "pro"
 		}
-		modelCosts[modelKey] += log.EstimatedCost
-		modelCounts[modelKey]++
+		mc := summary.CostByModel[modelKey]
+		mc.Cost += log.EstimatedCost
+		mc.Requests++
+		summary.CostByModel[modelKey] = mc
+	}
+
+	summary.TotalCost = summary.QueryCost + summary.CacheCreationCost
+	summary.TotalTokens = summary.PromptTokens + summary.CompletionTokens
+	if len(logs) > 0 {
+		summary.ErrorRate = float64(summary.Errors) / float64(len(logs))
+		summary.CacheHitRate = float64(summary.CacheHits) / float64(len(logs))
+		summary.AvgResponseTime = totalResponseTime / float64(len(logs))
 	}
+	if summary.CacheHits > 0 {
+		summary.AvgCacheRate = float64(summary.CachedTokens) / float64(summary.PromptTokens+summary.CachedTokens)
+		savedTokens := float64(summary.CachedTokens) * 0.75    // 75% discount on cached tokens
+		summary.CacheSavings = savedTokens / 1_000_000 * 0.075 // Assuming flash input pricing
+	}
+
+	// Project from steady-state query cost; cache creation is a one-time expense
+	if hours > 0 {
+		summary.ProjectedHourlyCost = summary.QueryCost / float64(hours)
+	}
+	summary.ProjectedDailyCost = summary.ProjectedHourlyCost * 24
+	summary.ProjectedMonthlyCost = summary.ProjectedDailyCost * 30
+
+	return summary
+}
+
+func displaySummary(ctx context.Context, logs []logging.QueryLog, hours int, groupBy string) {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n=== Summary (showing %d requests) ===\n", len(logs)))
 
-	output.WriteString(fmt.Sprintf("Total Cost: $%.6f\n", totalCost))
-	output.WriteString(fmt.Sprintf("Total Tokens: %d (Prompt: %d, Completion: %d, Cached: %d)\n",
-		totalPromptTokens+totalCompletionTokens, totalPromptTokens, totalCompletionTokens, totalCachedTokens))
+	summary := summarizeLocalLogs(logs, hours)
 
-	if requestsWithUserPrompt > 0 {
-		output.WriteString(fmt.Sprintf("User Prompt Tokens: %d (from %d requests with prompts)\n", totalUserPromptTokens, requestsWithUserPrompt))
+	output.WriteString(fmt.Sprintf("Total Cost: %s\n", pretty.FormatCost(summary.TotalCost)))
+	if summary.NewCaches > 0 {
+		output.WriteString(fmt.Sprintf("  Queries: %s\n", pretty.FormatCost(summary.QueryCost)))
+		output.WriteString(fmt.Sprintf("  Cache Creation: %s (%d new caches)\n", pretty.FormatCost(summary.CacheCreationCost), summary.NewCaches))
 	}
+	output.WriteString(fmt.Sprintf("Total Tokens: %s (Prompt: %s, Completion: %s, Cached: %s)\n",
+		pretty.FormatTokens(summary.TotalTokens), pretty.FormatTokens(summary.PromptTokens),
+		pretty.FormatTokens(summary.CompletionTokens), pretty.FormatTokens(summary.CachedTokens)))
 
-	if errorCount > 0 {
-		output.WriteString(fmt.Sprintf("Error Rate: %.1f%% (%d errors)\n", float64(errorCount)/float64(len(logs))*100, errorCount))
+	if summary.RequestsWithUserPrompt > 0 {
+		output.WriteString(fmt.Sprintf("User Prompt Tokens: %s (from %d requests with prompts)\n", pretty.FormatTokens(summary.UserPromptTokens), summary.RequestsWithUserPrompt))
 	}
 
-	if cacheHits > 0 {
-		output.WriteString(fmt.Sprintf("Cache Hit Rate: %.1f%% (%d requests with cache)\n", float64(cacheHits)/float64(len(logs))*100, cacheHits))
+	if summary.Errors > 0 {
+		output.WriteString(fmt.Sprintf("Error Rate: %.1f%% (%d errors)\n", summary.ErrorRate*100, summary.Errors))
+	}
 
-		// Calculate cache savings
-		avgCacheRate := float64(totalCachedTokens) / float64(totalPromptTokens+totalCachedTokens)
-		savedTokens := float64(totalCachedTokens) * 0.75 // 75% discount on cached tokens
-		savedCost := savedTokens / 1_000_000 * 0.075     // Assuming flash input pricing
-		output.WriteString(fmt.Sprintf("Cache Savings: ~$%.6f (%.1f%% avg cache rate)\n", savedCost, avgCacheRate*100))
+	if summary.CacheHits > 0 {
+		output.WriteString(fmt.Sprintf("Cache Hit Rate: %.1f%% (%d requests with cache)\n", summary.CacheHitRate*100, summary.CacheHits))
+		output.WriteString(fmt.Sprintf("Cache Savings: ~%s (%.1f%% avg cache rate)\n", pretty.FormatCost(summary.CacheSavings), summary.AvgCacheRate*100))
 	}
 
-	output.WriteString(fmt.Sprintf("Average Response Time: %.2fs\n", totalResponseTime/float64(len(logs))))
+	output.WriteString(fmt.Sprintf("Average Response Time: %.2fs\n", summary.AvgResponseTime))
 
 	// Cost breakdown by model
-	if len(modelCosts) > 1 {
+	if len(summary.CostByModel) > 1 {
 		output.WriteString("\nCost by Model:\n")
-		for model, cost := range modelCosts {
-			output.WriteString(fmt.Sprintf("  %s: $%.6f (%d requests)\n", model, cost, modelCounts[model]))
+		for model, mc := range summary.CostByModel {
+			output.WriteString(fmt.Sprintf("  %s: %s (%d requests)\n", model, pretty.FormatCost(mc.Cost), mc.Requests))
+		}
+	}
+
+	// Cost breakdown by the --group-by dimension
+	if groups := groupLocalLogs(logs, groupBy); len(groups) > 0 {
+		output.WriteString(fmt.Sprintf("\nCost by %s:\n", costGroupings[groupBy]))
+		for _, g := range groups {
+			output.WriteString(fmt.Sprintf("  %-30s %10s %12s tokens %6d requests\n", g.Key, pretty.FormatCost(g.Cost), pretty.FormatTokens(g.Tokens), g.Requests))
 		}
 	}
 
-	// Hourly rate
-	hourlyRate := totalCost / float64(localHours)
-	dailyProjection := hourlyRate * 24
-	monthlyProjection := dailyProjection * 30
 	output.WriteString("\nProjected Costs:\n")
-	output.WriteString(fmt.Sprintf("  Hourly: $%.6f\n", hourlyRate))
-	output.WriteString(fmt.Sprintf("  Daily: $%.2f\n", dailyProjection))
-	output.WriteString(fmt.Sprintf("  Monthly: $%.2f\n", monthlyProjection))
+	output.WriteString(fmt.Sprintf("  Hourly: %s\n", pretty.FormatCost(summary.ProjectedHourlyCost)))
+	output.WriteString(fmt.Sprintf("  Daily: %s\n", pretty.FormatCost(summary.ProjectedDailyCost)))
+	output.WriteString(fmt.Sprintf("  Monthly: %s\n", pretty.FormatCost(summary.ProjectedMonthlyCost)))
 
 	ulog.Info("Summary statistics").
-		Field("total_cost", totalCost).
-		Field("total_tokens", totalPromptTokens+totalCompletionTokens).
-		Field("error_count", errorCount).
-		Field("cache_hits", cacheHits).
-		Field("monthly_projection", monthlyProjection).
+		Field("total_cost", summary.QueryCost).
+		Field("cache_creation_cost", summary.CacheCreationCost).
+		Field("total_tokens", summary.TotalTokens).
+		Field("error_count", summary.Errors).
+		Field("cache_hits", summary.CacheHits).
+		Field("monthly_projection", summary.ProjectedMonthlyCost).
 		Pretty(output.String()).
 		PrettyOnly().
 		Log(ctx)
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestSummarizeLocalLogs(t *testing.T) {
+	logs := []logging.QueryLog{
+		{Model: "gemini-2.0-flash", EstimatedCost: 0.10, PromptTokens: 1000, CompletionTokens: 100, CachedTokens: 800, ResponseTime: 1, Success: true},
+		{Model: "gemini-2.5-pro", EstimatedCost: 0.30, PromptTokens: 2000, CompletionTokens: 200, ResponseTime: 3, Success: true, IsNewCache: true, CacheCreationCost: 0.05},
+		{Model: "gemini-2.5-pro", PromptTokens: 500, ResponseTime: 2, Success: false},
+		{Model: "gemini-2.0-flash", EstimatedCost: 0.20, PromptTokens: 1000, CompletionTokens: 100, UserPromptTokens: 50, ResponseTime: 2, Success: true},
+	}
+
+	summary := summarizeLocalLogs(logs, 24)
+
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	approx("QueryCost", summary.QueryCost, 0.60)
+	approx("TotalCost", summary.TotalCost, 0.65)
+	approx("ErrorRate", summary.ErrorRate, 0.25)
+	approx("CacheHitRate", summary.CacheHitRate, 0.25)
+	approx("AvgResponseTime", summary.AvgResponseTime, 2)
+	approx("ProjectedDailyCost", summary.ProjectedDailyCost, 0.60)
+
+	if summary.Requests != 4 || summary.Errors != 1 || summary.NewCaches != 1 {
+		t.Errorf("unexpected counts: %+v", summary)
+	}
+	if summary.TotalTokens != 4500+400 {
+		t.Errorf("TotalTokens = %d, want 4900", summary.TotalTokens)
+	}
+	if summary.CostByModel["pro"].Requests != 2 || summary.CostByModel["flash"].Requests != 2 {
+		t.Errorf("unexpected model breakdown: %+v", summary.CostByModel)
+	}
+}
+
+func TestLocalLogsReportJSON(t *testing.T) {
+	report := localLogsReport{
+		Hours:    1,
+		Requests: []logging.QueryLog{},
+		Summary:  summarizeLocalLogs(nil, 1),
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	for _, field := range []string{`"requests":[]`, `"summary":{`, `"total_cost_usd":0`, `"projected_monthly_cost_usd":0`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("JSON missing %s: %s", field, data)
+		}
+	}
+}
+
+func TestMatchesCaller(t *testing.T) {
+	tests := []struct {
+		caller, filter string
+		want           bool
+	}{
+		{"grove-flow", "", true},
+		{"grove-flow", "grove-flow", true},
+		{"grove-flow", "flow", true},
+		{"grove-flow", "FLOW", true},
+		{"grove-gemini-request", "flow", false},
+	}
+	for _, tt := range tests {
+		if got := matchesCaller(tt.caller, tt.filter); got != tt.want {
+			t.Errorf("matchesCaller(%q, %q) = %v, want %v", tt.caller, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestGroupLocalLogs(t *testing.T) {
+	logs := []logging.QueryLog{
+		{GitRepo: "grove-flow", Model: "gemini-2.5-pro", Caller: "grove-flow", EstimatedCost: 0.10, TotalTokens: 100},
+		{GitRepo: "grove-gemini", Model: "gemini-2.0-flash", EstimatedCost: 0.50, TotalTokens: 500, IsNewCache: true, CacheCreationCost: 0.05},
+		{Model: "gemini-2.0-flash", Caller: "grove-flow", EstimatedCost: 0.01, TotalTokens: 10},
+		{GitRepo: "grove-flow", Model: "gemini-2.5-pro", EstimatedCost: 0.20, TotalTokens: 200},
+	}
+
+	groups := groupLocalLogs(logs, "repo")
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 repo groups, got %+v", groups)
+	}
+	if groups[0].Key != "grove-gemini" || math.Abs(groups[0].Cost-0.55) > 1e-9 {
+		t.Errorf("most expensive repo should come first with cache creation included, got %+v", groups[0])
+	}
+	if groups[1].Key != "grove-flow" || groups[1].Requests != 2 || groups[1].Tokens != 300 {
+		t.Errorf("unexpected grove-flow group: %+v", groups[1])
+	}
+	if groups[2].Key != "(no repo)" {
+		t.Errorf("logs without a repo should be grouped under (no repo), got %q", groups[2].Key)
+	}
+
+	byCaller := groupLocalLogs(logs, "caller")
+	if len(byCaller) != 2 || byCaller[0].Key != "(no caller)" || byCaller[1].Key != "grove-flow" {
+		t.Errorf("unexpected caller groups: %+v", byCaller)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/analytics"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsExportPromHours int
+	metricsExportPromServe string
+)
+
+func newQueryMetricsExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics-export",
+		Short: "Export local query logs as Prometheus metrics",
+		Long: `Emit Prometheus text-format metrics computed from the local query logs: request
+counts, tokens by type, estimated cost and cache hit rate, labeled by model,
+caller and success. These are the same request series 'metrics export' writes. Unlike 'query metrics', which queries Cloud Monitoring, this
+only reads local data.
+
+With --serve, run an HTTP endpoint that serves /metrics until interrupted. Each
+scrape recomputes the metrics from the last --hours of logs.
+
+Examples:
+  # Print metrics for the last day
+  grove-gemini query metrics-export
+
+  # Serve /metrics for Prometheus to scrape
+  grove-gemini query metrics-export --serve :9090`,
+		RunE: runQueryMetricsExport,
+	}
+
+	cmd.Flags().IntVarP(&metricsExportPromHours, "hours", "H", 24, "Number of hours of query logs to aggregate")
+	cmd.Flags().StringVar(&metricsExportPromServe, "serve", "", "Serve /metrics on this address (e.g. :9090) instead of printing once")
+
+	return cmd
+}
+
+func runQueryMetricsExport(cmd *cobra.Command, args []string) error {
+	if metricsExportPromHours <= 0 {
+		return fmt.Errorf("--hours must be positive")
+	}
+	window := time.Duration(metricsExportPromHours) * time.Hour
+	readLogs := logging.GetLogger().ReadLogs
+
+	if metricsExportPromServe == "" {
+		data, err := renderPrometheusMetrics(readLogs, window, time.Now())
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prometheusMetricsHandler(readLogs, window))
+	server := &http.Server{
+		Addr:              metricsExportPromServe,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	ulog.Info("Serving metrics").
+		Field("address", metricsExportPromServe).
+		Pretty(fmt.Sprintf("Serving Prometheus metrics on %s/metrics (Ctrl+C to stop)", metricsExportPromServe)).
+		PrettyOnly().
+		Log(ctx)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving metrics: %w", err)
+	}
+	return nil
+}
+
+// renderPrometheusMetrics reads the logs in the window ending at now and renders them as metrics
+func renderPrometheusMetrics(readLogs func(startTime, endTime time.Time) ([]logging.QueryLog, error), window time.Duration, now time.Time) ([]byte, error) {
+	logs, err := readLogs(now.Add(-window), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := analytics.WritePrometheusMetrics(&buf, logs); err != nil {
+		return nil, fmt.Errorf("writing metrics: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// prometheusMetricsHandler serves metrics recomputed from the logs on every scrape
+func prometheusMetricsHandler(readLogs func(startTime, endTime time.Time) ([]logging.QueryLog, error), window time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := renderPrometheusMetrics(readLogs, window, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", analytics.PrometheusContentType)
+		_, _ = w.Write(data)
+	})
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/analytics"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestPrometheusMetricsHandler(t *testing.T) {
+	var gotStart, gotEnd time.Time
+	readLogs := func(startTime, endTime time.Time) ([]logging.QueryLog, error) {
+		gotStart, gotEnd = startTime, endTime
+		return []logging.QueryLog{{Model: "gemini-2.0-flash", Caller: "grove-gemini-request", Success: true, PromptTokens: 10}}, nil
+	}
+
+	rec := httptest.NewRecorder()
+	prometheusMetricsHandler(readLogs, 6*time.Hour).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != analytics.PrometheusContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `grove_gemini_requests{model="gemini-2.0-flash",caller="grove-gemini-request",success="true"} 1`) {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+	if window := gotEnd.Sub(gotStart); window != 6*time.Hour {
+		t.Errorf("read logs over %s, want 6h", window)
+	}
+}
+
+func TestPrometheusMetricsHandlerReadError(t *testing.T) {
+	readLogs := func(time.Time, time.Time) ([]logging.QueryLog, error) {
+		return nil, errors.New("disk gone")
+	}
+	rec := httptest.NewRecorder()
+	prometheusMetricsHandler(readLogs, time.Hour).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "disk gone") {
+		t.Errorf("got %d %q, want a 500 with the read error", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// queryTimeLayouts are the formats accepted by --from and --to
+var queryTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"}
+
+// maxQueryRange bounds a --from/--to range; logs are read one file per day
+const maxQueryRange = 366 * 24 * time.Hour
+
+// parseQueryTime parses a --from/--to value in local time. A bare date given
+// with endOfDay set resolves to the last instant of that day.
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	for _, layout := range queryTimeLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			continue
+		}
+		if layout == "2006-01-02" && endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use 2006-01-02, \"2006-01-02 15:04\" or RFC 3339", value)
+}
+
+// resolveQueryRange returns the time range selected by --hours or --from/--to.
+// --from overrides --hours, and --to defaults to now.
+func resolveQueryRange(hours int, from, to string, now time.Time) (time.Time, time.Time, error) {
+	if from == "" {
+		if to != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--to requires --from")
+		}
+		if hours <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("--hours must be positive")
+		}
+		return now.Add(-time.Duration(hours) * time.Hour), now, nil
+	}
+
+	start, err := parseQueryTime(from, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from: %w", err)
+	}
+	if start.After(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from is in the future")
+	}
+	end := now
+	if to != "" {
+		if end, err = parseQueryTime(to, true); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("--to: %w", err)
+		}
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to must be after --from")
+	}
+	if end.Sub(start) > maxQueryRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("time range is longer than %d days", int(maxQueryRange.Hours()/24))
+	}
+	return start, end, nil
+}
+
+// queryRangeHours returns the length of a range in whole hours, rounded up
+func queryRangeHours(start, end time.Time) int {
+	return int(math.Ceil(end.Sub(start).Hours()))
+}
+
+// describeQueryRange phrases a range for progress messages, e.g. "the last 24 hour(s)"
+func describeQueryRange(hours int, from string, start, end time.Time) string {
+	if from == "" {
+		return fmt.Sprintf("the last %d hour(s)", hours)
+	}
+	return fmt.Sprintf("%s to %s", start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+}
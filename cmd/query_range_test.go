@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveQueryRange(t *testing.T) {
+	now := time.Date(2025, 6, 15, 10, 0, 0, 0, time.Local)
+
+	start, end, err := resolveQueryRange(24, "", "", now)
+	if err != nil || !end.Equal(now) || !start.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("hours range = %v..%v (%v)", start, end, err)
+	}
+
+	start, end, err = resolveQueryRange(24, "2025-06-01", "2025-06-02", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !start.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("start = %v", start)
+	}
+	if end.Day() != 2 || end.Hour() != 23 || end.Minute() != 59 {
+		t.Errorf("a --to date should include the whole day, got %v", end)
+	}
+
+	if _, end, _ = resolveQueryRange(24, "2025-06-01 08:00", "", now); !end.Equal(now) {
+		t.Errorf("--to should default to now, got %v", end)
+	}
+
+	for _, tc := range []struct{ from, to string }{
+		{"", "2025-06-02"},
+		{"yesterday", ""},
+		{"2025-06-02", "2025-06-01"},
+		{"2025-06-01 08:00", "2025-06-01 08:00"},
+		{"2025-07-01", ""},
+		{"2024-01-01", "2025-06-01"},
+	} {
+		if _, _, err := resolveQueryRange(24, tc.from, tc.to, now); err == nil {
+			t.Errorf("expected an error for --from %q --to %q", tc.from, tc.to)
+		}
+	}
+}
+
+func TestQueryRangeHours(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	if got := queryRangeHours(start, start.Add(90*time.Minute)); got != 2 {
+		t.Errorf("queryRangeHours = %d, want 2", got)
+	}
+	if got := queryRangeHours(start, start.AddDate(0, 0, 1).Add(-time.Nanosecond)); got != 24 {
+		t.Errorf("queryRangeHours for a whole day = %d, want 24", got)
+	}
+}
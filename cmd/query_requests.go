@@ -9,13 +9,17 @@ import (
 
 	"github.com/grovetools/core/tui/theme"
 	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 	"github.com/spf13/cobra"
 )
 
 var (
 	requestsHours  int
+	requestsFrom   string
+	requestsTo     string
 	requestsLimit  int
 	requestsModel  string
+	requestsCaller string
 	requestsErrors bool
 )
 
@@ -25,13 +29,24 @@ func newQueryRequestsCmd() *cobra.Command {
 		Short: "Query individual Gemini API requests from local logs",
 		Long: `Displays a table of individual Gemini API requests with details like timestamp, method, tokens, latency, and status.
 
-This command reads from local logs since Google doesn't publish individual Gemini API requests to Cloud Logging.`,
+This command reads from local logs since Google doesn't publish individual Gemini API requests to Cloud Logging.
+
+The time range is either the last --hours, or --from/--to. Both accept a date
+(2006-01-02), a date and time (2006-01-02 15:04) or an RFC 3339 timestamp; a
+date given to --to includes that whole day. --to defaults to now.
+
+Examples:
+  # Requests during an incident
+  grove-gemini query requests --from "2025-06-01 14:00" --to "2025-06-01 16:30" --errors`,
 		RunE: runQueryRequests,
 	}
 
-	cmd.Flags().IntVarP(&requestsHours, "hours", "H", 1, "Number of hours to look back")
+	cmd.Flags().IntVarP(&requestsHours, "hours", "H", 1, "Number of hours to look back (ignored with --from)")
+	cmd.Flags().StringVar(&requestsFrom, "from", "", "Start of the time range")
+	cmd.Flags().StringVar(&requestsTo, "to", "", "End of the time range (requires --from)")
 	cmd.Flags().IntVarP(&requestsLimit, "limit", "l", 100, "Maximum number of requests to display")
 	cmd.Flags().StringVarP(&requestsModel, "model", "m", "", "Filter by model name")
+	cmd.Flags().StringVar(&requestsCaller, "caller", "", "Filter by caller (e.g. grove-flow); matches exactly or as a substring")
 	cmd.Flags().BoolVar(&requestsErrors, "errors", false, "Show only failed requests")
 
 	return cmd
@@ -41,14 +56,17 @@ func runQueryRequests(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	logger := logging.GetLogger()
 
-	endTime := time.Now()
-	startTime := endTime.Add(-time.Duration(requestsHours) * time.Hour)
+	startTime, endTime, err := resolveQueryRange(requestsHours, requestsFrom, requestsTo, time.Now())
+	if err != nil {
+		return err
+	}
+	hours := queryRangeHours(startTime, endTime)
 
 	ulog.Info("Fetching Gemini API requests").
-		Field("hours", requestsHours).
+		Field("hours", hours).
 		Field("start_time", startTime).
 		Field("end_time", endTime).
-		Pretty(fmt.Sprintf("Fetching Gemini API requests for the last %d hour(s)...\n", requestsHours)).
+		Pretty(fmt.Sprintf("Fetching Gemini API requests for %s...\n", describeQueryRange(requestsHours, requestsFrom, startTime, endTime))).
 		PrettyOnly().
 		Log(ctx)
 
@@ -59,7 +77,7 @@ func runQueryRequests(cmd *cobra.Command, args []string) error {
 
 	if len(logs) == 0 {
 		ulog.Info("No requests found").
-			Field("time_range_hours", requestsHours).
+			Field("time_range_hours", hours).
 			Pretty("No requests found for the specified time range.\n\nNote: This command reads from local logs. Make sure you have made some Gemini API calls.").
 			PrettyOnly().
 			Log(ctx)
@@ -74,6 +92,11 @@ func runQueryRequests(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Filter by caller if specified
+		if !matchesCaller(log.Caller, requestsCaller) {
+			continue
+		}
+
 		// Filter by errors if specified
 		if requestsErrors && log.Success {
 			continue
@@ -169,15 +192,15 @@ func displayRequestsTable(logs []logging.QueryLog) {
 			caller = caller[:13] + ".."
 		}
 
-		row := fmt.Sprintf("%-20s %-15s %-8s %-10d %-10d %-10d %-8.2fs %-10s %-30s %-15s %s\n",
+		row := fmt.Sprintf("%-20s %-15s %-8s %-10s %-10s %-10s %-8.2fs %-10s %-30s %-15s %s\n",
 			timestamp,
 			model,
 			method,
-			log.PromptTokens,
-			log.CompletionTokens,
-			log.TotalTokens,
+			pretty.FormatTokens(log.PromptTokens),
+			pretty.FormatTokens(log.CompletionTokens),
+			pretty.FormatTokens(log.TotalTokens),
 			log.ResponseTime,
-			fmt.Sprintf("$%.6f", log.EstimatedCost),
+			pretty.FormatCost(log.EstimatedCost),
 			repoInfo,
 			caller,
 			status)
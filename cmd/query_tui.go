@@ -3,10 +3,13 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/grovetools/core/config"
@@ -15,6 +18,7 @@ import (
 	"github.com/grovetools/core/tui/theme"
 	"github.com/grovetools/grove-gemini/pkg/analytics"
 	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 )
 
 // queryTuiKeyMap extends the base keymap with custom keybindings
@@ -24,20 +28,25 @@ type queryTuiKeyMap struct {
 	WeeklyView   key.Binding
 	MonthlyView  key.Binding
 	ToggleMetric key.Binding
+	Histogram    key.Binding
 	PrevPeriod   key.Binding
 	NextPeriod   key.Binding
+	Inspect      key.Binding
+	SortCost     key.Binding
+	SortTime     key.Binding
+	SortTokens   key.Binding
 }
 
 // ShortHelp returns the short help keybindings
 func (k queryTuiKeyMap) ShortHelp() []key.Binding {
 	baseHelp := k.Base.ShortHelp()
-	return append(baseHelp, k.DailyView, k.WeeklyView, k.MonthlyView, k.ToggleMetric, k.PrevPeriod, k.NextPeriod)
+	return append(baseHelp, k.DailyView, k.WeeklyView, k.MonthlyView, k.ToggleMetric, k.Histogram, k.PrevPeriod, k.NextPeriod, k.Inspect, k.SortCost, k.SortTime, k.SortTokens)
 }
 
 // FullHelp returns the full help keybindings
 func (k queryTuiKeyMap) FullHelp() [][]key.Binding {
 	baseHelp := k.Base.FullHelp()
-	customKeys := []key.Binding{k.DailyView, k.WeeklyView, k.MonthlyView, k.ToggleMetric, k.PrevPeriod, k.NextPeriod}
+	customKeys := []key.Binding{k.DailyView, k.WeeklyView, k.MonthlyView, k.ToggleMetric, k.Histogram, k.PrevPeriod, k.NextPeriod, k.Inspect, k.SortCost, k.SortTime, k.SortTokens}
 	return append(baseHelp, customKeys)
 }
 
@@ -60,7 +69,15 @@ func (k queryTuiKeyMap) Sections() []keymap.Section {
 		},
 		{
 			Name:     "Display",
-			Bindings: []key.Binding{k.ToggleMetric},
+			Bindings: []key.Binding{k.ToggleMetric, k.Histogram, k.Inspect},
+		},
+		{
+			Name:     "Sort",
+			Bindings: []key.Binding{k.SortCost, k.SortTime, k.SortTokens},
+		},
+		{
+			Name:     "Filter",
+			Bindings: []key.Binding{k.Search},
 		},
 		k.Base.SystemSection(),
 	}
@@ -69,7 +86,8 @@ func (k queryTuiKeyMap) Sections() []keymap.Section {
 // Main model for the TUI
 type queryTuiModel struct {
 	isLoading  bool
-	logs       []logging.QueryLog
+	allLogs    []logging.QueryLog // Every log in the current period
+	logs       []logging.QueryLog // allLogs matching the caller filter, in table order
 	buckets    []analytics.Bucket
 	totals     analytics.Totals
 	timeFrame  time.Duration
@@ -77,11 +95,21 @@ type queryTuiModel struct {
 	table      table.Model
 	plot       PlotModel
 	plotMetric string // "cost" or "tokens"
+	histogram  bool   // Show the latency histogram instead of the timeline
+	sortColumn string // "cost", "time" or "tokens"; empty sorts by timestamp, newest first
+	sortAsc    bool
 	keys       queryTuiKeyMap
 	help       help.Model
 	err        error
 	width      int
 	height     int
+
+	// Detail view for the selected request
+	inspecting      bool
+	inspectViewport viewport.Model
+
+	// Caller filter, focused with '/'
+	filterInput textinput.Model
 }
 
 // Message for when logs are loaded
@@ -125,6 +153,10 @@ func newQueryTuiKeyMap(cfg *config.Config) queryTuiKeyMap {
 			key.WithKeys("t"),
 			key.WithHelp("t", "toggle metric"),
 		),
+		Histogram: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "latency histogram"),
+		),
 		PrevPeriod: key.NewBinding(
 			key.WithKeys("left", "h"),
 			key.WithHelp("←/h", "previous period"),
@@ -133,6 +165,22 @@ func newQueryTuiKeyMap(cfg *config.Config) queryTuiKeyMap {
 			key.WithKeys("right", "l"),
 			key.WithHelp("→/l", "next period"),
 		),
+		Inspect: key.NewBinding(
+			key.WithKeys("enter", "i"),
+			key.WithHelp("enter", "inspect request"),
+		),
+		SortCost: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "sort by cost"),
+		),
+		SortTime: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "sort by response time"),
+		),
+		SortTokens: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "sort by tokens"),
+		),
 	}
 
 	// Apply TUI-specific overrides from config
@@ -161,14 +209,40 @@ func initialModel() queryTuiModel {
 	keys := newQueryTuiKeyMap(cfg)
 	helpModel := help.New(keys)
 
-	return queryTuiModel{
-		isLoading:  true,
-		timeFrame:  24 * time.Hour,
-		plotMetric: "cost",
-		table:      tbl,
-		keys:       keys,
-		help:       helpModel,
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "Filter by caller..."
+	ti.CharLimit = 100
+	ti.Width = 30
+
+	m := queryTuiModel{
+		isLoading:       true,
+		timeFrame:       24 * time.Hour,
+		plotMetric:      "cost",
+		table:           tbl,
+		keys:            keys,
+		help:            helpModel,
+		inspectViewport: viewport.New(80, 20),
+		filterInput:     ti,
+	}
+
+	// Start in the view selected last time
+	state := loadTUIState()
+	if frame, ok := timeFrameFromName(state.QueryTimeFrame); ok && frame <= 30*24*time.Hour {
+		m.timeFrame = frame
+	}
+	if state.QueryMetric == "cost" || state.QueryMetric == "tokens" {
+		m.plotMetric = state.QueryMetric
 	}
+	return m
+}
+
+// saveView remembers the current time frame and metric for the next run
+func (m queryTuiModel) saveView() {
+	saveTUIState(func(s *tuiState) {
+		s.QueryTimeFrame = timeFrameNames[m.timeFrame]
+		s.QueryMetric = m.plotMetric
+	})
 }
 
 func (m queryTuiModel) Init() tea.Cmd {
@@ -194,26 +268,66 @@ func (m queryTuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// While the filter is focused, keys edit it; enter keeps it and esc clears it
+		if m.filterInput.Focused() {
+			switch {
+			case msg.Type == tea.KeyEnter:
+				m.filterInput.Blur()
+			case key.Matches(msg, m.keys.Back):
+				m.filterInput.SetValue("")
+				m.filterInput.Blur()
+				m.applyFilter()
+			default:
+				m.filterInput, cmd = m.filterInput.Update(msg)
+				m.applyFilter()
+				return m, cmd
+			}
+			return m, nil
+		}
+
+		// The detail view scrolls with the remaining keys until closed
+		if m.inspecting {
+			if key.Matches(msg, m.keys.Back) || key.Matches(msg, m.keys.Quit) {
+				m.inspecting = false
+				return m, nil
+			}
+			m.inspectViewport, cmd = m.inspectViewport.Update(msg)
+			return m, cmd
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
+		case key.Matches(msg, m.keys.Inspect):
+			if cursor := m.table.Cursor(); cursor >= 0 && cursor < len(m.logs) {
+				m.inspectViewport.SetContent(renderQueryLogDetails(m.logs[cursor]))
+				m.inspectViewport.GotoTop()
+				m.inspecting = true
+			}
+			return m, nil
 		case key.Matches(msg, m.keys.Help):
 			m.help.Toggle()
 			return m, nil
+		case key.Matches(msg, m.keys.Search):
+			m.filterInput.Focus()
+			return m, textinput.Blink
 		case key.Matches(msg, m.keys.DailyView):
 			m.timeFrame = 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadLogsCmd(m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.WeeklyView):
 			m.timeFrame = 7 * 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadLogsCmd(m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.MonthlyView):
 			m.timeFrame = 30 * 24 * time.Hour
 			m.timeOffset = 0 // Reset to current period
 			m.isLoading = true
+			m.saveView()
 			return m, loadLogsCmd(m.timeFrame, m.timeOffset)
 		case key.Matches(msg, m.keys.PrevPeriod):
 			m.timeOffset++
@@ -232,11 +346,21 @@ func (m queryTuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.plotMetric = "cost"
 			}
-			plotHeight := m.plot.Height
-			if plotHeight == 0 {
-				plotHeight = 10
-			}
-			m.plot = NewPlot(m.buckets, m.plotMetric, m.timeFrame, m.width, plotHeight)
+			m.saveView()
+			m.plot = m.newPlot()
+			return m, nil
+		case key.Matches(msg, m.keys.Histogram):
+			m.histogram = !m.histogram
+			m.plot = m.newPlot()
+			return m, nil
+		case key.Matches(msg, m.keys.SortCost):
+			m.toggleSort("cost")
+			return m, nil
+		case key.Matches(msg, m.keys.SortTime):
+			m.toggleSort("time")
+			return m, nil
+		case key.Matches(msg, m.keys.SortTokens):
+			m.toggleSort("tokens")
 			return m, nil
 		}
 	case tea.WindowSizeMsg:
@@ -256,6 +380,8 @@ func (m queryTuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.plot.Height = plotHeight
 		m.table.SetHeight(tableHeight)
+		m.inspectViewport.Width = m.width
+		m.inspectViewport.Height = m.height - titleHeight - footerHeight - 1
 		return m, nil
 	case logsLoadedMsg:
 		m.isLoading = false
@@ -263,60 +389,127 @@ func (m queryTuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			return m, nil
 		}
-		m.logs = msg.logs
+		m.allLogs = msg.logs
+		m.applyFilter()
+		return m, nil
+	}
 
-		// Sort logs newest-first so the most recent entries render at top of table
-		sort.Slice(m.logs, func(i, j int) bool {
-			return m.logs[i].Timestamp.After(m.logs[j].Timestamp)
-		})
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
 
-		// Aggregate logs - use same time range as loadLogsCmd
-		endTime := time.Now().Add(-time.Duration(m.timeOffset) * m.timeFrame)
-		startTime := endTime.Add(-m.timeFrame)
-
-		// Calculate bucket size based on time frame
-		// Daily view: 20-minute buckets (3x more granular)
-		// Weekly/Monthly: Keep original granularity
-		var bucketSize time.Duration
-		if m.timeFrame == 24*time.Hour {
-			bucketSize = m.timeFrame / 72 // 20-minute buckets for daily view
-		} else {
-			bucketSize = m.timeFrame / 24 // Original granularity for weekly/monthly
+// applyFilter narrows the loaded logs to those matching the caller filter and
+// rebuilds the totals, plot and table from them
+func (m *queryTuiModel) applyFilter() {
+	filter := strings.TrimSpace(m.filterInput.Value())
+	m.logs = make([]logging.QueryLog, 0, len(m.allLogs))
+	for _, log := range m.allLogs {
+		if matchesCaller(log.Caller, filter) {
+			m.logs = append(m.logs, log)
 		}
+	}
+	m.sortLogs()
 
-		m.buckets = analytics.AggregateLogs(m.logs, bucketSize, startTime, endTime)
-		m.totals = analytics.CalculateTotals(m.buckets)
+	// Aggregate logs - use same time range as loadLogsCmd
+	endTime := time.Now().Add(-time.Duration(m.timeOffset) * m.timeFrame)
+	startTime := endTime.Add(-m.timeFrame)
 
-		// Create plot with current dimensions
-		plotHeight := m.plot.Height
-		if plotHeight == 0 {
-			plotHeight = 10 // Default height
+	// Calculate bucket size based on time frame
+	// Daily view: 20-minute buckets (3x more granular)
+	// Weekly/Monthly: Keep original granularity
+	var bucketSize time.Duration
+	if m.timeFrame == 24*time.Hour {
+		bucketSize = m.timeFrame / 72 // 20-minute buckets for daily view
+	} else {
+		bucketSize = m.timeFrame / 24 // Original granularity for weekly/monthly
+	}
+
+	m.buckets = analytics.AggregateLogs(m.logs, bucketSize, startTime, endTime)
+	m.totals = analytics.CalculateTotals(m.buckets)
+
+	// Create plot with current dimensions
+	m.plot = m.newPlot()
+
+	m.populateTable()
+	if m.table.Cursor() >= len(m.logs) {
+		m.table.SetCursor(max(0, len(m.logs)-1))
+	}
+}
+
+// toggleSort sorts the table by column, flipping the direction when it is
+// already the active column. A new column starts with the largest values first.
+func (m *queryTuiModel) toggleSort(column string) {
+	if m.sortColumn == column {
+		m.sortAsc = !m.sortAsc
+	} else {
+		m.sortColumn = column
+		m.sortAsc = false
+	}
+	m.sortLogs()
+	m.populateTable()
+	m.table.GotoTop()
+}
+
+// sortLogs orders m.logs by the active sort column. Without one the most
+// recent entries come first.
+func (m *queryTuiModel) sortLogs() {
+	var less func(a, b logging.QueryLog) bool
+	switch m.sortColumn {
+	case "cost":
+		less = func(a, b logging.QueryLog) bool { return a.EstimatedCost < b.EstimatedCost }
+	case "time":
+		less = func(a, b logging.QueryLog) bool { return a.ResponseTime < b.ResponseTime }
+	case "tokens":
+		less = func(a, b logging.QueryLog) bool { return a.TotalTokens < b.TotalTokens }
+	default:
+		sort.SliceStable(m.logs, func(i, j int) bool {
+			return m.logs[i].Timestamp.After(m.logs[j].Timestamp)
+		})
+		return
+	}
+	sort.SliceStable(m.logs, func(i, j int) bool {
+		if m.sortAsc {
+			return less(m.logs[i], m.logs[j])
 		}
-		m.plot = NewPlot(m.buckets, m.plotMetric, m.timeFrame, m.width, plotHeight)
-
-		// Populate table
-		var rows []table.Row
-		for _, log := range m.logs {
-			status := "*"
-			if !log.Success {
-				status = "x"
-			}
-			rows = append(rows, table.Row{
-				log.Timestamp.Format("15:04:05"),
-				log.Model,
-				log.Caller,
-				fmt.Sprintf("%d", log.TotalTokens),
-				fmt.Sprintf("$%.4f", log.EstimatedCost),
-				fmt.Sprintf("%.2fs", log.ResponseTime),
-				status,
-			})
+		return less(m.logs[j], m.logs[i])
+	})
+}
+
+// populateTable fills the table rows from m.logs in their current order
+func (m *queryTuiModel) populateTable() {
+	rows := make([]table.Row, 0, len(m.logs))
+	for _, log := range m.logs {
+		status := "*"
+		if !log.Success {
+			status = "x"
 		}
-		m.table.SetRows(rows)
-		return m, nil
+		rows = append(rows, table.Row{
+			log.Timestamp.Format("15:04:05"),
+			log.Model,
+			log.Caller,
+			pretty.FormatTokens(log.TotalTokens),
+			pretty.FormatCost(log.EstimatedCost),
+			fmt.Sprintf("%.2fs", log.ResponseTime),
+			status,
+		})
 	}
+	m.table.SetRows(rows)
+}
 
-	m.table, cmd = m.table.Update(msg)
-	return m, cmd
+// newPlot builds the plot for the loaded logs at the current plot size
+func (m queryTuiModel) newPlot() PlotModel {
+	plotHeight := m.plot.Height
+	if plotHeight == 0 {
+		plotHeight = 10 // Default height
+	}
+	plot := NewPlot(m.buckets, m.plotMetric, m.timeFrame, m.width, plotHeight)
+	plot.Histogram = m.histogram
+	for _, log := range m.logs {
+		if log.Success {
+			plot.Latencies = append(plot.Latencies, log.ResponseTime)
+		}
+	}
+	return plot
 }
 
 func (m queryTuiModel) renderSummaryView() string {
@@ -325,8 +518,11 @@ func (m queryTuiModel) renderSummaryView() string {
 		Foreground(theme.DefaultTheme.Colors.Cyan).
 		Bold(true)
 
-	cost := fmt.Sprintf("%s $%.2f", titleStyle.Render("Cost:"), m.totals.TotalCost)
-	tokens := fmt.Sprintf("%s %dK", titleStyle.Render("Tokens:"), m.totals.TotalTokens/1000)
+	cost := fmt.Sprintf("%s %s", titleStyle.Render("Cost:"), pretty.FormatCost(m.totals.TotalCost))
+	if m.totals.CacheCreationCost > 0 {
+		cost += fmt.Sprintf(" (+%s caching)", pretty.FormatCost(m.totals.CacheCreationCost))
+	}
+	tokens := fmt.Sprintf("%s %s", titleStyle.Render("Tokens:"), pretty.FormatTokensCompact(m.totals.TotalTokens))
 	requests := fmt.Sprintf("%s %d", titleStyle.Render("Requests:"), m.totals.TotalRequests)
 	errors := fmt.Sprintf("%s %.1f%%", titleStyle.Render("Errors:"), m.totals.ErrorRate)
 
@@ -371,6 +567,24 @@ func (m queryTuiModel) View() string {
 	}
 
 	header := titleStyle.Render(fmt.Sprintf("Gemini API Usage - %s View%s", timeFrameLabel, dateRange))
+	if m.sortColumn != "" {
+		arrow := "↓"
+		if m.sortAsc {
+			arrow = "↑"
+		}
+		header += theme.DefaultTheme.Muted.Render(fmt.Sprintf("  sorted by %s %s", m.sortColumn, arrow))
+	}
+	if m.filterInput.Focused() || m.filterInput.Value() != "" {
+		header += "  " + m.filterInput.View()
+	}
+
+	if m.inspecting {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			header,
+			m.inspectViewport.View(),
+			theme.DefaultTheme.Muted.Render("Press esc to return to the table"),
+		)
+	}
 
 	summaryView := m.renderSummaryView()
 	plotView := m.plot.View()
@@ -387,6 +601,93 @@ func (m queryTuiModel) View() string {
 	)
 }
 
+// renderQueryLogDetails renders every field of a query log entry for the inspect view
+func renderQueryLogDetails(log logging.QueryLog) string {
+	var b strings.Builder
+	section := func(title string) {
+		b.WriteString(theme.DefaultTheme.Header.Underline(false).MarginBottom(0).Render(fmt.Sprintf("--- %s ---", title)))
+	}
+	field := func(label, value string) {
+		if value != "" {
+			b.WriteString(fmt.Sprintf("\n%s: %s", label, value))
+		}
+	}
+
+	status := fmt.Sprintf("%s success", theme.IconSuccess)
+	if !log.Success {
+		status = fmt.Sprintf("%s failed", theme.IconWarning)
+	}
+
+	b.WriteString(theme.DefaultTheme.Title.Render(fmt.Sprintf("Details for Request: %s", log.Timestamp.Local().Format(time.RFC1123))))
+	b.WriteString("\n\n")
+
+	section("Request")
+	field("Status", status)
+	field("Request ID", log.RequestID)
+	field("Model", log.Model)
+	field("Method", log.Method)
+	field("Caller", log.Caller)
+	if log.UploadTime > 0 {
+		field("Latency", pretty.FormatLatency(time.Duration(log.UploadTime*float64(time.Second)), time.Duration(log.ResponseTime*float64(time.Second))))
+	} else {
+		field("Response Time", fmt.Sprintf("%.2fs", log.ResponseTime))
+	}
+	field("Estimated Cost", pretty.FormatCost(log.EstimatedCost))
+	if log.ResponseCacheHit {
+		field("Response Cache", "hit (no API call)")
+	}
+	if log.IsNewCache {
+		field("Cache Creation", pretty.FormatCost(log.CacheCreationCost))
+	}
+	if log.CandidateCount > 1 {
+		field("Candidates", fmt.Sprintf("%d", log.CandidateCount))
+	}
+	if log.Continuations > 0 {
+		field("Continuations", fmt.Sprintf("%d", log.Continuations))
+	}
+	b.WriteString("\n\n")
+
+	section("Tokens")
+	field("Prompt", pretty.FormatTokens(log.PromptTokens))
+	if log.UserPromptTokens > 0 {
+		field("User Prompt", pretty.FormatTokens(log.UserPromptTokens))
+	}
+	field("Cached", pretty.FormatTokens(log.CachedTokens))
+	field("Completion", pretty.FormatTokens(log.CompletionTokens))
+	field("Total", pretty.FormatTokens(log.TotalTokens))
+	field("Cache Hit Rate", fmt.Sprintf("%.1f%%", log.CacheHitRate*100))
+	field("Cache ID", log.CacheID)
+	b.WriteString("\n\n")
+
+	section("Context")
+	field("Working Dir", log.WorkingDir)
+	field("Repo", log.GitRepo)
+	field("Branch", log.GitBranch)
+	field("Commit", log.GitCommit)
+	if len(log.Labels) > 0 {
+		keys := make([]string, 0, len(log.Labels))
+		for k := range log.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("\nLabels:")
+		for _, k := range keys {
+			b.WriteString(fmt.Sprintf("\n  %s=%s", k, log.Labels[k]))
+		}
+	}
+	b.WriteString("\n")
+
+	if log.Error != "" {
+		b.WriteString("\n")
+		section("Error")
+		b.WriteString("\n")
+		b.WriteString(log.Error)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
 func runQueryTUI() error {
 	m := initialModel()
 	// Set reasonable default dimensions before first render
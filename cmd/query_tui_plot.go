@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,6 +17,8 @@ type PlotModel struct {
 	TimeFrame time.Duration
 	Width     int
 	Height    int
+	Latencies []float64 // Response times in seconds, used by the histogram
+	Histogram bool      // Render a latency histogram instead of the timeline
 }
 
 func NewPlot(buckets []analytics.Bucket, metric string, timeFrame time.Duration, width, height int) PlotModel {
@@ -23,6 +26,9 @@ func NewPlot(buckets []analytics.Bucket, metric string, timeFrame time.Duration,
 }
 
 func (p PlotModel) View() string {
+	if p.Histogram {
+		return p.renderHistogram()
+	}
 	if len(p.Buckets) == 0 || p.Width < 20 || p.Height < 5 {
 		// Not enough space to render a meaningful chart with axes.
 		return ""
@@ -205,6 +211,14 @@ func (p PlotModel) renderChartWithAxes() string {
 	// X-axis labels and ticks
 	xTicks, xLabels := generateXAxisLabels(p.Buckets, p.TimeFrame, chartWidth)
 
+	return renderBars(barHeights, yLabels, xTicks, xLabels, yAxisWidth, chartHeight)
+}
+
+// renderBars draws a bar chart with a labeled Y axis and an X axis with tick
+// marks. barHeights holds one bar per column, in rows.
+func renderBars(barHeights []int, yLabels map[int]string, xTicks map[int]struct{}, xLabels string, yAxisWidth, chartHeight int) string {
+	chartWidth := len(barHeights)
+
 	// --- Assemble the Chart ---
 	var b strings.Builder
 	plotStyle := lipgloss.NewStyle().Foreground(theme.DefaultTheme.Colors.Cyan)
@@ -251,3 +265,157 @@ func (p PlotModel) renderChartWithAxes() string {
 
 	return b.String()
 }
+
+// latencyBin counts the requests whose response time falls in [Start, End)
+type latencyBin struct {
+	Start float64
+	End   float64
+	Count int
+}
+
+// latencyHistogram splits the range from zero to the slowest response into
+// numBins equal-width bins. The slowest response lands in the last bin.
+func latencyHistogram(latencies []float64, numBins int) []latencyBin {
+	if len(latencies) == 0 || numBins < 1 {
+		return nil
+	}
+
+	maxLatency := 0.0
+	for _, l := range latencies {
+		if l > maxLatency {
+			maxLatency = l
+		}
+	}
+	if maxLatency == 0 {
+		return []latencyBin{{Start: 0, End: 0, Count: len(latencies)}}
+	}
+
+	width := maxLatency / float64(numBins)
+	bins := make([]latencyBin, numBins)
+	for i := range bins {
+		bins[i].Start = float64(i) * width
+		bins[i].End = float64(i+1) * width
+	}
+	for _, l := range latencies {
+		index := int(l / width)
+		if index >= numBins {
+			index = numBins - 1
+		}
+		if index < 0 {
+			index = 0
+		}
+		bins[index].Count++
+	}
+	return bins
+}
+
+// latencyPercentile returns the nearest-rank percentile (0-100) of the given latencies
+func latencyPercentile(latencies []float64, percentile float64) float64 {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), latencies...)
+	sort.Float64s(sorted)
+	rank := int(percentile/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// formatLatency formats a response time in seconds for axis labels
+func formatLatency(seconds float64) string {
+	if seconds < 1 {
+		return fmt.Sprintf("%.0fms", seconds*1000)
+	}
+	if seconds < 10 {
+		return fmt.Sprintf("%.1fs", seconds)
+	}
+	return fmt.Sprintf("%.0fs", seconds)
+}
+
+// renderHistogram draws the distribution of response times, with the
+// p50/p95/p99 latencies on a header line
+func (p PlotModel) renderHistogram() string {
+	const yAxisWidth = 8
+	const headerHeight = 1
+	const xAxisHeight = 2
+
+	if len(p.Latencies) == 0 {
+		return "No requests in this period."
+	}
+	if p.Width < 20 || p.Height < 6 {
+		return ""
+	}
+
+	chartWidth := p.Width - yAxisWidth
+	chartHeight := p.Height - headerHeight - xAxisHeight
+	if chartWidth < 10 || chartHeight < 3 {
+		return "Chart too small to render."
+	}
+
+	// Leave room for a gap column between bins
+	numBins := chartWidth / 3
+	if numBins > 20 {
+		numBins = 20
+	}
+	bins := latencyHistogram(p.Latencies, numBins)
+	colsPerBin := chartWidth / len(bins)
+	chartWidth = colsPerBin * len(bins)
+
+	maxCount := 0
+	for _, bin := range bins {
+		if bin.Count > maxCount {
+			maxCount = bin.Count
+		}
+	}
+
+	barHeights := make([]int, chartWidth)
+	for i, bin := range bins {
+		height := bin.Count * chartHeight / maxCount
+		if bin.Count > 0 && height == 0 {
+			height = 1 // Keep small but non-empty bins visible
+		}
+		for col := 0; col < colsPerBin; col++ {
+			if col == colsPerBin-1 && colsPerBin > 1 {
+				continue
+			}
+			barHeights[i*colsPerBin+col] = height
+		}
+	}
+
+	yLabels := map[int]string{
+		chartHeight - 1: fmt.Sprintf("%d", maxCount),
+		0:               "0",
+	}
+	if chartHeight > 4 && maxCount > 1 {
+		yLabels[chartHeight/2] = fmt.Sprintf("%d", maxCount/2)
+	}
+
+	// Label bin starts from the left, skipping any that would overlap
+	xTicks := make(map[int]struct{})
+	labels := []rune(strings.Repeat(" ", chartWidth))
+	lastLabelEnd := -1
+	for i, bin := range bins {
+		pos := i * colsPerBin
+		label := formatLatency(bin.Start)
+		if pos <= lastLabelEnd || pos+len(label) > chartWidth {
+			continue
+		}
+		copy(labels[pos:], []rune(label))
+		xTicks[pos] = struct{}{}
+		lastLabelEnd = pos + len(label)
+	}
+
+	header := fmt.Sprintf("%sLatency  p50 %s  p95 %s  p99 %s  (%d requests)\n",
+		strings.Repeat(" ", yAxisWidth),
+		formatLatency(latencyPercentile(p.Latencies, 50)),
+		formatLatency(latencyPercentile(p.Latencies, 95)),
+		formatLatency(latencyPercentile(p.Latencies, 99)),
+		len(p.Latencies))
+
+	return header + renderBars(barHeights, yLabels, xTicks, string(labels), yAxisWidth, chartHeight)
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestRenderQueryLogDetails(t *testing.T) {
+	log := logging.QueryLog{
+		Timestamp:        time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC),
+		RequestID:        "req-123",
+		Model:            "gemini-2.5-pro-preview-05-06",
+		Caller:           "grove-flow",
+		PromptTokens:     1200,
+		CachedTokens:     800,
+		CompletionTokens: 300,
+		TotalTokens:      1500,
+		CacheID:          "cachedContents/abc123",
+		GitRepo:          "grove-gemini",
+		GitBranch:        "main",
+		GitCommit:        "deadbeef",
+		Error:            "rpc error: code = ResourceExhausted desc = quota exceeded for project",
+		Labels:           map[string]string{"team": "platform"},
+	}
+
+	details := renderQueryLogDetails(log)
+	for _, want := range []string{
+		"req-123",
+		"gemini-2.5-pro-preview-05-06",
+		"grove-flow",
+		"Completion: 300",
+		"cachedContents/abc123",
+		"Branch: main",
+		"Commit: deadbeef",
+		"team=platform",
+		"quota exceeded for project",
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("Expected details to contain %q", want)
+		}
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	latencies := []float64{0.5, 1.0, 1.2, 3.9, 4.0}
+	bins := latencyHistogram(latencies, 4)
+	if len(bins) != 4 {
+		t.Fatalf("Expected 4 bins, got %d", len(bins))
+	}
+
+	// Bins are one second wide from 0 to the slowest response
+	wantCounts := []int{1, 2, 0, 2}
+	for i, bin := range bins {
+		if bin.Count != wantCounts[i] {
+			t.Errorf("bin %d [%.1f, %.1f): count %d, want %d", i, bin.Start, bin.End, bin.Count, wantCounts[i])
+		}
+	}
+
+	if bins := latencyHistogram(nil, 4); bins != nil {
+		t.Errorf("Expected no bins for no latencies, got %v", bins)
+	}
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, float64(i))
+	}
+	for _, tc := range []struct{ p, want float64 }{{50, 50}, {95, 95}, {99, 99}, {100, 100}} {
+		if got := latencyPercentile(latencies, tc.p); got != tc.want {
+			t.Errorf("p%.0f = %v, want %v", tc.p, got, tc.want)
+		}
+	}
+	if latencies[0] != 100 {
+		t.Error("latencyPercentile must not reorder its input")
+	}
+}
+
+func TestRenderHistogram(t *testing.T) {
+	plot := PlotModel{Width: 60, Height: 12, Histogram: true, Latencies: []float64{0.2, 0.4, 1.5, 2.5}}
+	view := plot.View()
+	for _, want := range []string{"p50 400ms", "p99 2.5s", "(4 requests)", "0ms"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected histogram to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	empty := PlotModel{Width: 60, Height: 12, Histogram: true}
+	if !strings.Contains(empty.View(), "No requests") {
+		t.Errorf("Expected empty-state message, got %q", empty.View())
+	}
+}
+
+func TestQueryTUISort(t *testing.T) {
+	useTempTUIState(t)
+	base := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	m := initialModel()
+	m.logs = []logging.QueryLog{
+		{Timestamp: base, EstimatedCost: 0.02, ResponseTime: 3, TotalTokens: 100},
+		{Timestamp: base.Add(time.Minute), EstimatedCost: 0.50, ResponseTime: 1, TotalTokens: 300},
+		{Timestamp: base.Add(2 * time.Minute), EstimatedCost: 0.10, ResponseTime: 2, TotalTokens: 200},
+	}
+
+	costs := func() []float64 {
+		var out []float64
+		for _, log := range m.logs {
+			out = append(out, log.EstimatedCost)
+		}
+		return out
+	}
+
+	m.toggleSort("cost")
+	if got := costs(); got[0] != 0.50 || got[2] != 0.02 {
+		t.Errorf("cost descending = %v", got)
+	}
+	if row := m.table.Rows()[0]; !strings.Contains(row[4], "0.5") {
+		t.Errorf("table rows not repopulated, first row %v", row)
+	}
+
+	m.toggleSort("cost")
+	if got := costs(); got[0] != 0.02 || got[2] != 0.50 {
+		t.Errorf("cost ascending = %v", got)
+	}
+
+	m.toggleSort("time")
+	if m.sortAsc || m.logs[0].ResponseTime != 3 {
+		t.Errorf("switching column should sort descending, got %v first", m.logs[0].ResponseTime)
+	}
+
+	m.sortColumn = ""
+	m.sortLogs()
+	if !m.logs[0].Timestamp.Equal(base.Add(2 * time.Minute)) {
+		t.Errorf("default order should be newest first, got %v", m.logs[0].Timestamp)
+	}
+}
+
+func TestQueryTUICallerFilter(t *testing.T) {
+	useTempTUIState(t)
+	now := time.Now()
+	m := initialModel()
+	updated, _ := m.Update(logsLoadedMsg{logs: []logging.QueryLog{
+		{Timestamp: now.Add(-time.Hour), Caller: "grove-flow", EstimatedCost: 0.10, Success: true},
+		{Timestamp: now.Add(-2 * time.Hour), Caller: "grove-gemini-request", EstimatedCost: 0.02, Success: true},
+		{Timestamp: now.Add(-3 * time.Hour), Caller: "grove-flow", EstimatedCost: 0.30, Success: true},
+	}})
+	m = updated.(queryTuiModel)
+	if len(m.logs) != 3 {
+		t.Fatalf("expected all 3 logs unfiltered, got %d", len(m.logs))
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "flow" {
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m = updated.(queryTuiModel)
+	if len(m.logs) != 2 || len(m.table.Rows()) != 2 {
+		t.Fatalf("expected 2 grove-flow logs, got %d logs and %d rows", len(m.logs), len(m.table.Rows()))
+	}
+	if m.totals.TotalRequests != 2 {
+		t.Errorf("totals should cover only the filtered logs, got %d requests", m.totals.TotalRequests)
+	}
+
+	// Esc clears the filter
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(queryTuiModel); len(m.logs) != 3 || m.filterInput.Focused() {
+		t.Errorf("esc should clear and close the filter, got %d logs", len(m.logs))
+	}
+}
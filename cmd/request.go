@@ -4,32 +4,80 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/grovetools/grove-gemini/pkg/config"
 	"github.com/grovetools/grove-gemini/pkg/gemini"
 	"github.com/grovetools/grove-gemini/pkg/pretty"
 	"github.com/spf13/cobra"
 )
 
 var (
-	requestModel         string
-	requestPrompt        string
-	requestPromptFile    string
-	requestWorkDir       string
-	requestCacheTTL      string
-	requestNoCache       bool
-	requestRegenerateCtx bool
-	requestRecache       bool
-	requestUseCache      string
-	requestOutputFile    string
-	requestContextFiles  []string
-	requestYes           bool
+	requestModel             string
+	requestAllowUnknown      bool
+	requestPrompt            string
+	requestPromptFile        string
+	requestSystem            string
+	requestSystemFile        string
+	requestPromptDir         string
+	requestTemplate          string
+	requestTemplateVars      []string
+	requestInteractive       bool
+	requestDedup             bool
+	requestPromptPrefix      string
+	requestPromptSuffix      string
+	requestWorkDir           string
+	requestCacheTTL          string
+	requestNoCache           bool
+	requestRegenerateCtx     bool
+	requestRecache           bool
+	requestUseCache          string
+	requestOutputFile        string
+	requestOutputTmpl        string
+	requestAppend            bool
+	requestExtractCode       bool
+	requestExtractLang       string
+	requestExtractStrict     bool
+	requestContextFiles      []string
+	requestAttachments       []string
+	requestContextURLs       []string
+	requestURLTimeout        time.Duration
+	requestTimeout           time.Duration
+	requestResponseCache     bool
+	requestResponseCacheTTL  time.Duration
+	requestYes               bool
+	requestShowCost          bool
+	requestIncludeDiff       bool
+	requestDiffStaged        bool
+	requestLabels            []string
+	requestJSONSchema        string
+	requestResponseJSON      bool
+	requestValidate          bool
+	requestDumpContents      string
+	requestFallbacks         []string
+	requestStream            bool
+	requestMaxRetries        int
+	requestDryRun            bool
+	requestCountTokens       bool
+	requestMinCacheTokens    int
+	requestMinTTL            time.Duration
+	requestAutoExtend        bool
+	requestUploadConcurrency int
+	requestNoUploadCache     bool
+	requestSession           string
 	// Generation parameters
 	requestTemperature     float32
 	requestTopP            float32
 	requestTopK            int32
 	requestMaxOutputTokens int32
+	requestPresencePenalty float32
+	requestFreqPenalty     float32
+	requestCandidates      int32
+	requestStopSequences   []string
+	requestAutoContinue    bool
+	requestMaxContinue     int
 )
 
 func newRequestCmd() *cobra.Command {
@@ -64,28 +112,133 @@ Examples:
   grove-gemini request --use-cache 53f364cda78e82a8 -p "Review using old context"
 
   # With custom working directory
-  grove-gemini request -w /path/to/project -p "Analyze this project"`,
+  grove-gemini request -w /path/to/project -p "Analyze this project"
+
+  # Compose the prompt in $EDITOR (seeded from -f or -p if given)
+  grove-gemini request --interactive
+
+  # Run every prompt in a directory, writing prompts/<name>.response.md for each
+  grove-gemini request --prompt-dir prompts/
+
+  # Same, but answer identical prompts only once
+  grove-gemini request --prompt-dir prompts/ --dedup
+
+  # Hold a conversation across requests
+  grove-gemini request --session chat.json -p "Where is the cache key computed?"
+  grove-gemini request --session chat.json -p "Why is it hashed that way?"
+
+  # Fill in the team's review prompt from .grove/prompts/review.md
+  grove-gemini request --template review --var focus=security --var pr=1234
+
+  # Wrap the prompt with boilerplate instructions
+  grove-gemini request --prompt-prefix "Answer concisely." --prompt-suffix "Cite files." -f prompt.md
+
+  # Return JSON matching a schema, retrying once if it doesn't validate
+  grove-gemini request --json-schema schema.json --validate-response -p "List the packages"
+
+  # Accumulate responses into a running document
+  grove-gemini request --append -o notes.md -p "Summarize today's changes"
+
+  # Write each run to a uniquely named file
+  grove-gemini request --output-template "responses/{date}-{time}-{model}.md" -p "Review the API"
+
+  # Write only the generated Go code, without the markdown around it
+  grove-gemini request --extract-code --extract-lang go -o out.go -p "Write a retry helper"
+
+  # Include a spec published on the web
+  grove-gemini request --context-url https://example.com/spec.md -p "Does our API follow this spec?"
+
+  # Ask about a screenshot and a design document
+  grove-gemini request --attach screenshot.png --attach design.pdf -p "Does the UI match the design?"
+
+  # Review the current uncommitted changes
+  grove-gemini request --include-diff -p "Review this diff for bugs"
+
+  # Attribute usage to a team
+  grove-gemini request --label team=platform --label project=search -p "Summarize the design"
+
+  # Generate three alternative responses
+  grove-gemini request --candidates 3 -p "Suggest names for this package"
+
+  # See which files, cache and cost a request would use without sending it
+  grove-gemini request --dry-run -p "Review the API"
+
+  # Print the response as it is generated
+  grove-gemini request --stream -p "Explain the cache layout"
+
+  # Fall back to other models if the primary is overloaded
+  grove-gemini request -m gemini-2.5-pro --fallback-model gemini-2.5-flash --fallback-model gemini-2.0-flash -p "Review this"
+
+  # Print only a one-line cost summary to stderr (or set GROVE_GEMINI_COST_ONLY=1)
+  grove-gemini request --show-cost -p "Summarize the changes"`,
 		RunE: runRequest,
 	}
 
-	cmd.Flags().StringVarP(&requestModel, "model", "m", "gemini-2.0-flash", "Gemini model to use")
+	cmd.Flags().StringVarP(&requestModel, "model", "m", config.DefaultRequestModel, "Gemini model to use (defaults to gemini.default_model in grove.yml if set)")
 	cmd.Flags().StringVarP(&requestPrompt, "prompt", "p", "", "Prompt text")
 	cmd.Flags().StringVarP(&requestPromptFile, "file", "f", "", "Read prompt from file")
+	cmd.Flags().StringVar(&requestSession, "session", "", "Session file holding the conversation; earlier turns are sent with the prompt and the new exchange is appended")
+	cmd.Flags().StringVar(&requestSystem, "system", "", "System instruction sent separately from the prompt")
+	cmd.Flags().StringVar(&requestSystemFile, "system-file", "", "Read the system instruction from a file")
+	cmd.Flags().BoolVarP(&requestInteractive, "interactive", "i", false, "Compose the prompt in $EDITOR; -p or -f seeds the buffer")
+	cmd.Flags().StringVar(&requestTemplate, "template", "", "Use the prompt template with this name from .grove/prompts (-p/-f/argument text is available as {{input}})")
+	cmd.Flags().StringArrayVar(&requestTemplateVars, "var", nil, "Set a {{key}} placeholder in --template as key=value (repeatable)")
+	cmd.Flags().StringVar(&requestPromptDir, "prompt-dir", "", "Run each file in this directory as a separate prompt, writing <name>.response.md next to it")
+	cmd.Flags().BoolVar(&requestDedup, "dedup", false, "With --prompt-dir, reuse the response for identical prompts instead of calling the API again")
+	cmd.Flags().StringVar(&requestPromptPrefix, "prompt-prefix", "", "Text to place before the prompt (defaults to gemini.prompt_prefix in grove.yml)")
+	cmd.Flags().StringVar(&requestPromptSuffix, "prompt-suffix", "", "Text to place after the prompt (defaults to gemini.prompt_suffix in grove.yml)")
 	cmd.Flags().StringVarP(&requestWorkDir, "workdir", "w", "", "Working directory (defaults to current)")
 	cmd.Flags().StringVar(&requestCacheTTL, "cache-ttl", "5m", "Cache TTL (e.g., 1h, 30m, 24h)")
 	cmd.Flags().BoolVar(&requestNoCache, "no-cache", false, "Disable context caching")
 	cmd.Flags().BoolVar(&requestRegenerateCtx, "regenerate", false, "Regenerate context before request")
 	cmd.Flags().BoolVar(&requestRecache, "recache", false, "Force recreation of the Gemini cache")
-	cmd.Flags().StringVar(&requestUseCache, "use-cache", "", "Specify a cache name (short hash) to use for this request, bypassing automatic selection")
+	cmd.Flags().DurationVar(&requestMinTTL, "min-ttl", gemini.DefaultMinCacheTTL, "Warn when a reused cache expires sooner than this")
+	cmd.Flags().BoolVar(&requestAutoExtend, "auto-extend", false, "Extend the TTL of a reused cache that expires within --min-ttl instead of only warning")
+	cmd.Flags().StringVar(&requestUseCache, "use-cache", "", "Specify a cache by short hash or the name given with cache create --cache-name, bypassing automatic selection")
 	cmd.Flags().StringVarP(&requestOutputFile, "output", "o", "", "Write response to file instead of stdout")
-	cmd.Flags().StringSliceVar(&requestContextFiles, "context", nil, "Additional context files to include")
+	cmd.Flags().StringVar(&requestOutputTmpl, "output-template", "", "Write response to a file named from a template with {date}, {time} and {model} placeholders")
+	cmd.Flags().BoolVar(&requestAppend, "append", false, "Append the response to the output file with a timestamped separator instead of overwriting")
+	cmd.Flags().BoolVar(&requestExtractCode, "extract-code", false, "Output only the fenced code blocks of the response, joined by blank lines")
+	cmd.Flags().StringVar(&requestExtractLang, "extract-lang", "", "With --extract-code, keep only blocks with this language tag (e.g. go)")
+	cmd.Flags().BoolVar(&requestExtractStrict, "extract-strict", false, "With --extract-code, fail unless exactly one code block matches")
+	cmd.Flags().StringSliceVar(&requestContextFiles, "context", nil, "Additional context files to include (text, images or PDFs)")
+	cmd.Flags().StringArrayVar(&requestAttachments, "attach", nil, "Attach an image (PNG, JPEG, WebP, HEIC, HEIF) or PDF to the request (repeatable)")
+	cmd.Flags().StringArrayVar(&requestContextURLs, "context-url", nil, "Fetch a text document over HTTP(S) and include it as context (repeatable)")
+	cmd.Flags().DurationVar(&requestURLTimeout, "context-url-timeout", gemini.DefaultContextURLTimeout, "Timeout for fetching each --context-url")
+	cmd.Flags().BoolVar(&requestIncludeDiff, "include-diff", false, "Include the current git diff as dynamic context")
+	cmd.Flags().BoolVar(&requestDiffStaged, "staged", false, "With --include-diff, use staged changes (git diff --staged)")
+	cmd.Flags().DurationVar(&requestTimeout, "timeout", 0, "Cancel the request if no response arrives within this duration, e.g. 5m (default from gemini.timeout, otherwise none)")
+	cmd.Flags().BoolVar(&requestResponseCache, "response-cache", false, "Reuse the saved response of an identical earlier request instead of calling the API")
+	cmd.Flags().DurationVar(&requestResponseCacheTTL, "response-cache-ttl", gemini.DefaultResponseCacheTTL, "How long a saved response is reused with --response-cache")
 	cmd.Flags().BoolVarP(&requestYes, "yes", "y", false, "Skip cache creation confirmation prompt")
+	cmd.Flags().StringArrayVar(&requestLabels, "label", nil, "Attach a key=value label to the request (repeatable)")
+	cmd.Flags().BoolVar(&requestShowCost, "show-cost", false, "Print a single cost line to stderr instead of the token usage box")
+	cmd.Flags().StringVar(&requestJSONSchema, "json-schema", "", "Constrain the response to JSON matching the JSON Schema in this file")
+	cmd.Flags().BoolVar(&requestResponseJSON, "response-json", false, "Ask for a JSON response and fail if the output doesn't parse as JSON")
+	cmd.Flags().StringVar(&requestJSONSchema, "response-schema-file", "", "Like --json-schema, and also fail if the output doesn't parse as JSON")
+	cmd.Flags().BoolVar(&requestValidate, "validate-response", false, "With --json-schema, validate the response and retry once if it doesn't conform")
+	cmd.Flags().BoolVar(&requestAllowUnknown, "allow-unknown-model", false, "Send --model even if it isn't a known model (for newly released models)")
+	cmd.Flags().StringArrayVar(&requestFallbacks, "fallback-model", nil, "Model to try if the previous one is unavailable (repeatable, tried in order)")
+	cmd.Flags().IntVar(&requestUploadConcurrency, "upload-concurrency", gemini.DefaultUploadConcurrency, "Number of context files uploaded at once")
+	cmd.Flags().BoolVar(&requestNoUploadCache, "no-upload-cache", false, "Always upload context files instead of reusing earlier uploads of unchanged files")
+	cmd.Flags().IntVar(&requestMaxRetries, "max-retries", gemini.DefaultMaxRetries, "Retry rate-limited (429) or failed (500/503) requests up to this many times with backoff (0 to disable)")
+	cmd.Flags().BoolVar(&requestStream, "stream", false, "Print the response to stdout as it is generated")
+	cmd.Flags().BoolVar(&requestCountTokens, "count-cache-tokens", false, "Count cold context tokens with the API before deciding whether to cache (costs a round trip)")
+	cmd.Flags().IntVar(&requestMinCacheTokens, "min-cache-tokens", 0, "Smallest cold context in tokens worth caching (default gemini.min_cache_tokens or 4096)")
+	cmd.Flags().BoolVar(&requestDryRun, "dry-run", false, "Show attached files, the cache decision and estimated tokens and cost without calling the API")
+	cmd.Flags().StringVar(&requestDumpContents, "dump-contents", "", "Write each assembled request part and an index.json to this directory for debugging")
 
 	// Generation parameters
 	cmd.Flags().Float32Var(&requestTemperature, "temperature", -1, "Temperature for randomness (0.0-2.0, -1 to use default)")
 	cmd.Flags().Float32Var(&requestTopP, "top-p", -1, "Top-p nucleus sampling (0.0-1.0, -1 to use default)")
 	cmd.Flags().Int32Var(&requestTopK, "top-k", -1, "Top-k sampling (-1 to use default)")
 	cmd.Flags().Int32Var(&requestMaxOutputTokens, "max-output-tokens", -1, "Maximum tokens in response (-1 to use default)")
+	cmd.Flags().Float32Var(&requestPresencePenalty, "presence-penalty", 0, "Penalize tokens that already appeared, encouraging new topics (model support varies)")
+	cmd.Flags().Float32Var(&requestFreqPenalty, "frequency-penalty", 0, "Penalize tokens by how often they appeared, reducing repetition (model support varies)")
+	cmd.Flags().Int32Var(&requestCandidates, "candidates", 1, "Number of alternative responses to generate")
+	cmd.Flags().BoolVar(&requestAutoContinue, "auto-continue", false, "Ask the model to continue when a response is cut off by the output token limit")
+	cmd.Flags().IntVar(&requestMaxContinue, "max-continuations", 3, "Maximum follow-up turns with --auto-continue")
+	cmd.Flags().StringArrayVar(&requestStopSequences, "stop", nil, fmt.Sprintf("Stop generating at this string (repeatable, up to %d)", gemini.MaxStopSequences))
 
 	return cmd
 }
@@ -94,8 +247,31 @@ func runRequest(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	// Validate inputs
-	if requestPrompt == "" && requestPromptFile == "" && len(args) == 0 {
-		return fmt.Errorf("must provide prompt via -p, -f, or as argument")
+	if cmd.Flags().Changed("model") {
+		if err := validateModelFlag(requestModel, requestAllowUnknown); err != nil {
+			return err
+		}
+	}
+	if requestPromptDir != "" {
+		if requestPrompt != "" || requestPromptFile != "" || len(args) > 0 {
+			return fmt.Errorf("--prompt-dir cannot be combined with -p, -f, or a prompt argument")
+		}
+		if requestOutputFile != "" || requestOutputTmpl != "" {
+			return fmt.Errorf("--prompt-dir writes responses next to each prompt and cannot be combined with --output or --output-template")
+		}
+		if requestInteractive {
+			return fmt.Errorf("--interactive cannot be combined with --prompt-dir")
+		}
+		if requestTemplate != "" {
+			return fmt.Errorf("--template cannot be combined with --prompt-dir")
+		}
+	} else if requestDedup {
+		return fmt.Errorf("--dedup requires --prompt-dir")
+	} else if requestPrompt == "" && requestPromptFile == "" && len(args) == 0 && !requestInteractive && requestTemplate == "" {
+		return fmt.Errorf("must provide prompt via -p, -f, --template, --interactive, --prompt-dir, or as argument")
+	}
+	if len(requestTemplateVars) > 0 && requestTemplate == "" {
+		return fmt.Errorf("--var requires --template")
 	}
 
 	// Get prompt text
@@ -111,6 +287,34 @@ func runRequest(cmd *cobra.Command, args []string) error {
 	} else if len(args) > 0 {
 		promptText = strings.Join(args, " ")
 	}
+	// A template replaces the prompt; any -p/-f/argument text becomes {{input}}
+	var templatePath string
+	if requestTemplate != "" {
+		rendered, path, err := loadPromptTemplate(requestWorkDir, requestTemplate, requestTemplateVars, promptText)
+		if err != nil {
+			return err
+		}
+		promptText, templatePath = rendered, path
+	}
+	if requestInteractive {
+		edited, err := editPrompt(promptText)
+		if err != nil {
+			return err
+		}
+		promptText = edited
+	}
+
+	if requestSystem != "" && requestSystemFile != "" {
+		return fmt.Errorf("--system and --system-file cannot be used together")
+	}
+	systemInstruction := requestSystem
+	if requestSystemFile != "" {
+		content, err := os.ReadFile(requestSystemFile) //nolint:gosec // requestSystemFile is user-provided path
+		if err != nil {
+			return fmt.Errorf("reading system instruction file: %w", err)
+		}
+		systemInstruction = string(content)
+	}
 
 	// Parse cache TTL
 	ttl := 1 * time.Hour
@@ -124,26 +328,71 @@ func runRequest(cmd *cobra.Command, args []string) error {
 
 	// Create prompt files slice
 	var promptFiles []string
-	if requestPromptFile != "" {
+	if requestPromptFile != "" && requestTemplate == "" {
 		promptFiles = []string{requestPromptFile}
 	}
 
+	// Fall back to the repo's configured defaults for flags that weren't passed
+	model, modelSource := config.ResolveModel(requestWorkDir, requestModel, cmd.Flags().Changed("model"))
+	ulog.Info("Resolved model").
+		Field("model", model).
+		Field("source", modelSource).
+		StructuredOnly().
+		Log(ctx)
+	promptPrefix := requestPromptPrefix
+	promptSuffix := requestPromptSuffix
+	geminiCfg, err := config.LoadGeminiConfig(requestWorkDir)
+	if err == nil {
+		if !cmd.Flags().Changed("prompt-prefix") {
+			promptPrefix = geminiCfg.PromptPrefix
+		}
+		if !cmd.Flags().Changed("prompt-suffix") {
+			promptSuffix = geminiCfg.PromptSuffix
+		}
+	}
+
 	// Create options
 	options := gemini.RequestOptions{
-		Model:            requestModel,
-		Prompt:           promptText,
-		PromptFiles:      promptFiles,
-		WorkDir:          requestWorkDir,
-		CacheTTL:         ttl,
-		NoCache:          requestNoCache,
-		RegenerateCtx:    requestRegenerateCtx,
-		Recache:          requestRecache,
-		UseCache:         requestUseCache,
-		ContextFiles:     requestContextFiles,
-		SkipConfirmation: requestYes,
-	}
-
-	// Add generation parameters if specified
+		Model:             model,
+		Prompt:            promptText,
+		PromptPrefix:      promptPrefix,
+		PromptSuffix:      promptSuffix,
+		PromptFiles:       promptFiles,
+		WorkDir:           requestWorkDir,
+		CacheTTL:          ttl,
+		NoCache:           requestNoCache,
+		RegenerateCtx:     requestRegenerateCtx,
+		Recache:           requestRecache,
+		UseCache:          requestUseCache,
+		ContextFiles:      requestContextFiles,
+		Attachments:       requestAttachments,
+		ContextURLs:       requestContextURLs,
+		IncludeDiff:       requestIncludeDiff,
+		DiffStaged:        requestDiffStaged,
+		SkipConfirmation:  requestYes,
+		ShowCostOnly:      requestShowCost,
+		DumpContentsDir:   requestDumpContents,
+		FallbackModels:    requestFallbacks,
+		MaxRetries:        requestMaxRetries,
+		UploadConcurrency: requestUploadConcurrency,
+		NoUploadCache:     requestNoUploadCache,
+		DryRun:            requestDryRun,
+		CountCacheTokens:  requestCountTokens,
+		MinCacheTokens:    requestMinCacheTokens,
+		MinCacheTTL:       requestMinTTL,
+		AutoExtendCache:   requestAutoExtend,
+		SystemInstruction: systemInstruction,
+		PromptTemplate:    templatePath,
+	}
+
+	// Add generation parameters: a flag wins over the grove.yml default, and
+	// anything left unset uses the API default
+	if geminiCfg != nil {
+		options.Temperature = geminiCfg.Temperature
+		options.TopP = geminiCfg.TopP
+		options.TopK = geminiCfg.TopK
+		options.MaxOutputTokens = geminiCfg.MaxOutputTokens
+	}
 	if cmd.Flags().Changed("temperature") {
 		options.Temperature = &requestTemperature
 	}
@@ -156,22 +405,197 @@ func runRequest(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("max-output-tokens") {
 		options.MaxOutputTokens = &requestMaxOutputTokens
 	}
+	if cmd.Flags().Changed("presence-penalty") {
+		options.PresencePenalty = &requestPresencePenalty
+	}
+	if cmd.Flags().Changed("frequency-penalty") {
+		options.FrequencyPenalty = &requestFreqPenalty
+	}
+	if cmd.Flags().Changed("timeout") {
+		if requestTimeout <= 0 {
+			return fmt.Errorf("--timeout must be positive")
+		}
+		options.Timeout = requestTimeout
+	} else if options.Timeout, err = config.ResolveTimeout(requestWorkDir); err != nil {
+		return err
+	}
+	if requestOutputFile != "" && requestOutputTmpl != "" {
+		return fmt.Errorf("cannot use both --output and --output-template")
+	}
+	if requestAppend && requestOutputFile == "" && requestOutputTmpl == "" && requestPromptDir == "" {
+		return fmt.Errorf("--append requires --output, --output-template or --prompt-dir")
+	}
+	if len(requestLabels) > 0 {
+		labels, err := parseLabels(requestLabels)
+		if err != nil {
+			return err
+		}
+		options.Labels = labels
+	}
+	if requestDiffStaged && !requestIncludeDiff {
+		return fmt.Errorf("--staged requires --include-diff")
+	}
+	if requestMaxRetries < 0 {
+		return fmt.Errorf("--max-retries cannot be negative")
+	}
+	if requestUploadConcurrency < 1 {
+		return fmt.Errorf("--upload-concurrency must be at least 1")
+	}
+	if requestCandidates < 1 {
+		return fmt.Errorf("--candidates must be at least 1")
+	}
+	options.CandidateCount = requestCandidates
+	if len(requestStopSequences) > gemini.MaxStopSequences {
+		return fmt.Errorf("--stop can be given at most %d times", gemini.MaxStopSequences)
+	}
+	options.StopSequences = requestStopSequences
+	if requestAutoContinue {
+		if requestMaxContinue < 1 {
+			return fmt.Errorf("--max-continuations must be at least 1")
+		}
+		if requestCandidates > 1 {
+			return fmt.Errorf("--auto-continue cannot be combined with --candidates")
+		}
+		options.AutoContinue = requestMaxContinue
+	}
+	if requestJSONSchema != "" {
+		schema, err := os.ReadFile(requestJSONSchema) //nolint:gosec // requestJSONSchema is user-provided path
+		if err != nil {
+			return fmt.Errorf("reading JSON schema: %w", err)
+		}
+		options.ResponseJSONSchema = schema
+	}
+	if requestResponseJSON || cmd.Flags().Changed("response-schema-file") {
+		if cmd.Flags().Changed("json-schema") && cmd.Flags().Changed("response-schema-file") {
+			return fmt.Errorf("--json-schema and --response-schema-file cannot be used together")
+		}
+		if requestCandidates > 1 {
+			return fmt.Errorf("--response-json cannot be combined with --candidates")
+		}
+		options.ResponseMIMEType = gemini.JSONMIMEType
+	}
+	if requestValidate {
+		if requestJSONSchema == "" {
+			return fmt.Errorf("--validate-response requires --json-schema or --response-schema-file")
+		}
+		if requestCandidates > 1 {
+			return fmt.Errorf("--validate-response cannot be combined with --candidates")
+		}
+		options.ValidateResponse = true
+	}
+	if requestStream {
+		switch {
+		case requestPromptDir != "":
+			return fmt.Errorf("--stream cannot be combined with --prompt-dir")
+		case requestOutputFile != "" || requestOutputTmpl != "":
+			return fmt.Errorf("--stream writes to stdout and cannot be combined with --output or --output-template")
+		case requestCandidates > 1:
+			return fmt.Errorf("--stream cannot be combined with --candidates")
+		case requestValidate:
+			return fmt.Errorf("--stream cannot be combined with --validate-response")
+		}
+		options.StreamWriter = os.Stdout
+	}
+
+	if requestSession != "" {
+		if requestPromptDir != "" {
+			return fmt.Errorf("--session cannot be combined with --prompt-dir")
+		}
+		if requestResponseCache {
+			return fmt.Errorf("--response-cache cannot be combined with --session")
+		}
+		if requestCandidates > 1 {
+			return fmt.Errorf("--session cannot be combined with --candidates")
+		}
+		options.SessionFile = requestSession
+	}
+
+	if requestResponseCache {
+		if requestResponseCacheTTL <= 0 {
+			return fmt.Errorf("--response-cache-ttl must be positive")
+		}
+		options.ResponseCache = true
+		options.ResponseCacheTTL = requestResponseCacheTTL
+	} else if cmd.Flags().Changed("response-cache-ttl") {
+		return fmt.Errorf("--response-cache-ttl requires --response-cache")
+	}
+
+	if requestExtractCode {
+		switch {
+		case requestPromptDir != "":
+			return fmt.Errorf("--extract-code cannot be combined with --prompt-dir")
+		case requestStream:
+			return fmt.Errorf("--extract-code cannot be combined with --stream")
+		}
+	} else if requestExtractLang != "" || requestExtractStrict {
+		return fmt.Errorf("--extract-lang and --extract-strict require --extract-code")
+	}
+
+	if requestDryRun {
+		switch {
+		case requestPromptDir != "":
+			return fmt.Errorf("--dry-run cannot be combined with --prompt-dir")
+		case requestStream:
+			return fmt.Errorf("--dry-run cannot be combined with --stream")
+		}
+	}
 
 	// Create and run request runner
 	runner := gemini.NewRequestRunner()
-	response, err := runner.Run(ctx, options)
+	if requestPromptDir != "" {
+		return runPromptDir(ctx, runner, options, requestPromptDir, requestDedup)
+	}
+	result, err := runner.RunWithResult(ctx, options)
 	if err != nil {
 		return err
 	}
+	if requestDryRun {
+		// The plan was already printed and no response exists to write
+		return nil
+	}
+	if requestStream {
+		// The response was already written as it arrived
+		if !strings.HasSuffix(result.Text, "\n") {
+			fmt.Println()
+		}
+		return nil
+	}
+	response := result.Text
+	if requestExtractCode {
+		response, err = extractCode(response, requestExtractLang, requestExtractStrict)
+		if err != nil {
+			return err
+		}
+	}
+	// A fallback model may have answered instead of the requested one
+	answeredModel := options.Model
+	if result.Model != "" {
+		answeredModel = result.Model
+	}
+
+	// Resolve the output file from the template if given
+	outputFile := requestOutputFile
+	if requestOutputTmpl != "" {
+		outputFile = expandOutputTemplate(requestOutputTmpl, answeredModel, time.Now())
+		if dir := filepath.Dir(outputFile); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // output dir needs to be traversable
+				return fmt.Errorf("creating output directory: %w", err)
+			}
+		}
+	}
 
 	// Output the response
-	if requestOutputFile != "" {
+	if outputFile != "" {
 		// Write to file
-		if err := os.WriteFile(requestOutputFile, []byte(response), 0o600); err != nil { //nolint:gosec // output file
+		if requestAppend {
+			if err := appendResponse(outputFile, response, answeredModel, time.Now()); err != nil {
+				return fmt.Errorf("appending to output file: %w", err)
+			}
+		} else if err := os.WriteFile(outputFile, []byte(response), 0o600); err != nil { //nolint:gosec // output file
 			return fmt.Errorf("writing output file: %w", err)
 		}
 		logger := pretty.New()
-		logger.ResponseWritten(requestOutputFile)
+		logger.ResponseWritten(outputFile)
 	} else {
 		// Write to stdout (not stderr) for piping
 		responseOutput := response
@@ -180,8 +604,9 @@ func runRequest(cmd *cobra.Command, args []string) error {
 			responseOutput += "\n"
 		}
 		// When called non-interactively (for capturing output), write to stdout
-		// Otherwise use ulog to avoid corrupting TUIs
-		if isNonInteractive() {
+		// Otherwise use ulog to avoid corrupting TUIs. Quiet mode discards ulog
+		// output, so the response always goes straight to stdout there.
+		if isNonInteractive() || quiet {
 			fmt.Print(responseOutput)
 		} else {
 			ulog.Info("Response output").
@@ -195,6 +620,51 @@ func runRequest(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// expandOutputTemplate replaces {date}, {time} and {model} placeholders in an output path template
+func expandOutputTemplate(tmpl, model string, now time.Time) string {
+	return strings.NewReplacer(
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("150405"),
+		"{model}", strings.ReplaceAll(model, "/", "-"),
+	).Replace(tmpl)
+}
+
+// appendResponse appends a response to path, preceded by a timestamped separator
+// when the file already has content. The separator and response go out in a
+// single O_APPEND write followed by a sync, so concurrent appenders don't lose
+// each other's responses and a process that dies mid-write leaves the earlier
+// responses intact.
+func appendResponse(path, response, model string, now time.Time) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // output file
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if stat.Size() > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "---\n<!-- %s %s -->\n\n", now.Format("2006-01-02 15:04:05"), model)
+	b.WriteString(response)
+	if !strings.HasSuffix(response, "\n") {
+		b.WriteString("\n")
+	}
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 // isNonInteractive returns true if stdout is being captured (not a TTY)
 // This allows grove-gemini to output the response to stdout when being piped,
 // while using ulog (stderr) when running interactively to avoid corrupting TUIs
@@ -207,3 +677,17 @@ func isNonInteractive() bool {
 	// If not, we're being piped or redirected
 	return (fileInfo.Mode() & os.ModeCharDevice) == 0
 }
+
+// parseLabels parses key=value label flags into a map
+func parseLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", v)
+		}
+		labels[key] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
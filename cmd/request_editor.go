@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// promptScissors marks the start of the help text; it and everything below it
+// are dropped from the prompt. A marker is used instead of '#' comments so
+// markdown headings in the prompt survive.
+const promptScissors = "# ------------------------ >8 ------------------------"
+
+// promptEditorHelp is appended to the editor buffer below the scissors line
+const promptEditorHelp = "\n" + promptScissors + `
+# Write your prompt above this line. Do not modify or remove it.
+# Save and close the editor to send the request; an empty prompt aborts it.
+`
+
+// resolveEditor returns the user's editor command from $VISUAL or $EDITOR, defaulting to vi
+func resolveEditor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// editPrompt opens initial in the user's editor and returns the saved prompt
+// without the help text. It returns an error if the result is empty.
+func editPrompt(initial string) (string, error) {
+	f, err := os.CreateTemp("", "grove-gemini-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating prompt file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	buffer := initial
+	if buffer != "" && !strings.HasSuffix(buffer, "\n") {
+		buffer += "\n"
+	}
+	_, err = f.WriteString(buffer + promptEditorHelp)
+	_ = f.Close()
+	if err != nil {
+		return "", fmt.Errorf("writing prompt file: %w", err)
+	}
+
+	// Run through the shell so editors configured with arguments (e.g. "code --wait") work
+	editor := resolveEditor()
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path) //nolint:gosec // editor comes from the user's own environment
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running editor %q: %w", editor, err)
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec // temp file created above
+	if err != nil {
+		return "", fmt.Errorf("reading prompt file: %w", err)
+	}
+
+	prompt := stripPromptHelp(string(content))
+	if prompt == "" {
+		return "", fmt.Errorf("aborting request due to empty prompt")
+	}
+	return prompt, nil
+}
+
+// stripPromptHelp cuts an edited prompt at the scissors line and trims surrounding whitespace
+func stripPromptHelp(content string) string {
+	if i := strings.Index(content, promptScissors); i >= 0 {
+		content = content[:i]
+	}
+	return strings.TrimSpace(content)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// codeBlock is a fenced code block found in a markdown response
+type codeBlock struct {
+	Lang string
+	Code string
+}
+
+// findCodeBlocks returns the fenced (``` or ~~~) code blocks in text, in
+// order. A block left open at the end of a truncated response runs to the end.
+func findCodeBlocks(text string) []codeBlock {
+	var blocks []codeBlock
+	var current *codeBlock
+	var fence string
+	var body []string
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current == nil {
+			if f := fenceOf(trimmed); f != "" {
+				lang, _, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(trimmed, f)), " ")
+				current, fence, body = &codeBlock{Lang: lang}, f, nil
+			}
+			continue
+		}
+		if fenceOf(trimmed) != "" && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			current.Code = strings.Join(body, "\n")
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		body = append(body, line)
+	}
+	if current != nil {
+		current.Code = strings.Join(body, "\n")
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
+
+// fenceOf returns the run of three or more backticks or tildes a line starts with
+func fenceOf(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n >= 3 {
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// extractCode returns the code of the blocks in response whose language tag
+// matches lang (any block if lang is empty), joined by blank lines. With
+// strict, more than one matching block is an error.
+func extractCode(response, lang string, strict bool) (string, error) {
+	var matched []string
+	for _, block := range findCodeBlocks(response) {
+		if lang == "" || strings.EqualFold(block.Lang, lang) {
+			matched = append(matched, block.Code)
+		}
+	}
+
+	switch {
+	case len(matched) == 0 && lang != "":
+		return "", fmt.Errorf("response has no %s code block", lang)
+	case len(matched) == 0:
+		return "", fmt.Errorf("response has no fenced code block")
+	case strict && len(matched) > 1:
+		return "", fmt.Errorf("response has %d code blocks but --extract-strict allows only one (narrow it down with --extract-lang)", len(matched))
+	}
+
+	code := strings.Join(matched, "\n\n")
+	if !strings.HasSuffix(code, "\n") {
+		code += "\n"
+	}
+	return code, nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractCode(t *testing.T) {
+	response := "Here is the helper:\n\n```go\npackage retry\n\nfunc Do() {}\n```\n\nAnd a test:\n\n~~~go\nfunc TestDo(t *testing.T) {}\n~~~\n\nRun it with:\n\n```sh\ngo test ./...\n```\n"
+
+	tests := []struct {
+		name    string
+		lang    string
+		strict  bool
+		want    string
+		wantErr string
+	}{
+		{name: "all blocks", want: "package retry\n\nfunc Do() {}\n\nfunc TestDo(t *testing.T) {}\n\ngo test ./...\n"},
+		{name: "by language", lang: "GO", want: "package retry\n\nfunc Do() {}\n\nfunc TestDo(t *testing.T) {}\n"},
+		{name: "strict single match", lang: "sh", strict: true, want: "go test ./...\n"},
+		{name: "strict multiple", lang: "go", strict: true, wantErr: "2 code blocks"},
+		{name: "no match", lang: "python", wantErr: "no python code block"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractCode(response, tt.lang, tt.strict)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("extractCode() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractCode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("extractCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindCodeBlocks_NestedAndTruncated(t *testing.T) {
+	// A longer fence can hold a shorter one, and a block cut off by the
+	// output limit runs to the end of the response
+	response := "````md\n```go\nx := 1\n```\n````\n\n```python\nprint('cut"
+	blocks := findCodeBlocks(response)
+	if len(blocks) != 2 {
+		t.Fatalf("findCodeBlocks() found %d blocks, want 2: %+v", len(blocks), blocks)
+	}
+	if blocks[0].Lang != "md" || blocks[0].Code != "```go\nx := 1\n```" {
+		t.Errorf("nested block = %+v", blocks[0])
+	}
+	if blocks[1].Lang != "python" || blocks[1].Code != "print('cut" {
+		t.Errorf("truncated block = %+v", blocks[1])
+	}
+}
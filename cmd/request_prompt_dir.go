@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+)
+
+// promptResponseSuffix names the response file written next to each prompt
+const promptResponseSuffix = ".response.md"
+
+// listPromptFiles returns the prompt files in dir in name order, skipping
+// hidden files, subdirectories and responses written by earlier runs.
+func listPromptFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, promptResponseSuffix) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	return files, nil
+}
+
+// promptResponsePath returns where the response for a prompt file is written
+func promptResponsePath(promptFile string) string {
+	return strings.TrimSuffix(promptFile, filepath.Ext(promptFile)) + promptResponseSuffix
+}
+
+// promptDeduper remembers responses by prompt hash so identical prompts in a
+// single run reuse the first response instead of calling the API again.
+type promptDeduper struct {
+	responses   map[string]*gemini.GenerateResult
+	saved       int
+	costAvoided float64
+}
+
+func newPromptDeduper() *promptDeduper {
+	return &promptDeduper{responses: make(map[string]*gemini.GenerateResult)}
+}
+
+// promptHash identifies a prompt by its content, ignoring surrounding whitespace
+func promptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(prompt)))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookup returns the stored response for an identical earlier prompt and
+// counts the call and cost it saves
+func (d *promptDeduper) lookup(prompt string) (*gemini.GenerateResult, bool) {
+	result, ok := d.responses[promptHash(prompt)]
+	if ok {
+		d.saved++
+		d.costAvoided += result.EstimatedCost
+	}
+	return result, ok
+}
+
+// store records the response for a prompt
+func (d *promptDeduper) store(prompt string, result *gemini.GenerateResult) {
+	d.responses[promptHash(prompt)] = result
+}
+
+// runPromptDir runs each prompt file in dir as a separate request, reusing the
+// same context and cache, and writes each response next to its prompt. A
+// failed prompt doesn't stop the rest; failures are summarized at the end.
+// With dedup set, identical prompts reuse the first response.
+func runPromptDir(ctx context.Context, runner *gemini.RequestRunner, options gemini.RequestOptions, dir string, dedup bool) error {
+	files, err := listPromptFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no prompt files found in %s", dir)
+	}
+
+	// Progress goes where the pretty logs go, so --quiet silences it; failures stay on stderr
+	progress := grovelogging.GetGlobalOutput()
+
+	var deduper *promptDeduper
+	if dedup {
+		deduper = newPromptDeduper()
+	}
+
+	var failed []string
+	for i, file := range files {
+		ulog.Info("Running prompt").
+			Field("file", file).
+			Field("index", i+1).
+			Field("total", len(files)).
+			Pretty(fmt.Sprintf("%s [%d/%d] %s", theme.IconFile, i+1, len(files), file)).
+			PrettyOnly().
+			Log(ctx)
+
+		content, err := os.ReadFile(file) //nolint:gosec // file is from the user-provided prompt directory
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to read %s: %v\n", theme.IconWarning, file, err)
+			failed = append(failed, file)
+			continue
+		}
+
+		opts := options
+		opts.Prompt = string(content)
+		opts.PromptFiles = []string{file}
+		// Context regeneration and recaching only need to happen once for the whole directory
+		if i > 0 {
+			opts.RegenerateCtx = false
+			opts.Recache = false
+		}
+
+		var result *gemini.GenerateResult
+		reused := false
+		if deduper != nil {
+			result, reused = deduper.lookup(opts.Prompt)
+		}
+		if !reused {
+			result, err = runner.RunWithResult(ctx, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s Request for %s failed: %v\n", theme.IconWarning, file, err)
+				failed = append(failed, file)
+				continue
+			}
+			if deduper != nil {
+				deduper.store(opts.Prompt, result)
+			}
+		}
+		response := result.Text
+		model := opts.Model
+		if result.Model != "" {
+			model = result.Model
+		}
+
+		outputFile := promptResponsePath(file)
+		if requestAppend {
+			err = appendResponse(outputFile, response, model, time.Now())
+		} else {
+			err = os.WriteFile(outputFile, []byte(response), 0o600) //nolint:gosec // output file
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to write %s: %v\n", theme.IconWarning, outputFile, err)
+			failed = append(failed, file)
+			continue
+		}
+		if reused {
+			fmt.Fprintf(progress, "%s Wrote %s (duplicate prompt, reused earlier response)\n", theme.IconSuccess, outputFile)
+		} else {
+			fmt.Fprintf(progress, "%s Wrote %s\n", theme.IconSuccess, outputFile)
+		}
+	}
+
+	fmt.Fprintf(progress, "\nCompleted %d of %d prompts.\n", len(files)-len(failed), len(files))
+	if deduper != nil && deduper.saved > 0 {
+		fmt.Fprintf(progress, "%s Deduplication saved %d API call(s), about %s.\n", theme.IconChart, deduper.saved, pretty.FormatCost(deduper.costAvoided))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d prompt(s) failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
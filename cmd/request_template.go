@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// promptTemplateDir is where --template looks for templates, relative to the working directory
+const promptTemplateDir = ".grove/prompts"
+
+// promptTemplateExts are tried in order when a template name has no extension
+var promptTemplateExts = []string{".md", ".txt", ".tmpl"}
+
+// templateInputVar holds the -p/-f/argument text when it is combined with --template
+const templateInputVar = "input"
+
+// bareTemplateVar matches {{key}} placeholders, which are rewritten to
+// {{.key}} so templates don't need text/template's dot syntax
+var bareTemplateVar = regexp.MustCompile(`\{\{(-?\s*)([A-Za-z_][A-Za-z0-9_]*)(\s*-?)\}\}`)
+
+// templateKeywords are bare actions that must not be rewritten into variables
+var templateKeywords = map[string]bool{
+	"end": true, "else": true, "break": true, "continue": true,
+	"nil": true, "true": true, "false": true,
+}
+
+// findPromptTemplate returns the path of the named template in workDir's
+// .grove/prompts, trying the common prompt extensions if name has none
+func findPromptTemplate(workDir, name string) (string, error) {
+	if workDir == "" {
+		workDir = "."
+	}
+	dir := filepath.Join(workDir, promptTemplateDir)
+	candidates := []string{name}
+	if filepath.Ext(name) == "" {
+		for _, ext := range promptTemplateExts {
+			candidates = append(candidates, name+ext)
+		}
+	}
+	for _, candidate := range candidates {
+		path := filepath.Join(dir, candidate)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("prompt template %q not found in %s", name, dir)
+}
+
+// loadPromptTemplate finds and renders the named template with the --var
+// values. input, the -p/-f/argument text, fills {{input}} unless a --var sets
+// it. It returns the rendered prompt and the template's path.
+func loadPromptTemplate(workDir, name string, varFlags []string, input string) (string, string, error) {
+	path, err := findPromptTemplate(workDir, name)
+	if err != nil {
+		return "", "", err
+	}
+	content, err := os.ReadFile(path) //nolint:gosec // path is inside the project's prompt template directory
+	if err != nil {
+		return "", "", fmt.Errorf("reading prompt template: %w", err)
+	}
+	vars, err := parseTemplateVars(varFlags)
+	if err != nil {
+		return "", "", err
+	}
+	if _, ok := vars[templateInputVar]; !ok && input != "" {
+		vars[templateInputVar] = input
+	}
+	rendered, err := renderPromptTemplate(name, string(content), vars)
+	if err != nil {
+		return "", "", err
+	}
+	return rendered, path, nil
+}
+
+// parseTemplateVars parses repeated --var key=value flags
+func parseTemplateVars(values []string) (map[string]string, error) {
+	vars := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected key=value", v)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// renderPromptTemplate substitutes vars into the template text. Placeholders
+// may be written {{key}} or {{.key}}; the rest of text/template (if, range,
+// ...) works as usual. Every variable the template refers to must be set, and
+// the error lists the ones that aren't.
+func renderPromptTemplate(name, text string, vars map[string]string) (string, error) {
+	text = bareTemplateVar.ReplaceAllStringFunc(text, func(m string) string {
+		parts := bareTemplateVar.FindStringSubmatch(m)
+		if templateKeywords[parts[2]] {
+			return m
+		}
+		return "{{" + parts[1] + "." + parts[2] + parts[3] + "}}"
+	})
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing prompt template %s: %w", name, err)
+	}
+
+	var missing []string
+	for _, key := range templateVarNames(tmpl.Root) {
+		if _, ok := vars[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("prompt template %s needs variables that weren't set: %s (pass them with --var key=value)",
+			name, strings.Join(missing, ", "))
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("rendering prompt template %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// templateVarNames returns the sorted top-level field names referenced in a template
+func templateVarNames(root parse.Node) []string {
+	seen := make(map[string]bool)
+	var walk func(parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			seen[n.Ident[0]] = true
+		}
+	}
+	walk(root)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
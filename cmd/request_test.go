@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := parseLabels([]string{"team=platform", "project = search", "empty="})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]string{"team": "platform", "project": "search", "empty": ""}
+	if len(labels) != len(expected) {
+		t.Fatalf("Expected %d labels, got %d", len(expected), len(labels))
+	}
+	for k, v := range expected {
+		if labels[k] != v {
+			t.Errorf("Expected label %q to be %q, got %q", k, v, labels[k])
+		}
+	}
+
+	for _, invalid := range []string{"noequals", "=value"} {
+		if _, err := parseLabels([]string{invalid}); err == nil {
+			t.Errorf("Expected error for invalid label %q", invalid)
+		}
+	}
+}
+
+func TestExpandOutputTemplate(t *testing.T) {
+	now := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+	got := expandOutputTemplate("out/{date}-{time}-{model}.md", "models/gemini-2.5-pro", now)
+	want := "out/2025-03-14-150926-models-gemini-2.5-pro.md"
+	if got != want {
+		t.Errorf("expandOutputTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestAppendResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.md")
+	now := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	if err := appendResponse(path, "first", "gemini-2.0-flash", now); err != nil {
+		t.Fatalf("first append failed: %v", err)
+	}
+	if err := appendResponse(path, "second\n", "gemini-2.0-flash", now); err != nil {
+		t.Fatalf("second append failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+
+	want := "---\n<!-- 2025-03-14 15:09:26 gemini-2.0-flash -->\n\nfirst\n" +
+		"\n---\n<!-- 2025-03-14 15:09:26 gemini-2.0-flash -->\n\nsecond\n"
+	if string(data) != want {
+		t.Errorf("unexpected file content:\n%q\nwant:\n%q", string(data), want)
+	}
+}
+
+func TestAppendResponse_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.md")
+	now := time.Now()
+
+	const appenders = 20
+	var wg sync.WaitGroup
+	for i := 0; i < appenders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := appendResponse(path, fmt.Sprintf("response %d", i), "gemini-2.0-flash", now); err != nil {
+				t.Errorf("append %d failed: %v", i, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	for i := 0; i < appenders; i++ {
+		if !strings.Contains(string(data), fmt.Sprintf("response %d\n", i)) {
+			t.Errorf("response %d was lost", i)
+		}
+	}
+}
+
+func TestListPromptFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.md", "a.txt", "a.response.md", ".hidden"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("prompt"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := listPromptFiles(dir)
+	if err != nil {
+		t.Fatalf("listPromptFiles() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.md")}
+	if len(files) != len(want) {
+		t.Fatalf("listPromptFiles() = %v, want %v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("listPromptFiles()[%d] = %q, want %q", i, files[i], want[i])
+		}
+	}
+
+	if got := promptResponsePath(filepath.Join("prompts", "q1.md")); got != filepath.Join("prompts", "q1.response.md") {
+		t.Errorf("promptResponsePath() = %q", got)
+	}
+}
+
+func TestLoadPromptTemplate(t *testing.T) {
+	workDir := t.TempDir()
+	dir := filepath.Join(workDir, ".grove", "prompts")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "Review PR {{pr}} for {{ .focus }}.\n\n{{- if .input}}\n{{input}}{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, "review.md"), []byte(tmpl), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, path, err := loadPromptTemplate(workDir, "review", []string{"pr=42", "focus=security"}, "Check the auth changes")
+	if err != nil {
+		t.Fatalf("loadPromptTemplate() error = %v", err)
+	}
+	if want := "Review PR 42 for security.\nCheck the auth changes"; got != want {
+		t.Errorf("loadPromptTemplate() = %q, want %q", got, want)
+	}
+	if path != filepath.Join(dir, "review.md") {
+		t.Errorf("loadPromptTemplate() path = %q", path)
+	}
+
+	// Every missing variable is named, sorted
+	_, _, err = loadPromptTemplate(workDir, "review", nil, "")
+	if err == nil || !strings.Contains(err.Error(), "focus, input, pr") {
+		t.Errorf("expected missing focus, input and pr, got %v", err)
+	}
+
+	if _, _, err := loadPromptTemplate(workDir, "missing", nil, ""); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+	if _, err := parseTemplateVars([]string{"novalue"}); err == nil {
+		t.Error("expected an error for a --var without =")
+	}
+}
+
+func TestPromptDeduper(t *testing.T) {
+	d := newPromptDeduper()
+	if _, ok := d.lookup("Summarize the API"); ok {
+		t.Fatal("Expected no stored response before store")
+	}
+
+	d.store("Summarize the API\n", &gemini.GenerateResult{Text: "summary", EstimatedCost: 0.02})
+
+	result, ok := d.lookup("  Summarize the API")
+	if !ok || result.Text != "summary" {
+		t.Fatalf("Expected duplicate prompt to reuse the stored response, got %+v (ok=%v)", result, ok)
+	}
+	if _, ok := d.lookup("Summarize the CLI"); ok {
+		t.Error("Expected a different prompt not to match")
+	}
+	if d.saved != 1 || d.costAvoided != 0.02 {
+		t.Errorf("Expected 1 saved call and $0.02 avoided, got %d and %f", d.saved, d.costAvoided)
+	}
+}
+
+func TestStripPromptHelp(t *testing.T) {
+	content := "# Heading\n\nExplain this.\n" + promptEditorHelp
+	if got := stripPromptHelp(content); got != "# Heading\n\nExplain this." {
+		t.Errorf("stripPromptHelp() = %q", got)
+	}
+	if got := stripPromptHelp(promptEditorHelp); got != "" {
+		t.Errorf("Expected empty prompt from untouched buffer, got %q", got)
+	}
+}
+
+func TestEditPrompt(t *testing.T) {
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "editor.sh")
+	// The fake editor prepends a line to whatever is in the buffer
+	script := "#!/bin/sh\nprintf 'Review the API\\n' | cat - \"$1\" > \"$1.new\" && mv \"$1.new\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", editor)
+
+	got, err := editPrompt("Focus on errors.")
+	if err != nil {
+		t.Fatalf("editPrompt: %v", err)
+	}
+	if got != "Review the API\nFocus on errors." {
+		t.Errorf("editPrompt() = %q", got)
+	}
+
+	t.Setenv("VISUAL", "true")
+	if _, err := editPrompt(""); err == nil || !strings.Contains(err.Error(), "empty prompt") {
+		t.Errorf("Expected empty prompt error, got %v", err)
+	}
+}
@@ -1,14 +1,82 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/gemini"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/grovetools/grove-gemini/pkg/redact"
 	"github.com/spf13/cobra"
 )
 
-var rootCmd *cobra.Command
+// outputModeEnvVar set to "merged" sends progress and logs to stdout, like --merge-output
+const outputModeEnvVar = "GROVE_GEMINI_OUTPUT"
+
+// backendHelp explains the Vertex AI backend in the root command's help
+const backendHelp = `Backends:
+  Requests go to the Gemini Developer API with an API key by default. Set
+  ` + config.BackendEnvVar + `=vertex (or gemini.backend: vertex in grove.yml) to use Vertex AI
+  with Application Default Credentials and gemini.vertex.project/location.
+  On Vertex AI there is no Files API: context files and attachments are sent
+  inline with each request (subject to its request size limit) and the upload
+  reuse index is not used. Context caches work on both, but a cache exists
+  only on the backend that created it. Request labels are sent only to Vertex AI.`
+
+// tracingShutdownTimeout bounds how long exiting waits for spans to be exported
+const tracingShutdownTimeout = 5 * time.Second
+
+var (
+	rootCmd     *cobra.Command
+	mergeOutput bool
+	quiet       bool
+)
 
 func init() {
 	rootCmd = cli.NewStandardCommand("grove-gemini", "Tools for Google's Gemini API")
+	rootCmd.Long = "Tools for Google's Gemini API.\n\n" + backendHelp + "\n\n" + exitCodesHelp
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &invalidInputError{err: err}
+	})
+	rootCmd.PersistentFlags().BoolVar(&mergeOutput, "merge-output", false, "Send progress and logs to stdout along with the response (or set "+outputModeEnvVar+"=merged)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress progress and log output; only the response and errors are printed")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		grovelogging.SetGlobalOutput(resolveLogOutput(mergeOutput, quiet))
+		pretty.SetQuiet(quiet)
+		if quiet {
+			// Errors are still reported, just without the usage text
+			cmd.SilenceUsage = true
+		}
+
+		// Mask secrets in error output, which can echo prompts or config values
+		redactor, err := config.ResolveRedactor("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring gemini.redaction patterns: %v\n", err)
+		}
+		rootCmd.SetErr(redact.NewWriter(os.Stderr, redactor))
+
+		if sep, ok := config.ResolveThousandsSeparator(""); ok {
+			pretty.SetThousandsSeparator(sep)
+		}
+
+		// Install pricing.yml and gemini.pricing_overrides before any command
+		// estimates a cost, using the project named by --workdir where there is one
+		workDir := ""
+		if flag := cmd.Flags().Lookup("workdir"); flag != nil {
+			workDir = flag.Value.String()
+		}
+		if err := logging.LoadPricingOverrides(workDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring invalid pricing overrides: %v\n", err)
+		}
+	}
 
 	// Add commands
 	rootCmd.AddCommand(newVersionCmd())
@@ -16,10 +84,47 @@ func init() {
 	rootCmd.AddCommand(newCountTokensCmd())
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newRequestCmd())
+	rootCmd.AddCommand(newChatCmd())
 	rootCmd.AddCommand(newCacheCmd())
 	rootCmd.AddCommand(newEmbedCmd())
+	rootCmd.AddCommand(newMetricsCmd())
+	rootCmd.AddCommand(newBenchCmd())
+	rootCmd.AddCommand(newBatchCmd())
+	rootCmd.AddCommand(newDoctorCmd())
+	rootCmd.AddCommand(newModelsCmd())
 }
 
+// resolveLogOutput picks where progress and log output goes for this invocation.
+// It defaults to stderr so stdout can be piped; merged mode sends everything to
+// stdout and quiet mode discards it.
+func resolveLogOutput(merge, quiet bool) io.Writer {
+	if quiet {
+		return io.Discard
+	}
+	if merge || strings.EqualFold(os.Getenv(outputModeEnvVar), "merged") {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+// Execute runs the root command. A failure is returned as an *ExitError
+// carrying the exit code for its category.
 func Execute() error {
-	return rootCmd.Execute()
+	shutdownTracing, err := gemini.SetupTracing(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: tracing disabled: %v\n", err)
+	}
+	defer func() {
+		// Flush spans still queued for the OTLP exporter
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: exporting traces: %v\n", err)
+		}
+	}()
+
+	if err := rootCmd.Execute(); err != nil {
+		return &ExitError{Code: ExitCode(err), Err: err}
+	}
+	return nil
 }
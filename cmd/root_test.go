@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestResolveLogOutput(t *testing.T) {
+	t.Setenv(outputModeEnvVar, "")
+	if got := resolveLogOutput(false, false); got != os.Stderr {
+		t.Error("Expected stderr by default")
+	}
+	if got := resolveLogOutput(true, false); got != os.Stdout {
+		t.Error("Expected stdout with --merge-output")
+	}
+
+	t.Setenv(outputModeEnvVar, "merged")
+	if got := resolveLogOutput(false, false); got != os.Stdout {
+		t.Errorf("Expected stdout with %s=merged", outputModeEnvVar)
+	}
+
+	// Quiet wins over merged output
+	if got := resolveLogOutput(true, true); got != io.Discard {
+		t.Error("Expected output to be discarded with --quiet")
+	}
+}
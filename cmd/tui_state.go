@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grovetools/core/pkg/paths"
+)
+
+// tuiStateFile holds the views last selected in the query TUIs
+const tuiStateFile = "tui-state.json"
+
+// tuiState is the view selection remembered between TUI runs
+type tuiState struct {
+	QueryTimeFrame     string `json:"query_time_frame,omitempty"`
+	QueryMetric        string `json:"query_metric,omitempty"`
+	DashboardTimeFrame string `json:"dashboard_time_frame,omitempty"`
+}
+
+// tuiStatePath returns where TUI state is stored; tests point it elsewhere
+var tuiStatePath = func() string {
+	stateDir := paths.StateDir()
+	if stateDir == "" {
+		return ""
+	}
+	return filepath.Join(stateDir, "gemini", tuiStateFile)
+}
+
+// timeFrameNames maps the TUI time frames to the names stored in the state file
+var timeFrameNames = map[time.Duration]string{
+	24 * time.Hour:       "daily",
+	7 * 24 * time.Hour:   "weekly",
+	30 * 24 * time.Hour:  "monthly",
+	90 * 24 * time.Hour:  "quarterly",
+	365 * 24 * time.Hour: "yearly",
+}
+
+// timeFrameFromName returns the time frame stored under name, if it is known
+func timeFrameFromName(name string) (time.Duration, bool) {
+	for frame, n := range timeFrameNames {
+		if n == name {
+			return frame, true
+		}
+	}
+	return 0, false
+}
+
+// loadTUIState reads the saved TUI state. A missing or unreadable file yields
+// the zero state, so the TUIs fall back to their defaults.
+func loadTUIState() tuiState {
+	var state tuiState
+	path := tuiStatePath()
+	if path == "" {
+		return state
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is under the grove state directory
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state)
+	return state
+}
+
+// saveTUIState applies update to the saved TUI state and writes it back.
+// Saving is best-effort: a failure never interrupts the TUI.
+func saveTUIState(update func(*tuiState)) {
+	path := tuiStatePath()
+	if path == "" {
+		return
+	}
+	state := loadTUIState()
+	update(&state)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // state dir needs to be traversable
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil { //nolint:gosec // state file is not sensitive
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
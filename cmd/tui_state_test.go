@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func useTempTUIState(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gemini", tuiStateFile)
+	orig := tuiStatePath
+	tuiStatePath = func() string { return path }
+	t.Cleanup(func() { tuiStatePath = orig })
+	return path
+}
+
+func TestQueryTUIRemembersView(t *testing.T) {
+	useTempTUIState(t)
+
+	m := initialModel()
+	if m.timeFrame != 24*time.Hour || m.plotMetric != "cost" {
+		t.Fatalf("expected daily cost view by default, got %v %s", m.timeFrame, m.plotMetric)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if got := updated.(queryTuiModel); got.timeFrame != 7*24*time.Hour || got.plotMetric != "tokens" {
+		t.Fatalf("keys did not switch view: %v %s", got.timeFrame, got.plotMetric)
+	}
+
+	m = initialModel()
+	if m.timeFrame != 7*24*time.Hour || m.plotMetric != "tokens" {
+		t.Errorf("expected the saved weekly tokens view, got %v %s", m.timeFrame, m.plotMetric)
+	}
+}
+
+func TestLoadTUIStateIgnoresBadFile(t *testing.T) {
+	path := useTempTUIState(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if state := loadTUIState(); state != (tuiState{}) {
+		t.Errorf("expected zero state for a corrupt file, got %+v", state)
+	}
+	if m := initialModel(); m.timeFrame != 24*time.Hour {
+		t.Errorf("corrupt state should fall back to daily view, got %v", m.timeFrame)
+	}
+}
+
+func TestSaveTUIStateBestEffort(t *testing.T) {
+	// A state path under a regular file can't be created; saving must not panic
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	orig := tuiStatePath
+	tuiStatePath = func() string { return filepath.Join(blocker, "gemini", tuiStateFile) }
+	t.Cleanup(func() { tuiStatePath = orig })
+
+	saveTUIState(func(s *tuiState) { s.QueryMetric = "tokens" })
+}
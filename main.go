@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	grovelogging "github.com/grovetools/core/logging"
@@ -8,10 +9,15 @@ import (
 )
 
 func main() {
-	// CLI logging/progress goes to stderr so stdout can be used for piping LLM responses
+	// CLI logging/progress goes to stderr so stdout can be used for piping LLM responses.
+	// Commands can redirect it per invocation with --merge-output.
 	grovelogging.SetGlobalOutput(os.Stderr)
 
 	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		os.Exit(cmd.ExitCodeError)
 	}
 }
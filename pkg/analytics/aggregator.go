@@ -10,6 +10,7 @@ import (
 type Bucket struct {
 	StartTime             time.Time
 	TotalCost             float64
+	CacheCreationCost     float64 // One-time cost of new caches, not included in TotalCost
 	TotalTokens           int64
 	TotalPromptTokens     int64
 	TotalCompletionTokens int64
@@ -19,10 +20,11 @@ type Bucket struct {
 
 // Totals holds the summary statistics for a given time range.
 type Totals struct {
-	TotalCost     float64
-	TotalTokens   int64
-	TotalRequests int
-	ErrorRate     float64
+	TotalCost         float64
+	CacheCreationCost float64
+	TotalTokens       int64
+	TotalRequests     int
+	ErrorRate         float64
 }
 
 // AggregateLogs groups logs into time-based buckets.
@@ -42,6 +44,7 @@ func AggregateLogs(logs []logging.QueryLog, interval time.Duration, startTime ti
 		index := int(log.Timestamp.Sub(startTime) / interval)
 		if index >= 0 && index < numBuckets {
 			buckets[index].TotalCost += log.EstimatedCost
+			buckets[index].CacheCreationCost += log.CacheCreationCost
 			buckets[index].TotalTokens += int64(log.TotalTokens)
 			buckets[index].TotalPromptTokens += int64(log.PromptTokens)
 			buckets[index].TotalCompletionTokens += int64(log.CompletionTokens)
@@ -60,6 +63,7 @@ func CalculateTotals(buckets []Bucket) Totals {
 	var totals Totals
 	for _, bucket := range buckets {
 		totals.TotalCost += bucket.TotalCost
+		totals.CacheCreationCost += bucket.CacheCreationCost
 		totals.TotalTokens += bucket.TotalTokens
 		totals.TotalRequests += bucket.RequestCount
 	}
@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+// PeriodComparison holds the totals for a current period and an earlier period of the same length.
+type PeriodComparison struct {
+	CurrentStart  time.Time
+	CurrentEnd    time.Time
+	PreviousStart time.Time
+	PreviousEnd   time.Time
+	Current       Totals
+	Previous      Totals
+}
+
+// ParsePeriod converts a period name (day, week, month) into its duration.
+// Months are treated as 30 days.
+func ParsePeriod(period string) (time.Duration, error) {
+	switch period {
+	case "day":
+		return 24 * time.Hour, nil
+	case "week":
+		return 7 * 24 * time.Hour, nil
+	case "month":
+		return 30 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown period %q (expected day, week or month)", period)
+	}
+}
+
+// ComparePeriods aggregates the rolling period ending at now and the period
+// offset periods before it, so offset 1 compares against the immediately
+// preceding period.
+func ComparePeriods(logs []logging.QueryLog, period time.Duration, offset int, now time.Time) PeriodComparison {
+	c := PeriodComparison{
+		CurrentStart: now.Add(-period),
+		CurrentEnd:   now,
+	}
+	c.PreviousStart = c.CurrentStart.Add(-time.Duration(offset) * period)
+	// End just before the next period starts so boundary logs aren't counted twice
+	c.PreviousEnd = c.PreviousStart.Add(period - time.Nanosecond)
+
+	c.Current = CalculateTotals(AggregateLogs(logs, time.Hour, c.CurrentStart, c.CurrentEnd))
+	c.Previous = CalculateTotals(AggregateLogs(logs, time.Hour, c.PreviousStart, c.PreviousEnd))
+	return c
+}
+
+// PercentChange returns the relative change from previous to current in percent.
+// ok is false when previous is zero and the change is undefined.
+func PercentChange(previous, current float64) (change float64, ok bool) {
+	if previous == 0 {
+		return 0, false
+	}
+	return (current - previous) / previous * 100, true
+}
@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestComparePeriods(t *testing.T) {
+	now := time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	logs := []logging.QueryLog{
+		// Current week
+		{Timestamp: now.Add(-1 * time.Hour), Success: true, EstimatedCost: 2.0, TotalTokens: 200},
+		{Timestamp: now.Add(-48 * time.Hour), Success: false},
+		// Previous week
+		{Timestamp: now.Add(-week - time.Hour), Success: true, EstimatedCost: 1.0, TotalTokens: 100},
+		// Two weeks back
+		{Timestamp: now.Add(-2*week - time.Hour), Success: true, EstimatedCost: 4.0, TotalTokens: 400},
+	}
+
+	c := ComparePeriods(logs, week, 1, now)
+	if c.Current.TotalRequests != 2 || c.Current.TotalCost != 2.0 || c.Current.ErrorRate != 50 {
+		t.Errorf("Unexpected current totals: %+v", c.Current)
+	}
+	if c.Previous.TotalRequests != 1 || c.Previous.TotalCost != 1.0 {
+		t.Errorf("Unexpected previous totals: %+v", c.Previous)
+	}
+
+	c = ComparePeriods(logs, week, 2, now)
+	if c.Previous.TotalRequests != 1 || c.Previous.TotalTokens != 400 {
+		t.Errorf("Expected offset 2 to compare against two weeks back, got %+v", c.Previous)
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	if got, ok := PercentChange(2, 3); !ok || got != 50 {
+		t.Errorf("PercentChange(2, 3) = %v, %v; want 50, true", got, ok)
+	}
+	if got, ok := PercentChange(4, 1); !ok || got != -75 {
+		t.Errorf("PercentChange(4, 1) = %v, %v; want -75, true", got, ok)
+	}
+	if _, ok := PercentChange(0, 5); ok {
+		t.Error("Expected change from zero to be undefined")
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	if d, err := ParsePeriod("week"); err != nil || d != 7*24*time.Hour {
+		t.Errorf("ParsePeriod(week) = %v, %v", d, err)
+	}
+	if _, err := ParsePeriod("fortnight"); err == nil {
+		t.Error("Expected error for unknown period")
+	}
+}
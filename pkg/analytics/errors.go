@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+// ErrorCount is a distinct error message and how many times it occurred.
+type ErrorCount struct {
+	Message string
+	Count   int
+}
+
+// ErrorSummary describes the failures in a set of logs.
+type ErrorSummary struct {
+	TotalRequests  int
+	FailedRequests int
+	ErrorRate      float64      // Percentage of failed requests (0-100)
+	Messages       []ErrorCount // Distinct error messages, most frequent first
+}
+
+// SummarizeErrors computes the error rate of logs and groups failures by message.
+func SummarizeErrors(logs []logging.QueryLog) ErrorSummary {
+	summary := ErrorSummary{TotalRequests: len(logs)}
+	counts := make(map[string]int)
+	for _, log := range logs {
+		if log.Success {
+			continue
+		}
+		summary.FailedRequests++
+		message := log.Error
+		if message == "" {
+			message = "(no error message)"
+		}
+		counts[message]++
+	}
+
+	if summary.TotalRequests > 0 {
+		summary.ErrorRate = float64(summary.FailedRequests) / float64(summary.TotalRequests) * 100
+	}
+
+	for message, count := range counts {
+		summary.Messages = append(summary.Messages, ErrorCount{Message: message, Count: count})
+	}
+	sort.Slice(summary.Messages, func(i, j int) bool {
+		if summary.Messages[i].Count != summary.Messages[j].Count {
+			return summary.Messages[i].Count > summary.Messages[j].Count
+		}
+		return summary.Messages[i].Message < summary.Messages[j].Message
+	})
+
+	return summary
+}
@@ -0,0 +1,33 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestSummarizeErrors(t *testing.T) {
+	logs := []logging.QueryLog{
+		{Success: true},
+		{Success: true},
+		{Success: false, Error: "quota exceeded"},
+		{Success: false, Error: "quota exceeded"},
+		{Success: false, Error: "deadline exceeded"},
+		{Success: false},
+	}
+
+	s := SummarizeErrors(logs)
+	if s.TotalRequests != 6 || s.FailedRequests != 4 {
+		t.Errorf("Unexpected counts: %+v", s)
+	}
+	if s.ErrorRate < 66.6 || s.ErrorRate > 66.7 {
+		t.Errorf("Expected ~66.7%% error rate, got %.2f", s.ErrorRate)
+	}
+	if len(s.Messages) != 3 || s.Messages[0].Message != "quota exceeded" || s.Messages[0].Count != 2 {
+		t.Errorf("Expected most frequent message first, got %+v", s.Messages)
+	}
+
+	if empty := SummarizeErrors(nil); empty.ErrorRate != 0 || len(empty.Messages) != 0 {
+		t.Errorf("Expected empty summary, got %+v", empty)
+	}
+}
@@ -0,0 +1,196 @@
+package analytics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+// CacheMetrics holds the per-cache values exported as OpenMetrics samples.
+type CacheMetrics struct {
+	Name       string
+	Repo       string
+	Model      string
+	HitRate    float64 // Average cache hit rate (0-1)
+	TokenCount int64
+	Queries    int64
+	SavingsUSD float64
+}
+
+// metricsFormat selects the text exposition format metrics are written in.
+type metricsFormat int
+
+const (
+	// formatOpenMetrics names counter families without the _total suffix and ends with # EOF
+	formatOpenMetrics metricsFormat = iota
+	// formatPrometheus is the Prometheus text format, version 0.0.4
+	formatPrometheus
+)
+
+// requestKey groups request metrics by model, caller and outcome.
+type requestKey struct {
+	model   string
+	caller  string
+	success bool
+}
+
+// requestMetrics holds aggregated request counters for a single requestKey.
+type requestMetrics struct {
+	requests         int64
+	promptTokens     int64
+	completionTokens int64
+	cachedTokens     int64
+	costUSD          float64
+}
+
+// aggregateRequests sums request usage from the logs per requestKey and
+// returns the keys sorted so scrapes are stable.
+func aggregateRequests(logs []logging.QueryLog) ([]requestKey, map[requestKey]*requestMetrics) {
+	byKey := make(map[requestKey]*requestMetrics)
+	for _, log := range logs {
+		key := requestKey{model: log.Model, caller: log.Caller, success: log.Success}
+		m, ok := byKey[key]
+		if !ok {
+			m = &requestMetrics{}
+			byKey[key] = m
+		}
+		m.requests++
+		m.promptTokens += int64(log.PromptTokens)
+		m.completionTokens += int64(log.CompletionTokens)
+		m.cachedTokens += int64(log.CachedTokens)
+		m.costUSD += log.EstimatedCost
+	}
+
+	keys := make([]requestKey, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].model != keys[j].model {
+			return keys[i].model < keys[j].model
+		}
+		if keys[i].caller != keys[j].caller {
+			return keys[i].caller < keys[j].caller
+		}
+		return keys[i].success && !keys[j].success
+	})
+	return keys, byKey
+}
+
+// WriteOpenMetrics writes cache analytics and aggregate request usage from the
+// local query logs to w in the OpenMetrics text exposition format.
+func WriteOpenMetrics(w io.Writer, caches []CacheMetrics, logs []logging.QueryLog) error {
+	return writeMetrics(w, formatOpenMetrics, caches, logs)
+}
+
+// writeMetrics writes the per-cache metrics, if any, and the request usage
+// aggregated from logs to w in the given format.
+func writeMetrics(w io.Writer, format metricsFormat, caches []CacheMetrics, logs []logging.QueryLog) error {
+	var b strings.Builder
+
+	// Per-cache metrics
+	if len(caches) > 0 {
+		writeFamily(&b, format, "grove_gemini_cache_hit_rate", "gauge", "Average cache hit rate (0-1) across queries using the cache.")
+		for _, c := range caches {
+			writeSample(&b, "grove_gemini_cache_hit_rate", cacheLabels(c), c.HitRate)
+		}
+		writeFamily(&b, format, "grove_gemini_cache_tokens", "gauge", "Number of tokens stored in the cache.")
+		for _, c := range caches {
+			writeSample(&b, "grove_gemini_cache_tokens", cacheLabels(c), float64(c.TokenCount))
+		}
+		writeFamily(&b, format, "grove_gemini_cache_queries", "counter", "Number of queries served using the cache.")
+		for _, c := range caches {
+			writeSample(&b, "grove_gemini_cache_queries_total", cacheLabels(c), float64(c.Queries))
+		}
+		writeFamily(&b, format, "grove_gemini_cache_savings_usd", "gauge", "Estimated cost savings in USD from using the cache.")
+		for _, c := range caches {
+			writeSample(&b, "grove_gemini_cache_savings_usd", cacheLabels(c), c.SavingsUSD)
+		}
+	}
+
+	// Aggregate request metrics from logs. The logs cover a sliding window, so
+	// these sums can go down between scrapes and are exported as gauges.
+	keys, byKey := aggregateRequests(logs)
+
+	writeFamily(&b, format, "grove_gemini_requests", "gauge", "Number of Gemini API requests in the exported log window.")
+	for _, k := range keys {
+		writeSample(&b, "grove_gemini_requests", requestLabels(k), float64(byKey[k].requests))
+	}
+	writeFamily(&b, format, "grove_gemini_tokens", "gauge", "Tokens used by Gemini API requests in the exported log window.")
+	for _, k := range keys {
+		m := byKey[k]
+		for _, t := range []struct {
+			kind  string
+			value int64
+		}{
+			{"prompt", m.promptTokens},
+			{"completion", m.completionTokens},
+			{"cached", m.cachedTokens},
+		} {
+			labels := append(requestLabels(k), [2]string{"type", t.kind})
+			writeSample(&b, "grove_gemini_tokens", labels, float64(t.value))
+		}
+	}
+	writeFamily(&b, format, "grove_gemini_cost_usd", "gauge", "Estimated cost in USD of Gemini API requests in the exported log window.")
+	for _, k := range keys {
+		writeSample(&b, "grove_gemini_cost_usd", requestLabels(k), byKey[k].costUSD)
+	}
+	writeFamily(&b, format, "grove_gemini_request_cache_hit_rate", "gauge", "Fraction of prompt tokens served from cache (0-1).")
+	for _, k := range keys {
+		m := byKey[k]
+		hitRate := 0.0
+		if m.promptTokens > 0 {
+			hitRate = float64(m.cachedTokens) / float64(m.promptTokens)
+		}
+		writeSample(&b, "grove_gemini_request_cache_hit_rate", requestLabels(k), hitRate)
+	}
+
+	if format == formatOpenMetrics {
+		b.WriteString("# EOF\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func cacheLabels(c CacheMetrics) [][2]string {
+	return [][2]string{{"cache", c.Name}, {"repo", c.Repo}, {"model", c.Model}}
+}
+
+func requestLabels(k requestKey) [][2]string {
+	success := "false"
+	if k.success {
+		success = "true"
+	}
+	return [][2]string{{"model", k.model}, {"caller", k.caller}, {"success", success}}
+}
+
+// writeFamily writes the TYPE and HELP lines of a metric family. The
+// Prometheus text format names counter families after their _total samples.
+func writeFamily(b *strings.Builder, format metricsFormat, name, metricType, help string) {
+	if format == formatPrometheus && metricType == "counter" {
+		name += "_total"
+	}
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, metricType)
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+}
+
+func writeSample(b *strings.Builder, name string, labels [][2]string, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		parts := make([]string, 0, len(labels))
+		for _, l := range labels {
+			parts = append(parts, fmt.Sprintf("%s=\"%s\"", l[0], escapeLabelValue(l[1])))
+		}
+		b.WriteString("{" + strings.Join(parts, ",") + "}")
+	}
+	fmt.Fprintf(b, " %g\n", value)
+}
+
+// escapeLabelValue escapes a label value per the OpenMetrics text format.
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	caches := []CacheMetrics{
+		{Name: "abc123", Repo: `my"repo`, Model: "gemini-2.5-pro", HitRate: 0.5, TokenCount: 10000, Queries: 3, SavingsUSD: 0.25},
+	}
+	logs := []logging.QueryLog{
+		{Model: "gemini-2.5-pro", Success: true, PromptTokens: 100, CompletionTokens: 50, CachedTokens: 20, EstimatedCost: 0.01},
+		{Model: "gemini-2.5-pro", Success: true, PromptTokens: 200, CompletionTokens: 25, EstimatedCost: 0.02},
+		{Model: "gemini-2.5-pro", Success: false},
+	}
+
+	var b strings.Builder
+	if err := WriteOpenMetrics(&b, caches, logs); err != nil {
+		t.Fatalf("WriteOpenMetrics returned error: %v", err)
+	}
+	out := b.String()
+
+	expected := []string{
+		"# TYPE grove_gemini_cache_hit_rate gauge",
+		`grove_gemini_cache_hit_rate{cache="abc123",repo="my\"repo",model="gemini-2.5-pro"} 0.5`,
+		`grove_gemini_cache_queries_total{cache="abc123",repo="my\"repo",model="gemini-2.5-pro"} 3`,
+		`grove_gemini_requests{model="gemini-2.5-pro",caller="",success="true"} 2`,
+		`grove_gemini_requests{model="gemini-2.5-pro",caller="",success="false"} 1`,
+		`grove_gemini_tokens{model="gemini-2.5-pro",caller="",success="true",type="prompt"} 300`,
+		"# TYPE grove_gemini_requests gauge",
+		`grove_gemini_cost_usd{model="gemini-2.5-pro",caller="",success="true"} 0.03`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q\n%s", want, out)
+		}
+	}
+
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("expected output to end with # EOF")
+	}
+}
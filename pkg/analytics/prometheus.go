@@ -0,0 +1,17 @@
+package analytics
+
+import (
+	"io"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheusMetrics writes request usage from the local query logs to w
+// in the Prometheus text exposition format. It emits the same request
+// families as WriteOpenMetrics, labeled by model, caller and outcome.
+func WritePrometheusMetrics(w io.Writer, logs []logging.QueryLog) error {
+	return writeMetrics(w, formatPrometheus, nil, logs)
+}
@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+func TestWritePrometheusMetrics(t *testing.T) {
+	logs := []logging.QueryLog{
+		{Model: "gemini-2.5-pro", Caller: "grove-flow", Success: true, PromptTokens: 100, CompletionTokens: 50, CachedTokens: 80, EstimatedCost: 0.01},
+		{Model: "gemini-2.5-pro", Caller: "grove-flow", Success: true, PromptTokens: 300, CompletionTokens: 25, CachedTokens: 120, EstimatedCost: 0.02},
+		{Model: "gemini-2.5-pro", Caller: "grove-flow", Success: false},
+		{Model: "gemini-2.0-flash", Caller: `my"tool`, Success: true, PromptTokens: 10},
+	}
+
+	var b strings.Builder
+	if err := WritePrometheusMetrics(&b, logs); err != nil {
+		t.Fatalf("WritePrometheusMetrics returned error: %v", err)
+	}
+	out := b.String()
+
+	expected := []string{
+		"# TYPE grove_gemini_requests gauge",
+		`grove_gemini_requests{model="gemini-2.5-pro",caller="grove-flow",success="true"} 2`,
+		`grove_gemini_requests{model="gemini-2.5-pro",caller="grove-flow",success="false"} 1`,
+		`grove_gemini_requests{model="gemini-2.0-flash",caller="my\"tool",success="true"} 1`,
+		`grove_gemini_tokens{model="gemini-2.5-pro",caller="grove-flow",success="true",type="cached"} 200`,
+		`grove_gemini_cost_usd{model="gemini-2.5-pro",caller="grove-flow",success="true"} 0.03`,
+		"# TYPE grove_gemini_request_cache_hit_rate gauge",
+		`grove_gemini_request_cache_hit_rate{model="gemini-2.5-pro",caller="grove-flow",success="true"} 0.5`,
+		`grove_gemini_request_cache_hit_rate{model="gemini-2.0-flash",caller="my\"tool",success="true"} 0`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q\n%s", want, out)
+		}
+	}
+
+	// Models are sorted so scrapes are stable
+	if strings.Index(out, `model="gemini-2.0-flash"`) > strings.Index(out, `model="gemini-2.5-pro"`) {
+		t.Error("expected series to be sorted by model")
+	}
+	// The logs cover a sliding window, so none of the request sums are counters
+	if strings.Contains(out, " counter\n") {
+		t.Errorf("expected request families to be gauges\n%s", out)
+	}
+	if strings.Contains(out, "grove_gemini_cache_tokens") {
+		t.Error("expected no per-cache families without caches")
+	}
+	if strings.Contains(out, "# EOF") {
+		t.Error("Prometheus text format has no EOF marker")
+	}
+}
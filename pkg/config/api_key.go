@@ -16,6 +16,73 @@ import (
 type GeminiConfig struct {
 	APIKey        string `yaml:"api_key" jsonschema:"description=Direct API key for Google Gemini" jsonschema_extras:"x-layer=global,x-priority=200,x-sensitive=true,x-important=true,x-hint=Consider using api_key_command to fetch from a secrets manager"`
 	APIKeyCommand string `yaml:"api_key_command" jsonschema:"description=Shell command to retrieve API key (e.g. gcloud secrets or 1password)" jsonschema_extras:"x-layer=global,x-priority=60,x-important=true"`
+	DefaultModel  string `yaml:"default_model" jsonschema:"description=Model used by request and count-tokens when --model is not passed" jsonschema_extras:"x-layer=project,x-priority=100"`
+
+	Backend string        `yaml:"backend,omitempty" jsonschema:"description=API backend: gemini (Gemini Developer API with an API key) or vertex (Vertex AI with Application Default Credentials); GROVE_GEMINI_BACKEND takes precedence" jsonschema_extras:"x-layer=global,x-priority=55"`
+	Vertex  *VertexConfig `yaml:"vertex,omitempty" jsonschema:"description=Project and location used with the vertex backend" jsonschema_extras:"x-layer=global,x-priority=56"`
+
+	PromptPrefix string `yaml:"prompt_prefix,omitempty" jsonschema:"description=Text prepended to every request prompt unless --prompt-prefix is passed" jsonschema_extras:"x-layer=project,x-priority=110"`
+	PromptSuffix string `yaml:"prompt_suffix,omitempty" jsonschema:"description=Text appended to every request prompt unless --prompt-suffix is passed" jsonschema_extras:"x-layer=project,x-priority=111"`
+
+	// Generation defaults for request; the matching flags override them
+	Temperature     *float32 `yaml:"temperature,omitempty" jsonschema:"description=Default sampling temperature (0.0-2.0) used when --temperature is not passed" jsonschema_extras:"x-layer=project,x-priority=112"`
+	TopP            *float32 `yaml:"top_p,omitempty" jsonschema:"description=Default nucleus sampling top-p (0.0-1.0) used when --top-p is not passed" jsonschema_extras:"x-layer=project,x-priority=113"`
+	TopK            *int32   `yaml:"top_k,omitempty" jsonschema:"description=Default top-k sampling used when --top-k is not passed" jsonschema_extras:"x-layer=project,x-priority=114"`
+	MaxOutputTokens *int32   `yaml:"max_output_tokens,omitempty" jsonschema:"description=Default maximum response length in tokens used when --max-output-tokens is not passed" jsonschema_extras:"x-layer=project,x-priority=116"`
+
+	Timeout string `yaml:"timeout,omitempty" jsonschema:"description=Default request timeout used when --timeout is not passed (a Go duration such as 5m; unset waits indefinitely)" jsonschema_extras:"x-layer=project,x-priority=117"`
+
+	MinCacheTokens int `yaml:"min_cache_tokens,omitempty" jsonschema:"description=Smallest cold context in tokens that is cached (default 4096; some models accept less)" jsonschema_extras:"x-layer=project,x-priority=115"`
+
+	SharedCache bool `yaml:"shared_cache,omitempty" jsonschema:"description=Reuse a server cache with the same content and model created by another project through a shared index in ~/.grove/gemini-cache (off by default since projects then share caches)" jsonschema_extras:"x-layer=global,x-priority=118"`
+
+	CacheAdvice *CacheAdviceConfig `yaml:"cache_advice,omitempty" jsonschema:"description=Thresholds for recommending that caching be disabled for a context" jsonschema_extras:"x-layer=global,x-priority=120"`
+
+	ThousandsSeparator *string `yaml:"thousands_separator,omitempty" jsonschema:"description=Digit grouping separator for token counts and costs (default is a comma; an empty string disables grouping)" jsonschema_extras:"x-layer=global,x-priority=122"`
+
+	PricingOverrides map[string]PricingOverride `yaml:"pricing_overrides,omitempty" jsonschema:"description=Per-model prices (USD per million tokens) used for cost estimates instead of the built-in table" jsonschema_extras:"x-layer=global,x-priority=125"`
+
+	Redaction *RedactionConfig `yaml:"redaction,omitempty" jsonschema:"description=Masking of secrets in debug logs and error output" jsonschema_extras:"x-layer=global,x-priority=130"`
+
+	LogDir string `yaml:"log_dir,omitempty" jsonschema:"description=Directory for query logs instead of the grove state directory (GROVE_GEMINI_LOG_DIR takes precedence)" jsonschema_extras:"x-layer=global,x-priority=135"`
+}
+
+// VertexConfig locates the Vertex AI endpoint used with backend: vertex
+type VertexConfig struct {
+	Project  string `yaml:"project,omitempty" jsonschema:"description=Google Cloud project ID (default GOOGLE_CLOUD_PROJECT or the saved default project)"`
+	Location string `yaml:"location,omitempty" jsonschema:"description=Vertex AI region such as us-central1 or global (default GOOGLE_CLOUD_LOCATION or us-central1)"`
+}
+
+// CacheAdviceConfig controls when a cache is considered not worth keeping
+type CacheAdviceConfig struct {
+	MinHitRate       float64 `yaml:"min_hit_rate,omitempty" jsonschema:"description=Average cache hit rate (0-1) below which disabling caching is recommended (default 0.3)"`
+	MinQueries       int     `yaml:"min_queries,omitempty" jsonschema:"description=Number of queries a cache must serve before a recommendation is made (default 5)"`
+	WarnAfterRequest bool    `yaml:"warn_after_request,omitempty" jsonschema:"description=Print a warning after a request that used a low hit rate cache"`
+}
+
+// PricingOverride replaces the built-in prices for a model. Keys in
+// pricing_overrides match a model exactly or as a substring of its name.
+type PricingOverride struct {
+	Input          float64  `yaml:"input" jsonschema:"description=USD per million input tokens"`
+	Output         float64  `yaml:"output" jsonschema:"description=USD per million output tokens"`
+	CachedDiscount *float64 `yaml:"cached_discount,omitempty" jsonschema:"description=Fraction (0-1) taken off the input price for cached tokens (default 0.75)"`
+	Storage        float64  `yaml:"storage,omitempty" jsonschema:"description=USD per million cached tokens per hour of cache storage (default from the built-in model table)"`
+	// LongContext sets higher prices for prompts above a token threshold
+	LongContext *LongContextPricing `yaml:"long_context,omitempty" jsonschema:"description=Prices that apply instead when the prompt exceeds a token threshold"`
+}
+
+// LongContextPricing is the price tier for prompts longer than Threshold tokens.
+// A zero price falls back to the override's regular price.
+type LongContextPricing struct {
+	Threshold int     `yaml:"threshold" jsonschema:"description=Prompt tokens above which these prices apply (e.g. 200000)"`
+	Input     float64 `yaml:"input,omitempty" jsonschema:"description=USD per million input tokens for long prompts"`
+	Output    float64 `yaml:"output,omitempty" jsonschema:"description=USD per million output tokens for long prompts"`
+}
+
+// RedactionConfig controls which secrets are masked before prompts and errors are logged
+type RedactionConfig struct {
+	Patterns        []string `yaml:"patterns,omitempty" jsonschema:"description=Additional regular expressions whose matches are redacted (a named group 'secret' limits redaction to that group)"`
+	DisableDefaults bool     `yaml:"disable_defaults,omitempty" jsonschema:"description=Skip the built-in API key and token patterns and use only the patterns listed here"`
 }
 
 // ResolveAPIKey resolves the Gemini API key from multiple sources in order of precedence:
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Backends the Gemini client can talk to
+const (
+	BackendGeminiAPI = "gemini"
+	BackendVertexAI  = "vertex"
+)
+
+// BackendEnvVar selects the backend, overriding gemini.backend in grove.yml
+const BackendEnvVar = "GROVE_GEMINI_BACKEND"
+
+// DefaultVertexLocation is the Vertex AI region used when none is configured
+const DefaultVertexLocation = "us-central1"
+
+// BackendSettings is the resolved backend and, for Vertex AI, where to send requests
+type BackendSettings struct {
+	Backend  string
+	Project  string // Vertex AI only
+	Location string // Vertex AI only
+}
+
+// IsVertex reports whether requests go to Vertex AI
+func (s BackendSettings) IsVertex() bool {
+	return s.Backend == BackendVertexAI
+}
+
+// ResolveBackend returns the backend to use: GROVE_GEMINI_BACKEND, then
+// gemini.backend in grove.yml, then the Gemini Developer API. For Vertex AI
+// the project comes from gemini.vertex.project, GOOGLE_CLOUD_PROJECT or the
+// saved default GCP project, and the location from gemini.vertex.location,
+// GOOGLE_CLOUD_LOCATION or DefaultVertexLocation. grove.yml is read for
+// workDir, or the current directory when it is empty.
+func ResolveBackend(workDir string) (BackendSettings, error) {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil {
+		geminiCfg = &GeminiConfig{}
+	}
+
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv(BackendEnvVar)))
+	if backend == "" {
+		backend = strings.ToLower(strings.TrimSpace(geminiCfg.Backend))
+	}
+	switch backend {
+	case "", BackendGeminiAPI:
+		return BackendSettings{Backend: BackendGeminiAPI}, nil
+	case BackendVertexAI, "vertexai", "vertex-ai":
+	default:
+		return BackendSettings{}, fmt.Errorf("unknown Gemini backend %q (expected %q or %q)", backend, BackendGeminiAPI, BackendVertexAI)
+	}
+
+	settings := BackendSettings{Backend: BackendVertexAI}
+	if geminiCfg.Vertex != nil {
+		settings.Project = geminiCfg.Vertex.Project
+		settings.Location = geminiCfg.Vertex.Location
+	}
+	if settings.Project == "" {
+		settings.Project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	if settings.Project == "" {
+		settings.Project = GetDefaultProject(workDir)
+	}
+	if settings.Location == "" {
+		settings.Location = os.Getenv("GOOGLE_CLOUD_LOCATION")
+	}
+	if settings.Location == "" {
+		settings.Location = DefaultVertexLocation
+	}
+	if settings.Project == "" {
+		return BackendSettings{}, fmt.Errorf("the Vertex AI backend needs a project: set gemini.vertex.project in grove.yml or GOOGLE_CLOUD_PROJECT")
+	}
+	return settings, nil
+}
@@ -0,0 +1,32 @@
+package config
+
+const (
+	// DefaultCacheMinHitRate is the average hit rate below which disabling caching is recommended
+	DefaultCacheMinHitRate = 0.3
+	// DefaultCacheMinQueries is the number of queries needed before a recommendation is made
+	DefaultCacheMinQueries = 5
+)
+
+// ResolveCacheAdvice returns the gemini.cache_advice settings for workDir,
+// filling unset values with defaults.
+func ResolveCacheAdvice(workDir string) CacheAdviceConfig {
+	advice := CacheAdviceConfig{
+		MinHitRate: DefaultCacheMinHitRate,
+		MinQueries: DefaultCacheMinQueries,
+	}
+
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil || geminiCfg.CacheAdvice == nil {
+		return advice
+	}
+
+	if geminiCfg.CacheAdvice.MinHitRate > 0 {
+		advice.MinHitRate = geminiCfg.CacheAdvice.MinHitRate
+	}
+	if geminiCfg.CacheAdvice.MinQueries > 0 {
+		advice.MinQueries = geminiCfg.CacheAdvice.MinQueries
+	}
+	advice.WarnAfterRequest = geminiCfg.CacheAdvice.WarnAfterRequest
+
+	return advice
+}
@@ -0,0 +1,21 @@
+package config
+
+// DefaultMinCacheTokens is the smallest cold context, in tokens, that is cached
+// when gemini.min_cache_tokens isn't set
+const DefaultMinCacheTokens = 4096
+
+// ResolveMinCacheTokens returns the gemini.min_cache_tokens configured for
+// workDir, or DefaultMinCacheTokens when it is unset or not positive.
+func ResolveMinCacheTokens(workDir string) int {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil || geminiCfg.MinCacheTokens <= 0 {
+		return DefaultMinCacheTokens
+	}
+	return geminiCfg.MinCacheTokens
+}
+
+// ResolveSharedCache reports whether gemini.shared_cache is enabled for workDir
+func ResolveSharedCache(workDir string) bool {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	return err == nil && geminiCfg.SharedCache
+}
@@ -0,0 +1,11 @@
+package config
+
+// ResolveThousandsSeparator returns gemini.thousands_separator for workDir.
+// ok is false when it isn't set, so callers keep their default.
+func ResolveThousandsSeparator(workDir string) (sep string, ok bool) {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil || geminiCfg.ThousandsSeparator == nil {
+		return "", false
+	}
+	return *geminiCfg.ThousandsSeparator, true
+}
@@ -0,0 +1,390 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	core_config "github.com/grovetools/core/config"
+	"github.com/grovetools/core/pkg/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// mapKeyPlaceholder stands for a user-chosen map key, such as the model in pricing_overrides
+const mapKeyPlaceholder = "<model>"
+
+// ConfigKey describes a gemini setting that can be read and written by key
+type ConfigKey struct {
+	Key         string // dotted path, e.g. "cache_advice.min_hit_rate" or "pricing_overrides.<model>.input"
+	Type        string // string, integer, number or boolean
+	Description string
+}
+
+// ConfigKeys lists every scalar gemini setting in the order GeminiConfig declares them.
+// List-valued settings such as redaction.patterns are left out; edit those in grove.yml.
+func ConfigKeys() []ConfigKey {
+	var keys []ConfigKey
+	collectConfigKeys(reflect.TypeOf(GeminiConfig{}), "", &keys)
+	return keys
+}
+
+func collectConfigKeys(t reflect.Type, prefix string, keys *[]ConfigKey) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+		key := prefix + name
+		ft := derefType(field.Type)
+		switch {
+		case ft.Kind() == reflect.Struct:
+			collectConfigKeys(ft, key+".", keys)
+		case ft.Kind() == reflect.Map && derefType(ft.Elem()).Kind() == reflect.Struct:
+			collectConfigKeys(derefType(ft.Elem()), key+"."+mapKeyPlaceholder+".", keys)
+		case scalarTypeName(ft) != "":
+			*keys = append(*keys, ConfigKey{Key: key, Type: scalarTypeName(ft), Description: schemaDescription(field)})
+		}
+	}
+}
+
+// GetGeminiValue resolves a single gemini setting for workDir and reports which
+// grove.yml layer supplied it. Keys shown by 'config effective' resolve the same
+// way, including environment overrides and defaults.
+func GetGeminiValue(workDir, key string) (EffectiveSetting, error) {
+	segments, _, err := lookupConfigKey(key)
+	if err != nil {
+		return EffectiveSetting{}, err
+	}
+
+	settings, err := ResolveEffectiveConfig(workDir)
+	if err != nil {
+		return EffectiveSetting{}, err
+	}
+	for _, s := range settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+
+	if workDir == "" {
+		if workDir, err = os.Getwd(); err != nil {
+			return EffectiveSetting{}, fmt.Errorf("getting current directory: %w", err)
+		}
+	}
+	layered, err := core_config.LoadLayered(workDir)
+	if err != nil {
+		return EffectiveSetting{}, fmt.Errorf("loading grove.yml layers: %w", err)
+	}
+	layers := geminiLayers(layered)
+	for i := len(layers) - 1; i >= 0; i-- {
+		if value, ok := rawValue(layers[i].gemini, segments); ok {
+			return EffectiveSetting{
+				Key:    key,
+				Value:  fmt.Sprint(value),
+				Source: fmt.Sprintf("%s (%s)", layers[i].source, layers[i].path),
+			}, nil
+		}
+	}
+	return EffectiveSetting{Key: key, Source: SourceUnset}, nil
+}
+
+// DefaultsToGlobal reports whether 'config set' writes key to the global
+// grove.yml when no scope is given: sensitive settings such as api_key and
+// settings the schema places in the global layer (x-layer=global) stay out of
+// project files, which are often committed. Nested keys inherit the layer of
+// their section.
+func DefaultsToGlobal(key string) bool {
+	t := reflect.TypeOf(GeminiConfig{})
+	global := false
+	for _, segment := range strings.Split(key, ".") {
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByYAMLName(t, segment)
+			if !ok {
+				return global
+			}
+			extras := strings.Split(field.Tag.Get("jsonschema_extras"), ",")
+			switch {
+			case slices.Contains(extras, "x-sensitive=true"):
+				return true
+			case slices.Contains(extras, "x-layer=global"):
+				global = true
+			case slices.Contains(extras, "x-layer=project"):
+				global = false
+			}
+			t = derefType(field.Type)
+		case reflect.Map:
+			t = derefType(t.Elem())
+		default:
+			return global
+		}
+	}
+	return global
+}
+
+// ConfigFileForScope returns the grove.yml that 'config set' writes to: the global
+// config when global is true, otherwise the project config that applies to workDir.
+func ConfigFileForScope(workDir string, global bool) (string, error) {
+	if global {
+		configDir := paths.ConfigDir()
+		if configDir == "" {
+			return "", fmt.Errorf("could not determine grove config directory")
+		}
+		return filepath.Join(configDir, "grove.yml"), nil
+	}
+
+	if workDir == "" {
+		var err error
+		if workDir, err = os.Getwd(); err != nil {
+			return "", fmt.Errorf("getting current directory: %w", err)
+		}
+	}
+	layered, err := core_config.LoadLayered(workDir)
+	if err != nil {
+		return "", fmt.Errorf("loading grove.yml layers: %w", err)
+	}
+	if path := layered.FilePaths[core_config.SourceProject]; path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("no project grove.yml found from %s; create one or pass --global", workDir)
+}
+
+// SetGeminiValue sets key in the gemini section of the grove.yml at path,
+// creating the file and any intermediate sections as needed. The value is
+// parsed as the type the key expects, the resulting section is validated,
+// and the file is replaced atomically so other settings and comments survive.
+func SetGeminiValue(path, key, value string) error {
+	if ext := filepath.Ext(path); ext == ".toml" {
+		return fmt.Errorf("editing TOML config files is not supported; set gemini.%s in %s by hand", key, path)
+	}
+
+	segments, leafType, err := lookupConfigKey(key)
+	if err != nil {
+		return err
+	}
+	valueNode, err := scalarNode(leafType, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path) //nolint:gosec // path is the user's grove.yml
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s does not contain a YAML mapping", path)
+	}
+
+	gemini, err := mappingChild(root, "gemini")
+	if err != nil {
+		return err
+	}
+	parent := gemini
+	for _, segment := range segments[:len(segments)-1] {
+		if parent, err = mappingChild(parent, segment); err != nil {
+			return fmt.Errorf("gemini.%s: %w", key, err)
+		}
+	}
+	setMappingValue(parent, segments[len(segments)-1], valueNode)
+
+	var cfg GeminiConfig
+	if err := gemini.Decode(&cfg); err != nil {
+		return fmt.Errorf("gemini section is invalid after setting %s: %w", key, err)
+	}
+	if _, err := validOverrides("pricing_overrides", cfg.PricingOverrides); err != nil {
+		return err
+	}
+	if cfg.Timeout != "" {
+		if _, err := parseTimeout(cfg.Timeout); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // config directory
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, buf.Bytes(), mode); err != nil { //nolint:gosec // grove.yml is not secret unless it already was
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		_ = os.Remove(tempFile) // best-effort cleanup
+		return fmt.Errorf("saving %s: %w", path, err)
+	}
+	return nil
+}
+
+// lookupConfigKey splits a dotted key and checks it against GeminiConfig,
+// returning the path segments and the Go type of the setting
+func lookupConfigKey(key string) ([]string, reflect.Type, error) {
+	segments := strings.Split(key, ".")
+	t := reflect.TypeOf(GeminiConfig{})
+	for i, segment := range segments {
+		if segment == "" {
+			return nil, nil, fmt.Errorf("invalid config key %q", key)
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByYAMLName(t, segment)
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown config key %q (run 'grove-gemini config list' to see valid keys)", key)
+			}
+			t = derefType(field.Type)
+		case reflect.Map:
+			t = derefType(t.Elem())
+		default:
+			return nil, nil, fmt.Errorf("unknown config key %q: %s is not a section", key, strings.Join(segments[:i], "."))
+		}
+	}
+	if scalarTypeName(t) == "" {
+		return nil, nil, fmt.Errorf("%s is a section or list; set one of its keys or edit grove.yml directly", key)
+	}
+	return segments, t, nil
+}
+
+// scalarNode parses value as type t and returns it as a YAML scalar node
+func scalarNode(t reflect.Type, value string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	switch scalarTypeName(t) {
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false")
+		}
+		node.Tag, node.Value = "!!bool", strconv.FormatBool(b)
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer")
+		}
+		node.Tag, node.Value = "!!int", strconv.FormatInt(n, 10)
+	case "number":
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("expected a number")
+		}
+		node.Tag, node.Value = "!!float", strconv.FormatFloat(f, 'g', -1, t.Bits())
+	}
+	return node, nil
+}
+
+// mappingChild returns the mapping stored under key in m, adding an empty one if missing
+func mappingChild(m *yaml.Node, key string) (*yaml.Node, error) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != key {
+			continue
+		}
+		child := m.Content[i+1]
+		if child.Kind == yaml.ScalarNode && child.Tag == "!!null" {
+			child.Kind, child.Tag, child.Value = yaml.MappingNode, "!!map", ""
+		}
+		if child.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s is not a mapping", key)
+		}
+		return child, nil
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+	return child, nil
+}
+
+// setMappingValue replaces the value under key in m, or appends the pair
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			value.LineComment = m.Content[i+1].LineComment
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// rawValue walks the nested key path in a raw config section
+func rawValue(section map[string]interface{}, keys []string) (interface{}, bool) {
+	if !hasKey(section, keys...) {
+		return nil, false
+	}
+	var value interface{} = section
+	for _, key := range keys {
+		value = value.(map[string]interface{})[key]
+	}
+	return value, true
+}
+
+func fieldByYAMLName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlName(t.Field(i)) == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// schemaDescription returns the description from a field's jsonschema tag
+func schemaDescription(field reflect.StructField) string {
+	for _, part := range strings.Split(field.Tag.Get("jsonschema"), ",") {
+		if desc, ok := strings.CutPrefix(part, "description="); ok {
+			return desc
+		}
+	}
+	return ""
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// scalarTypeName names a settable scalar type the way the JSON schema does, or "" for anything else
+func scalarTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return ""
+}
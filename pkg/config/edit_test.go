@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetGeminiValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grove.yml")
+	original := "name: test\n# keep this comment\ngemini:\n  default_model: gemini-2.0-flash\n"
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kv := range [][2]string{
+		{"default_model", "gemini-2.5-pro"},
+		{"top_k", "40"},
+		{"cache_advice.warn_after_request", "true"},
+		{"pricing_overrides.my-model.input", "1.25"},
+		{"pricing_overrides.my-model.output", "10"},
+		{"pricing_overrides.my-model.long_context.threshold", "200000"},
+	} {
+		if err := SetGeminiValue(path, kv[0], kv[1]); err != nil {
+			t.Fatalf("SetGeminiValue(%s): %v", kv[0], err)
+		}
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // test path
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# keep this comment") || !strings.HasPrefix(string(data), "name: test\n") {
+		t.Errorf("other content was not preserved:\n%s", data)
+	}
+	var file struct {
+		Gemini GeminiConfig `yaml:"gemini"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	cfg := file.Gemini
+	if cfg.DefaultModel != "gemini-2.5-pro" || cfg.TopK == nil || *cfg.TopK != 40 {
+		t.Errorf("default_model = %q, top_k = %v", cfg.DefaultModel, cfg.TopK)
+	}
+	if cfg.CacheAdvice == nil || !cfg.CacheAdvice.WarnAfterRequest {
+		t.Errorf("cache_advice = %+v, want warn_after_request set", cfg.CacheAdvice)
+	}
+	override := cfg.PricingOverrides["my-model"]
+	if override.Input != 1.25 || override.Output != 10 || override.LongContext == nil || override.LongContext.Threshold != 200000 {
+		t.Errorf("pricing override = %+v", override)
+	}
+
+	errTests := []struct {
+		key, value, want string
+	}{
+		{"bogus", "1", "unknown config key"},
+		{"top_k", "forty", "expected an integer"},
+		{"cache_advice", "1", "is a section"},
+		{"redaction.patterns", "x", "is a section or list"},
+		{"pricing_overrides.my-model.input", "-1", "must not be negative"},
+		{"timeout", "5", "invalid gemini.timeout"},
+		{"timeout", "-5m", "must be positive"},
+	}
+	for _, tt := range errTests {
+		err := SetGeminiValue(path, tt.key, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("SetGeminiValue(%s, %s) error = %v, want %q", tt.key, tt.value, err, tt.want)
+		}
+	}
+	after, _ := os.ReadFile(path) //nolint:gosec // test path
+	if string(after) != string(data) {
+		t.Error("a rejected value should leave the file untouched")
+	}
+
+	if err := SetGeminiValue(filepath.Join(t.TempDir(), "grove.toml"), "default_model", "x"); err == nil {
+		t.Error("expected TOML files to be rejected")
+	}
+}
+
+func TestSetGeminiValueCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grove", "grove.yml")
+	if err := SetGeminiValue(path, "log_dir", "~/gemini-logs"); err != nil {
+		t.Fatalf("SetGeminiValue: %v", err)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // test path
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "gemini:\n  log_dir: ~/gemini-logs\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+}
+
+func TestConfigKeys(t *testing.T) {
+	keys := make(map[string]string)
+	for _, k := range ConfigKeys() {
+		keys[k.Key] = k.Type
+	}
+	for key, typ := range map[string]string{
+		"default_model":                   "string",
+		"temperature":                     "number",
+		"cache_advice.min_queries":        "integer",
+		"pricing_overrides.<model>.input": "number",
+	} {
+		if keys[key] != typ {
+			t.Errorf("ConfigKeys()[%s] = %q, want %q", key, keys[key], typ)
+		}
+	}
+	if _, ok := keys["redaction.patterns"]; ok {
+		t.Error("list settings should not be listed")
+	}
+}
+
+func TestDefaultsToGlobal(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"api_key", true},
+		{"backend", true},
+		{"cache_advice.min_hit_rate", true},
+		{"pricing_overrides.my-model.input", true},
+		{"default_model", false},
+		{"timeout", false},
+		{"bogus", false},
+	}
+	for _, tt := range tests {
+		if got := DefaultsToGlobal(tt.key); got != tt.want {
+			t.Errorf("DefaultsToGlobal(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
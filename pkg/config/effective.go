@@ -0,0 +1,272 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	core_config "github.com/grovetools/core/config"
+)
+
+// DefaultRequestModel is the model used when neither --model nor gemini.default_model is set
+const DefaultRequestModel = "gemini-2.0-flash"
+
+// Source labels for values that don't come from a grove.yml layer
+const (
+	SourceDefault = "default"
+	SourceUnset   = "unset"
+)
+
+// EffectiveSetting is a single resolved gemini setting and where its value came from.
+type EffectiveSetting struct {
+	Key    string
+	Value  string
+	Source string // e.g. "env (GEMINI_API_KEY)", "project (/repo/grove.yml)", "default"
+}
+
+// configLayer is one grove.yml layer that can contribute gemini settings
+type configLayer struct {
+	source core_config.ConfigSource
+	path   string
+	gemini map[string]interface{}
+}
+
+// ResolveEffectiveConfig resolves every gemini setting for workDir and reports,
+// for each one, which source supplied it. Layers are walked in the same order
+// grove-core merges them, so the last layer that sets a key wins.
+// Secret values such as the API key are masked.
+func ResolveEffectiveConfig(workDir string) ([]EffectiveSetting, error) {
+	if workDir == "" {
+		var err error
+		if workDir, err = os.Getwd(); err != nil {
+			return nil, fmt.Errorf("getting current directory: %w", err)
+		}
+	}
+
+	layered, err := core_config.LoadLayered(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading grove.yml layers: %w", err)
+	}
+
+	var geminiCfg GeminiConfig
+	if layered.Final != nil {
+		if err := layered.Final.UnmarshalExtension("gemini", &geminiCfg); err != nil {
+			return nil, fmt.Errorf("failed to parse 'gemini' configuration from grove.yml: %w", err)
+		}
+	}
+
+	layers := geminiLayers(layered)
+	source := func(keys ...string) string {
+		for i := len(layers) - 1; i >= 0; i-- {
+			if hasKey(layers[i].gemini, keys...) {
+				return fmt.Sprintf("%s (%s)", layers[i].source, layers[i].path)
+			}
+		}
+		return SourceUnset
+	}
+	orDefault := func(src string) string {
+		if src == SourceUnset {
+			return SourceDefault
+		}
+		return src
+	}
+
+	var settings []EffectiveSetting
+
+	// API key follows ResolveAPIKey precedence: env, then api_key_command, then api_key
+	apiKey := EffectiveSetting{Key: "api_key", Value: "(not set)", Source: SourceUnset}
+	switch {
+	case os.Getenv("GEMINI_API_KEY") != "":
+		apiKey.Value = MaskSecret(os.Getenv("GEMINI_API_KEY"))
+		apiKey.Source = "env (GEMINI_API_KEY)"
+	case geminiCfg.APIKeyCommand != "":
+		apiKey.Value = "(from api_key_command)"
+		apiKey.Source = source("api_key_command")
+	case geminiCfg.APIKey != "":
+		apiKey.Value = MaskSecret(geminiCfg.APIKey)
+		apiKey.Source = source("api_key")
+	}
+	settings = append(settings, apiKey)
+
+	backend := EffectiveSetting{Key: "backend", Value: geminiCfg.Backend, Source: source("backend")}
+	switch {
+	case os.Getenv(BackendEnvVar) != "":
+		backend.Value = os.Getenv(BackendEnvVar)
+		backend.Source = fmt.Sprintf("env (%s)", BackendEnvVar)
+	case backend.Value == "":
+		backend.Value = BackendGeminiAPI
+		backend.Source = SourceDefault
+	}
+	settings = append(settings, backend)
+
+	settings = append(settings, EffectiveSetting{Key: "api_key_command", Value: geminiCfg.APIKeyCommand, Source: source("api_key_command")})
+
+	model := EffectiveSetting{Key: "default_model", Value: geminiCfg.DefaultModel, Source: source("default_model")}
+	if model.Value == "" {
+		model.Value = DefaultRequestModel
+		model.Source = SourceDefault
+	}
+	settings = append(settings, model)
+
+	settings = append(settings,
+		EffectiveSetting{Key: "prompt_prefix", Value: geminiCfg.PromptPrefix, Source: source("prompt_prefix")},
+		EffectiveSetting{Key: "prompt_suffix", Value: geminiCfg.PromptSuffix, Source: source("prompt_suffix")},
+	)
+
+	// Generation defaults left unset use whatever the API defaults to
+	generation := func(key string, value *string) EffectiveSetting {
+		if value == nil {
+			return EffectiveSetting{Key: key, Value: "(API default)", Source: SourceDefault}
+		}
+		return EffectiveSetting{Key: key, Value: *value, Source: source(key)}
+	}
+	settings = append(settings,
+		generation("temperature", formatFloat32(geminiCfg.Temperature)),
+		generation("top_p", formatFloat32(geminiCfg.TopP)),
+		generation("top_k", formatInt32(geminiCfg.TopK)),
+		generation("max_output_tokens", formatInt32(geminiCfg.MaxOutputTokens)),
+	)
+
+	timeout := EffectiveSetting{Key: "timeout", Value: geminiCfg.Timeout, Source: source("timeout")}
+	if timeout.Value == "" {
+		timeout.Value = "(none)"
+		timeout.Source = SourceDefault
+	}
+	settings = append(settings, timeout)
+
+	overrides := make([]string, 0, len(geminiCfg.PricingOverrides))
+	for model := range geminiCfg.PricingOverrides {
+		overrides = append(overrides, model)
+	}
+	sort.Strings(overrides)
+	settings = append(settings, EffectiveSetting{Key: "pricing_overrides", Value: strings.Join(overrides, ", "), Source: source("pricing_overrides")})
+
+	pricingFile := EffectiveSetting{Key: "pricing_file", Value: ResolvePricingFile(), Source: SourceDefault}
+	if os.Getenv(PricingFileEnvVar) != "" {
+		pricingFile.Source = fmt.Sprintf("env (%s)", PricingFileEnvVar)
+	}
+	if _, err := os.Stat(pricingFile.Value); err != nil {
+		pricingFile.Value += " (not found)"
+	}
+	settings = append(settings, pricingFile)
+
+	minCacheTokens := EffectiveSetting{Key: "min_cache_tokens", Value: strconv.Itoa(geminiCfg.MinCacheTokens), Source: source("min_cache_tokens")}
+	if geminiCfg.MinCacheTokens <= 0 {
+		minCacheTokens.Value = strconv.Itoa(DefaultMinCacheTokens)
+		minCacheTokens.Source = SourceDefault
+	}
+	settings = append(settings, minCacheTokens)
+
+	settings = append(settings, EffectiveSetting{Key: "shared_cache", Value: strconv.FormatBool(geminiCfg.SharedCache), Source: orDefault(source("shared_cache"))})
+
+	// Cache advice values fall back to defaults when unset or zero, matching ResolveCacheAdvice
+	advice := CacheAdviceConfig{}
+	if geminiCfg.CacheAdvice != nil {
+		advice = *geminiCfg.CacheAdvice
+	}
+	minHitRate := EffectiveSetting{Key: "cache_advice.min_hit_rate", Value: strconv.FormatFloat(advice.MinHitRate, 'g', -1, 64), Source: orDefault(source("cache_advice", "min_hit_rate"))}
+	if advice.MinHitRate <= 0 {
+		minHitRate.Value = strconv.FormatFloat(DefaultCacheMinHitRate, 'g', -1, 64)
+		minHitRate.Source = SourceDefault
+	}
+	minQueries := EffectiveSetting{Key: "cache_advice.min_queries", Value: strconv.Itoa(advice.MinQueries), Source: orDefault(source("cache_advice", "min_queries"))}
+	if advice.MinQueries <= 0 {
+		minQueries.Value = strconv.Itoa(DefaultCacheMinQueries)
+		minQueries.Source = SourceDefault
+	}
+	settings = append(settings,
+		minHitRate,
+		minQueries,
+		EffectiveSetting{Key: "cache_advice.warn_after_request", Value: strconv.FormatBool(advice.WarnAfterRequest), Source: orDefault(source("cache_advice", "warn_after_request"))},
+	)
+
+	// The log directory override follows ResolveLogDir precedence: env, then log_dir
+	logDir := EffectiveSetting{Key: "log_dir", Value: "(grove state directory)", Source: SourceDefault}
+	switch {
+	case os.Getenv(LogDirEnvVar) != "":
+		logDir.Value = os.Getenv(LogDirEnvVar)
+		logDir.Source = fmt.Sprintf("env (%s)", LogDirEnvVar)
+	case geminiCfg.LogDir != "":
+		logDir.Value = geminiCfg.LogDir
+		logDir.Source = source("log_dir")
+	}
+	settings = append(settings, logDir)
+
+	return settings, nil
+}
+
+// MaskSecret hides all but the first and last few characters of a secret
+func MaskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return secret[:4] + "..." + secret[len(secret)-4:]
+}
+
+// geminiLayers returns the layers that define a gemini section, in merge order
+func geminiLayers(layered *core_config.LayeredConfig) []configLayer {
+	var layers []configLayer
+	add := func(source core_config.ConfigSource, path string, cfg *core_config.Config) {
+		if cfg == nil {
+			return
+		}
+		if gemini, ok := cfg.Extensions["gemini"].(map[string]interface{}); ok {
+			layers = append(layers, configLayer{source: source, path: path, gemini: gemini})
+		}
+	}
+
+	add(core_config.SourceGlobal, layered.FilePaths[core_config.SourceGlobal], layered.Global)
+	if layered.GlobalOverride != nil {
+		add(core_config.SourceGlobalOverride, layered.GlobalOverride.Path, layered.GlobalOverride.Config)
+	}
+	if layered.EnvOverlay != nil {
+		add(core_config.SourceEnvOverlay, layered.EnvOverlay.Path, layered.EnvOverlay.Config)
+	}
+	add(core_config.SourceEcosystem, layered.FilePaths[core_config.SourceEcosystem], layered.Ecosystem)
+	add(core_config.SourceProject, layered.FilePaths[core_config.SourceProject], layered.Project)
+	// Project overrides are skipped while an env overlay is active
+	if layered.EnvOverlay == nil {
+		for _, override := range layered.Overrides {
+			add(core_config.SourceOverride, override.Path, override.Config)
+		}
+	}
+	return layers
+}
+
+// hasKey reports whether the nested key path is present in a raw config section
+func hasKey(section map[string]interface{}, keys ...string) bool {
+	current := section
+	for i, key := range keys {
+		value, ok := current[key]
+		if !ok {
+			return false
+		}
+		if i == len(keys)-1 {
+			return true
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return false
+}
+
+// formatFloat32 renders an optional float setting, or nil when it is unset
+func formatFloat32(v *float32) *string {
+	if v == nil {
+		return nil
+	}
+	s := strconv.FormatFloat(float64(*v), 'g', -1, 32)
+	return &s
+}
+
+// formatInt32 renders an optional integer setting, or nil when it is unset
+func formatInt32(v *int32) *string {
+	if v == nil {
+		return nil
+	}
+	s := strconv.FormatInt(int64(*v), 10)
+	return &s
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaskSecret(t *testing.T) {
+	tests := map[string]string{
+		"":                     "****",
+		"short":                "****",
+		"AIzaSyExampleKey1234": "AIza...1234",
+	}
+	for secret, want := range tests {
+		if got := MaskSecret(secret); got != want {
+			t.Errorf("MaskSecret(%q) = %q, want %q", secret, got, want)
+		}
+	}
+}
+
+func TestHasKey(t *testing.T) {
+	section := map[string]interface{}{
+		"default_model": "gemini-2.5-pro",
+		"cache_advice":  map[string]interface{}{"min_queries": 10},
+	}
+
+	if !hasKey(section, "default_model") {
+		t.Error("Expected default_model to be found")
+	}
+	if !hasKey(section, "cache_advice", "min_queries") {
+		t.Error("Expected nested cache_advice.min_queries to be found")
+	}
+	if hasKey(section, "cache_advice", "min_hit_rate") {
+		t.Error("Did not expect cache_advice.min_hit_rate")
+	}
+	if hasKey(section, "default_model", "nested") {
+		t.Error("Did not expect to descend into a scalar")
+	}
+}
+
+func TestPricingOverrideValidate(t *testing.T) {
+	half, tooBig := 0.5, 1.5
+	tests := []struct {
+		name    string
+		p       PricingOverride
+		wantErr bool
+	}{
+		{"valid", PricingOverride{Input: 1.25, Output: 10, CachedDiscount: &half}, false},
+		{"output only", PricingOverride{Output: 10}, false},
+		{"unset", PricingOverride{}, true},
+		{"negative", PricingOverride{Input: -1, Output: 10}, true},
+		{"bad discount", PricingOverride{Input: 1, Output: 10, CachedDiscount: &tooBig}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	if got := (PricingOverride{Input: 1}).Discount(); got != DefaultCachedDiscount {
+		t.Errorf("Discount() = %v, want default %v", got, DefaultCachedDiscount)
+	}
+}
+
+func TestGenerationDefaultsFromGroveYML(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("GEMINI_API_KEY", "")
+	groveYML := "name: test\ngemini:\n  temperature: 0.2\n  top_k: 40\n  max_output_tokens: 2048\n"
+	if err := os.WriteFile(filepath.Join(dir, "grove.yml"), []byte(groveYML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadGeminiConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadGeminiConfig: %v", err)
+	}
+	if cfg.Temperature == nil || *cfg.Temperature != 0.2 {
+		t.Errorf("Temperature = %v, want 0.2", cfg.Temperature)
+	}
+	if cfg.TopP != nil {
+		t.Errorf("TopP = %v, want unset", *cfg.TopP)
+	}
+	if cfg.TopK == nil || *cfg.TopK != 40 || cfg.MaxOutputTokens == nil || *cfg.MaxOutputTokens != 2048 {
+		t.Errorf("TopK = %v, MaxOutputTokens = %v, want 40 and 2048", cfg.TopK, cfg.MaxOutputTokens)
+	}
+
+	settings, err := ResolveEffectiveConfig(dir)
+	if err != nil {
+		t.Fatalf("ResolveEffectiveConfig: %v", err)
+	}
+	got := make(map[string]EffectiveSetting)
+	for _, s := range settings {
+		got[s.Key] = s
+	}
+	if s := got["temperature"]; s.Value != "0.2" || !strings.HasPrefix(s.Source, "project") {
+		t.Errorf("temperature setting = %+v, want 0.2 from the project grove.yml", s)
+	}
+	if s := got["top_p"]; s.Value != "(API default)" || s.Source != SourceDefault {
+		t.Errorf("top_p setting = %+v, want the API default", s)
+	}
+}
+
+func TestResolveModel(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	if model, source := ResolveModel(dir, "ignored", false); model != DefaultRequestModel || source != ModelSourceDefault {
+		t.Errorf("without config: got %q from %q, want the built-in default", model, source)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "grove.yml"), []byte("name: test\ngemini:\n  default_model: gemini-2.5-pro\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if model, source := ResolveModel(dir, "ignored", false); model != "gemini-2.5-pro" || source != ModelSourceConfig {
+		t.Errorf("with config: got %q from %q, want gemini-2.5-pro from grove.yml", model, source)
+	}
+	if model, source := ResolveModel(dir, "gemini-2.0-flash-lite", true); model != "gemini-2.0-flash-lite" || source != ModelSourceFlag {
+		t.Errorf("with flag: got %q from %q, want the flag value", model, source)
+	}
+}
+
+func TestResolveBackendUsesWorkDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv(BackendEnvVar, "")
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	t.Setenv("GOOGLE_CLOUD_LOCATION", "")
+
+	project := t.TempDir()
+	groveYML := "name: test\ngemini:\n  backend: vertex\n  vertex:\n    project: workdir-project\n"
+	if err := os.WriteFile(filepath.Join(project, "grove.yml"), []byte(groveYML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := ResolveBackend(project)
+	if err != nil {
+		t.Fatalf("ResolveBackend: %v", err)
+	}
+	if !backend.IsVertex() || backend.Project != "workdir-project" || backend.Location != DefaultVertexLocation {
+		t.Errorf("ResolveBackend(%s) = %+v, want Vertex AI in workdir-project", project, backend)
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LogDirEnvVar overrides the directory query logs are written to and read from
+const LogDirEnvVar = "GROVE_GEMINI_LOG_DIR"
+
+// ResolveLogDir returns the query log directory override for workDir:
+// GROVE_GEMINI_LOG_DIR if set, otherwise gemini.log_dir from grove.yml.
+// A leading ~ is expanded. An empty string means no override is configured.
+func ResolveLogDir(workDir string) string {
+	dir := os.Getenv(LogDirEnvVar)
+	if dir == "" {
+		if geminiCfg, err := LoadGeminiConfig(workDir); err == nil {
+			dir = geminiCfg.LogDir
+		}
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+		}
+	}
+	return dir
+}
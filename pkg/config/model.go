@@ -0,0 +1,60 @@
+package config
+
+import (
+	core_config "github.com/grovetools/core/config"
+)
+
+// LoadGeminiConfig loads the 'gemini' extension from the grove.yml that
+// applies to workDir (or the current directory when workDir is empty).
+// The configuration is discovered by walking up from workDir, so a repo-level
+// grove.yml takes effect for every directory inside the repo.
+func LoadGeminiConfig(workDir string) (*GeminiConfig, error) {
+	var (
+		cfg *core_config.Config
+		err error
+	)
+	if workDir != "" {
+		cfg, err = core_config.LoadFrom(workDir)
+	} else {
+		cfg, err = core_config.LoadDefault()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var geminiCfg GeminiConfig
+	if err := cfg.UnmarshalExtension("gemini", &geminiCfg); err != nil {
+		return nil, err
+	}
+	return &geminiCfg, nil
+}
+
+// ResolveDefaultModel returns the gemini.default_model configured in the
+// grove.yml that applies to workDir, or an empty string if none is set.
+func ResolveDefaultModel(workDir string) string {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil {
+		return ""
+	}
+	return geminiCfg.DefaultModel
+}
+
+// Sources reported by ResolveModel
+const (
+	ModelSourceFlag    = "flag"
+	ModelSourceConfig  = "grove.yml"
+	ModelSourceDefault = "default"
+)
+
+// ResolveModel picks the model a command runs with: an explicit --model wins,
+// then gemini.default_model from grove.yml, then DefaultRequestModel. It also
+// returns which of those sources supplied the model.
+func ResolveModel(workDir, flagValue string, flagChanged bool) (model, source string) {
+	if flagChanged {
+		return flagValue, ModelSourceFlag
+	}
+	if configured := ResolveDefaultModel(workDir); configured != "" {
+		return configured, ModelSourceConfig
+	}
+	return DefaultRequestModel, ModelSourceDefault
+}
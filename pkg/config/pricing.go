@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/grovetools/core/pkg/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultCachedDiscount is the fraction taken off the input price for cached tokens
+const DefaultCachedDiscount = 0.75
+
+// PricingFileEnvVar points at a pricing table to use instead of the default pricing.yml
+const PricingFileEnvVar = "GROVE_GEMINI_PRICING_FILE"
+
+// Validate checks that an override's prices are usable
+func (p PricingOverride) Validate() error {
+	if p.Input < 0 || p.Output < 0 || p.Storage < 0 {
+		return fmt.Errorf("prices must not be negative")
+	}
+	if p.Input == 0 && p.Output == 0 {
+		return fmt.Errorf("at least one of input or output must be set")
+	}
+	if p.CachedDiscount != nil && (*p.CachedDiscount < 0 || *p.CachedDiscount > 1) {
+		return fmt.Errorf("cached_discount must be between 0 and 1")
+	}
+	if lc := p.LongContext; lc != nil {
+		if lc.Threshold <= 0 {
+			return fmt.Errorf("long_context.threshold must be positive")
+		}
+		if lc.Input < 0 || lc.Output < 0 {
+			return fmt.Errorf("long_context prices must not be negative")
+		}
+	}
+	return nil
+}
+
+// Discount returns the cached token discount, falling back to DefaultCachedDiscount
+func (p PricingOverride) Discount() float64 {
+	if p.CachedDiscount == nil {
+		return DefaultCachedDiscount
+	}
+	return *p.CachedDiscount
+}
+
+// ResolvePricingOverrides returns the valid gemini.pricing_overrides for
+// workDir. Invalid entries are dropped and reported together in the error,
+// so callers can warn and still use the rest.
+func ResolvePricingOverrides(workDir string) (map[string]PricingOverride, error) {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil || len(geminiCfg.PricingOverrides) == 0 {
+		return nil, nil
+	}
+	return validOverrides("pricing_overrides", geminiCfg.PricingOverrides)
+}
+
+// ResolvePricingFile returns the pricing table path: GROVE_GEMINI_PRICING_FILE
+// if set, otherwise gemini/pricing.yml in the grove config directory.
+func ResolvePricingFile() string {
+	if path := os.Getenv(PricingFileEnvVar); path != "" {
+		return path
+	}
+	configDir := paths.ConfigDir()
+	if configDir == "" {
+		return ""
+	}
+	return filepath.Join(configDir, "gemini", "pricing.yml")
+}
+
+// LoadPricingFile reads a pricing table mapping model name patterns to prices,
+// in the same format as gemini.pricing_overrides. A missing file is not an
+// error. Invalid entries are dropped and reported together in the error.
+func LoadPricingFile(path string) (map[string]PricingOverride, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is the user's pricing table
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading pricing file: %w", err)
+	}
+
+	var table map[string]PricingOverride
+	if err := yaml.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("parsing pricing file %s: %w", path, err)
+	}
+	return validOverrides(filepath.Base(path), table)
+}
+
+// validOverrides returns the entries of table that pass validation, reporting
+// the rest in the error prefixed with where they came from
+func validOverrides(source string, table map[string]PricingOverride) (map[string]PricingOverride, error) {
+	models := make([]string, 0, len(table))
+	for model := range table {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	overrides := make(map[string]PricingOverride, len(models))
+	var errs []error
+	for _, model := range models {
+		override := table[model]
+		if err := override.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", source, model, err))
+			continue
+		}
+		overrides[model] = override
+	}
+	return overrides, errors.Join(errs...)
+}
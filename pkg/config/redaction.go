@@ -0,0 +1,22 @@
+package config
+
+import (
+	"github.com/grovetools/grove-gemini/pkg/redact"
+)
+
+// ResolveRedactor builds the redactor configured by gemini.redaction for
+// workDir. The built-in patterns are used when nothing is configured. An
+// invalid user pattern is reported as an error alongside the default
+// redactor, so callers can warn and still mask the obvious secrets.
+func ResolveRedactor(workDir string) (*redact.Redactor, error) {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil || geminiCfg.Redaction == nil {
+		return redact.Default(), nil
+	}
+
+	r, err := redact.New(geminiCfg.Redaction.Patterns, !geminiCfg.Redaction.DisableDefaults)
+	if err != nil {
+		return redact.Default(), err
+	}
+	return r, nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ResolveTimeout returns the gemini.timeout configured for workDir, or zero
+// (no timeout) when it is unset. A value that isn't a positive Go duration is
+// an error rather than being silently ignored.
+func ResolveTimeout(workDir string) (time.Duration, error) {
+	geminiCfg, err := LoadGeminiConfig(workDir)
+	if err != nil || geminiCfg.Timeout == "" {
+		return 0, nil
+	}
+	return parseTimeout(geminiCfg.Timeout)
+}
+
+// parseTimeout parses a gemini.timeout value, which must be a positive Go duration
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gemini.timeout %q: %w", value, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid gemini.timeout %q: must be positive", value)
+	}
+	return timeout, nil
+}
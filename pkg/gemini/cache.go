@@ -1,20 +1,26 @@
 package gemini
 
 import (
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/grovetools/core/pkg/workspace"
 	grovecontext "github.com/grovetools/cx/pkg/context"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/models"
 	"github.com/grovetools/grove-gemini/pkg/pretty"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/genai"
@@ -37,9 +43,16 @@ func ResolveGeminiCacheDir(workDir string) string {
 // It includes the cache ID, name, file hashes for validation,
 // the model used, creation/expiration timestamps, token count, repo name,
 // clear tracking information, and usage statistics.
+// Pinned caches are skipped by bulk cleanup operations such as prune and clear --all,
+// and stay pinned when an expired or stale cache is recreated.
+// KeyVersion records the cache key scheme the record was written with; orphaned
+// records duplicate one 'cache migrate' re-keyed and are removed by prune.
+// Shared caches are listed in the cross-project index and may be used by other projects.
+// DisplayName is an optional human-readable name that resolves to CacheName.
 type CacheInfo struct {
 	CacheID           string            `json:"cache_id"`
 	CacheName         string            `json:"cache_name"`
+	DisplayName       string            `json:"display_name,omitempty"`
 	CachedFileHashes  map[string]string `json:"cached_file_hashes"`
 	Model             string            `json:"model"`
 	CreatedAt         time.Time         `json:"created_at"`
@@ -49,11 +62,25 @@ type CacheInfo struct {
 	ClearReason       string            `json:"clear_reason,omitempty"`
 	ClearedAt         *time.Time        `json:"cleared_at,omitempty"`
 	RegenerationCount int               `json:"regeneration_count,omitempty"`
+	Pinned            bool              `json:"pinned,omitempty"`
+	KeyVersion        int               `json:"key_version,omitempty"`
+	Orphaned          bool              `json:"orphaned,omitempty"`
+	Shared            bool              `json:"shared,omitempty"`
 
 	// Usage tracking fields
 	UsageStats *CacheUsageStats `json:"usage_stats,omitempty"`
 }
 
+// CachedFiles returns the paths of the files the cache was built from, sorted
+func (c *CacheInfo) CachedFiles() []string {
+	files := make([]string, 0, len(c.CachedFileHashes))
+	for file := range c.CachedFileHashes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
 // CacheUsageStats tracks usage statistics for a cache
 type CacheUsageStats struct {
 	TotalQueries     int               `json:"total_queries"`
@@ -77,19 +104,68 @@ type CacheQueryStats struct {
 // It handles cache creation, validation, and expiration tracking
 // for cached content used with the Gemini API.
 type CacheManager struct {
-	workingDir string
-	cacheDir   string
+	workingDir     string
+	cacheDir       string
+	minCacheTokens int
+	minTTL         time.Duration
+	autoExtend     bool
+	sharedDir      string
+	displayName    string
 }
 
+// DefaultMinCacheTTL is how much lifetime a reused cache should have left when
+// a request starts. It stays below the default request TTL so a cache created
+// moments ago doesn't trigger the warning.
+const DefaultMinCacheTTL = 2 * time.Minute
+
 // NewCacheManager creates a new cache manager
 func NewCacheManager(workingDir string) *CacheManager {
 	cacheDir := ResolveGeminiCacheDir(workingDir)
 	return &CacheManager{
-		workingDir: workingDir,
-		cacheDir:   cacheDir,
+		workingDir:     workingDir,
+		cacheDir:       cacheDir,
+		minCacheTokens: config.DefaultMinCacheTokens,
+		minTTL:         DefaultMinCacheTTL,
 	}
 }
 
+// SetExpiryPolicy sets how much lifetime a reused cache must have left, and
+// whether a cache expiring sooner has its TTL extended instead of only being
+// warned about. A minTTL that isn't positive keeps the current minimum.
+func (m *CacheManager) SetExpiryPolicy(minTTL time.Duration, autoExtend bool) {
+	if minTTL > 0 {
+		m.minTTL = minTTL
+	}
+	m.autoExtend = autoExtend
+}
+
+// SetSharedCacheDir enables the cross-project cache index in dir, so a server
+// cache created by another project for the same content and model is reused
+// instead of creating a new one. An empty dir disables it.
+func (m *CacheManager) SetSharedCacheDir(dir string) {
+	m.sharedDir = dir
+}
+
+// SetDisplayName sets the human-readable name given to caches this manager
+// creates. It is sent to the API and recorded locally, where it can be used in
+// place of the content hash (see ResolveCacheName).
+func (m *CacheManager) SetDisplayName(name string) {
+	m.displayName = name
+}
+
+// SetMinCacheTokens sets the smallest cold context, in tokens, that will be cached.
+// Values that aren't positive are ignored.
+func (m *CacheManager) SetMinCacheTokens(tokens int) {
+	if tokens > 0 {
+		m.minCacheTokens = tokens
+	}
+}
+
+// MinCacheTokens returns the smallest cold context, in tokens, that will be cached
+func (m *CacheManager) MinCacheTokens() int {
+	return m.minCacheTokens
+}
+
 // LoadCacheInfo loads cache information from a JSON file
 func LoadCacheInfo(filePath string) (*CacheInfo, error) {
 	data, err := os.ReadFile(filePath) //nolint:gosec // filePath is internal cache path, not user input
@@ -127,12 +203,17 @@ func SaveCacheInfo(filePath string, info *CacheInfo) error {
 	return nil
 }
 
-// FindAndValidateCache finds and validates a specific cache by name
+// FindAndValidateCache finds and validates a specific cache by name or display name
 // This method does NOT check for file content changes - it's meant to force use of a specific cache
 func (m *CacheManager) FindAndValidateCache(ctx context.Context, client *Client, cacheName string, disableExpiration bool) (*CacheInfo, error) {
 	// Create pretty logger
 	logger := pretty.New()
 
+	cacheName, err := ResolveCacheName(m.cacheDir, cacheName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Construct path to cache info file
 	cacheInfoFile := filepath.Join(m.cacheDir, "hybrid_"+cacheName+".json")
 
@@ -140,7 +221,7 @@ func (m *CacheManager) FindAndValidateCache(ctx context.Context, client *Client,
 	info, err := LoadCacheInfo(cacheInfoFile)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("cache '%s' not found", cacheName)
+			return nil, fmt.Errorf("%w: '%s'", ErrCacheNotFound, cacheName)
 		}
 		return nil, fmt.Errorf("loading cache info: %w", err)
 	}
@@ -153,18 +234,19 @@ func (m *CacheManager) FindAndValidateCache(ctx context.Context, client *Client,
 		return nil, fmt.Errorf("verifying cache on server: %w", err)
 	}
 	if !exists {
-		return nil, fmt.Errorf("cache '%s' no longer exists on server", cacheName)
+		return nil, fmt.Errorf("%w: '%s' no longer exists on server", ErrCacheNotFound, cacheName)
 	}
 
 	// Check if cache has expired (unless expiration is disabled)
 	if !disableExpiration && time.Now().After(info.ExpiresAt) {
-		return nil, fmt.Errorf("cache '%s' has expired (expired at %s)", cacheName, info.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST"))
+		return nil, fmt.Errorf("%w: '%s' expired at %s", ErrCacheExpired, cacheName, info.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST"))
 	}
 
 	// Cache is valid
 	if disableExpiration {
 		logger.Success(fmt.Sprintf("Using specified cache '%s' (expiration check disabled)", cacheName))
 	} else {
+		m.ensureRemainingTTL(ctx, client, logger, info, cacheInfoFile, m.minTTL)
 		logger.Success(fmt.Sprintf("Using specified cache '%s' (expires %s)", cacheName, info.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST")))
 	}
 
@@ -172,57 +254,74 @@ func (m *CacheManager) FindAndValidateCache(ctx context.Context, client *Client,
 }
 
 // GetOrCreateCache returns an existing valid cache or creates a new one
+// covering all of the cold context files. Files that don't exist are left
+// out, so adding or removing one changes the cache key.
+// The estimator sizes the files against the caching minimum; nil uses HeuristicEstimator.
 // The second return value indicates whether a new cache was created
-func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, model string, coldContextFilePath string, ttl time.Duration, ignoreChanges bool, disableExpiration bool, forceRecache bool, skipConfirmation bool) (*CacheInfo, bool, error) {
+func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, model string, coldContextFilePaths []string, ttl time.Duration, ignoreChanges bool, disableExpiration bool, forceRecache bool, skipConfirmation bool, estimator TokenEstimator) (*CacheInfo, bool, error) {
 	// Create pretty logger for UI output
 	logger := pretty.New()
+	if estimator == nil {
+		estimator = HeuristicEstimator{}
+	}
 
-	// Check if the cold context file exists
-	if _, err := os.Stat(coldContextFilePath); err != nil {
-		if os.IsNotExist(err) {
-			// No cold context file, return nil (no cache to use)
-			return nil, false, nil
+	// Keep only the cold context files that exist
+	var coldContextFiles []string
+	for _, path := range coldContextFilePaths {
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, false, fmt.Errorf("checking cold context file: %w", err)
 		}
-		return nil, false, fmt.Errorf("checking cold context file: %w", err)
+		coldContextFiles = append(coldContextFiles, path)
 	}
+	if len(coldContextFiles) == 0 {
+		// No cold context files, return nil (no cache to use)
+		return nil, false, nil
+	}
+	sort.Strings(coldContextFiles)
 
 	// Ensure cache directory exists
 	if err := os.MkdirAll(m.cacheDir, 0o755); err != nil { //nolint:gosec // cache dir needs to be traversable
 		return nil, false, fmt.Errorf("creating cache directory: %w", err)
 	}
 
-	// Generate cache key based on the cold context file content
-	cacheKey, err := generateCacheKey([]string{coldContextFilePath})
+	// Generate cache key based on the cold context file contents
+	cacheKey, err := generateCacheKey(coldContextFiles)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to generate cache key: %w", err)
 	}
 	cacheInfoFile := filepath.Join(m.cacheDir, "hybrid_"+cacheKey+".json")
 
+	// Serialize cache lookup/creation per key so a concurrent process waits
+	// and then reuses the cache created by the first one
+	unlock, err := acquireCacheLock(ctx, m.cacheDir, cacheKey)
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+
 	// Try to load existing cache info
 	var cacheInfo CacheInfo
-	var existingRegenerationCount int
 	needNewCache := forceRecache
 
 	if forceRecache {
 		logger.Info("Forcing cache regeneration due to --recache flag")
 	}
 
-	// Check for existing cache info to preserve regeneration count
-	if data, err := os.ReadFile(cacheInfoFile); err == nil { //nolint:gosec // cacheInfoFile is internal path
-		var existingInfo CacheInfo
-		if err := json.Unmarshal(data, &existingInfo); err == nil {
-			existingRegenerationCount = existingInfo.RegenerationCount
-		}
-	}
-
 	if !needNewCache {
 		if data, err := os.ReadFile(cacheInfoFile); err == nil { //nolint:gosec // cacheInfoFile is internal path
 			if err := json.Unmarshal(data, &cacheInfo); err == nil {
 				logger.CacheInfo("Found existing cache info")
 
-				// Verify cache exists on the server
-				exists, err := client.VerifyCacheExists(ctx, cacheInfo.CacheID)
-				if err != nil {
+				// Caches are model-specific on the server, so one created for
+				// another model can't serve this request even with unchanged content
+				if !cacheModelMatches(cacheInfo.Model, model) {
+					logger.Warning(fmt.Sprintf("Cache was created for %s, not %s - new cache required", cacheInfo.Model, model))
+					needNewCache = true
+				} else if exists, err := client.VerifyCacheExists(ctx, cacheInfo.CacheID); err != nil {
+					// Verify cache exists on the server
 					logger.Warning(fmt.Sprintf("Could not verify cache on server: %v", err))
 				} else if !exists {
 					logger.Warning("Cache not found on server - will create new cache")
@@ -234,14 +333,17 @@ func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, mod
 					logger.CacheExpired(cacheInfo.ExpiresAt)
 					needNewCache = true
 				} else if !needNewCache {
-					if changed, changedFiles := hasFilesChanged(cacheInfo.CachedFileHashes, []string{coldContextFilePath}); changed {
+					if changed, changedFiles := hasFilesChanged(cacheInfo.CachedFileHashes, coldContextFiles); changed {
 						if ignoreChanges {
 							logger.Warning("Cache is frozen - detected file changes but using existing cache")
 							logger.ChangedFiles(changedFiles)
+							if !disableExpiration {
+								m.ensureRemainingTTL(ctx, client, logger, &cacheInfo, cacheInfoFile, ttl)
+							}
 							return &cacheInfo, false, nil
 						}
 						logger.ChangedFiles(changedFiles)
-						fmt.Fprintln(os.Stderr)
+						logger.Blank()
 						logger.Warning("Cache invalidated due to file changes - new cache required")
 						needNewCache = true
 					} else {
@@ -249,6 +351,7 @@ func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, mod
 							logger.Success("Cache is valid (expiration disabled by @no-expire)")
 						} else {
 							logger.CacheValid(cacheInfo.ExpiresAt)
+							m.ensureRemainingTTL(ctx, client, logger, &cacheInfo, cacheInfoFile, ttl)
 						}
 						return &cacheInfo, false, nil
 					}
@@ -260,21 +363,68 @@ func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, mod
 		}
 	}
 
+	// Reuse a cache another project created for the same content before creating one
+	if needNewCache && !forceRecache && m.sharedDir != "" {
+		if shared, err := m.findSharedCache(ctx, client, cacheKey, model); err != nil {
+			logger.Warning(fmt.Sprintf("Could not check the shared cache index: %v", err))
+		} else if shared != nil {
+			fileHashes := make(map[string]string, len(coldContextFiles))
+			for _, path := range coldContextFiles {
+				if fileHashes[path], err = hashFile(path); err != nil {
+					return nil, false, fmt.Errorf("failed to hash %s: %w", path, err)
+				}
+			}
+			cacheInfo = CacheInfo{
+				CacheID:          shared.CacheID,
+				CacheName:        cacheKey,
+				CachedFileHashes: fileHashes,
+				Model:            model,
+				CreatedAt:        shared.CreatedAt,
+				ExpiresAt:        shared.ExpiresAt,
+				TokenCount:       shared.TokenCount,
+				RepoName:         getRepoName(m.workingDir),
+				KeyVersion:       CacheKeyVersion,
+				Shared:           true,
+			}
+			if err := m.replaceCacheInfo(ctx, client, logger, cacheInfoFile, &cacheInfo, 0); err != nil {
+				return nil, false, fmt.Errorf("saving shared cache info: %w", err)
+			}
+			if err := m.registerSharedCache(ctx, &cacheInfo); err != nil {
+				logger.Warning(fmt.Sprintf("Failed to record this project in the shared cache index: %v", err))
+			}
+			logger.Success(fmt.Sprintf("Reusing shared cache %s from the cross-project index", shared.CacheID))
+			if !disableExpiration {
+				m.ensureRemainingTTL(ctx, client, logger, &cacheInfo, cacheInfoFile, ttl)
+			}
+			return &cacheInfo, false, nil
+		}
+	}
+
 	// Create new cache if needed
 	if needNewCache {
-		// First, check if the file is large enough for caching
-		content, err := os.ReadFile(coldContextFilePath) //nolint:gosec // path from trusted config
-		if err != nil {
-			return nil, false, fmt.Errorf("failed to read %s: %w", coldContextFilePath, err)
+		// First, check if the files together are large enough for caching
+		fileHashes := make(map[string]string)
+		var estimatedTokens int
+		var sizeBytes int64
+		for _, path := range coldContextFiles {
+			content, err := os.ReadFile(path) //nolint:gosec // path from trusted config
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			hashArray := sha256.Sum256(content)
+			fileHashes[path] = hex.EncodeToString(hashArray[:])
+			tokens, err := estimator.EstimateTokens(ctx, model, content)
+			if err != nil {
+				logger.Warning(fmt.Sprintf("Token count failed for %s, falling back to estimate: %v", filepath.Base(path), err))
+				tokens = estimateTokens(content)
+			}
+			estimatedTokens += tokens
+			sizeBytes += int64(len(content))
 		}
-
-		estimatedTokens := estimateTokens(content)
-		minTokensForCache := 4096
-
-		if estimatedTokens < minTokensForCache {
-			fmt.Fprintln(os.Stderr)
+		if estimatedTokens < m.minCacheTokens {
+			logger.Blank()
 			logger.Warning("Cached context is too small for Gemini caching")
-			fmt.Fprintf(os.Stderr, "   Estimated tokens: %d (minimum required: %d)\n", estimatedTokens, minTokensForCache)
+			logger.Info(fmt.Sprintf("   Estimated tokens: %d (minimum required: %d)", estimatedTokens, m.minCacheTokens))
 			logger.Info("   Suggestion: Move all content to hot context (.grove/context) for better performance")
 			logger.Info("   Proceeding without cache...")
 			return nil, false, nil // Return nil to indicate no cache should be used
@@ -282,7 +432,6 @@ func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, mod
 
 		// Show confirmation prompt unless skipped
 		if !skipConfirmation {
-			sizeBytes := int64(len(content))
 			logger.Info(fmt.Sprintf("Cache confirmation required (skipConfirmation=%v)", skipConfirmation))
 			if !logger.CacheCreationPrompt(estimatedTokens, sizeBytes, ttl) {
 				logger.Warning("Cache creation cancelled by user")
@@ -290,35 +439,54 @@ func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, mod
 			}
 		}
 
-		fmt.Fprintln(os.Stderr)
+		logger.Blank()
 		logger.UploadProgressCtx(ctx, "Uploading files for cache...")
 		logger.EstimatedTokens(estimatedTokens)
 
-		fileHashes := make(map[string]string)
+		// Upload each file as its own part; Vertex AI takes the content inline
 		var parts []*genai.Part
+		if client.isVertex() {
+			inline, err := inlineFileParts(coldContextFiles)
+			if err != nil {
+				return nil, false, err
+			}
+			parts = inline
+		} else {
+			for _, path := range coldContextFiles {
+				f, _, err := uploadFile(ctx, client.GetClient(), path)
+				if err != nil {
+					return nil, false, fmt.Errorf("failed to upload %s: %w", path, err)
+				}
+				parts = append(parts, genai.NewPartFromURI(f.URI, f.MIMEType))
+			}
+		}
 
-		// Calculate hash
-		hashArray := sha256.Sum256(content)
-		hash := hex.EncodeToString(hashArray[:])
-		fileHashes[coldContextFilePath] = hash
+		contents := []*genai.Content{
+			genai.NewContentFromParts(parts, genai.RoleUser),
+		}
 
-		// Upload file
-		f, _, err := uploadFile(ctx, client.GetClient(), coldContextFilePath)
-		if err != nil {
-			return nil, false, fmt.Errorf("failed to upload %s: %w", coldContextFilePath, err)
+		// Check the real token count against the model's cache limit so an
+		// oversized context fails with actionable guidance instead of a generic API error
+		if countResp, err := client.GetClient().Models.CountTokens(ctx, model, contents, nil); err == nil {
+			estimatedTokens = int(countResp.TotalTokens)
+			if limit, ok := models.MaxCacheTokens(model); ok && countResp.TotalTokens > limit {
+				return nil, false, &CacheTooLargeError{Model: model, Tokens: countResp.TotalTokens, Limit: limit}
+			}
 		}
-		parts = append(parts, genai.NewPartFromURI(f.URI, f.MIMEType))
 
 		// Create cache
-		fmt.Fprintln(os.Stderr)
+		logger.Blank()
 		logger.CreatingCache()
-		contents := []*genai.Content{
-			genai.NewContentFromParts(parts, genai.RoleUser),
-		}
 
+		// A recreated cache keeps the name of the one it replaces
+		displayName := m.displayName
+		if displayName == "" {
+			displayName = cacheInfo.DisplayName
+		}
 		cacheConfig := &genai.CreateCachedContentConfig{
-			Contents: contents,
-			TTL:      ttl,
+			Contents:    contents,
+			TTL:         ttl,
+			DisplayName: serverCacheDisplayName(m.cacheDir, displayName, cacheKey),
 		}
 
 		cache, err := client.GetClient().Caches.Create(ctx, model, cacheConfig)
@@ -328,35 +496,159 @@ func (m *CacheManager) GetOrCreateCache(ctx context.Context, client *Client, mod
 
 		// Save cache info
 		cacheInfo = CacheInfo{
-			CacheID:           cache.Name,
-			CacheName:         cacheKey,
-			CachedFileHashes:  fileHashes,
-			Model:             model,
-			CreatedAt:         time.Now(),
-			ExpiresAt:         cache.ExpireTime,
-			TokenCount:        estimatedTokens,
-			RepoName:          getRepoName(m.workingDir),
-			RegenerationCount: existingRegenerationCount + 1,
+			CacheID:          cache.Name,
+			CacheName:        cacheKey,
+			DisplayName:      displayName,
+			CachedFileHashes: fileHashes,
+			Model:            model,
+			CreatedAt:        time.Now(),
+			ExpiresAt:        cache.ExpireTime,
+			TokenCount:       estimatedTokens,
+			RepoName:         getRepoName(m.workingDir),
+			KeyVersion:       CacheKeyVersion,
+			Shared:           m.sharedDir != "",
+		}
+		if err := m.replaceCacheInfo(ctx, client, logger, cacheInfoFile, &cacheInfo, 1); err != nil {
+			return nil, false, fmt.Errorf("failed to save cache info: %w", err)
 		}
 
-		data, _ := json.MarshalIndent(cacheInfo, "", "  ")
+		logger.CacheCreated(cache.Name, cache.ExpireTime)
 
-		// Write to temporary file first for atomic operation
-		tempFile := cacheInfoFile + ".tmp"
-		if err := os.WriteFile(tempFile, data, 0o644); err != nil { //nolint:gosec // cache files need to be readable
-			return nil, false, fmt.Errorf("failed to save cache info to temp file: %w", err)
+		if cacheInfo.Shared {
+			if err := m.registerSharedCache(ctx, &cacheInfo); err != nil {
+				logger.Warning(fmt.Sprintf("Failed to add the cache to the shared index: %v", err))
+			}
 		}
+	}
 
-		// Rename temporary file to final location (atomic operation)
-		if err := os.Rename(tempFile, cacheInfoFile); err != nil {
-			_ = os.Remove(tempFile) // best-effort cleanup
-			return nil, false, fmt.Errorf("failed to rename cache info file: %w", err)
+	return &cacheInfo, needNewCache, nil
+}
+
+// cacheModelMatches reports whether a cache recorded for cachedModel can serve
+// requests to model. Records from before the model was saved match any model.
+func cacheModelMatches(cachedModel, model string) bool {
+	if cachedModel == "" {
+		return true
+	}
+	return strings.TrimPrefix(cachedModel, "models/") == strings.TrimPrefix(model, "models/")
+}
+
+// ensureRemainingTTL checks a reused cache against the minimum TTL. A cache
+// expiring sooner is warned about or, with auto-extend, refreshed to expire at
+// least ttl (and the minimum) from now, with the new expiry saved to infoFile.
+// A failed extension only warns, since the cache is still usable for now.
+func (m *CacheManager) ensureRemainingTTL(ctx context.Context, client *Client, logger *pretty.Logger, info *CacheInfo, infoFile string, ttl time.Duration) {
+	if time.Until(info.ExpiresAt) >= m.minTTL {
+		return
+	}
+	if !m.autoExtend {
+		logger.CacheExpiringSoon(info.ExpiresAt)
+		return
+	}
+
+	expiresAt, err := client.ExtendCache(ctx, info.CacheID, max(ttl, m.minTTL))
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Cache expires soon and could not be extended: %v", err))
+		return
+	}
+	info.ExpiresAt = expiresAt
+	if err := updateCacheInfo(ctx, infoFile, func(saved *CacheInfo) bool {
+		// Keep usage recorded by other processes; only the expiry changed.
+		// A record since replaced by a different cache is left alone.
+		switch saved.CacheID {
+		case "":
+			*saved = *info
+		case info.CacheID:
+			saved.ExpiresAt = expiresAt
+		default:
+			return false
+		}
+		return true
+	}); err != nil {
+		logger.Warning(fmt.Sprintf("Failed to save extended cache expiry: %v", err))
+	}
+	if info.Shared && m.sharedDir != "" {
+		if err := m.registerSharedCache(ctx, info); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to save extended expiry to the shared cache index: %v", err))
 		}
+	}
+	logger.CacheExtended(expiresAt)
+}
 
-		logger.CacheCreated(cache.Name, cache.ExpireTime)
+// replaceCacheInfo writes a new record for the cache key, carrying over the
+// regeneration count of the record it replaces plus regenerated, and its
+// display name unless info sets one. The previous
+// record is read under the info lock so a concurrent update isn't lost. If the
+// lock times out the record is written anyway, since losing it would orphan a
+// server cache that has already been paid for. A server cache the previous
+// record pointed at, such as one created for another model, is deleted once
+// the new record is saved, since nothing would refer to it any more.
+func (m *CacheManager) replaceCacheInfo(ctx context.Context, client *Client, logger *pretty.Logger, infoFile string, info *CacheInfo, regenerated int) error {
+	var previous CacheInfo
+	err := updateCacheInfo(ctx, infoFile, func(saved *CacheInfo) bool {
+		previous = *saved
+		info.RegenerationCount = saved.RegenerationCount + regenerated
+		if info.DisplayName == "" {
+			info.DisplayName = saved.DisplayName
+		}
+		// A pin outlives the server cache it was set on, so recreating an
+		// expired or stale cache keeps it exempt from cleanup
+		info.Pinned = info.Pinned || saved.Pinned
+		*saved = *info
+		return true
+	})
+	if errors.Is(err, ErrCacheLockTimeout) {
+		logger.Warning(fmt.Sprintf("%v; saving cache info without it", err))
+		if saved, loadErr := LoadCacheInfo(infoFile); loadErr == nil {
+			previous = *saved
+			info.Pinned = info.Pinned || saved.Pinned
+		}
+		err = SaveCacheInfo(infoFile, info)
+	}
+	if err != nil {
+		return err
+	}
+	if previous.CacheID != "" && previous.CacheID != info.CacheID {
+		m.deleteReplacedCache(ctx, client, logger, &previous)
+	}
+	return nil
+}
+
+// deleteReplacedCache deletes the server cache of a record that was just
+// overwritten, unless it is a shared cache other projects still use.
+// Failures only warn: the new record is already saved.
+func (m *CacheManager) deleteReplacedCache(ctx context.Context, client *Client, logger *pretty.Logger, previous *CacheInfo) {
+	stillUsedBy, err := ReleaseCache(ctx, client, cmp.Or(m.sharedDir, SharedCacheDir()), m.workingDir, previous)
+	switch {
+	case err != nil:
+		logger.Warning(fmt.Sprintf("Failed to delete replaced cache %s: %v", previous.CacheID, err))
+	case len(stillUsedBy) > 0:
+		logger.Info(fmt.Sprintf("Kept replaced cache %s: still used by %d other project(s)", previous.CacheID, len(stillUsedBy)))
+	default:
+		logger.Info(fmt.Sprintf("Deleted replaced cache %s", previous.CacheID))
 	}
+}
 
-	return &cacheInfo, needNewCache, nil
+// CacheTooLargeError is returned when a cold context has more tokens than the
+// model can hold in a single cache.
+type CacheTooLargeError struct {
+	Model  string
+	Tokens int32
+	Limit  int32
+}
+
+func (e *CacheTooLargeError) Error() string {
+	msg := fmt.Sprintf("cold context is %d tokens, which exceeds the %d token cache limit for %s.\n"+
+		"Split the context: move some rules to hot context or narrow the cold section of .grove/rules", e.Tokens, e.Limit, e.Model)
+	if larger := models.ModelsWithLargerContext(e.Limit); len(larger) > 0 {
+		msg += fmt.Sprintf(", or use a model with a larger context window (%s)", strings.Join(larger, ", "))
+	}
+	return msg
+}
+
+// Is lets errors.Is match a CacheTooLargeError against ErrContextTooLarge
+func (e *CacheTooLargeError) Is(target error) bool {
+	return target == ErrContextTooLarge
 }
 
 // hashFile calculates SHA256 hash of a file
@@ -369,15 +661,24 @@ func hashFile(filePath string) (string, error) {
 	return hex.EncodeToString(hash[:]), nil
 }
 
-// generateCacheKey creates a unique key for a set of files based on their content
+// generateCacheKey creates a unique key for a set of files based on their content.
+// Files are hashed in path order. With more than one file each content is
+// length-prefixed, so moving bytes between files changes the key; a single
+// file hashes exactly as before multiple files were supported.
 func generateCacheKey(files []string) (string, error) {
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+
 	h := sha256.New()
-	h.Write([]byte("hybrid_v2")) // v2 indicates content-based hashing
-	for _, f := range files {
+	fmt.Fprintf(h, "hybrid_v%d", CacheKeyVersion) // v2 indicates content-based hashing
+	for _, f := range sorted {
 		content, err := os.ReadFile(f) //nolint:gosec // f is from trusted rules config
 		if err != nil {
 			return "", fmt.Errorf("failed to read file %s: %w", f, err)
 		}
+		if len(sorted) > 1 {
+			fmt.Fprintf(h, "%d:", len(content))
+		}
 		h.Write(content)
 	}
 	return hex.EncodeToString(h.Sum(nil))[:16], nil
@@ -407,17 +708,32 @@ func hasFilesChanged(oldHashes map[string]string, files []string) (bool, []strin
 		}
 	}
 
+	current := make(map[string]bool, len(files))
+	for _, file := range files {
+		current[file] = true
+	}
+	var removed []string
+	for file := range oldHashes {
+		if !current[file] {
+			removed = append(removed, fmt.Sprintf("%s (removed)", file))
+		}
+	}
+	sort.Strings(removed)
+	changedFiles = append(changedFiles, removed...)
+
 	return len(changedFiles) > 0, changedFiles
 }
 
 // IsNotFoundError checks if an error is a Google API "Not Found" error
 func IsNotFoundError(err error) bool {
 	// Check for googleapi.Error
-	if apiErr, ok := err.(*googleapi.Error); ok {
-		return apiErr.Code == 404
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return googleErr.Code == 404
 	}
 	// Check for genai.APIError
-	if apiErr, ok := err.(genai.APIError); ok {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
 		return apiErr.Code == 404
 	}
 	return false
@@ -426,11 +742,13 @@ func IsNotFoundError(err error) bool {
 // IsPermissionError checks if an error is a Google API permission/forbidden error
 func IsPermissionError(err error) bool {
 	// Check for googleapi.Error
-	if apiErr, ok := err.(*googleapi.Error); ok {
-		return apiErr.Code == 403
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return googleErr.Code == 403
 	}
 	// Check for genai.APIError
-	if apiErr, ok := err.(genai.APIError); ok {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
 		return apiErr.Code == 403
 	}
 	return false
@@ -485,12 +803,18 @@ func (m *CacheManager) UpdateCacheUsageStats(cacheID string, cachedTokens, dynam
 		return nil
 	}
 
-	// Load current cache info
-	info, err := LoadCacheInfo(cacheFile)
-	if err != nil {
-		return fmt.Errorf("loading cache info: %w", err)
-	}
+	// Reload and update under the info lock so concurrent requests don't lose increments
+	return updateCacheInfo(context.Background(), cacheFile, func(info *CacheInfo) bool {
+		if info.CacheID != cacheID {
+			return false // replaced or removed since it was found
+		}
+		recordCacheUsage(info, cachedTokens, dynamicTokens, completionTokens, cacheHitRate)
+		return true
+	})
+}
 
+// recordCacheUsage adds one query's usage to the cache's statistics
+func recordCacheUsage(info *CacheInfo, cachedTokens, dynamicTokens, completionTokens int, cacheHitRate float64) {
 	// Initialize usage stats if needed
 	if info.UsageStats == nil {
 		info.UsageStats = &CacheUsageStats{
@@ -526,9 +850,6 @@ func (m *CacheManager) UpdateCacheUsageStats(cacheID string, cachedTokens, dynam
 		// Keep only the last 100 queries
 		info.UsageStats.QueryHistory = info.UsageStats.QueryHistory[len(info.UsageStats.QueryHistory)-100:]
 	}
-
-	// Save updated cache info
-	return SaveCacheInfo(cacheFile, info)
 }
 
 // CacheAnalytics represents aggregated analytics for a cache
@@ -628,8 +949,48 @@ func CalculateCacheAnalytics(info *CacheInfo) *CacheAnalytics {
 	return analytics
 }
 
+// CacheRecommendation is actionable advice derived from a cache's usage statistics
+type CacheRecommendation struct {
+	DisableCaching bool   // True when caching this context is not paying off
+	Reason         string // Human-readable explanation
+}
+
+// RecommendCacheUsage uses the cache analytics to decide whether caching is worth
+// keeping for a context. Caching is recommended to be disabled when the average
+// hit rate stays below minHitRate after at least minQueries queries.
+func RecommendCacheUsage(info *CacheInfo, minHitRate float64, minQueries int) CacheRecommendation {
+	if info.UsageStats == nil || info.UsageStats.TotalQueries < minQueries {
+		queries := 0
+		if info.UsageStats != nil {
+			queries = info.UsageStats.TotalQueries
+		}
+		return CacheRecommendation{
+			Reason: fmt.Sprintf("Not enough data yet (%d of %d queries)", queries, minQueries),
+		}
+	}
+
+	analytics := CalculateCacheAnalytics(info)
+	hitRate := info.UsageStats.AverageHitRate
+
+	if hitRate < minHitRate {
+		return CacheRecommendation{
+			DisableCaching: true,
+			Reason: fmt.Sprintf("Average hit rate %.1f%% over %d queries is below %.0f%% (saved $%.2f); consider removing @enable-cache for this context",
+				hitRate*100, info.UsageStats.TotalQueries, minHitRate*100, analytics.TotalSavings),
+		}
+	}
+
+	return CacheRecommendation{
+		Reason: fmt.Sprintf("Average hit rate %.1f%% over %d queries (saved $%.2f)",
+			hitRate*100, info.UsageStats.TotalQueries, analytics.TotalSavings),
+	}
+}
+
 // getCostPerMillionTokens returns the cost per million tokens for a given model
 func getCostPerMillionTokens(model string) float64 {
+	if pricing, ok := logging.PricingOverrideFor(model); ok {
+		return pricing.InputPrice
+	}
 	// Gemini pricing as of 2024
 	switch {
 	case strings.Contains(model, "gemini-exp"):
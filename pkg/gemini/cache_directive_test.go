@@ -175,12 +175,13 @@ func TestCacheManager_CachingDisabledByDefault(t *testing.T) {
 		context.Background(),
 		mockClient,
 		"gemini-1.5-flash",
-		coldContextPath,
+		[]string{coldContextPath},
 		1*time.Hour,
 		false, // ignoreChanges
 		false, // disableExpiration
 		false, // forceRecache
 		true,  // skipConfirmation for tests
+		nil,   // heuristic token estimate
 	)
 
 	// Should return nil due to content being too small for caching
@@ -0,0 +1,82 @@
+package gemini
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KnownCacheIDs returns the server cache IDs that have a local record in
+// cacheDir, including records in its trash, or an entry in the shared index
+// in sharedDir. An empty sharedDir skips the shared index.
+func KnownCacheIDs(cacheDir, sharedDir string) (map[string]bool, error) {
+	known := make(map[string]bool)
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "hybrid_") || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if info, err := LoadCacheInfo(filepath.Join(cacheDir, entry.Name())); err == nil {
+			known[info.CacheID] = true
+		}
+	}
+
+	trashed, err := ListTrashedCaches(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range trashed {
+		known[t.Info.CacheID] = true
+	}
+
+	if sharedDir != "" {
+		entries, err := os.ReadDir(sharedDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".json")
+			if !ok || !strings.HasPrefix(name, "shared_") {
+				continue
+			}
+			if data, err := os.ReadFile(filepath.Join(sharedDir, entry.Name())); err == nil { //nolint:gosec // path is inside the shared cache dir
+				if shared, err := parseSharedCacheEntry(data); err == nil {
+					known[shared.CacheID] = true
+				}
+			}
+		}
+	}
+
+	return known, nil
+}
+
+// FindOrphanedCaches returns the server caches with no local record in known
+// that were created more than olderThan before now, oldest first. With a
+// projectTag only caches grove-gemini created for that project are returned,
+// since any other cache may still be in use by another project or tool; an
+// empty projectTag returns every unknown cache.
+func FindOrphanedCaches(apiCaches []CachedContentInfo, known map[string]bool, projectTag string, olderThan time.Duration, now time.Time) []CachedContentInfo {
+	cutoff := now.Add(-olderThan)
+	var orphaned []CachedContentInfo
+	for _, cache := range apiCaches {
+		if known[cache.Name] || cache.CreateTime.After(cutoff) {
+			continue
+		}
+		if projectTag != "" {
+			if tag, ok := CacheOwnerTag(cache.DisplayName); !ok || tag != projectTag {
+				continue
+			}
+		}
+		orphaned = append(orphaned, cache)
+	}
+	sort.Slice(orphaned, func(i, j int) bool {
+		return orphaned[i].CreateTime.Before(orphaned[j].CreateTime)
+	})
+	return orphaned
+}
@@ -0,0 +1,73 @@
+package gemini
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindOrphanedCaches(t *testing.T) {
+	cacheDir := t.TempDir()
+	sharedDir := t.TempDir()
+	if err := SaveCacheInfo(filepath.Join(cacheDir, "hybrid_local.json"), &CacheInfo{CacheID: "cachedContents/local", CacheName: "local"}); err != nil {
+		t.Fatal(err)
+	}
+	trashed := filepath.Join(cacheDir, "hybrid_trashed.json")
+	if err := SaveCacheInfo(trashed, &CacheInfo{CacheID: "cachedContents/trashed", CacheName: "trashed"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := TrashCacheRecord(cacheDir, trashed); err != nil {
+		t.Fatal(err)
+	}
+	cm := &CacheManager{workingDir: t.TempDir(), sharedDir: sharedDir}
+	if err := cm.registerSharedCache(context.Background(), &CacheInfo{CacheID: "cachedContents/shared", CacheName: "shared", Model: "gemini-2.0-flash"}); err != nil {
+		t.Fatal(err)
+	}
+
+	known, err := KnownCacheIDs(cacheDir, sharedDir)
+	if err != nil {
+		t.Fatalf("KnownCacheIDs: %v", err)
+	}
+	for _, id := range []string{"cachedContents/local", "cachedContents/trashed", "cachedContents/shared"} {
+		if !known[id] {
+			t.Errorf("expected %s to be known", id)
+		}
+	}
+
+	now := time.Now()
+	apiCaches := []CachedContentInfo{
+		{Name: "cachedContents/local", CreateTime: now.Add(-48 * time.Hour)},
+		{Name: "cachedContents/shared", CreateTime: now.Add(-48 * time.Hour)},
+		{Name: "cachedContents/recent", CreateTime: now.Add(-time.Hour)},
+		{Name: "cachedContents/newer", CreateTime: now.Add(-30 * time.Hour)},
+		{Name: "cachedContents/oldest", CreateTime: now.Add(-72 * time.Hour)},
+	}
+	orphaned := FindOrphanedCaches(apiCaches, known, "", 24*time.Hour, now)
+	if len(orphaned) != 2 || orphaned[0].Name != "cachedContents/oldest" || orphaned[1].Name != "cachedContents/newer" {
+		t.Errorf("orphaned = %+v, want oldest then newer", orphaned)
+	}
+
+	// With a project tag only caches grove-gemini created for that project qualify
+	tag := ProjectCacheTag(cacheDir)
+	old := now.Add(-48 * time.Hour)
+	apiCaches = []CachedContentInfo{
+		{Name: "cachedContents/mine", DisplayName: serverCacheDisplayName(cacheDir, "", "abcdef"), CreateTime: old},
+		{Name: "cachedContents/other-project", DisplayName: serverCacheDisplayName(t.TempDir(), "docs", "abcdef"), CreateTime: old},
+		{Name: "cachedContents/other-tool", DisplayName: "notebook cache", CreateTime: old},
+		{Name: "cachedContents/unnamed", CreateTime: old},
+	}
+	orphaned = FindOrphanedCaches(apiCaches, known, tag, 24*time.Hour, now)
+	if len(orphaned) != 1 || orphaned[0].Name != "cachedContents/mine" {
+		t.Errorf("orphaned for this project = %+v, want only mine", orphaned)
+	}
+	if all := FindOrphanedCaches(apiCaches, known, "", 24*time.Hour, now); len(all) != len(apiCaches) {
+		t.Errorf("orphaned for all projects = %d caches, want %d", len(all), len(apiCaches))
+	}
+
+	// A missing cache directory just means nothing is known
+	known, err = KnownCacheIDs(filepath.Join(cacheDir, "missing"), "")
+	if err != nil || len(known) != 0 {
+		t.Errorf("KnownCacheIDs(missing) = %v, %v", known, err)
+	}
+}
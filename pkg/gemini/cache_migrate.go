@@ -0,0 +1,154 @@
+package gemini
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheKeyVersion identifies the scheme generateCacheKey uses. Bump it whenever
+// the hashing changes so 'cache migrate' can find records keyed the old way.
+const CacheKeyVersion = 2
+
+// CacheMigrationAction describes what MigrateCacheKeys did with a cache record
+type CacheMigrationAction string
+
+const (
+	// MigrationCurrent means the record was already written by the current key scheme
+	MigrationCurrent CacheMigrationAction = "current"
+	// MigrationStamped means the record was kept under its key and only the version was recorded
+	MigrationStamped CacheMigrationAction = "stamped"
+	// MigrationRekeyed means the record was moved to the key the current scheme produces
+	MigrationRekeyed CacheMigrationAction = "rekeyed"
+	// MigrationOrphaned means the record duplicates one under its new key and was marked for cleanup
+	MigrationOrphaned CacheMigrationAction = "orphaned"
+)
+
+// CacheMigration is the outcome of migrating a single cache record
+type CacheMigration struct {
+	File         string
+	CacheName    string
+	NewCacheName string
+	Action       CacheMigrationAction
+	Reason       string
+}
+
+// MigrateCacheKeys finds local cache records written by older key schemes and
+// brings them up to CacheKeyVersion. A record whose source files are unchanged
+// is re-keyed so later requests find it again. A record that can't be re-keyed,
+// because its sources changed or are gone, is kept under its old key with the
+// version recorded: it may still be in use through a frozen context, and it
+// expires on its own. Only a record that duplicates one already stored under
+// its new key is flagged as orphaned so 'cache prune' deletes the server-side
+// cache, and never a pinned one. Cleared records are skipped. With dryRun set
+// the planned actions are returned without touching any files.
+func (m *CacheManager) MigrateCacheKeys(dryRun bool) ([]CacheMigration, error) {
+	entries, err := os.ReadDir(m.cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	var migrations []CacheMigration
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "hybrid_") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		path := filepath.Join(m.cacheDir, name)
+		info, err := LoadCacheInfo(path)
+		if err != nil {
+			return migrations, fmt.Errorf("loading %s: %w", name, err)
+		}
+		if info.ClearedAt != nil || info.Orphaned {
+			continue
+		}
+
+		migration, err := m.migrateCacheRecord(path, info, dryRun)
+		if err != nil {
+			return migrations, fmt.Errorf("migrating %s: %w", name, err)
+		}
+		migrations = append(migrations, migration)
+	}
+	return migrations, nil
+}
+
+// migrateCacheRecord decides and applies the migration for one cache record
+func (m *CacheManager) migrateCacheRecord(path string, info *CacheInfo, dryRun bool) (CacheMigration, error) {
+	migration := CacheMigration{File: filepath.Base(path), CacheName: info.CacheName}
+	if info.KeyVersion == CacheKeyVersion {
+		migration.Action = MigrationCurrent
+		return migration, nil
+	}
+
+	// keep records the version without moving the record, leaving its
+	// pinned state and server cache alone
+	keep := func(reason string) (CacheMigration, error) {
+		migration.Action = MigrationStamped
+		migration.Reason = reason
+		if dryRun {
+			return migration, nil
+		}
+		info.KeyVersion = CacheKeyVersion
+		return migration, SaveCacheInfo(path, info)
+	}
+
+	// Current keys are derived from the cold context files' content, so the
+	// record can only be re-keyed if every file is still exactly as cached
+	sourcePaths := info.CachedFiles()
+	if len(sourcePaths) == 0 {
+		return keep("records no cached files")
+	}
+	for _, sourcePath := range sourcePaths {
+		currentHash, err := hashFile(sourcePath)
+		if err != nil {
+			return keep(fmt.Sprintf("source file %s is no longer readable", filepath.Base(sourcePath)))
+		}
+		if currentHash != info.CachedFileHashes[sourcePath] {
+			return keep(fmt.Sprintf("source file %s changed since the cache was created", filepath.Base(sourcePath)))
+		}
+	}
+
+	newKey, err := generateCacheKey(sourcePaths)
+	if err != nil {
+		return migration, err
+	}
+	migration.NewCacheName = newKey
+
+	if newKey == info.CacheName {
+		return keep("")
+	}
+
+	newPath := filepath.Join(m.cacheDir, "hybrid_"+newKey+".json")
+	if _, err := os.Stat(newPath); err == nil {
+		reason := fmt.Sprintf("a cache already exists under the new key %s", newKey)
+		if info.Pinned {
+			return keep(reason)
+		}
+		migration.Action = MigrationOrphaned
+		migration.Reason = reason
+		if dryRun {
+			return migration, nil
+		}
+		info.Orphaned = true
+		info.KeyVersion = CacheKeyVersion
+		return migration, SaveCacheInfo(path, info)
+	}
+
+	migration.Action = MigrationRekeyed
+	if dryRun {
+		return migration, nil
+	}
+	info.CacheName = newKey
+	info.KeyVersion = CacheKeyVersion
+	if err := SaveCacheInfo(newPath, info); err != nil {
+		return migration, err
+	}
+	if err := os.Remove(path); err != nil {
+		return migration, fmt.Errorf("removing old cache record: %w", err)
+	}
+	return migration, nil
+}
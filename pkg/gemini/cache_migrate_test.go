@@ -0,0 +1,139 @@
+package gemini
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMigrateCacheKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	cm := NewCacheManager(tmpDir)
+	if err := os.MkdirAll(cm.cacheDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeSource := func(name, content string) (string, string) {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := hashFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path, hash
+	}
+	writeRecord := func(key string, info CacheInfo) {
+		info.CacheName = key
+		info.ExpiresAt = time.Now().Add(time.Hour)
+		if err := SaveCacheInfo(filepath.Join(cm.cacheDir, "hybrid_"+key+".json"), &info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Written by the current scheme but before versions were recorded
+	stampPath, stampHash := writeSource("stamp.md", "stamp")
+	stampKey, _ := generateCacheKey([]string{stampPath})
+	writeRecord(stampKey, CacheInfo{CachedFileHashes: map[string]string{stampPath: stampHash}})
+
+	// Written by an older scheme, source unchanged
+	rekeyPath, rekeyHash := writeSource("rekey.md", "rekey")
+	newKey, _ := generateCacheKey([]string{rekeyPath})
+	writeRecord("oldkey0000000001", CacheInfo{CachedFileHashes: map[string]string{rekeyPath: rekeyHash}})
+
+	// Written by an older scheme, source since changed
+	changedPath, _ := writeSource("changed.md", "changed")
+	writeRecord("oldkey0000000002", CacheInfo{CachedFileHashes: map[string]string{changedPath: "stale"}})
+
+	// Written by an older scheme, pinned and since changed
+	writeRecord("oldkey0000000004", CacheInfo{Pinned: true, CachedFileHashes: map[string]string{changedPath: "stale"}})
+
+	// Written by an older scheme, duplicating a record under its new key
+	dupPath, dupHash := writeSource("dup.md", "dup")
+	dupKey, _ := generateCacheKey([]string{dupPath})
+	writeRecord(dupKey, CacheInfo{KeyVersion: CacheKeyVersion, CachedFileHashes: map[string]string{dupPath: dupHash}})
+	writeRecord("oldkey0000000005", CacheInfo{CachedFileHashes: map[string]string{dupPath: dupHash}})
+
+	// Already current
+	writeRecord("currentkey000003", CacheInfo{KeyVersion: CacheKeyVersion})
+
+	dryRun, err := cm.MigrateCacheKeys(true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(dryRun) != 7 {
+		t.Fatalf("Expected 7 planned migrations, got %d", len(dryRun))
+	}
+	if _, err := os.Stat(filepath.Join(cm.cacheDir, "hybrid_oldkey0000000001.json")); err != nil {
+		t.Error("Dry run should not move cache records")
+	}
+
+	migrations, err := cm.MigrateCacheKeys(false)
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	actions := make(map[string]CacheMigrationAction)
+	for _, m := range migrations {
+		actions[m.CacheName] = m.Action
+	}
+	expected := map[string]CacheMigrationAction{
+		stampKey:           MigrationStamped,
+		"oldkey0000000001": MigrationRekeyed,
+		"oldkey0000000002": MigrationStamped,
+		"oldkey0000000004": MigrationStamped,
+		"oldkey0000000005": MigrationOrphaned,
+		dupKey:             MigrationCurrent,
+		"currentkey000003": MigrationCurrent,
+	}
+	for name, want := range expected {
+		if actions[name] != want {
+			t.Errorf("Expected %s to be %s, got %s", name, want, actions[name])
+		}
+	}
+
+	rekeyed, err := LoadCacheInfo(filepath.Join(cm.cacheDir, "hybrid_"+newKey+".json"))
+	if err != nil {
+		t.Fatalf("Expected re-keyed record: %v", err)
+	}
+	if rekeyed.CacheName != newKey || rekeyed.KeyVersion != CacheKeyVersion {
+		t.Errorf("Unexpected re-keyed record: %+v", rekeyed)
+	}
+	if _, err := os.Stat(filepath.Join(cm.cacheDir, "hybrid_oldkey0000000001.json")); !os.IsNotExist(err) {
+		t.Error("Expected old record to be removed after re-keying")
+	}
+
+	// Changed records stay live under their old key with their pinned state
+	for _, key := range []string{"oldkey0000000002", "oldkey0000000004"} {
+		kept, err := LoadCacheInfo(filepath.Join(cm.cacheDir, "hybrid_"+key+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kept.Orphaned || kept.KeyVersion != CacheKeyVersion {
+			t.Errorf("Expected %s to be kept and stamped, got %+v", key, kept)
+		}
+	}
+	if pinned, _ := LoadCacheInfo(filepath.Join(cm.cacheDir, "hybrid_oldkey0000000004.json")); pinned == nil || !pinned.Pinned {
+		t.Error("Expected pinned record to stay pinned")
+	}
+
+	orphaned, err := LoadCacheInfo(filepath.Join(cm.cacheDir, "hybrid_oldkey0000000005.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !orphaned.Orphaned {
+		t.Error("Expected duplicate record to be marked orphaned")
+	}
+
+	// A second run has nothing left to do
+	again, err := cm.MigrateCacheKeys(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range again {
+		if m.Action != MigrationCurrent {
+			t.Errorf("Expected %s to be current on second run, got %s", m.CacheName, m.Action)
+		}
+	}
+}
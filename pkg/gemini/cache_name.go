@@ -0,0 +1,152 @@
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ResolveCacheName maps a display name given with 'cache create --cache-name'
+// to the content-hash name the cache is recorded under. A name that already
+// has a record is returned unchanged, as is one that matches no display name,
+// so lookups fail with the usual not-found error. A display name carried by
+// several live records is ambiguous.
+func ResolveCacheName(cacheDir, name string) (string, error) {
+	if _, err := os.Stat(filepath.Join(cacheDir, "hybrid_"+name+".json")); err == nil {
+		return name, nil
+	}
+
+	records, err := cacheRecordsNamed(cacheDir, name)
+	if err != nil {
+		return "", err
+	}
+	// Prefer live records; a cleared one is only resolved if nothing else has the name
+	var live []string
+	for _, info := range records {
+		if info.ClearedAt == nil {
+			live = append(live, info.CacheName)
+		}
+	}
+	switch {
+	case len(live) == 1:
+		return live[0], nil
+	case len(live) > 1:
+		sort.Strings(live)
+		return "", fmt.Errorf("cache name %q is used by several caches (%s); pass the hash instead", name, strings.Join(live, ", "))
+	case len(records) > 0:
+		return records[0].CacheName, nil
+	}
+	return name, nil
+}
+
+// AssignCacheDisplayName gives the cache recorded as cacheName the display
+// name, moving it off any other record that had it so the name always
+// refers to a single cache.
+func AssignCacheDisplayName(ctx context.Context, cacheDir, cacheName, displayName string) error {
+	others, err := cacheRecordsNamed(cacheDir, displayName)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.CacheName == cacheName {
+			continue
+		}
+		if err := updateCacheInfo(ctx, filepath.Join(cacheDir, "hybrid_"+other.CacheName+".json"), func(info *CacheInfo) bool {
+			if info.DisplayName != displayName {
+				return false
+			}
+			info.DisplayName = ""
+			return true
+		}); err != nil {
+			return fmt.Errorf("moving cache name from %s: %w", other.CacheName, err)
+		}
+	}
+
+	return updateCacheInfo(ctx, filepath.Join(cacheDir, "hybrid_"+cacheName+".json"), func(info *CacheInfo) bool {
+		if info.CacheID == "" || info.DisplayName == displayName {
+			return false
+		}
+		info.DisplayName = displayName
+		return true
+	})
+}
+
+// cacheRecordsNamed returns the records in cacheDir with the display name
+func cacheRecordsNamed(cacheDir, displayName string) ([]*CacheInfo, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var records []*CacheInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "hybrid_") || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := LoadCacheInfo(filepath.Join(cacheDir, entry.Name()))
+		if err != nil || info.DisplayName != displayName {
+			continue
+		}
+		records = append(records, info)
+	}
+	return records, nil
+}
+
+// CacheDisplayNamePrefix starts the display name grove-gemini sends to the API
+// for every cache it creates, followed by the owning project's tag, so 'cache
+// gc' can tell its own caches from those created by other tools.
+const CacheDisplayNamePrefix = "grove-gemini:"
+
+// maxCacheDisplayNameLength is the longest display name the API accepts
+const maxCacheDisplayNameLength = 128
+
+// ProjectCacheTag returns a short tag identifying the project whose cache
+// records live in cacheDir
+func ProjectCacheTag(cacheDir string) string {
+	if abs, err := filepath.Abs(cacheDir); err == nil {
+		cacheDir = abs
+	}
+	sum := sha256.Sum256([]byte(cacheDir))
+	return hex.EncodeToString(sum[:4])
+}
+
+// serverCacheDisplayName returns the display name sent to the API for a cache
+// created from cacheDir: the prefix, the project tag, then the user's display
+// name or, without one, the start of the content key
+func serverCacheDisplayName(cacheDir, displayName, cacheKey string) string {
+	name := displayName
+	if name == "" {
+		name = cacheKey[:min(len(cacheKey), 12)]
+	}
+	full := CacheDisplayNamePrefix + ProjectCacheTag(cacheDir) + ":" + name
+	return full[:min(len(full), maxCacheDisplayNameLength)]
+}
+
+// CacheOwnerTag returns the project tag in the display name of a server cache
+// grove-gemini created, and false for caches created by anything else
+func CacheOwnerTag(displayName string) (string, bool) {
+	rest, ok := strings.CutPrefix(displayName, CacheDisplayNamePrefix)
+	if !ok {
+		return "", false
+	}
+	tag, _, ok := strings.Cut(rest, ":")
+	return tag, ok && tag != ""
+}
+
+// UserCacheDisplayName strips the grove-gemini prefix and project tag from a
+// server display name, leaving the name the user chose
+func UserCacheDisplayName(displayName string) string {
+	if _, ok := CacheOwnerTag(displayName); !ok {
+		return displayName
+	}
+	rest := strings.TrimPrefix(displayName, CacheDisplayNamePrefix)
+	_, name, _ := strings.Cut(rest, ":")
+	return name
+}
@@ -0,0 +1,97 @@
+package gemini
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveAndAssignCacheDisplayName(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for _, key := range []string{"aaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbb"} {
+		info := &CacheInfo{CacheID: "cachedContents/" + key, CacheName: key, ExpiresAt: time.Now().Add(time.Hour)}
+		if err := SaveCacheInfo(filepath.Join(dir, "hybrid_"+key+".json"), info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Unknown names pass through so lookups report them as not found
+	if got, err := ResolveCacheName(dir, "main-context"); err != nil || got != "main-context" {
+		t.Fatalf("ResolveCacheName(unassigned) = %q, %v", got, err)
+	}
+
+	if err := AssignCacheDisplayName(ctx, dir, "aaaaaaaaaaaaaaaa", "main-context"); err != nil {
+		t.Fatalf("AssignCacheDisplayName: %v", err)
+	}
+	if got, err := ResolveCacheName(dir, "main-context"); err != nil || got != "aaaaaaaaaaaaaaaa" {
+		t.Errorf("ResolveCacheName(main-context) = %q, %v; want aaaaaaaaaaaaaaaa", got, err)
+	}
+	if got, _ := ResolveCacheName(dir, "bbbbbbbbbbbbbbbb"); got != "bbbbbbbbbbbbbbbb" {
+		t.Errorf("hashes should resolve to themselves, got %q", got)
+	}
+
+	// Giving the name to another cache moves it
+	if err := AssignCacheDisplayName(ctx, dir, "bbbbbbbbbbbbbbbb", "main-context"); err != nil {
+		t.Fatalf("AssignCacheDisplayName: %v", err)
+	}
+	if got, _ := ResolveCacheName(dir, "main-context"); got != "bbbbbbbbbbbbbbbb" {
+		t.Errorf("expected the name to move to bbbbbbbbbbbbbbbb, got %q", got)
+	}
+	old, err := LoadCacheInfo(filepath.Join(dir, "hybrid_aaaaaaaaaaaaaaaa.json"))
+	if err != nil || old.DisplayName != "" {
+		t.Errorf("expected the name cleared from the old cache, got %+v, %v", old, err)
+	}
+
+	// Two live records with the same name are ambiguous
+	dup := &CacheInfo{CacheID: "cachedContents/c", CacheName: "cccccccccccccccc", DisplayName: "main-context"}
+	if err := SaveCacheInfo(filepath.Join(dir, "hybrid_cccccccccccccccc.json"), dup); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResolveCacheName(dir, "main-context"); err == nil || !strings.Contains(err.Error(), "several caches") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "hybrid_main-context.json")); !os.IsNotExist(err) {
+		t.Errorf("resolving should not create records: %v", err)
+	}
+}
+
+func TestServerCacheDisplayName(t *testing.T) {
+	dir := t.TempDir()
+	tag := ProjectCacheTag(dir)
+	tests := []struct {
+		displayName string
+		wantUser    string
+	}{
+		{"main-context", "main-context"},
+		{"", "0123456789ab"},
+		{"with:colons", "with:colons"},
+	}
+	for _, tt := range tests {
+		name := serverCacheDisplayName(dir, tt.displayName, "0123456789abcdef")
+		if got, ok := CacheOwnerTag(name); !ok || got != tag {
+			t.Errorf("CacheOwnerTag(%q) = %q, %v; want %q", name, got, ok, tag)
+		}
+		if got := UserCacheDisplayName(name); got != tt.wantUser {
+			t.Errorf("UserCacheDisplayName(%q) = %q, want %q", name, got, tt.wantUser)
+		}
+	}
+
+	if long := serverCacheDisplayName(dir, strings.Repeat("x", 200), "abc"); len(long) != maxCacheDisplayNameLength {
+		t.Errorf("expected long names to be cut to %d characters, got %d", maxCacheDisplayNameLength, len(long))
+	}
+	if ProjectCacheTag(t.TempDir()) == tag {
+		t.Error("expected different cache directories to get different tags")
+	}
+	for _, other := range []string{"", "notebook cache", "grove-gemini:", "grove-gemini-x:abc:name"} {
+		if _, ok := CacheOwnerTag(other); ok {
+			t.Errorf("CacheOwnerTag(%q) should not claim the cache", other)
+		}
+		if got := UserCacheDisplayName(other); got != other {
+			t.Errorf("UserCacheDisplayName(%q) = %q, want it unchanged", other, got)
+		}
+	}
+}
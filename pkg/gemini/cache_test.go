@@ -1,11 +1,19 @@
 package gemini
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/pretty"
 )
 
 func TestNewCacheManager(t *testing.T) {
@@ -62,7 +70,7 @@ func TestGetOrCreateCache_WithoutColdContext(t *testing.T) {
 	nonExistentFile := filepath.Join(tmpDir, "non-existent.txt")
 
 	// This should return nil without error (no cache to use)
-	cacheInfo, _, err := cm.GetOrCreateCache(ctx, nil, "gemini-pro", nonExistentFile, 24*time.Hour, false, false, false, true)
+	cacheInfo, _, err := cm.GetOrCreateCache(ctx, nil, "gemini-pro", []string{nonExistentFile}, 24*time.Hour, false, false, false, true, nil)
 	if err != nil {
 		t.Errorf("Expected no error for non-existent file, got %v", err)
 	}
@@ -85,7 +93,7 @@ func TestGetOrCreateCache_SmallFile(t *testing.T) {
 	ctx := context.Background()
 
 	// This should return nil (file too small for caching)
-	cacheInfo, _, err := cm.GetOrCreateCache(ctx, nil, "gemini-pro", smallFile, 24*time.Hour, false, false, false, true)
+	cacheInfo, _, err := cm.GetOrCreateCache(ctx, nil, "gemini-pro", []string{smallFile}, 24*time.Hour, false, false, false, true, nil)
 	if err != nil {
 		t.Errorf("Expected no error for small file, got %v", err)
 	}
@@ -93,3 +101,397 @@ func TestGetOrCreateCache_SmallFile(t *testing.T) {
 		t.Error("Expected nil cache info for small file")
 	}
 }
+
+func TestGenerateCacheKey_MultipleFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil { //nolint:gosec // test file
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		return path
+	}
+	a := write("a.md", "alpha")
+	b := write("b.md", "beta")
+	c := write("c.md", "gamma")
+	shiftedA := write("shifted-a.md", "alphab")
+	shiftedB := write("shifted-b.md", "eta")
+
+	key := func(files ...string) string {
+		k, err := generateCacheKey(files)
+		if err != nil {
+			t.Fatalf("generateCacheKey failed: %v", err)
+		}
+		return k
+	}
+
+	if key(a, b) != key(b, a) {
+		t.Error("Expected the key not to depend on file order")
+	}
+	if key(a, b) == key(a, b, c) {
+		t.Error("Expected adding a file to change the key")
+	}
+	if key(a, b) == key(a) {
+		t.Error("Expected removing a file to change the key")
+	}
+	if key(a, b) == key(shiftedA, shiftedB) {
+		t.Error("Expected moving content between files to change the key")
+	}
+}
+
+func TestGetOrCreateCache_SmallFilesSummed(t *testing.T) {
+	tmpDir := t.TempDir()
+	cm := NewCacheManager(tmpDir)
+
+	// Two files that are each well under the minimum, and still under it together
+	var files []string
+	for _, name := range []string{"one.md", "two.md"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", 4000)), 0o600); err != nil { //nolint:gosec // test file
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		files = append(files, path)
+	}
+	files = append(files, filepath.Join(tmpDir, "missing.md"))
+
+	cacheInfo, _, err := cm.GetOrCreateCache(context.Background(), nil, "gemini-pro", files, time.Hour, false, false, false, true, nil)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if cacheInfo != nil {
+		t.Error("Expected nil cache info when the combined files are too small")
+	}
+}
+
+func TestHasFilesChanged_RemovedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	kept := filepath.Join(tmpDir, "kept.md")
+	if err := os.WriteFile(kept, []byte("kept"), 0o600); err != nil { //nolint:gosec // test file
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	hash, err := hashFile(kept)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := filepath.Join(tmpDir, "removed.md")
+
+	changed, files := hasFilesChanged(map[string]string{kept: hash, removed: "old"}, []string{kept})
+	if !changed {
+		t.Fatal("Expected a removed file to count as a change")
+	}
+	if len(files) != 1 || !strings.Contains(files[0], "removed.md (removed)") {
+		t.Errorf("Expected the removed file to be reported, got %v", files)
+	}
+}
+
+func TestCacheInfo_CachedFiles(t *testing.T) {
+	info := &CacheInfo{CachedFileHashes: map[string]string{"/b.md": "2", "/c.md": "3", "/a.md": "1"}}
+	got := strings.Join(info.CachedFiles(), ",")
+	if got != "/a.md,/b.md,/c.md" {
+		t.Errorf("CachedFiles() = %s, want sorted paths", got)
+	}
+}
+
+func TestRecommendCacheUsage(t *testing.T) {
+	tests := []struct {
+		name        string
+		stats       *CacheUsageStats
+		wantDisable bool
+	}{
+		{name: "no stats", stats: nil, wantDisable: false},
+		{name: "too few queries", stats: &CacheUsageStats{TotalQueries: 2, AverageHitRate: 0.05}, wantDisable: false},
+		{name: "low hit rate", stats: &CacheUsageStats{TotalQueries: 10, AverageHitRate: 0.1}, wantDisable: true},
+		{name: "healthy hit rate", stats: &CacheUsageStats{TotalQueries: 10, AverageHitRate: 0.8}, wantDisable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &CacheInfo{Model: "gemini-2.5-pro", CreatedAt: time.Now(), UsageStats: tt.stats}
+			rec := RecommendCacheUsage(info, 0.3, 5)
+			if rec.DisableCaching != tt.wantDisable {
+				t.Errorf("DisableCaching = %v, want %v (%s)", rec.DisableCaching, tt.wantDisable, rec.Reason)
+			}
+			if rec.Reason == "" {
+				t.Error("Expected a reason to be set")
+			}
+		})
+	}
+}
+
+func TestCacheTooLargeError(t *testing.T) {
+	err := &CacheTooLargeError{Model: "gemini-2.5-flash", Tokens: 1_200_000, Limit: 1_048_576}
+	msg := err.Error()
+	for _, want := range []string{"1200000 tokens", "1048576 token cache limit", "gemini-2.5-flash", "Split the context"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected error message to contain %q, got %q", want, msg)
+		}
+	}
+}
+
+func TestEnsureRemainingTTL(t *testing.T) {
+	var updates int
+	var body string
+	newExpiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		updates++
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": "cachedContents/abc", "expireTime": newExpiry.Format(time.RFC3339)})
+	})
+
+	dir := t.TempDir()
+	infoFile := filepath.Join(dir, "hybrid_abc.json")
+	soon := time.Now().Add(30 * time.Second)
+	newInfo := func() *CacheInfo {
+		return &CacheInfo{CacheID: "cachedContents/abc", CacheName: "abc", ExpiresAt: soon}
+	}
+
+	// Plenty of time left: nothing to do
+	var out bytes.Buffer
+	cm := NewCacheManager(dir)
+	info := &CacheInfo{CacheID: "cachedContents/abc", ExpiresAt: time.Now().Add(time.Hour)}
+	cm.ensureRemainingTTL(context.Background(), client, pretty.NewWithWriter(&out), info, infoFile, 5*time.Minute)
+	if out.Len() != 0 || updates != 0 {
+		t.Fatalf("expected no action, got %d updates and output %q", updates, out.String())
+	}
+
+	// Expiring soon without auto-extend: warn only
+	info = newInfo()
+	cm.ensureRemainingTTL(context.Background(), client, pretty.NewWithWriter(&out), info, infoFile, 5*time.Minute)
+	if !strings.Contains(out.String(), "Cache expires soon") || updates != 0 || !info.ExpiresAt.Equal(soon) {
+		t.Fatalf("expected a warning and no update, got %d updates and output %q", updates, out.String())
+	}
+
+	// With auto-extend the TTL is refreshed and the new expiry saved
+	out.Reset()
+	cm.SetExpiryPolicy(10*time.Minute, true)
+	info = newInfo()
+	cm.ensureRemainingTTL(context.Background(), client, pretty.NewWithWriter(&out), info, infoFile, 5*time.Minute)
+	if updates != 1 || !info.ExpiresAt.Equal(newExpiry) {
+		t.Fatalf("expected one update to %s, got %d updates and expiry %s", newExpiry, updates, info.ExpiresAt)
+	}
+	if !strings.Contains(body, `"600s"`) {
+		t.Errorf("expected the TTL to be extended by the larger --min-ttl, request body %s", body)
+	}
+	saved, err := LoadCacheInfo(infoFile)
+	if err != nil || !saved.ExpiresAt.Equal(newExpiry) {
+		t.Errorf("saved expiry = %v (err %v), want %s", saved, err, newExpiry)
+	}
+}
+
+func TestGetOrCreateCache_ReusesSharedCache(t *testing.T) {
+	deleted := map[string]bool{"cachedContents/gone": true}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		name := r.URL.Path[strings.Index(r.URL.Path, "cachedContents/"):]
+		if deleted[name] {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 404, "message": "not found", "status": "NOT_FOUND"}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": name})
+	})
+
+	sharedDir := t.TempDir()
+	content := []byte(strings.Repeat("shared cold context ", 100))
+	newProject := func() (*CacheManager, string) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "cold.md")
+		if err := os.WriteFile(path, content, 0o600); err != nil { //nolint:gosec // test file
+			t.Fatal(err)
+		}
+		cm := NewCacheManager(dir)
+		cm.SetSharedCacheDir(sharedDir)
+		return cm, path
+	}
+
+	// Project A created the cache and registered it
+	projectA, pathA := newProject()
+	key, err := generateCacheKey([]string{pathA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := projectA.registerSharedCache(context.Background(), &CacheInfo{CacheID: "cachedContents/abc", CacheName: key, Model: "gemini-2.0-flash", ExpiresAt: expires}); err != nil {
+		t.Fatalf("registerSharedCache: %v", err)
+	}
+
+	// Project B has the same content and reuses it without creating a cache
+	projectB, pathB := newProject()
+	info, created, err := projectB.GetOrCreateCache(context.Background(), client, "gemini-2.0-flash", []string{pathB}, 5*time.Minute, false, false, false, true, nil)
+	if err != nil {
+		t.Fatalf("GetOrCreateCache: %v", err)
+	}
+	if info == nil || created || info.CacheID != "cachedContents/abc" || !info.Shared || !info.ExpiresAt.Equal(expires) {
+		t.Fatalf("expected the shared cache to be reused, got %+v (created %v)", info, created)
+	}
+	if _, err := LoadCacheInfo(filepath.Join(projectB.cacheDir, "hybrid_"+key+".json")); err != nil {
+		t.Errorf("expected project B to record the cache locally: %v", err)
+	}
+	entry, err := LoadSharedCacheEntry(sharedDir, key, "gemini-2.0-flash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.Projects) != 2 {
+		t.Errorf("shared entry projects = %v, want both projects", entry.Projects)
+	}
+
+	// A different model doesn't match
+	if shared, err := projectB.findSharedCache(context.Background(), client, key, "gemini-2.5-pro"); err != nil || shared != nil {
+		t.Errorf("findSharedCache for another model = %+v, %v; want nothing", shared, err)
+	}
+
+	// An entry whose server cache is gone is dropped
+	if err := projectA.registerSharedCache(context.Background(), &CacheInfo{CacheID: "cachedContents/gone", CacheName: "gonekey", Model: "gemini-2.0-flash", ExpiresAt: expires}); err != nil {
+		t.Fatal(err)
+	}
+	if shared, err := projectB.findSharedCache(context.Background(), client, "gonekey", "gemini-2.0-flash"); err != nil || shared != nil {
+		t.Errorf("findSharedCache for a deleted cache = %+v, %v; want nothing", shared, err)
+	}
+	if _, err := LoadSharedCacheEntry(sharedDir, "gonekey", "gemini-2.0-flash"); !os.IsNotExist(err) {
+		t.Errorf("expected the stale entry to be removed, got %v", err)
+	}
+}
+
+func TestReplaceCacheInfo_DeletesReplacedCache(t *testing.T) {
+	var deleted []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		deleted = append(deleted, r.URL.Path[strings.Index(r.URL.Path, "cachedContents/"):])
+		_ = json.NewEncoder(w).Encode(map[string]any{})
+	})
+
+	tests := []struct {
+		name        string
+		previous    *CacheInfo
+		wantDeleted []string
+	}{
+		{"no previous record", nil, nil},
+		{"same cache", &CacheInfo{CacheID: "cachedContents/new", Model: "gemini-2.5-pro"}, nil},
+		{"other model", &CacheInfo{CacheID: "cachedContents/old", Model: "gemini-2.5-flash"}, []string{"cachedContents/old"}},
+		{"shared cache still used elsewhere", &CacheInfo{CacheID: "cachedContents/old", CacheName: "abc", Model: "gemini-2.5-flash", Shared: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted = nil
+			dir := t.TempDir()
+			sharedDir := t.TempDir()
+			infoFile := filepath.Join(dir, "hybrid_abc.json")
+			if tt.previous != nil && tt.previous.Shared {
+				other := &CacheManager{workingDir: t.TempDir(), sharedDir: sharedDir}
+				if err := other.registerSharedCache(context.Background(), tt.previous); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.previous != nil {
+				if err := SaveCacheInfo(infoFile, tt.previous); err != nil {
+					t.Fatal(err)
+				}
+			}
+			info := &CacheInfo{CacheID: "cachedContents/new", CacheName: "abc", Model: "gemini-2.5-pro"}
+			var out bytes.Buffer
+			cm := NewCacheManager(dir)
+			cm.SetSharedCacheDir(sharedDir)
+			if err := cm.replaceCacheInfo(context.Background(), client, pretty.NewWithWriter(&out), infoFile, info, 1); err != nil {
+				t.Fatalf("replaceCacheInfo: %v", err)
+			}
+			if !slices.Equal(deleted, tt.wantDeleted) {
+				t.Errorf("deleted %v, want %v", deleted, tt.wantDeleted)
+			}
+			saved, err := LoadCacheInfo(infoFile)
+			if err != nil || saved.CacheID != "cachedContents/new" {
+				t.Errorf("saved record = %+v (err %v), want the new cache", saved, err)
+			}
+		})
+	}
+}
+
+func TestReplaceCacheInfo_KeepsPin(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{})
+	})
+
+	tests := []struct {
+		name       string
+		previous   *CacheInfo
+		wantPinned bool
+	}{
+		{"expired pinned cache", &CacheInfo{CacheID: "cachedContents/old", CacheName: "abc", Pinned: true, ExpiresAt: time.Now().Add(-time.Hour)}, true},
+		{"expired unpinned cache", &CacheInfo{CacheID: "cachedContents/old", CacheName: "abc", ExpiresAt: time.Now().Add(-time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			infoFile := filepath.Join(dir, "hybrid_abc.json")
+			if err := SaveCacheInfo(infoFile, tt.previous); err != nil {
+				t.Fatal(err)
+			}
+			info := &CacheInfo{CacheID: "cachedContents/new", CacheName: "abc", ExpiresAt: time.Now().Add(time.Hour)}
+			var out bytes.Buffer
+			cm := NewCacheManager(dir)
+			cm.SetSharedCacheDir(t.TempDir())
+			if err := cm.replaceCacheInfo(context.Background(), client, pretty.NewWithWriter(&out), infoFile, info, 1); err != nil {
+				t.Fatalf("replaceCacheInfo: %v", err)
+			}
+			saved, err := LoadCacheInfo(infoFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Pinned != tt.wantPinned {
+				t.Errorf("recreated record pinned = %v, want %v", saved.Pinned, tt.wantPinned)
+			}
+		})
+	}
+}
+
+func TestReleaseCache(t *testing.T) {
+	var deleted []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		deleted = append(deleted, r.URL.Path[strings.Index(r.URL.Path, "cachedContents/"):])
+		_ = json.NewEncoder(w).Encode(map[string]any{})
+	})
+	ctx := context.Background()
+	sharedDir := t.TempDir()
+	projectA, projectB := t.TempDir(), t.TempDir()
+	info := &CacheInfo{CacheID: "cachedContents/shared", CacheName: "abc", Model: "gemini-2.5-flash", Shared: true}
+	for _, dir := range []string{projectA, projectB} {
+		cm := &CacheManager{workingDir: dir, sharedDir: sharedDir}
+		if err := cm.registerSharedCache(ctx, info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first project to let go only drops its reference
+	stillUsedBy, err := ReleaseCache(ctx, client, sharedDir, projectA, info)
+	if err != nil || len(stillUsedBy) != 1 || stillUsedBy[0] != projectB || len(deleted) != 0 {
+		t.Fatalf("first release = %v, %v with deletes %v; want kept for %s", stillUsedBy, err, deleted, projectB)
+	}
+	if entry, err := LoadSharedCacheEntry(sharedDir, "abc", "gemini-2.5-flash"); err != nil || !slices.Equal(entry.Projects, []string{projectB}) {
+		t.Fatalf("shared entry after first release = %+v, %v", entry, err)
+	}
+
+	// The last one deletes the server cache and the index entry
+	stillUsedBy, err = ReleaseCache(ctx, client, sharedDir, projectB, info)
+	if err != nil || len(stillUsedBy) != 0 || !slices.Equal(deleted, []string{"cachedContents/shared"}) {
+		t.Fatalf("last release = %v, %v with deletes %v; want the cache deleted", stillUsedBy, err, deleted)
+	}
+	if _, err := LoadSharedCacheEntry(sharedDir, "abc", "gemini-2.5-flash"); !os.IsNotExist(err) {
+		t.Errorf("expected the shared entry to be removed, got %v", err)
+	}
+
+	// Caches that aren't shared are deleted straight away
+	deleted = nil
+	if _, err := ReleaseCache(ctx, client, sharedDir, projectA, &CacheInfo{CacheID: "cachedContents/own"}); err != nil || !slices.Equal(deleted, []string{"cachedContents/own"}) {
+		t.Errorf("release of an unshared cache = %v with deletes %v", err, deleted)
+	}
+}
@@ -2,9 +2,14 @@ package gemini
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	corelogging "github.com/grovetools/core/logging"
@@ -12,7 +17,10 @@ import (
 	ctxinfo "github.com/grovetools/grove-gemini/pkg/context"
 	"github.com/grovetools/grove-gemini/pkg/logging"
 	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"github.com/grovetools/grove-gemini/pkg/redact"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genai"
 )
 
@@ -24,31 +32,66 @@ type Client struct {
 	client *genai.Client
 }
 
-// NewClient creates a new Gemini client
+// NewClient creates a new Gemini client for the backend resolved by
+// config.ResolveBackend. The Gemini Developer API authenticates with an API
+// key; Vertex AI uses Application Default Credentials, so no key is resolved
+// (apiKeyOverride is ignored there).
 func NewClient(ctx context.Context, apiKeyOverride string) (*Client, error) {
-	var apiKey string
-	var err error
+	return NewClientForWorkDir(ctx, "", apiKeyOverride)
+}
 
-	if apiKeyOverride != "" {
-		apiKey = apiKeyOverride
-	} else {
-		apiKey, err = config.ResolveAPIKey()
-		if err != nil {
-			return nil, err
+// NewClientForWorkDir is like NewClient but resolves the backend from the
+// grove.yml that applies to workDir rather than the current directory.
+func NewClientForWorkDir(ctx context.Context, workDir, apiKeyOverride string) (*Client, error) {
+	backend, err := config.ResolveBackend(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKey string
+	if !backend.IsVertex() {
+		if apiKeyOverride != "" {
+			apiKey = apiKeyOverride
+		} else {
+			apiKey, err = config.ResolveAPIKey()
+			if err != nil {
+				return nil, &APIKeyError{Err: err}
+			}
 		}
 	}
 
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
-	})
+	client, err := genai.NewClient(ctx, clientConfig(backend, apiKey))
 	if err != nil {
+		if backend.IsVertex() {
+			return nil, fmt.Errorf("failed to create Vertex AI client (run 'gcloud auth application-default login' if credentials are missing): %w", err)
+		}
 		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
 	}
 
 	return &Client{client: client}, nil
 }
 
+// clientConfig builds the genai client configuration for a backend
+func clientConfig(backend config.BackendSettings, apiKey string) *genai.ClientConfig {
+	if backend.IsVertex() {
+		return &genai.ClientConfig{
+			Backend:  genai.BackendVertexAI,
+			Project:  backend.Project,
+			Location: backend.Location,
+		}
+	}
+	return &genai.ClientConfig{
+		APIKey:  apiKey,
+		Backend: genai.BackendGeminiAPI,
+	}
+}
+
+// isVertex reports whether the client talks to Vertex AI, which has no Files
+// API: files are sent inline with each request instead of uploaded
+func (c *Client) isVertex() bool {
+	return c.client.ClientConfig().Backend == genai.BackendVertexAI
+}
+
 // GenerateContentOptions contains options for content generation
 type GenerateContentOptions struct {
 	WorkingDir  string
@@ -62,6 +105,79 @@ type GenerateContentOptions struct {
 	TopP            *float32
 	TopK            *int32
 	MaxOutputTokens *int32
+	// Repetition penalties; ignored with a warning if the model doesn't support them
+	PresencePenalty  *float32
+	FrequencyPenalty *float32
+	// CandidateCount requests multiple alternative responses (0 or 1 means a single response)
+	CandidateCount int32
+	// StopSequences halt generation at the first occurrence of any of these strings
+	StopSequences []string
+	// AutoContinue is the maximum number of follow-up turns sent when a response is
+	// cut off at the output token limit (0 disables auto-continue)
+	AutoContinue int
+	// Labels are recorded in the query log and, on backends that support
+	// request labels (Vertex AI), attached to the request for billing breakdowns
+	Labels map[string]string
+	// ShowCostOnly replaces the token usage box with a single cost line on stderr
+	ShowCostOnly bool
+	// ResponseMIMEType sets the response format, e.g. JSONMIMEType for JSON mode
+	ResponseMIMEType string
+	// ResponseJSONSchema constrains the response to JSON matching this JSON Schema document
+	ResponseJSONSchema []byte
+	// DumpContentsDir, when set, receives each assembled request part as a separate file plus an index.json
+	DumpContentsDir string
+	// StreamWriter, when set, receives the response text incrementally as it is generated
+	StreamWriter io.Writer
+	// MaxRetries is how many times a rate-limited (429) or failed (500/503) generate
+	// call is retried with exponential backoff (0 disables retrying)
+	MaxRetries int
+	// RetryBaseDelay is the backoff before the first retry (defaults to DefaultRetryBaseDelay)
+	RetryBaseDelay time.Duration
+	// MaxRetryWait caps the total backoff across retries (defaults to DefaultMaxRetryWait)
+	MaxRetryWait time.Duration
+	// UploadConcurrency is how many attached files are uploaded at once (defaults to DefaultUploadConcurrency)
+	UploadConcurrency int
+	// SystemInstruction steers the model separately from the prompt. Cached content can't be
+	// combined with a system instruction, so with a cache it is sent at the start of the user turn.
+	SystemInstruction string
+	// PromptTemplate is the path of the template the prompt was rendered from, for debug logging
+	PromptTemplate string
+	// ConversationHistory holds earlier turns sent ahead of this request's user turn
+	ConversationHistory []*genai.Content
+	// NoUploadCache always uploads attached files instead of reusing earlier uploads of the same content
+	NoUploadCache bool
+}
+
+// MaxStopSequences is the maximum number of stop sequences the Gemini API accepts per request.
+const MaxStopSequences = 5
+
+// isUnsupportedPenaltyError reports whether a request carrying presence or
+// frequency penalties was rejected because the model doesn't support them
+func isUnsupportedPenaltyError(err error, config *genai.GenerateContentConfig) bool {
+	if config.PresencePenalty == nil && config.FrequencyPenalty == nil {
+		return false
+	}
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 {
+		return false
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message), "penalty")
+}
+
+// costOnlyEnvVar enables cost-only output for every request when set to a truthy value.
+const costOnlyEnvVar = "GROVE_GEMINI_COST_ONLY"
+
+// costOnlyEnabled reports whether cost-only output was requested via options or environment.
+func costOnlyEnabled(opts *GenerateContentOptions) bool {
+	if opts != nil && opts.ShowCostOnly {
+		return true
+	}
+	switch strings.ToLower(os.Getenv(costOnlyEnvVar)) {
+	case "", "0", "false", "no", "off":
+		return false
+	default:
+		return true
+	}
 }
 
 // GenerateContentWithCache generates content using a cached context and dynamic files
@@ -69,8 +185,44 @@ func (c *Client) GenerateContentWithCache(ctx context.Context, model string, pro
 	return c.GenerateContentWithCacheAndOptions(ctx, model, prompt, cacheID, dynamicFilePaths, nil)
 }
 
+// GenerateResult holds the response text along with usage and timing information
+type GenerateResult struct {
+	Text             string
+	Model            string
+	CacheID          string
+	CachedTokens     int32
+	PromptTokens     int32
+	UserPromptTokens int32
+	CompletionTokens int32
+	TotalTokens      int32
+	CacheHitRate     float64 // Fraction of prompt tokens served from cache (0-1)
+	EstimatedCost    float64 // Estimated cost in USD
+	// CacheCreationCost is the one-time cost of the new cache this request built, not included in EstimatedCost
+	CacheCreationCost float64
+	Continuations     int           // Follow-up turns sent because the response hit the output token limit
+	Duration          time.Duration // Generation time, excluding file uploads
+	UploadDuration    time.Duration // Wall-clock time uploading files before generation
+	ResponseCacheHit  bool          // Answered from the local response cache without calling the API
+}
+
 // GenerateContentWithCacheAndOptions generates content with additional context options
 func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model string, prompt string, cacheID string, dynamicFilePaths []string, opts *GenerateContentOptions) (string, error) {
+	result, err := c.GenerateContentWithResult(ctx, model, prompt, cacheID, dynamicFilePaths, opts)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// GenerateContentWithResult generates content like GenerateContentWithCacheAndOptions
+// but returns the usage metadata, cost and latency alongside the response text
+func (c *Client) GenerateContentWithResult(ctx context.Context, model string, prompt string, cacheID string, dynamicFilePaths []string, opts *GenerateContentOptions) (*GenerateResult, error) {
+	ctx, span := tracer.Start(ctx, "gemini.GenerateContent", trace.WithAttributes(
+		attribute.String("gemini.model", model),
+		attribute.String("gemini.cache_id", cacheID),
+	))
+	defer span.End()
+
 	// Get request ID from environment for tracing
 	requestID := os.Getenv("GROVE_REQUEST_ID")
 
@@ -85,7 +237,7 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 	for _, filePath := range dynamicFilePaths {
 		absPath, err := filepath.Abs(filePath)
 		if err != nil {
-			return "", fmt.Errorf("resolving dynamic file path %s: %w", filePath, err)
+			return nil, fmt.Errorf("resolving dynamic file path %s: %w", filePath, err)
 		}
 		if !uploadedFiles[absPath] {
 			allFilesToUpload = append(allFilesToUpload, absPath)
@@ -98,7 +250,7 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 		for _, pFile := range opts.PromptFiles {
 			absPath, err := filepath.Abs(pFile)
 			if err != nil {
-				return "", fmt.Errorf("resolving prompt file path %s: %w", pFile, err)
+				return nil, fmt.Errorf("resolving prompt file path %s: %w", pFile, err)
 			}
 			if !uploadedFiles[absPath] {
 				allFilesToUpload = append(allFilesToUpload, absPath)
@@ -110,13 +262,13 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 	// Structured logging for Gemini requests using grove-core logging
 	// This logs detailed request information when log level is set to debug
 	if log.Logger.IsLevelEnabled(logrus.DebugLevel) {
-		// Create structured log fields
+		// Create structured log fields, masking secrets embedded in the prompt
 		fields := logrus.Fields{
 			"request_id":     requestID,
 			"timestamp":      time.Now(),
 			"model":          model,
 			"cache_id":       cacheID,
-			"prompt_text":    prompt,
+			"prompt_text":    requestRedactor(ctx, opts).Redact(prompt),
 			"attached_files": allFilesToUpload,
 			"total_files":    len(allFilesToUpload),
 		}
@@ -132,6 +284,18 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 			if opts.PlanName != "" {
 				fields["plan_name"] = opts.PlanName
 			}
+			if opts.PresencePenalty != nil {
+				fields["presence_penalty"] = *opts.PresencePenalty
+			}
+			if opts.FrequencyPenalty != nil {
+				fields["frequency_penalty"] = *opts.FrequencyPenalty
+			}
+			if opts.SystemInstruction != "" {
+				fields["system_instruction"] = requestRedactor(ctx, opts).Redact(opts.SystemInstruction)
+			}
+			if opts.PromptTemplate != "" {
+				fields["prompt_template"] = opts.PromptTemplate
+			}
 		}
 
 		// Log with structured fields
@@ -141,36 +305,63 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 	// Upload all files
 	var requestParts []*genai.Part
 	var uploadResults []FileUploadResult
-	if len(allFilesToUpload) > 0 {
+	var uploadDuration time.Duration
+	if len(allFilesToUpload) > 0 && c.isVertex() {
+		logger.FilesIncludedCtx(ctx, allFilesToUpload, nonTextMIMETypes(allFilesToUpload))
+		parts, err := inlineFileParts(allFilesToUpload)
+		if err != nil {
+			recordSpanError(span, err)
+			return nil, err
+		}
+		requestParts = append(requestParts, parts...)
+	} else if len(allFilesToUpload) > 0 {
 		// Show files to be uploaded (with full paths)
-		logger.FilesIncludedCtx(ctx, allFilesToUpload)
+		logger.FilesIncludedCtx(ctx, allFilesToUpload, nonTextMIMETypes(allFilesToUpload))
 
 		// Upload files silently
-		for _, filePath := range allFilesToUpload {
-			f, duration, err := uploadFileQuiet(ctx, c.client, filePath)
-			if err != nil {
-				return "", fmt.Errorf("failed to upload file %s: %w", filePath, err)
+		concurrency := DefaultUploadConcurrency
+		if opts != nil && opts.UploadConcurrency > 0 {
+			concurrency = opts.UploadConcurrency
+		}
+		uploadCtx, uploadSpan := tracer.Start(ctx, "gemini.upload_files", trace.WithAttributes(
+			attribute.Int("gemini.file_count", len(allFilesToUpload)),
+			attribute.Int("gemini.upload_concurrency", concurrency),
+		))
+		// Reuse uploads of unchanged files recorded in the project's cache directory
+		var uploadIndex *UploadIndex
+		if opts != nil && opts.WorkingDir != "" && !opts.NoUploadCache {
+			uploadIndex = LoadUploadIndex(ResolveGeminiCacheDir(opts.WorkingDir))
+		}
+		uploadStart := time.Now()
+		results, err := uploadFiles(uploadCtx, c.client, uploadIndex, allFilesToUpload, concurrency)
+		if uploadIndex != nil {
+			if saveErr := uploadIndex.Save(); saveErr != nil {
+				ulog.Warn("Failed to save upload index").Err(saveErr).Log(ctx)
 			}
-
-			uploadResults = append(uploadResults, FileUploadResult{
-				FilePath:   filePath,
-				FileURI:    f.URI,
-				MIMEType:   f.MIMEType,
-				DurationMs: duration.Milliseconds(),
-			})
-
-			part := genai.NewPartFromURI(f.URI, f.MIMEType)
-			requestParts = append(requestParts, part)
 		}
-
-		// Confirm uploads complete
-		var totalTimeMs int64
+		if err != nil {
+			recordSpanError(uploadSpan, err)
+			uploadSpan.End()
+			recordSpanError(span, err)
+			return nil, err
+		}
+		uploadSpan.End()
+		uploadDuration = time.Since(uploadStart)
+		uploadResults = results
+		reused := 0
 		for _, r := range uploadResults {
-			totalTimeMs += r.DurationMs
+			requestParts = append(requestParts, genai.NewPartFromURI(r.FileURI, r.MIMEType))
+			if r.Reused {
+				reused++
+			}
 		}
+
+		// Confirm uploads complete; uploads overlap, so report wall-clock time
 		ulog.Success("Files uploaded").
 			Field("file_count", len(uploadResults)).
-			Field("total_time_ms", totalTimeMs).
+			Field("reused_count", reused).
+			Field("concurrency", concurrency).
+			Field("total_time_ms", uploadDuration.Milliseconds()).
 			Log(ctx)
 	}
 
@@ -178,27 +369,55 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 	var promptTokens int
 	if prompt != "" {
 		// Count tokens for just the prompt text
-		tokenResp, err := c.client.Models.CountTokens(ctx,
+		countCtx, countSpan := tracer.Start(ctx, "gemini.count_tokens")
+		tokenResp, err := c.client.Models.CountTokens(countCtx,
 			model,
 			[]*genai.Content{{Parts: []*genai.Part{{Text: prompt}}}},
 			nil,
 		)
 		if err == nil {
 			promptTokens = int(tokenResp.TotalTokens)
+			countSpan.SetAttributes(attribute.Int("gemini.user_prompt_tokens", promptTokens))
+		} else {
+			recordSpanError(countSpan, err)
 		}
+		countSpan.End()
 		// Continue even if token counting fails - it's not critical
 
 		requestParts = append(requestParts, &genai.Part{Text: prompt})
 	}
 
+	var systemInstruction string
+	if opts != nil {
+		systemInstruction = opts.SystemInstruction
+	}
+	if systemInstruction != "" && cacheID != "" {
+		logger.WarningCtx(ctx, "System instructions can't be combined with a cache; sending it at the start of the prompt instead")
+		requestParts = append([]*genai.Part{{Text: systemInstruction}}, requestParts...)
+	}
+
 	// Create content object with all parts
 	userTurn := &genai.Content{
 		Role:  genai.RoleUser,
 		Parts: requestParts,
 	}
 
-	// Create contents slice for API
-	contentsForAPI := []*genai.Content{userTurn}
+	// Create contents slice for API, after any earlier turns of the conversation
+	var contentsForAPI []*genai.Content
+	if opts != nil {
+		contentsForAPI = append(contentsForAPI, opts.ConversationHistory...)
+	}
+	contentsForAPI = append(contentsForAPI, userTurn)
+
+	// Write the exact assembled contents for debugging, if requested
+	if opts != nil && opts.DumpContentsDir != "" {
+		if index, err := dumpRequestContents(opts.DumpContentsDir, model, cacheID, systemInstruction, contentsForAPI, allFilesToUpload); err != nil {
+			logger.WarningCtx(ctx, fmt.Sprintf("Failed to dump request contents: %v", err))
+		} else {
+			logger.InfoCtx(ctx, fmt.Sprintf("Dumped %d request parts to %s", len(index.Parts), opts.DumpContentsDir))
+		}
+	}
+
 	// Generate content with optional cache
 	var result *genai.GenerateContentResponse
 	var err error
@@ -212,6 +431,8 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 	// Add cache if provided
 	if cacheID != "" {
 		config.CachedContent = cacheID
+	} else if systemInstruction != "" {
+		config.SystemInstruction = genai.NewContentFromText(systemInstruction, genai.RoleUser)
 	}
 
 	// Add generation parameters from options
@@ -230,15 +451,56 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 		if opts.MaxOutputTokens != nil {
 			config.MaxOutputTokens = *opts.MaxOutputTokens
 		}
+		config.PresencePenalty = opts.PresencePenalty
+		config.FrequencyPenalty = opts.FrequencyPenalty
+		if opts.CandidateCount > 1 {
+			config.CandidateCount = opts.CandidateCount
+		}
+		if len(opts.StopSequences) > 0 {
+			config.StopSequences = opts.StopSequences
+		}
+		// The Gemini Developer API rejects request labels, so only send them to Vertex AI
+		if len(opts.Labels) > 0 && c.client.ClientConfig().Backend == genai.BackendVertexAI {
+			config.Labels = opts.Labels
+		}
+		if opts.ResponseMIMEType != "" {
+			config.ResponseMIMEType = opts.ResponseMIMEType
+		}
+		if len(opts.ResponseJSONSchema) > 0 {
+			var schema any
+			if err := json.Unmarshal(opts.ResponseJSONSchema, &schema); err != nil {
+				return nil, fmt.Errorf("parsing response JSON schema: %w", err)
+			}
+			config.ResponseMIMEType = JSONMIMEType
+			config.ResponseJsonSchema = schema
+		}
 	}
 
-	result, err = c.client.Models.GenerateContent(
-		ctx,
-		model,
-		contentsForAPI,
-		config,
-	)
+	generateCtx, generateSpan := tracer.Start(ctx, "gemini.generate")
+	var streamWriter io.Writer
+	if opts != nil {
+		streamWriter = opts.StreamWriter
+	}
+	retry := newRetryPolicy(opts)
+	generate := func() (*genai.GenerateContentResponse, error) {
+		return retryableGenerate(generateCtx, model, retry, func() (*genai.GenerateContentResponse, error) {
+			return c.generateContent(generateCtx, model, contentsForAPI, config, streamWriter)
+		})
+	}
+	result, err = generate()
+	if err != nil && isUnsupportedPenaltyError(err, config) {
+		// Not every model accepts penalties; drop them rather than failing the request
+		logger.WarningCtx(ctx, fmt.Sprintf("Model %s does not support presence/frequency penalties, retrying without them", model))
+		config.PresencePenalty = nil
+		config.FrequencyPenalty = nil
+		result, err = generate()
+	}
 	if err != nil {
+		err = classifyTimeout(ctx, err)
+		recordSpanError(generateSpan, err)
+		generateSpan.End()
+		recordSpanError(span, err)
+
 		// Gather context information
 		var contextInfo *ctxinfo.Info
 		if opts != nil && opts.WorkingDir != "" {
@@ -255,7 +517,8 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 			Model:        model,
 			Method:       "GenerateContent",
 			ResponseTime: time.Since(startTime).Seconds(),
-			Error:        err.Error(),
+			UploadTime:   uploadDuration.Seconds(),
+			Error:        requestRedactor(ctx, opts).Redact(err.Error()),
 			CacheID:      cacheID,
 			Success:      false,
 			WorkingDir:   contextInfo.WorkingDir,
@@ -268,26 +531,89 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 		} else {
 			logEntry.Caller = ctxinfo.GetCaller()
 		}
+		if opts != nil {
+			logEntry.Labels = opts.Labels
+		}
 		if err := geminiLogger.Log(logEntry); err != nil {
 			// Don't fail the request if logging fails
 			ulog.Warn("Failed to log query").Err(err).Log(ctx)
 		}
 
-		return "", fmt.Errorf("failed to generate content: %w", err)
+		if IsQuotaError(err) {
+			return nil, newQuotaError(model, err, time.Now())
+		}
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	// Keep asking the model to continue while the response is cut off at the
+	// output token limit, accumulating text and usage across the turns
+	text := responseText(result)
+	continuations := 0
+	if opts != nil && opts.AutoContinue > 0 && len(result.Candidates) == 1 {
+		for continuations < opts.AutoContinue && isTruncated(result) {
+			continuations++
+			logger.InfoCtx(ctx, fmt.Sprintf("Response hit the output token limit, continuing (%d/%d)", continuations, opts.AutoContinue))
+
+			contentsForAPI = append(contentsForAPI,
+				result.Candidates[0].Content,
+				genai.NewContentFromText(continuePrompt, genai.RoleUser),
+			)
+			next, err := generate()
+			if err != nil {
+				logger.WarningCtx(ctx, fmt.Sprintf("Continuation failed, returning the partial response: %v", err))
+				break
+			}
+
+			text += responseText(next)
+			result.UsageMetadata = addUsage(result.UsageMetadata, next.UsageMetadata)
+			result.Candidates = next.Candidates
+		}
+		if isTruncated(result) {
+			logger.WarningCtx(ctx, fmt.Sprintf("Response is still truncated after %d continuation turns", continuations))
+		}
+		generateSpan.SetAttributes(attribute.Int("gemini.continuations", continuations))
+	}
+
+	generateSpan.End()
+
+	// An empty answer is a failure even though the API call succeeded
+	emptyErr := checkEmptyResponse(model, result, text)
+	if emptyErr != nil {
+		recordSpanError(span, emptyErr)
 	}
 
 	// Calculate duration
 	duration := time.Since(startTime)
 
+	genResult := &GenerateResult{
+		Text:           text,
+		Model:          model,
+		CacheID:        cacheID,
+		Continuations:  continuations,
+		Duration:       duration,
+		UploadDuration: uploadDuration,
+	}
+
 	// Show token usage and log the query
 	if result.UsageMetadata != nil {
-		// Extract all token components
-		cachedTokens := int(result.UsageMetadata.CachedContentTokenCount)
-		totalPromptTokens := int(result.UsageMetadata.PromptTokenCount)
-		completionTokens := int(result.UsageMetadata.CandidatesTokenCount)
+		workDir := ""
+		if opts != nil {
+			workDir = opts.WorkingDir
+		}
+		loadPricingOverrides(ctx, workDir)
+		if pricing, ok := logging.PricingOverrideFor(model); ok {
+			logger.WarningCtx(ctx, fmt.Sprintf("Cost estimate uses gemini.pricing_overrides for %s ($%.2f/M input, $%.2f/M output)", model, pricing.InputPrice, pricing.OutputPrice))
+		}
 
-		// Calculate actual dynamic tokens (prompt tokens minus cached tokens)
-		dynamicTokens := totalPromptTokens - cachedTokens
+		usage, err := ReportTokenUsage(ctx, result, model)
+		if err != nil {
+			return nil, err
+		}
+		cachedTokens := usage.CachedTokens
+		dynamicTokens := usage.DynamicTokens
+		completionTokens := usage.CompletionTokens
+		cacheHitRate := usage.CacheHitRate
+		estimatedCost := usage.EstimatedCost
 
 		// Extract isNewCache flag from options
 		isNewCache := false
@@ -295,21 +621,27 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 			isNewCache = opts.IsNewCache
 		}
 
-		// Calculate cache hit rate for logging
-		cacheHitRate := float64(0)
-		if totalPromptTokens > 0 {
-			cacheHitRate = float64(cachedTokens) / float64(totalPromptTokens)
+		if len(result.Candidates) > 1 {
+			logger.CandidatesCtx(ctx, len(result.Candidates), completionTokens)
+		}
+		if continuations > 0 {
+			logger.InfoCtx(ctx, fmt.Sprintf("Combined %d continuation turns; token usage and cost below cover all turns", continuations))
 		}
 
-		logger.TokenUsageCtx(
-			ctx,
-			cachedTokens,
-			dynamicTokens,
-			completionTokens,
-			promptTokens,
-			duration,
-			isNewCache,
-		)
+		if costOnlyEnabled(opts) {
+			logger.CostLine(estimatedCost, int(result.UsageMetadata.TotalTokenCount), cacheHitRate)
+		} else {
+			logger.TokenUsageCtx(
+				ctx,
+				cachedTokens,
+				dynamicTokens,
+				completionTokens,
+				promptTokens,
+				uploadDuration,
+				duration,
+				isNewCache,
+			)
+		}
 
 		// Gather context information
 		var contextInfo *ctxinfo.Info
@@ -333,9 +665,13 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 			TotalTokens:      result.UsageMetadata.TotalTokenCount,
 			CacheHitRate:     cacheHitRate, // Store as decimal
 			ResponseTime:     duration.Seconds(),
-			EstimatedCost:    logging.EstimateCostWithCache(model, result.UsageMetadata.PromptTokenCount, result.UsageMetadata.CandidatesTokenCount, result.UsageMetadata.CachedContentTokenCount),
+			UploadTime:       uploadDuration.Seconds(),
+			EstimatedCost:    estimatedCost,
 			CacheID:          cacheID,
-			Success:          true,
+			Success:          emptyErr == nil,
+			CandidateCount:   config.CandidateCount,
+			Continuations:    continuations,
+			IsNewCache:       isNewCache,
 			WorkingDir:       contextInfo.WorkingDir,
 			GitRepo:          contextInfo.GitRepo,
 			GitBranch:        contextInfo.GitBranch,
@@ -347,12 +683,30 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 		} else {
 			logEntry.Caller = ctxinfo.GetCaller()
 		}
+		if opts != nil {
+			logEntry.Labels = opts.Labels
+		}
+		if emptyErr != nil {
+			logEntry.Error = emptyErr.Error()
+		}
+		if isNewCache {
+			logEntry.CacheCreationCost = logging.EstimateCacheCreationCost(model, result.UsageMetadata.CachedContentTokenCount)
+		}
 
 		if err := geminiLogger.Log(logEntry); err != nil {
 			// Don't fail the request if logging fails
 			ulog.Warn("Failed to log query").Err(err).Log(ctx)
 		}
 
+		genResult.CachedTokens = logEntry.CachedTokens
+		genResult.PromptTokens = logEntry.PromptTokens
+		genResult.UserPromptTokens = logEntry.UserPromptTokens
+		genResult.CompletionTokens = logEntry.CompletionTokens
+		genResult.TotalTokens = logEntry.TotalTokens
+		genResult.CacheHitRate = cacheHitRate
+		genResult.EstimatedCost = estimatedCost
+		genResult.CacheCreationCost = logEntry.CacheCreationCost
+
 		// Update cache usage statistics
 		if cacheID != "" && opts != nil && opts.WorkingDir != "" {
 			// Try to update cache usage stats
@@ -364,7 +718,75 @@ func (c *Client) GenerateContentWithCacheAndOptions(ctx context.Context, model s
 		}
 	}
 
-	return result.Text(), nil
+	if emptyErr != nil {
+		return nil, emptyErr
+	}
+	return genResult, nil
+}
+
+// requestRedactor resolves the secret redactor for the request's working directory
+func requestRedactor(ctx context.Context, opts *GenerateContentOptions) *redact.Redactor {
+	workDir := ""
+	if opts != nil {
+		workDir = opts.WorkingDir
+	}
+	r, err := config.ResolveRedactor(workDir)
+	if err != nil {
+		ulog.Warn("Ignoring gemini.redaction patterns").Err(err).Log(ctx)
+	}
+	return r
+}
+
+// continuePrompt is the follow-up turn sent when a response is cut off at the output token limit
+const continuePrompt = "Continue exactly where you left off. Do not repeat any earlier text."
+
+// isTruncated reports whether a single-candidate response stopped at the output token limit
+func isTruncated(result *genai.GenerateContentResponse) bool {
+	return len(result.Candidates) == 1 &&
+		result.Candidates[0].Content != nil &&
+		result.Candidates[0].FinishReason == genai.FinishReasonMaxTokens
+}
+
+// addUsage sums the token counts of two responses so continuation turns are reported together
+func addUsage(a, b *genai.GenerateContentResponseUsageMetadata) *genai.GenerateContentResponseUsageMetadata {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &genai.GenerateContentResponseUsageMetadata{
+		CachedContentTokenCount: a.CachedContentTokenCount + b.CachedContentTokenCount,
+		CandidatesTokenCount:    a.CandidatesTokenCount + b.CandidatesTokenCount,
+		PromptTokenCount:        a.PromptTokenCount + b.PromptTokenCount,
+		ThoughtsTokenCount:      a.ThoughtsTokenCount + b.ThoughtsTokenCount,
+		TotalTokenCount:         a.TotalTokenCount + b.TotalTokenCount,
+	}
+}
+
+// responseText returns the response text. When multiple candidates were
+// generated, each candidate is returned under its own numbered heading.
+func responseText(result *genai.GenerateContentResponse) string {
+	if len(result.Candidates) <= 1 {
+		return result.Text()
+	}
+
+	var b strings.Builder
+	for i, candidate := range result.Candidates {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "## Candidate %d\n\n", i+1)
+		if candidate.Content == nil {
+			continue
+		}
+		for _, part := range candidate.Content.Parts {
+			if part.Text != "" && !part.Thought {
+				b.WriteString(part.Text)
+			}
+		}
+	}
+	return b.String()
 }
 
 // GetClient returns the underlying genai client for cache operations
@@ -396,14 +818,27 @@ type CachedContentInfo struct {
 	TokenCount  int32
 }
 
-// ListCachesFromAPI lists all cached contents from the Google API
+// ListCachesFromAPI lists all cached contents from the Google API.
+// Iteration errors do not abort the listing: every cache that could be read
+// is returned together with an error describing what failed, so callers can
+// still display partial results.
 func (c *Client) ListCachesFromAPI(ctx context.Context) ([]CachedContentInfo, error) {
+	return collectCachedContents(c.client.Caches.All(ctx))
+}
+
+// collectCachedContents drains a cached-content iterator, keeping successfully
+// read entries and accumulating any errors encountered along the way.
+func collectCachedContents(seq iter.Seq2[*genai.CachedContent, error]) ([]CachedContentInfo, error) {
 	var caches []CachedContentInfo //nolint:prealloc // iterator-based, size unknown
+	var errs []error
 
-	// Iterate through all cached contents using the All method
-	for cache, err := range c.client.Caches.All(ctx) {
+	for cache, err := range seq {
 		if err != nil {
-			return nil, fmt.Errorf("failed to list caches from API: %w", err)
+			errs = append(errs, err)
+			continue
+		}
+		if cache == nil {
+			continue
 		}
 
 		tokenCount := int32(0)
@@ -423,9 +858,21 @@ func (c *Client) ListCachesFromAPI(ctx context.Context) ([]CachedContentInfo, er
 		caches = append(caches, info)
 	}
 
+	if len(errs) > 0 {
+		return caches, fmt.Errorf("failed to list caches from API (%d read successfully): %w", len(caches), errors.Join(errs...))
+	}
 	return caches, nil
 }
 
+// ExtendCache refreshes a cache's TTL so it expires ttl from now, returning the new expiry
+func (c *Client) ExtendCache(ctx context.Context, cacheID string, ttl time.Duration) (time.Time, error) {
+	cache, err := c.client.Caches.Update(ctx, cacheID, &genai.UpdateCachedContentConfig{TTL: ttl})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("extending cache: %w", err)
+	}
+	return cache.ExpireTime, nil
+}
+
 // DeleteCache deletes a cache from the Google API
 func (c *Client) DeleteCache(ctx context.Context, cacheID string) error {
 	_, err := c.client.Caches.Delete(ctx, cacheID, nil)
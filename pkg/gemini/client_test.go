@@ -2,7 +2,16 @@ package gemini
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
 	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"google.golang.org/genai"
 )
 
 func TestNewClient(t *testing.T) {
@@ -35,6 +44,63 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientBackends(t *testing.T) {
+	ctx := context.Background()
+	isolated := t.TempDir()
+	t.Chdir(isolated)
+	t.Setenv("HOME", isolated)
+	t.Setenv("XDG_CONFIG_HOME", isolated)
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("GOOGLE_API_KEY", "")
+
+	t.Setenv(config.BackendEnvVar, "")
+	client, err := NewClient(ctx, "")
+	if err != nil {
+		t.Fatalf("NewClient() with the Gemini API backend error = %v", err)
+	}
+	if client.isVertex() {
+		t.Error("expected the Gemini Developer API backend by default")
+	}
+
+	t.Setenv(config.BackendEnvVar, "vertex")
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	t.Setenv("GCP_PROJECT_ID", "")
+	t.Setenv("GOOGLE_CLOUD_LOCATION", "")
+	if _, err := NewClient(ctx, ""); err == nil || !strings.Contains(err.Error(), "needs a project") {
+		t.Fatalf("NewClient() without a Vertex project error = %v, want a missing project error", err)
+	}
+
+	// Vertex AI authenticates with ADC, so no API key is resolved. Supply an
+	// HTTP client in place of credentials to initialize without ADC on disk.
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "test-project")
+	backend, err := config.ResolveBackend("")
+	if err != nil {
+		t.Fatalf("ResolveBackend() error = %v", err)
+	}
+	cc := clientConfig(backend, "")
+	if cc.APIKey != "" {
+		t.Error("the Vertex AI config should not carry an API key")
+	}
+	cc.HTTPClient = http.DefaultClient
+	genaiClient, err := genai.NewClient(ctx, cc)
+	if err != nil {
+		t.Fatalf("genai.NewClient() with the Vertex AI backend error = %v", err)
+	}
+	vertex := &Client{client: genaiClient}
+	if !vertex.isVertex() {
+		t.Error("expected the Vertex AI backend")
+	}
+	got := genaiClient.ClientConfig()
+	if got.Project != "test-project" || got.Location != config.DefaultVertexLocation {
+		t.Errorf("Vertex client project/location = %q/%q, want test-project/%s", got.Project, got.Location, config.DefaultVertexLocation)
+	}
+
+	t.Setenv(config.BackendEnvVar, "bedrock")
+	if _, err := NewClient(ctx, ""); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
+
 func TestClient_GetClient(t *testing.T) {
 	ctx := context.Background()
 	t.Setenv("GEMINI_API_KEY", "test-key")
@@ -111,3 +177,228 @@ func TestGenerateContentWithCache_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestCostOnlyEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		opts     *GenerateContentOptions
+		expected bool
+	}{
+		{name: "default", env: "", opts: nil, expected: false},
+		{name: "option set", env: "", opts: &GenerateContentOptions{ShowCostOnly: true}, expected: true},
+		{name: "env truthy", env: "1", opts: nil, expected: true},
+		{name: "env false", env: "false", opts: &GenerateContentOptions{}, expected: false},
+		{name: "env zero", env: "0", opts: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(costOnlyEnvVar, tt.env)
+			if got := costOnlyEnabled(tt.opts); got != tt.expected {
+				t.Errorf("costOnlyEnabled() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResponseText_MultipleCandidates(t *testing.T) {
+	result := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{
+			{Content: &genai.Content{Parts: []*genai.Part{{Text: "first"}}}},
+			{Content: &genai.Content{Parts: []*genai.Part{{Text: "second"}}}},
+		},
+	}
+
+	text := responseText(result)
+	for _, want := range []string{"## Candidate 1\n\nfirst", "## Candidate 2\n\nsecond"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected response to contain %q, got %q", want, text)
+		}
+	}
+
+	single := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{
+			{Content: &genai.Content{Parts: []*genai.Part{{Text: "only"}}}},
+		},
+	}
+	if got := responseText(single); got != "only" {
+		t.Errorf("expected single candidate text %q, got %q", "only", got)
+	}
+}
+
+func TestCollectCachedContents_PartialFailure(t *testing.T) {
+	transient := errors.New("transient page error")
+
+	var seq iter.Seq2[*genai.CachedContent, error] = func(yield func(*genai.CachedContent, error) bool) {
+		if !yield(&genai.CachedContent{Name: "cachedContents/one"}, nil) {
+			return
+		}
+		if !yield(nil, transient) {
+			return
+		}
+		yield(&genai.CachedContent{
+			Name:          "cachedContents/two",
+			UsageMetadata: &genai.CachedContentUsageMetadata{TotalTokenCount: 5000},
+		}, nil)
+	}
+
+	caches, err := collectCachedContents(seq)
+	if err == nil {
+		t.Fatal("Expected an error for the failed page")
+	}
+	if !errors.Is(err, transient) {
+		t.Errorf("Expected error to wrap the iterator error, got %v", err)
+	}
+	if len(caches) != 2 {
+		t.Fatalf("Expected 2 caches to be collected, got %d", len(caches))
+	}
+	if caches[1].Name != "cachedContents/two" || caches[1].TokenCount != 5000 {
+		t.Errorf("Unexpected second cache: %+v", caches[1])
+	}
+}
+
+func TestCollectCachedContents_NoErrors(t *testing.T) {
+	var seq iter.Seq2[*genai.CachedContent, error] = func(yield func(*genai.CachedContent, error) bool) {
+		yield(&genai.CachedContent{Name: "cachedContents/only"}, nil)
+	}
+
+	caches, err := collectCachedContents(seq)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(caches) != 1 {
+		t.Fatalf("Expected 1 cache, got %d", len(caches))
+	}
+}
+
+func TestIsUnsupportedPenaltyError(t *testing.T) {
+	penalty := float32(0.5)
+	withPenalty := &genai.GenerateContentConfig{PresencePenalty: &penalty}
+	penaltyErr := genai.APIError{Code: 400, Message: "Penalty is not enabled for this model"}
+
+	if !isUnsupportedPenaltyError(penaltyErr, withPenalty) {
+		t.Error("Expected penalty rejection to be detected")
+	}
+	if isUnsupportedPenaltyError(penaltyErr, &genai.GenerateContentConfig{}) {
+		t.Error("Expected no match when no penalties were sent")
+	}
+	if isUnsupportedPenaltyError(genai.APIError{Code: 400, Message: "Invalid model"}, withPenalty) {
+		t.Error("Expected unrelated 400 errors not to match")
+	}
+	if isUnsupportedPenaltyError(genai.APIError{Code: 429, Message: "penalty"}, withPenalty) {
+		t.Error("Expected non-400 errors not to match")
+	}
+}
+
+func TestIsTruncated(t *testing.T) {
+	content := genai.NewContentFromText("partial", genai.RoleModel)
+	truncated := &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{Content: content, FinishReason: genai.FinishReasonMaxTokens}}}
+	complete := &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{Content: content, FinishReason: genai.FinishReasonStop}}}
+
+	if !isTruncated(truncated) {
+		t.Error("Expected MAX_TOKENS response to be truncated")
+	}
+	if isTruncated(complete) {
+		t.Error("Expected STOP response not to be truncated")
+	}
+}
+
+func TestAddUsage(t *testing.T) {
+	a := &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 50, CachedContentTokenCount: 20, TotalTokenCount: 150}
+	b := &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 160, CandidatesTokenCount: 30, CachedContentTokenCount: 20, TotalTokenCount: 190}
+
+	sum := addUsage(a, b)
+	if sum.PromptTokenCount != 260 || sum.CandidatesTokenCount != 80 || sum.CachedContentTokenCount != 40 || sum.TotalTokenCount != 340 {
+		t.Errorf("Unexpected summed usage: %+v", sum)
+	}
+	if addUsage(nil, b) != b || addUsage(a, nil) != a {
+		t.Error("Expected nil usage to be ignored")
+	}
+}
+
+// newGenerateTestClient fakes countTokens and generateContent, storing the
+// body of the last generate request in generateBody
+func newGenerateTestClient(t *testing.T, generateBody *string) *Client {
+	t.Helper()
+	t.Setenv(config.LogDirEnvVar, t.TempDir())
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, ":countTokens") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"totalTokens": 3})
+			return
+		}
+		*generateBody = string(data)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []any{map[string]any{
+				"content":      map[string]any{"role": "model", "parts": []any{map[string]any{"text": "ok"}}},
+				"finishReason": "STOP",
+			}},
+			"usageMetadata": map[string]any{"promptTokenCount": 3, "candidatesTokenCount": 1, "totalTokenCount": 4},
+		})
+	})
+}
+
+func TestGenerateContentSystemInstruction(t *testing.T) {
+	var generateBody string
+	client := newGenerateTestClient(t, &generateBody)
+	ctx := context.Background()
+	opts := &GenerateContentOptions{SystemInstruction: "Answer tersely."}
+
+	if _, err := client.GenerateContentWithResult(ctx, "gemini-2.0-flash", "hello", "", nil, opts); err != nil {
+		t.Fatalf("GenerateContentWithResult: %v", err)
+	}
+	var req struct {
+		SystemInstruction *genai.Content   `json:"systemInstruction"`
+		Contents          []*genai.Content `json:"contents"`
+	}
+	if err := json.Unmarshal([]byte(generateBody), &req); err != nil {
+		t.Fatalf("decoding request: %v", err)
+	}
+	if req.SystemInstruction == nil || req.SystemInstruction.Parts[0].Text != "Answer tersely." {
+		t.Errorf("expected the system instruction in the request config, got %s", generateBody)
+	}
+	if len(req.Contents[0].Parts) != 1 {
+		t.Errorf("expected only the prompt in the user turn, got %s", generateBody)
+	}
+
+	// With a cache the instruction leads the user turn instead
+	if _, err := client.GenerateContentWithResult(ctx, "gemini-2.0-flash", "hello", "cachedContents/abc", nil, opts); err != nil {
+		t.Fatalf("GenerateContentWithResult with cache: %v", err)
+	}
+	req.SystemInstruction, req.Contents = nil, nil
+	if err := json.Unmarshal([]byte(generateBody), &req); err != nil {
+		t.Fatalf("decoding request: %v", err)
+	}
+	if req.SystemInstruction != nil {
+		t.Errorf("system instruction must not be combined with cached content, got %s", generateBody)
+	}
+	if parts := req.Contents[0].Parts; len(parts) != 2 || parts[0].Text != "Answer tersely." || parts[1].Text != "hello" {
+		t.Errorf("expected the instruction before the prompt, got %s", generateBody)
+	}
+}
+
+func TestGenerateContentConversationHistory(t *testing.T) {
+	var generateBody string
+	client := newGenerateTestClient(t, &generateBody)
+
+	session := &Session{}
+	session.AppendExchange("What is cached?", "The cold context.")
+	opts := &GenerateContentOptions{ConversationHistory: session.Turns}
+	if _, err := client.GenerateContentWithResult(context.Background(), "gemini-2.0-flash", "And the hot context?", "", nil, opts); err != nil {
+		t.Fatalf("GenerateContentWithResult: %v", err)
+	}
+
+	var req struct {
+		Contents []*genai.Content `json:"contents"`
+	}
+	if err := json.Unmarshal([]byte(generateBody), &req); err != nil {
+		t.Fatalf("decoding request: %v", err)
+	}
+	if len(req.Contents) != 3 {
+		t.Fatalf("expected two history turns and the new prompt, got %s", generateBody)
+	}
+	if req.Contents[0].Parts[0].Text != "What is cached?" || req.Contents[1].Role != genai.RoleModel || req.Contents[2].Parts[0].Text != "And the hot context?" {
+		t.Errorf("unexpected turn order: %s", generateBody)
+	}
+}
@@ -0,0 +1,105 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// contextManifestFile records the files that went into the last generated context
+const contextManifestFile = "context_manifest.json"
+
+// fileFingerprint identifies a version of a file by size and modification time
+type fileFingerprint struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// contextManifest records the fingerprint of every file in the last generated
+// context so regeneration can be skipped when nothing has changed.
+type contextManifest struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Files       map[string]fileFingerprint `json:"files"`
+}
+
+// buildContextManifest fingerprints the given context files, resolving
+// relative paths against workDir. Files that can't be stat'ed are recorded
+// with a zero fingerprint so they show up as changed once they reappear.
+func buildContextManifest(workDir string, files []string) *contextManifest {
+	manifest := &contextManifest{
+		GeneratedAt: time.Now(),
+		Files:       make(map[string]fileFingerprint, len(files)),
+	}
+	for _, file := range files {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workDir, path)
+		}
+		var fp fileFingerprint
+		if info, err := os.Stat(path); err == nil {
+			fp = fileFingerprint{Size: info.Size(), ModTime: info.ModTime()}
+		}
+		manifest.Files[file] = fp
+	}
+	return manifest
+}
+
+// changedFiles counts the files that were added, modified or removed
+// between the previous manifest and the current one.
+func (m *contextManifest) changedFiles(prev *contextManifest) int {
+	if prev == nil {
+		return len(m.Files)
+	}
+
+	changed := 0
+	for file, fp := range m.Files {
+		old, ok := prev.Files[file]
+		if !ok || old.Size != fp.Size || !old.ModTime.Equal(fp.ModTime) {
+			changed++
+		}
+	}
+	for file := range prev.Files {
+		if _, ok := m.Files[file]; !ok {
+			changed++
+		}
+	}
+	return changed
+}
+
+// upToDate reports whether an implicitly triggered regeneration can be skipped
+// because no file changed since the previous manifest and the hot context
+// still exists. An explicit --regenerate always rebuilds the context.
+func (m *contextManifest) upToDate(prev *contextManifest, explicit, hotExists bool) bool {
+	return !explicit && prev != nil && hotExists && m.changedFiles(prev) == 0
+}
+
+// loadContextManifest reads a context manifest, returning nil if none exists yet
+func loadContextManifest(path string) (*contextManifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is built from the cache directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var manifest contextManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing context manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// save writes the manifest to path, creating the parent directory if needed
+func (m *contextManifest) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating manifest directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644) //nolint:gosec // manifest is not sensitive
+}
@@ -0,0 +1,88 @@
+package gemini
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestContextManifest_ChangedFiles(t *testing.T) {
+	workDir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte("package a\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := buildContextManifest(workDir, []string{"a.go", "b.go"})
+	if got := first.changedFiles(nil); got != 2 {
+		t.Errorf("Expected all files changed without a previous manifest, got %d", got)
+	}
+
+	manifestPath := filepath.Join(workDir, "cache", contextManifestFile)
+	if err := first.save(manifestPath); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	prev, err := loadContextManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	unchanged := buildContextManifest(workDir, []string{"a.go", "b.go"})
+	if got := unchanged.changedFiles(prev); got != 0 {
+		t.Errorf("Expected no changes, got %d", got)
+	}
+
+	// Modify one file, drop another and add a new one
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(workDir, "a.go"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "c.go"), []byte("package c\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	current := buildContextManifest(workDir, []string{"a.go", "c.go"})
+	if got := current.changedFiles(prev); got != 3 {
+		t.Errorf("Expected 3 changes (modified, added, removed), got %d", got)
+	}
+}
+
+func TestLoadContextManifest_Missing(t *testing.T) {
+	manifest, err := loadContextManifest(filepath.Join(t.TempDir(), contextManifestFile))
+	if err != nil {
+		t.Fatalf("Expected no error for missing manifest, got %v", err)
+	}
+	if manifest != nil {
+		t.Error("Expected nil manifest when none exists")
+	}
+}
+
+func TestContextManifest_UpToDate(t *testing.T) {
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "a.go"), []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prev := buildContextManifest(workDir, []string{"a.go"})
+	current := buildContextManifest(workDir, []string{"a.go"})
+
+	tests := []struct {
+		name      string
+		prev      *contextManifest
+		explicit  bool
+		hotExists bool
+		want      bool
+	}{
+		{"unchanged implicit regeneration", prev, false, true, true},
+		{"explicit regeneration", prev, true, true, false},
+		{"no previous manifest", nil, false, true, false},
+		{"hot context missing", prev, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := current.upToDate(tt.prev, tt.explicit, tt.hotExists); got != tt.want {
+				t.Errorf("upToDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
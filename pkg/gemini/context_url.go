@@ -0,0 +1,121 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultContextURLTimeout bounds how long fetching a --context-url may take
+	DefaultContextURLTimeout = 30 * time.Second
+	// MaxContextURLBytes is the largest document accepted from a --context-url
+	MaxContextURLBytes = 10 << 20
+)
+
+// contextURLExtensions maps the non-text content types accepted from a URL to
+// the file extension used for the downloaded copy
+var contextURLExtensions = map[string]string{
+	"application/json":   ".json",
+	"application/xml":    ".xml",
+	"application/yaml":   ".yaml",
+	"application/x-yaml": ".yaml",
+}
+
+// FetchContextURL downloads a text document over HTTP(S) to a temporary file so
+// it can be attached as context. It rejects non-text content types and bodies
+// larger than maxBytes, and gives up after timeout. The caller is responsible
+// for removing the file.
+func FetchContextURL(ctx context.Context, rawURL string, timeout time.Duration, maxBytes int64) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing context URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("context URL must use http or https: %s", rawURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("building request for %s: %w", rawURL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %s: %s", rawURL, resp.Status)
+	}
+
+	ext, err := contextURLExtension(u, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return "", fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	if resp.ContentLength > maxBytes {
+		return "", fmt.Errorf("fetching %s: document is %d bytes (limit %d)", rawURL, resp.ContentLength, maxBytes)
+	}
+
+	f, err := os.CreateTemp("", "grove-gemini-url-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("creating context file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// Read one byte past the limit so oversized bodies without a Content-Length are caught
+	n, err := io.Copy(f, io.LimitReader(resp.Body, maxBytes+1))
+	if err == nil && n > maxBytes {
+		err = fmt.Errorf("document exceeds %d bytes", maxBytes)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("downloading %s: %w", rawURL, err)
+	}
+
+	return f.Name(), nil
+}
+
+// contextURLExtension validates the response content type and picks the file
+// extension for the downloaded copy. The URL's own extension is kept when it
+// names a known text format, since servers often send source as text/plain.
+func contextURLExtension(u *url.URL, contentType string) (string, error) {
+	mediaType := "text/plain"
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return "", fmt.Errorf("invalid content type %q", contentType)
+		}
+		mediaType = parsed
+	}
+
+	ext, known := contextURLExtensions[mediaType]
+	if !known && !strings.HasPrefix(mediaType, "text/") {
+		return "", fmt.Errorf("unsupported content type %s (expected a text document)", mediaType)
+	}
+
+	if urlExt := strings.ToLower(path.Ext(u.Path)); urlExt != "" && (urlExt == ".txt" || detectMIMEType(urlExt) != "text/plain") {
+		return urlExt, nil
+	}
+	if known {
+		return ext, nil
+	}
+	switch mediaType {
+	case "text/markdown", "text/x-markdown":
+		return ".md", nil
+	case "text/html":
+		return ".html", nil
+	default:
+		return ".txt", nil
+	}
+}
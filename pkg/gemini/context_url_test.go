@@ -0,0 +1,85 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchContextURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/spec.md":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte("# Spec\n"))
+		case "/data":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+		case "/large":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(strings.Repeat("x", 64)))
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte("late"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("keeps url extension", func(t *testing.T) {
+		path, err := FetchContextURL(ctx, server.URL+"/spec.md", time.Second, 1024)
+		if err != nil {
+			t.Fatalf("FetchContextURL: %v", err)
+		}
+		defer func() { _ = os.Remove(path) }()
+		if filepath.Ext(path) != ".md" {
+			t.Errorf("Expected .md file, got %s", path)
+		}
+		content, _ := os.ReadFile(path)
+		if string(content) != "# Spec\n" {
+			t.Errorf("Unexpected content %q", content)
+		}
+	})
+
+	t.Run("extension from content type", func(t *testing.T) {
+		path, err := FetchContextURL(ctx, server.URL+"/data", time.Second, 1024)
+		if err != nil {
+			t.Fatalf("FetchContextURL: %v", err)
+		}
+		defer func() { _ = os.Remove(path) }()
+		if filepath.Ext(path) != ".json" {
+			t.Errorf("Expected .json file, got %s", path)
+		}
+	})
+
+	errorCases := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"binary content", server.URL + "/image.png", "unsupported content type"},
+		{"too large", server.URL + "/large", "limit 16"},
+		{"timeout", server.URL + "/slow", "deadline exceeded"},
+		{"not found", server.URL + "/missing", "404"},
+		{"bad scheme", "file:///etc/passwd", "http or https"},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := FetchContextURL(ctx, tc.url, 50*time.Millisecond, 16)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package gemini
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// getGitDiff returns the output of `git diff` (or `git diff --staged`) for the given working directory
+func getGitDiff(workingDir string, staged bool) (string, error) {
+	args := []string{"diff"}
+	if staged {
+		args = append(args, "--staged")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("running git %s: %w", strings.Join(args, " "), err)
+	}
+
+	return string(output), nil
+}
+
+// writeGitDiffFile writes the current git diff to a temporary markdown file with a
+// labeled header so it can be attached as dynamic context. It returns an empty path
+// when there are no changes. The caller is responsible for removing the file.
+func writeGitDiffFile(workingDir string, staged bool) (string, error) {
+	diff, err := getGitDiff(workingDir, staged)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return "", nil
+	}
+
+	label := "Unstaged changes (git diff)"
+	if staged {
+		label = "Staged changes (git diff --staged)"
+	}
+	if repoName := getRepoName(workingDir); repoName != "" {
+		label += " in " + repoName
+	}
+
+	f, err := os.CreateTemp("", "grove-gemini-diff-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating diff file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := fmt.Fprintf(f, "# %s\n\n```diff\n%s```\n", label, diff); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("writing diff file: %w", err)
+	}
+
+	return f.Name(), nil
+}
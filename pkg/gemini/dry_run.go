@@ -0,0 +1,160 @@
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+)
+
+// Cache decisions reported by a dry run
+const (
+	CachePlanDisabled = "disabled"  // caching is off for this request
+	CachePlanNone     = "none"      // caching is on but there is no cold context to cache
+	CachePlanReuse    = "reuse"     // an existing cache would be used
+	CachePlanCreate   = "create"    // a new cache would be created
+	CachePlanTooSmall = "too-small" // the cold context is below the minimum cache size
+)
+
+// PlanCache decides what GetOrCreateCache would do for the cold context files
+// without uploading anything or creating a cache. Only the local cache record
+// is consulted, so a cache deleted on the server is still reported as reused.
+// For CachePlanCreate the returned CacheInfo carries the new cache key and the
+// estimated token count.
+func (m *CacheManager) PlanCache(model string, coldContextFilePaths []string, ignoreChanges, disableExpiration, forceRecache bool) (string, *CacheInfo, error) {
+	var coldContextFiles []string
+	for _, path := range coldContextFilePaths {
+		if _, err := os.Stat(path); err == nil {
+			coldContextFiles = append(coldContextFiles, path)
+		}
+	}
+	if len(coldContextFiles) == 0 {
+		return CachePlanNone, nil, nil
+	}
+	sort.Strings(coldContextFiles)
+
+	cacheKey, err := generateCacheKey(coldContextFiles)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate cache key: %w", err)
+	}
+
+	if !forceRecache {
+		if info, err := LoadCacheInfo(filepath.Join(m.cacheDir, "hybrid_"+cacheKey+".json")); err == nil && info.ClearedAt == nil {
+			expired := !disableExpiration && time.Now().After(info.ExpiresAt)
+			changed, _ := hasFilesChanged(info.CachedFileHashes, coldContextFiles)
+			if !expired && (!changed || ignoreChanges) && cacheModelMatches(info.Model, model) {
+				return CachePlanReuse, info, nil
+			}
+		}
+	}
+
+	fileHashes := make(map[string]string)
+	var estimatedTokens int
+	for _, path := range coldContextFiles {
+		content, err := os.ReadFile(path) //nolint:gosec // path from trusted config
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		hashArray := sha256.Sum256(content)
+		fileHashes[path] = hex.EncodeToString(hashArray[:])
+		estimatedTokens += estimateTokens(content)
+	}
+	if estimatedTokens < m.minCacheTokens {
+		return CachePlanTooSmall, nil, nil
+	}
+
+	return CachePlanCreate, &CacheInfo{
+		CacheName:        cacheKey,
+		CachedFileHashes: fileHashes,
+		TokenCount:       estimatedTokens,
+	}, nil
+}
+
+// planNamedCache loads a cache record named by --use-cache for a dry run,
+// checking expiry locally instead of verifying it on the server
+func (m *CacheManager) planNamedCache(cacheName string, disableExpiration bool) (*CacheInfo, error) {
+	cacheName, err := ResolveCacheName(m.cacheDir, cacheName)
+	if err != nil {
+		return nil, err
+	}
+	info, err := LoadCacheInfo(filepath.Join(m.cacheDir, "hybrid_"+cacheName+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: '%s'", ErrCacheNotFound, cacheName)
+		}
+		return nil, fmt.Errorf("loading cache info: %w", err)
+	}
+	if !disableExpiration && time.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("%w: '%s' expired at %s", ErrCacheExpired, cacheName, info.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST"))
+	}
+	return info, nil
+}
+
+// reportDryRun prints the plan for a request that was not sent and returns a
+// result holding the estimated prompt tokens and input cost. Output tokens
+// aren't known ahead of time, so the cost covers the input only.
+func (r *RequestRunner) reportDryRun(ctx context.Context, model, prompt, cacheDecision string, cacheInfo *CacheInfo, dynamicFiles []string, minCacheTokens int) (*GenerateResult, error) {
+	promptTokens := estimateTokens([]byte(prompt))
+	dynamicTokens := 0
+	// Images and PDFs aren't tokenized like text, so they're left out of the estimate
+	attachments := nonTextMIMETypes(dynamicFiles)
+	for _, path := range dynamicFiles {
+		if _, ok := attachments[path]; ok {
+			continue
+		}
+		content, err := os.ReadFile(path) //nolint:gosec // path was resolved for the request
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		dynamicTokens += estimateTokens(content)
+	}
+
+	var cachedTokens int
+	if cacheInfo != nil {
+		cachedTokens = cacheInfo.TokenCount
+	}
+	totalTokens := int32(cachedTokens + dynamicTokens + promptTokens) //nolint:gosec // token estimates fit in int32
+	result := &GenerateResult{
+		Model:            model,
+		CachedTokens:     int32(cachedTokens), //nolint:gosec // token estimates fit in int32
+		PromptTokens:     totalTokens,
+		UserPromptTokens: int32(promptTokens), //nolint:gosec // token estimates fit in int32
+		TotalTokens:      totalTokens,
+		EstimatedCost:    logging.EstimateCostWithCache(model, totalTokens, 0, int32(cachedTokens)), //nolint:gosec // token estimates fit in int32
+	}
+
+	cacheLine := cacheDecision
+	switch cacheDecision {
+	case CachePlanReuse:
+		cacheLine = fmt.Sprintf("reuse %s (%s tokens)", cacheInfo.CacheName, pretty.FormatTokens(cachedTokens))
+	case CachePlanCreate:
+		result.CacheCreationCost = logging.EstimateCacheCreationCost(model, int32(cachedTokens)) //nolint:gosec // token estimates fit in int32
+		cacheLine = fmt.Sprintf("create %s (%s tokens, ~%s one-time)", cacheInfo.CacheName, pretty.FormatTokens(cachedTokens), pretty.FormatCost(result.CacheCreationCost))
+	case CachePlanTooSmall:
+		cacheLine = fmt.Sprintf("skipped, cold context is below %s tokens", pretty.FormatTokens(minCacheTokens))
+	}
+
+	r.logger.Blank()
+	r.logger.Section("Dry Run")
+	r.logger.Field("Model", model)
+	r.logger.Field("Cache", cacheLine)
+	r.logger.FilesIncludedCtx(ctx, dynamicFiles, attachments)
+	r.logger.Field("Estimated cached tokens", pretty.FormatTokens(cachedTokens))
+	r.logger.Field("Estimated dynamic tokens", pretty.FormatTokens(dynamicTokens))
+	if len(attachments) > 0 {
+		r.logger.Field("Not estimated", fmt.Sprintf("%d image/PDF attachments (counted from API usage when sent)", len(attachments)))
+	}
+	r.logger.Field("Estimated prompt tokens", pretty.FormatTokens(promptTokens))
+	r.logger.Field("Estimated input cost", pretty.FormatCost(result.EstimatedCost))
+	r.logger.Blank()
+	r.logger.InfoCtx(ctx, "Dry run: no request was sent to the Gemini API")
+
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package gemini
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/config"
+)
+
+func TestPlanCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	cm := NewCacheManager(tmpDir)
+
+	coldFile := filepath.Join(tmpDir, "cached-context")
+	if err := os.WriteFile(coldFile, []byte(strings.Repeat("x", 4*config.DefaultMinCacheTokens)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	decision, info, err := cm.PlanCache("gemini-2.5-flash", []string{filepath.Join(tmpDir, "missing")}, false, false, false)
+	if err != nil || decision != CachePlanNone || info != nil {
+		t.Fatalf("missing cold context: got %q, %v, %v", decision, info, err)
+	}
+
+	decision, info, err = cm.PlanCache("gemini-2.5-flash", []string{coldFile}, false, false, false)
+	if err != nil {
+		t.Fatalf("PlanCache: %v", err)
+	}
+	if decision != CachePlanCreate || info == nil || info.TokenCount != config.DefaultMinCacheTokens {
+		t.Fatalf("expected create with %d tokens, got %q, %+v", config.DefaultMinCacheTokens, decision, info)
+	}
+	if entries, _ := os.ReadDir(cm.cacheDir); len(entries) != 0 {
+		t.Errorf("planning should not write cache records, found %d", len(entries))
+	}
+
+	// Record a valid cache for the same contents; it should be reused
+	record := *info
+	record.CacheID = "cachedContents/abc"
+	record.Model = "gemini-2.5-flash"
+	record.ExpiresAt = time.Now().Add(time.Hour)
+	data, _ := json.Marshal(record)
+	if err := os.MkdirAll(cm.cacheDir, 0o755); err != nil { //nolint:gosec // test dir
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cm.cacheDir, "hybrid_"+record.CacheName+".json"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	decision, info, err = cm.PlanCache("gemini-2.5-flash", []string{coldFile}, false, false, false)
+	if err != nil || decision != CachePlanReuse || info.CacheID != record.CacheID {
+		t.Fatalf("expected reuse of %s, got %q, %+v, %v", record.CacheID, decision, info, err)
+	}
+
+	if decision, _, _ = cm.PlanCache("gemini-2.5-flash", []string{coldFile}, false, false, true); decision != CachePlanCreate {
+		t.Errorf("forced recache: got %q, want %q", decision, CachePlanCreate)
+	}
+
+	// The same contents cached for another model can't be reused
+	if decision, _, _ = cm.PlanCache("gemini-2.5-pro", []string{coldFile}, false, false, false); decision != CachePlanCreate {
+		t.Errorf("different model: got %q, want %q", decision, CachePlanCreate)
+	}
+	if decision, _, _ = cm.PlanCache("models/gemini-2.5-flash", []string{coldFile}, false, false, false); decision != CachePlanReuse {
+		t.Errorf("prefixed model name: got %q, want %q", decision, CachePlanReuse)
+	}
+
+	small := filepath.Join(tmpDir, "small")
+	if err := os.WriteFile(small, []byte("tiny"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if decision, _, _ = cm.PlanCache("gemini-2.5-flash", []string{small}, false, false, false); decision != CachePlanTooSmall {
+		t.Errorf("small cold context: got %q, want %q", decision, CachePlanTooSmall)
+	}
+}
+
+func TestPlanCacheMinCacheTokens(t *testing.T) {
+	tmpDir := t.TempDir()
+	cm := NewCacheManager(tmpDir)
+
+	coldFile := filepath.Join(tmpDir, "cached-context")
+	if err := os.WriteFile(coldFile, []byte(strings.Repeat("x", 4*1024)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if decision, _, _ := cm.PlanCache("gemini-2.5-flash", []string{coldFile}, false, false, false); decision != CachePlanTooSmall {
+		t.Fatalf("default minimum: got %q, want %q", decision, CachePlanTooSmall)
+	}
+
+	cm.SetMinCacheTokens(1024)
+	if decision, _, _ := cm.PlanCache("gemini-2.5-flash", []string{coldFile}, false, false, false); decision != CachePlanCreate {
+		t.Errorf("lowered minimum: got %q, want %q", decision, CachePlanCreate)
+	}
+
+	cm.SetMinCacheTokens(0)
+	if cm.MinCacheTokens() != 1024 {
+		t.Errorf("non-positive minimum should be ignored, got %d", cm.MinCacheTokens())
+	}
+}
@@ -0,0 +1,130 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// dumpIndexFile is the manifest written alongside the dumped parts
+const dumpIndexFile = "index.json"
+
+// DumpedPart describes one part of an assembled request written by --dump-contents
+type DumpedPart struct {
+	Index    int    `json:"index"`
+	Kind     string `json:"kind"`             // "system_instruction", "cached_context", "history", "file" or "prompt"
+	File     string `json:"file"`             // Name of the dumped file inside the dump directory
+	Source   string `json:"source,omitempty"` // Original path for file parts, cache ID for the cached context marker
+	Role     string `json:"role,omitempty"`   // Speaker of an earlier conversation turn
+	MIMEType string `json:"mime_type,omitempty"`
+	URI      string `json:"uri,omitempty"`
+	Bytes    int    `json:"bytes"`
+}
+
+// DumpIndex is the manifest describing a dumped request
+type DumpIndex struct {
+	Model     string       `json:"model"`
+	CacheID   string       `json:"cache_id,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	Parts     []DumpedPart `json:"parts"`
+}
+
+// dumpRequestContents writes each part of an assembled request to dir as a
+// separate numbered file, in the order the model receives them, along with an
+// index.json manifest. contents is the request exactly as sent: earlier
+// conversation turns followed by the new user turn, whose file parts come from
+// files in order, whether uploaded or sent inline. The cached context lives
+// server-side, so it is represented by a marker file naming the cache. A system
+// instruction is dumped first since it frames everything after it.
+func dumpRequestContents(dir, model, cacheID, systemInstruction string, contents []*genai.Content, files []string) (*DumpIndex, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // dump dir needs to be traversable
+		return nil, fmt.Errorf("creating dump directory: %w", err)
+	}
+
+	index := &DumpIndex{Model: model, CacheID: cacheID, CreatedAt: time.Now()}
+
+	writePart := func(part DumpedPart, content []byte) error {
+		part.Index = len(index.Parts)
+		part.File = fmt.Sprintf("%02d-%s", part.Index, part.File)
+		part.Bytes = len(content)
+		if err := os.WriteFile(filepath.Join(dir, part.File), content, 0o644); err != nil { //nolint:gosec // dump files are for local inspection
+			return fmt.Errorf("writing %s: %w", part.File, err)
+		}
+		index.Parts = append(index.Parts, part)
+		return nil
+	}
+
+	if systemInstruction != "" {
+		if err := writePart(DumpedPart{Kind: "system_instruction", File: "system-instruction.txt"}, []byte(systemInstruction)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cacheID != "" {
+		marker := fmt.Sprintf("Cached content: %s\nThe cached context is stored server-side and prepended to the request by Gemini.\n", cacheID)
+		if err := writePart(DumpedPart{Kind: "cached_context", File: "cached-context.txt", Source: cacheID}, []byte(marker)); err != nil {
+			return nil, err
+		}
+	}
+
+	for turn, content := range contents {
+		current := turn == len(contents)-1
+		nextFile := 0
+		for i, p := range content.Parts {
+			var part DumpedPart
+			var data []byte
+			switch {
+			case p.Text != "":
+				// With a cache the system instruction is sent at the start of the user turn; it was dumped above
+				if current && i == 0 && cacheID != "" && p.Text == systemInstruction {
+					continue
+				}
+				part, data = DumpedPart{Kind: "prompt", File: "prompt.txt"}, []byte(p.Text)
+			case p.InlineData != nil:
+				part = DumpedPart{Kind: "file", File: "inline", MIMEType: p.InlineData.MIMEType}
+				data = p.InlineData.Data
+			case p.FileData != nil:
+				part = DumpedPart{Kind: "file", File: "file", MIMEType: p.FileData.MIMEType, URI: p.FileData.FileURI}
+				data = []byte(fmt.Sprintf("Uploaded file: %s\n", p.FileData.FileURI))
+			default:
+				continue
+			}
+
+			if !current {
+				part.Kind, part.Role = "history", content.Role
+				if p.Text != "" {
+					part.File = fmt.Sprintf("turn%d-%s.txt", turn, content.Role)
+				} else {
+					part.File = fmt.Sprintf("turn%d-%s-%s", turn, content.Role, part.File)
+				}
+			} else if part.Kind == "file" && nextFile < len(files) {
+				// Dump the local file so the part can be read, not just its URI
+				part.Source = files[nextFile]
+				part.File = filepath.Base(files[nextFile])
+				nextFile++
+				if p.FileData != nil {
+					var err error
+					if data, err = os.ReadFile(part.Source); err != nil { //nolint:gosec // path was just sent as part of the request
+						return nil, fmt.Errorf("reading %s: %w", part.Source, err)
+					}
+				}
+			}
+			if err := writePart(part, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, dumpIndexFile), data, 0o644); err != nil { //nolint:gosec // dump files are for local inspection
+		return nil, fmt.Errorf("writing dump index: %w", err)
+	}
+	return index, nil
+}
@@ -0,0 +1,123 @@
+package gemini
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestDumpRequestContents(t *testing.T) {
+	srcDir := t.TempDir()
+	hotContext := filepath.Join(srcDir, "context")
+	if err := os.WriteFile(hotContext, []byte("<context>hot</context>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dumpDir := filepath.Join(t.TempDir(), "dump")
+	contents := []*genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{
+		genai.NewPartFromURI("files/abc", "text/plain"),
+		{Text: "Explain the cache."},
+	}}}
+
+	index, err := dumpRequestContents(dumpDir, "gemini-2.5-pro", "cachedContents/xyz", "", contents, []string{hotContext})
+	if err != nil {
+		t.Fatalf("dumpRequestContents failed: %v", err)
+	}
+
+	wantKinds := []string{"cached_context", "file", "prompt"}
+	if len(index.Parts) != len(wantKinds) {
+		t.Fatalf("Expected %d parts, got %d", len(wantKinds), len(index.Parts))
+	}
+	for i, kind := range wantKinds {
+		if index.Parts[i].Kind != kind {
+			t.Errorf("Part %d: expected kind %s, got %s", i, kind, index.Parts[i].Kind)
+		}
+	}
+	if index.Parts[1].File != "01-context" || index.Parts[1].URI != "files/abc" {
+		t.Errorf("Unexpected file part: %+v", index.Parts[1])
+	}
+	if data, err := os.ReadFile(filepath.Join(dumpDir, index.Parts[1].File)); err != nil || string(data) != "<context>hot</context>" {
+		t.Errorf("Expected the uploaded file's content, got %q (%v)", data, err)
+	}
+
+	prompt, err := os.ReadFile(filepath.Join(dumpDir, index.Parts[2].File))
+	if err != nil || string(prompt) != "Explain the cache." {
+		t.Errorf("Unexpected prompt dump: %q (%v)", prompt, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dumpDir, dumpIndexFile))
+	if err != nil {
+		t.Fatalf("Expected index.json: %v", err)
+	}
+	var onDisk DumpIndex
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatalf("Invalid index.json: %v", err)
+	}
+	if onDisk.CacheID != "cachedContents/xyz" || len(onDisk.Parts) != 3 {
+		t.Errorf("Unexpected index contents: %+v", onDisk)
+	}
+}
+
+func TestDumpRequestContentsSystemInstruction(t *testing.T) {
+	dumpDir := filepath.Join(t.TempDir(), "dump")
+
+	contents := []*genai.Content{genai.NewContentFromText("Explain the cache.", genai.RoleUser)}
+	index, err := dumpRequestContents(dumpDir, "gemini-2.5-pro", "", "Answer tersely.", contents, nil)
+	if err != nil {
+		t.Fatalf("dumpRequestContents failed: %v", err)
+	}
+	if len(index.Parts) != 2 || index.Parts[0].Kind != "system_instruction" || index.Parts[1].Kind != "prompt" {
+		t.Fatalf("Expected system instruction then prompt, got %+v", index.Parts)
+	}
+	data, err := os.ReadFile(filepath.Join(dumpDir, index.Parts[0].File))
+	if err != nil || string(data) != "Answer tersely." {
+		t.Errorf("Unexpected system instruction dump: %q (%v)", data, err)
+	}
+}
+
+func TestDumpRequestContentsInlineFilesAndHistory(t *testing.T) {
+	srcDir := t.TempDir()
+	hotContext := filepath.Join(srcDir, "context")
+	dumpDir := filepath.Join(t.TempDir(), "dump")
+
+	// Vertex AI sends files inline, so nothing is uploaded
+	contents := []*genai.Content{
+		genai.NewContentFromText("What is cached?", genai.RoleUser),
+		genai.NewContentFromText("The cold context.", genai.RoleModel),
+		{Role: genai.RoleUser, Parts: []*genai.Part{
+			genai.NewPartFromBytes([]byte("<context>hot</context>"), "text/plain"),
+			{Text: "And what is not?"},
+		}},
+	}
+	index, err := dumpRequestContents(dumpDir, "gemini-2.5-pro", "", "", contents, []string{hotContext})
+	if err != nil {
+		t.Fatalf("dumpRequestContents failed: %v", err)
+	}
+
+	want := []struct{ kind, role, file string }{
+		{"history", "user", "00-turn0-user.txt"},
+		{"history", "model", "01-turn1-model.txt"},
+		{"file", "", "02-context"},
+		{"prompt", "", "03-prompt.txt"},
+	}
+	if len(index.Parts) != len(want) {
+		t.Fatalf("Expected %d parts, got %+v", len(want), index.Parts)
+	}
+	for i, w := range want {
+		p := index.Parts[i]
+		if p.Kind != w.kind || p.Role != w.role || p.File != w.file {
+			t.Errorf("Part %d = %+v, want kind %s role %q file %s", i, p, w.kind, w.role, w.file)
+		}
+	}
+	if index.Parts[2].Source != hotContext {
+		t.Errorf("Inline file source = %q, want %s", index.Parts[2].Source, hotContext)
+	}
+	for file, wantContent := range map[string]string{"01-turn1-model.txt": "The cold context.", "02-context": "<context>hot</context>"} {
+		if data, err := os.ReadFile(filepath.Join(dumpDir, file)); err != nil || string(data) != wantContent {
+			t.Errorf("%s = %q (%v), want %q", file, data, err, wantContent)
+		}
+	}
+}
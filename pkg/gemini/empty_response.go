@@ -0,0 +1,45 @@
+package gemini
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// EmptyResponseError is returned when the model answers with no text, for
+// example because the candidate was stopped by a safety filter or the prompt
+// itself was blocked. FinishReason and BlockReason explain why, when known.
+type EmptyResponseError struct {
+	Model        string
+	FinishReason genai.FinishReason
+	BlockReason  genai.BlockedReason
+}
+
+func (e *EmptyResponseError) Error() string {
+	switch {
+	case e.BlockReason != "":
+		return fmt.Sprintf("model %s returned an empty response: prompt was blocked (%s)", e.Model, e.BlockReason)
+	case e.FinishReason != "":
+		return fmt.Sprintf("model %s returned an empty response (finish reason: %s)", e.Model, e.FinishReason)
+	default:
+		return fmt.Sprintf("model %s returned an empty response with no finish reason", e.Model)
+	}
+}
+
+// checkEmptyResponse returns an *EmptyResponseError when a single-candidate
+// response has no text beyond whitespace
+func checkEmptyResponse(model string, result *genai.GenerateContentResponse, text string) error {
+	if len(result.Candidates) > 1 || strings.TrimSpace(text) != "" {
+		return nil
+	}
+
+	emptyErr := &EmptyResponseError{Model: model}
+	if len(result.Candidates) == 1 {
+		emptyErr.FinishReason = result.Candidates[0].FinishReason
+	}
+	if result.PromptFeedback != nil {
+		emptyErr.BlockReason = result.PromptFeedback.BlockReason
+	}
+	return emptyErr
+}
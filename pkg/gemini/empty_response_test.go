@@ -0,0 +1,78 @@
+package gemini
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestCheckEmptyResponse(t *testing.T) {
+	candidate := func(text string, reason genai.FinishReason) *genai.Candidate {
+		return &genai.Candidate{Content: genai.NewContentFromText(text, genai.RoleModel), FinishReason: reason}
+	}
+
+	tests := []struct {
+		name    string
+		result  *genai.GenerateContentResponse
+		text    string
+		wantErr string
+	}{
+		{
+			name:   "text response",
+			result: &genai.GenerateContentResponse{Candidates: []*genai.Candidate{candidate("hello", genai.FinishReasonStop)}},
+			text:   "hello",
+		},
+		{
+			name:    "safety stop",
+			result:  &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonSafety}}},
+			wantErr: "finish reason: SAFETY",
+		},
+		{
+			name:    "whitespace only",
+			result:  &genai.GenerateContentResponse{Candidates: []*genai.Candidate{candidate(" \n\t", genai.FinishReasonStop)}},
+			text:    " \n\t",
+			wantErr: "finish reason: STOP",
+		},
+		{
+			name: "blocked prompt",
+			result: &genai.GenerateContentResponse{PromptFeedback: &genai.GenerateContentResponsePromptFeedback{
+				BlockReason: genai.BlockedReasonSafety,
+			}},
+			wantErr: "prompt was blocked (SAFETY)",
+		},
+		{
+			name:    "no candidates",
+			result:  &genai.GenerateContentResponse{},
+			wantErr: "no finish reason",
+		},
+		{
+			name: "multiple candidates",
+			result: &genai.GenerateContentResponse{Candidates: []*genai.Candidate{
+				{FinishReason: genai.FinishReasonSafety},
+				candidate("hi", genai.FinishReasonStop),
+			}},
+			text: "## Candidate 1\n\n\n\n## Candidate 2\n\nhi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkEmptyResponse("gemini-2.0-flash", tt.result, tt.text)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			var emptyErr *EmptyResponseError
+			if !errors.As(err, &emptyErr) {
+				t.Fatalf("Expected *EmptyResponseError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// Sentinel errors returned (wrapped) by the cache and request flow. Use
+// errors.Is to branch on them rather than matching error text.
+var (
+	// ErrCacheNotFound means a named cache has no local record or no longer exists on the server
+	ErrCacheNotFound = errors.New("cache not found")
+	// ErrCacheExpired means a named cache is past its expiration time
+	ErrCacheExpired = errors.New("cache expired")
+	// ErrCacheModelMismatch means a named cache was created for a different model than the request's
+	ErrCacheModelMismatch = errors.New("cache was created for a different model")
+	// ErrContextTooLarge means the context exceeds what a request or cache can hold
+	ErrContextTooLarge = errors.New("context size exceeds limit")
+	// ErrRequestTimeout means the request's deadline (--timeout) passed before the response arrived
+	ErrRequestTimeout = errors.New("request timed out")
+)
+
+// APIKeyError is returned when no Gemini API key could be resolved or the API
+// rejected the key. It wraps the underlying error.
+type APIKeyError struct {
+	Invalid bool // True when the API rejected the key, false when none was configured
+	Err     error
+}
+
+func (e *APIKeyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIKeyError) Unwrap() error {
+	return e.Err
+}
+
+// IsAPIKeyError checks if an error is a missing or rejected API key
+func IsAPIKeyError(err error) bool {
+	var keyErr *APIKeyError
+	return errors.As(err, &keyErr) || isInvalidAPIKey(err)
+}
+
+// isInvalidAPIKey reports whether err is the API rejecting the key (400 API_KEY_INVALID)
+func isInvalidAPIKey(err error) bool {
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if strings.Contains(apiErr.Message, "API key not valid") {
+		return true
+	}
+	return strings.Contains(fmt.Sprint(apiErr.Details), "API_KEY_INVALID")
+}
+
+// classifyAPIError wraps API errors that have a structured equivalent so
+// callers can branch on them with errors.As
+func classifyAPIError(err error) error {
+	if err != nil && isInvalidAPIKey(err) {
+		return &APIKeyError{Invalid: true, Err: err}
+	}
+	return err
+}
+
+// classifyTimeout wraps err with ErrRequestTimeout when it was caused by ctx's
+// deadline passing, so a stalled call is reported as a timeout rather than as
+// whatever the transport returned on cancellation
+func classifyTimeout(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, ErrRequestTimeout) || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrRequestTimeout, err)
+}
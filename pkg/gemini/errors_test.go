@@ -0,0 +1,73 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+func TestClassifyAPIError(t *testing.T) {
+	invalid := genai.APIError{Code: 400, Status: "INVALID_ARGUMENT", Message: "API key not valid. Please pass a valid API key."}
+	err := classifyAPIError(invalid)
+	var keyErr *APIKeyError
+	if !errors.As(err, &keyErr) || !keyErr.Invalid {
+		t.Fatalf("expected invalid APIKeyError, got %#v", err)
+	}
+	if !IsAPIKeyError(fmt.Errorf("request failed: %w", invalid)) {
+		t.Error("IsAPIKeyError should recognize a raw invalid key API error")
+	}
+
+	byReason := genai.APIError{Code: 400, Details: []map[string]any{{"reason": "API_KEY_INVALID"}}}
+	if !IsAPIKeyError(byReason) {
+		t.Error("IsAPIKeyError should recognize the API_KEY_INVALID reason")
+	}
+
+	other := genai.APIError{Code: 400, Message: "Invalid JSON payload"}
+	if got := classifyAPIError(other); IsAPIKeyError(got) {
+		t.Errorf("unrelated 400 classified as API key error: %v", got)
+	}
+}
+
+func TestCacheTooLargeErrorIsContextTooLarge(t *testing.T) {
+	err := fmt.Errorf("managing cache: %w", &CacheTooLargeError{Model: "gemini-2.5-pro", Tokens: 2, Limit: 1})
+	if !errors.Is(err, ErrContextTooLarge) {
+		t.Error("CacheTooLargeError should match ErrContextTooLarge")
+	}
+}
+
+func TestClassifyTimeout(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"deadline passed", expired, context.DeadlineExceeded, true},
+		{"stream cut off by deadline", expired, &StreamInterruptedError{Bytes: 10, Err: context.DeadlineExceeded}, true},
+		{"canceled", canceled, context.Canceled, false},
+		{"live context", context.Background(), errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyTimeout(tt.ctx, tt.err)
+			if errors.Is(got, ErrRequestTimeout) != tt.want {
+				t.Errorf("classifyTimeout() = %v, timeout = %v, want %v", got, errors.Is(got, ErrRequestTimeout), tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("classifyTimeout() lost the original error: %v", got)
+			}
+		})
+	}
+	if classifyTimeout(expired, nil) != nil {
+		t.Error("classifyTimeout(nil) should stay nil")
+	}
+}
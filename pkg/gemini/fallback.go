@@ -0,0 +1,47 @@
+package gemini
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// IsUnavailableError reports whether err means the model is temporarily
+// unavailable (HTTP 503 / UNAVAILABLE, or an "overloaded" message), in which
+// case another model may still be able to answer. Auth and quota failures are
+// not availability errors, and neither is a stream that failed after part of
+// the response was already written.
+func IsUnavailableError(err error) bool {
+	var streamErr *StreamInterruptedError
+	if err == nil || IsQuotaError(err) || errors.As(err, &streamErr) {
+		return false
+	}
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == 401 || apiErr.Code == 403 {
+			return false
+		}
+		if apiErr.Code == 503 || apiErr.Status == "UNAVAILABLE" {
+			return true
+		}
+		return strings.Contains(strings.ToLower(apiErr.Message), "overloaded")
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "overloaded")
+}
+
+// cachedSourceFiles returns the local files a cache was built from that still
+// exist, so they can be sent inline to a model that can't use the cache.
+func cachedSourceFiles(info *CacheInfo) []string {
+	if info == nil {
+		return nil
+	}
+	var files []string
+	for _, path := range info.CachedFiles() {
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
@@ -0,0 +1,59 @@
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestIsUnavailableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "503 code", err: genai.APIError{Code: 503}, expected: true},
+		{name: "unavailable status", err: genai.APIError{Status: "UNAVAILABLE"}, expected: true},
+		{name: "overloaded message", err: genai.APIError{Code: 500, Message: "The model is overloaded. Please try again later."}, expected: true},
+		{name: "wrapped", err: fmt.Errorf("failed to generate content: %w", genai.APIError{Code: 503}), expected: true},
+		{name: "quota", err: genai.APIError{Code: 429, Message: "overloaded"}, expected: false},
+		{name: "quota error type", err: &QuotaError{Err: genai.APIError{Code: 429}}, expected: false},
+		{name: "auth", err: genai.APIError{Code: 403, Message: "overloaded"}, expected: false},
+		{name: "bad request", err: genai.APIError{Code: 400}, expected: false},
+		{name: "interrupted stream", err: &StreamInterruptedError{Bytes: 10, Err: genai.APIError{Code: 503}}, expected: false},
+		{name: "plain error", err: errors.New("boom"), expected: false},
+		{name: "nil", err: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnavailableError(tt.err); got != tt.expected {
+				t.Errorf("IsUnavailableError() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCachedSourceFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "cold.md")
+	if err := os.WriteFile(existing, []byte("context"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	info := &CacheInfo{CachedFileHashes: map[string]string{
+		existing:                         "abc",
+		filepath.Join(tmpDir, "gone.md"): "def",
+	}}
+	files := cachedSourceFiles(info)
+	if len(files) != 1 || files[0] != existing {
+		t.Errorf("cachedSourceFiles() = %v, want [%s]", files, existing)
+	}
+	if files := cachedSourceFiles(nil); files != nil {
+		t.Errorf("cachedSourceFiles(nil) = %v, want nil", files)
+	}
+}
@@ -0,0 +1,137 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/genai"
+)
+
+// QueryRecorder receives a query log entry for each Generate call.
+// *logging.QueryLogger satisfies it.
+type QueryRecorder interface {
+	Log(entry logging.QueryLog) error
+}
+
+// GenerateOptions configures a Client.Generate call. The zero value sends
+// just the prompt with the model's default parameters and records nothing.
+type GenerateOptions struct {
+	// Contents are sent as text parts ahead of the prompt, in order
+	Contents []string
+	// Generation parameters (only sent when set)
+	Temperature     *float32
+	TopP            *float32
+	TopK            *int32
+	MaxOutputTokens *int32
+	// MaxRetries is how many times transient API errors (429/500/503) are retried with backoff
+	MaxRetries int
+	// Recorder, when set, receives a query log entry for the call, e.g. logging.GetLogger()
+	Recorder QueryRecorder
+	// Caller and Labels are recorded in the query log entry
+	Caller string
+	Labels map[string]string
+}
+
+// Generate sends prompt, preceded by any inline opts.Contents, to model and
+// returns the response text and its token usage. Unlike the request flow it
+// reads no files and needs no .grove directory, and it prints nothing, so it
+// suits programs that embed grove-gemini and already hold their content in
+// memory. The query log is only written when opts.Recorder is set.
+func (c *Client) Generate(ctx context.Context, model string, prompt string, opts *GenerateOptions) (string, *TokenUsage, error) {
+	if opts == nil {
+		opts = &GenerateOptions{}
+	}
+
+	ctx, span := tracer.Start(ctx, "gemini.Generate")
+	defer span.End()
+	span.SetAttributes(attribute.String("gemini.model", model))
+
+	contents, config := generateRequest(prompt, opts)
+	startTime := time.Now()
+	retry := retryPolicy{maxRetries: opts.MaxRetries, baseDelay: DefaultRetryBaseDelay, maxWait: DefaultMaxRetryWait}
+	result, err := retryableGenerate(ctx, model, retry, func() (*genai.GenerateContentResponse, error) {
+		return c.client.Models.GenerateContent(ctx, model, contents, config)
+	})
+
+	entry := logging.QueryLog{
+		Timestamp: startTime,
+		RequestID: os.Getenv("GROVE_REQUEST_ID"),
+		Model:     model,
+		Method:    "Generate",
+		Caller:    opts.Caller,
+		Labels:    opts.Labels,
+	}
+	record := func() {
+		if opts.Recorder == nil {
+			return
+		}
+		entry.ResponseTime = time.Since(startTime).Seconds()
+		if logErr := opts.Recorder.Log(entry); logErr != nil {
+			// Don't fail the request if logging fails
+			ulog.Warn("Failed to log query").Err(logErr).Log(ctx)
+		}
+	}
+
+	if err != nil {
+		recordSpanError(span, err)
+		entry.Error = err.Error()
+		record()
+		if IsQuotaError(err) {
+			return "", nil, newQuotaError(model, err, time.Now())
+		}
+		return "", nil, fmt.Errorf("failed to generate content: %w", classifyAPIError(err))
+	}
+
+	text := responseText(result)
+	emptyErr := checkEmptyResponse(model, result, text)
+
+	var usage *TokenUsage
+	if result.UsageMetadata != nil {
+		if usage, err = ReportTokenUsage(ctx, result, model); err != nil {
+			return "", nil, err
+		}
+		entry.CachedTokens = result.UsageMetadata.CachedContentTokenCount
+		entry.PromptTokens = result.UsageMetadata.PromptTokenCount
+		entry.CompletionTokens = result.UsageMetadata.CandidatesTokenCount
+		entry.TotalTokens = result.UsageMetadata.TotalTokenCount
+		entry.CacheHitRate = usage.CacheHitRate
+		entry.EstimatedCost = usage.EstimatedCost
+	}
+	entry.Success = emptyErr == nil
+	if emptyErr != nil {
+		recordSpanError(span, emptyErr)
+		entry.Error = emptyErr.Error()
+	}
+	record()
+
+	if emptyErr != nil {
+		return "", usage, emptyErr
+	}
+	return text, usage, nil
+}
+
+// generateRequest assembles the contents and config for a Generate call
+func generateRequest(prompt string, opts *GenerateOptions) ([]*genai.Content, *genai.GenerateContentConfig) {
+	parts := make([]*genai.Part, 0, len(opts.Contents)+1)
+	for _, content := range opts.Contents {
+		parts = append(parts, genai.NewPartFromText(content))
+	}
+	parts = append(parts, genai.NewPartFromText(prompt))
+
+	config := &genai.GenerateContentConfig{
+		Temperature: opts.Temperature,
+		TopP:        opts.TopP,
+	}
+	if opts.TopK != nil {
+		topK := float32(*opts.TopK)
+		config.TopK = &topK
+	}
+	if opts.MaxOutputTokens != nil {
+		config.MaxOutputTokens = *opts.MaxOutputTokens
+	}
+	return []*genai.Content{genai.NewContentFromParts(parts, genai.RoleUser)}, config
+}
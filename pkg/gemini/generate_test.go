@@ -0,0 +1,108 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"google.golang.org/genai"
+)
+
+type recordedLogs []logging.QueryLog
+
+func (r *recordedLogs) Log(entry logging.QueryLog) error {
+	*r = append(*r, entry)
+	return nil
+}
+
+// newTestClient returns a Client whose requests go to handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "test-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatalf("creating genai client: %v", err)
+	}
+	return &Client{client: client}
+}
+
+func TestClientGenerate(t *testing.T) {
+	var body string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []any{map[string]any{
+				"content":      map[string]any{"role": "model", "parts": []any{map[string]any{"text": "It is about caching."}}},
+				"finishReason": "STOP",
+			}},
+			"usageMetadata": map[string]any{"promptTokenCount": 20, "candidatesTokenCount": 5, "totalTokenCount": 25},
+		})
+	})
+
+	var logs recordedLogs
+	temperature := float32(0.2)
+	text, usage, err := client.Generate(context.Background(), "gemini-2.0-flash", "What is this about?", &GenerateOptions{
+		Contents:    []string{"First document", "Second document"},
+		Temperature: &temperature,
+		Recorder:    &logs,
+		Caller:      "embedder",
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if text != "It is about caching." {
+		t.Errorf("text = %q", text)
+	}
+	if usage == nil || usage.PromptTokens != 20 || usage.CompletionTokens != 5 {
+		t.Errorf("usage = %+v, want 20 prompt and 5 completion tokens", usage)
+	}
+
+	for _, want := range []string{"First document", "Second document", "What is this about?", `"temperature":0.2`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("request body missing %q: %s", want, body)
+		}
+	}
+	if strings.Index(body, "Second document") > strings.Index(body, "What is this about?") {
+		t.Error("expected inline contents to precede the prompt")
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 query log entry, got %d", len(logs))
+	}
+	if !logs[0].Success || logs[0].Caller != "embedder" || logs[0].TotalTokens != 25 {
+		t.Errorf("unexpected log entry: %+v", logs[0])
+	}
+}
+
+func TestClientGenerate_Error(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "bad prompt", "status": "INVALID_ARGUMENT"}}`))
+	})
+
+	var logs recordedLogs
+	_, _, err := client.Generate(context.Background(), "gemini-2.0-flash", "hi", &GenerateOptions{Recorder: &logs})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(logs) != 1 || logs[0].Success || logs[0].Error == "" {
+		t.Errorf("expected a failed query log entry, got %+v", logs)
+	}
+
+	// Without a recorder nothing is logged and the call still fails cleanly
+	if _, _, err := client.Generate(context.Background(), "gemini-2.0-flash", "hi", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,169 @@
+package gemini
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheLockPollInterval is how often a waiting process retries the lock.
+const cacheLockPollInterval = 250 * time.Millisecond
+
+// cacheLockStaleAfter is how long a lock file can go without its holder
+// refreshing it before it is considered abandoned by a crashed process and
+// broken. Holders refresh it well within this, so a lock held across a slow
+// upload or a confirmation prompt stays valid. It is a variable so tests can
+// shorten it.
+var cacheLockStaleAfter = 10 * time.Minute
+
+// acquireCacheLock takes an exclusive lock for a cache key so that concurrent
+// processes don't create duplicate caches for the same content. It blocks until
+// the lock is acquired or ctx is cancelled, and returns a function that releases it.
+// The lock file holds the owner's PID and a random nonce. While held, its
+// modification time is refreshed so it never looks stale, and releasing it
+// only removes the file if it still holds this owner's nonce.
+func acquireCacheLock(ctx context.Context, cacheDir, cacheKey string) (func(), error) {
+	lockPath := filepath.Join(cacheDir, "hybrid_"+cacheKey+".lock")
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating cache lock nonce: %w", err)
+	}
+	owner := fmt.Sprintf("%d %s", os.Getpid(), hex.EncodeToString(nonce))
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec // lockPath is internal path
+		if err == nil {
+			_, writeErr := f.WriteString(owner)
+			if closeErr := f.Close(); writeErr == nil {
+				writeErr = closeErr
+			}
+			if writeErr != nil {
+				_ = os.Remove(lockPath)
+				return nil, fmt.Errorf("writing cache lock: %w", writeErr)
+			}
+			return holdCacheLock(lockPath, owner), nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("creating cache lock: %w", err)
+		}
+
+		// Break locks abandoned by crashed processes
+		if stat, statErr := os.Stat(lockPath); statErr == nil && time.Since(stat.ModTime()) > cacheLockStaleAfter {
+			if data, readErr := os.ReadFile(lockPath); readErr == nil { //nolint:gosec // lockPath is internal path
+				breakStaleCacheLock(lockPath, string(data), owner)
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for cache lock: %w", ctx.Err())
+		case <-time.After(cacheLockPollInterval):
+		}
+	}
+}
+
+// holdCacheLock refreshes the lock file's modification time until the
+// returned release function is called, which then removes the file if it
+// still belongs to owner
+func holdCacheLock(lockPath, owner string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	interval := cacheLockStaleAfter / 4
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !ownsCacheLock(lockPath, owner) {
+					return // broken by another process; nothing left to keep alive
+				}
+				now := time.Now()
+				_ = os.Chtimes(lockPath, now, now)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+			if ownsCacheLock(lockPath, owner) {
+				_ = os.Remove(lockPath)
+			}
+		})
+	}
+}
+
+// ownsCacheLock reports whether the lock file at lockPath was written by owner
+func ownsCacheLock(lockPath, owner string) bool {
+	data, err := os.ReadFile(lockPath) //nolint:gosec // lockPath is internal path
+	return err == nil && string(data) == owner
+}
+
+// breakStaleCacheLock removes a stale lock whose contents were stale. The file
+// is first renamed aside, so when several processes break the same lock only
+// one succeeds; if the file moved turns out to be a fresh lock another
+// process took in the meantime, it is put back.
+func breakStaleCacheLock(lockPath, stale, owner string) {
+	aside := lockPath + ".stale-" + strings.ReplaceAll(owner, " ", "-")
+	if err := os.Rename(lockPath, aside); err != nil {
+		return
+	}
+	if data, err := os.ReadFile(aside); err == nil && string(data) != stale { //nolint:gosec // aside is internal path
+		_ = os.Link(aside, lockPath)
+	}
+	_ = os.Remove(aside)
+}
+
+// ErrCacheLockTimeout is returned when a cache info file stays locked by
+// another process for longer than cacheInfoLockTimeout.
+var ErrCacheLockTimeout = errors.New("timed out waiting for cache info lock")
+
+// cacheInfoLockTimeout bounds how long a read-modify-write of a cache info file
+// waits for another process. It is a variable so tests can shorten it.
+var cacheInfoLockTimeout = 5 * time.Second
+
+// updateCacheInfo applies update to the cache info in infoFile while holding a
+// lock on it, so concurrent processes don't lose each other's changes. A
+// missing file starts from an empty CacheInfo, and nothing is written when
+// update returns false. The lock is separate from the
+// creation lock, which is held during uploads, so updates never wait on one.
+// If the lock can't be taken within cacheInfoLockTimeout, nothing is written
+// and ErrCacheLockTimeout is returned.
+func updateCacheInfo(ctx context.Context, infoFile string, update func(info *CacheInfo) bool) error {
+	lockCtx, cancel := context.WithTimeout(ctx, cacheInfoLockTimeout)
+	defer cancel()
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(infoFile), "hybrid_"), ".json") + ".info"
+	unlock, err := acquireCacheLock(lockCtx, filepath.Dir(infoFile), name)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %s", ErrCacheLockTimeout, infoFile)
+		}
+		return err
+	}
+	defer unlock()
+
+	info := &CacheInfo{}
+	if _, statErr := os.Stat(infoFile); statErr == nil {
+		if info, err = LoadCacheInfo(infoFile); err != nil {
+			return err
+		}
+	}
+	if !update(info) {
+		return nil
+	}
+	return SaveCacheInfo(infoFile, info)
+}
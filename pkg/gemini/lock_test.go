@@ -0,0 +1,243 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAcquireCacheLock_SingleCreation(t *testing.T) {
+	cacheDir := t.TempDir()
+	cacheKey := "abc123"
+	infoFile := filepath.Join(cacheDir, "hybrid_"+cacheKey+".json")
+
+	var creates int32
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			unlock, err := acquireCacheLock(context.Background(), cacheDir, cacheKey)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer unlock()
+
+			// Mirror GetOrCreateCache: reuse the cache if another process created it
+			if _, err := os.Stat(infoFile); err == nil {
+				return
+			}
+
+			// Equivalent of Caches.Create
+			atomic.AddInt32(&creates, 1)
+			time.Sleep(50 * time.Millisecond)
+			if err := os.WriteFile(infoFile, []byte("{}"), 0o600); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&creates); got != 1 {
+		t.Errorf("Expected exactly 1 cache creation, got %d", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(cacheDir, "hybrid_"+cacheKey+".lock")); !os.IsNotExist(err) {
+		t.Error("Expected lock file to be removed after release")
+	}
+}
+
+func TestAcquireCacheLock_ContextCancelled(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	unlock, err := acquireCacheLock(context.Background(), cacheDir, "held")
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := acquireCacheLock(ctx, cacheDir, "held"); err == nil {
+		t.Fatal("Expected error when context is cancelled while waiting for lock")
+	}
+}
+
+func TestAcquireCacheLock_StaleLock(t *testing.T) {
+	cacheDir := t.TempDir()
+	lockPath := filepath.Join(cacheDir, "hybrid_stale.lock")
+
+	if err := os.WriteFile(lockPath, []byte("12345"), 0o600); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	old := time.Now().Add(-2 * cacheLockStaleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	unlock, err := acquireCacheLock(ctx, cacheDir, "stale")
+	if err != nil {
+		t.Fatalf("Expected stale lock to be broken, got %v", err)
+	}
+	unlock()
+}
+
+func TestAcquireCacheLock_HeldPastStaleAfter(t *testing.T) {
+	defer func(d time.Duration) { cacheLockStaleAfter = d }(cacheLockStaleAfter)
+	cacheLockStaleAfter = 200 * time.Millisecond
+	cacheDir := t.TempDir()
+
+	unlock, err := acquireCacheLock(context.Background(), cacheDir, "slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second acquirer waiting several stale periods must not break a lock
+	// whose holder is still alive, as during a long upload
+	acquired := make(chan func(), 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if second, err := acquireCacheLock(ctx, cacheDir, "slow"); err == nil {
+			acquired <- second
+		} else {
+			t.Errorf("second acquirer: %v", err)
+			close(acquired)
+		}
+	}()
+	select {
+	case second := <-acquired:
+		second()
+		t.Fatal("second acquirer broke a lock that was still held")
+	case <-time.After(5 * cacheLockStaleAfter):
+	}
+
+	unlock()
+	select {
+	case second, ok := <-acquired:
+		if ok {
+			second()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second acquirer did not get the lock after it was released")
+	}
+}
+
+func TestAcquireCacheLock_ReleaseKeepsNewOwnersLock(t *testing.T) {
+	cacheDir := t.TempDir()
+	lockPath := filepath.Join(cacheDir, "hybrid_crashed.lock")
+
+	first, err := acquireCacheLock(context.Background(), cacheDir, "crashed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first holder stops refreshing (say it was suspended), so its lock goes stale
+	old := time.Now().Add(-2 * cacheLockStaleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	second, err := acquireCacheLock(ctx, cacheDir, "crashed")
+	if err != nil {
+		t.Fatalf("expected the stale lock to be broken, got %v", err)
+	}
+
+	// When the first holder wakes up and releases, the second keeps its lock
+	first()
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("first holder removed the second holder's lock: %v", err)
+	}
+	if _, err := acquireCacheLock(ctx, cacheDir, "crashed"); err == nil {
+		t.Fatal("a third acquirer got the lock while the second still held it")
+	}
+
+	second()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("expected the lock to be removed by its owner, got %v", err)
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+		t.Errorf("expected no leftover files, got %d", len(entries))
+	}
+}
+
+func TestUpdateCacheUsageStats_Concurrent(t *testing.T) {
+	cm := NewCacheManager(t.TempDir())
+	if err := os.MkdirAll(cm.cacheDir, 0o755); err != nil { //nolint:gosec // test dir
+		t.Fatal(err)
+	}
+	infoFile := filepath.Join(cm.cacheDir, "hybrid_abc.json")
+	if err := SaveCacheInfo(infoFile, &CacheInfo{CacheID: "cachedContents/abc", CacheName: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const updates = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, updates)
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cm.UpdateCacheUsageStats("cachedContents/abc", 100, 10, 5, 0.9); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	info, err := LoadCacheInfo(infoFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.UsageStats == nil || info.UsageStats.TotalQueries != updates || info.UsageStats.TotalCacheHits != updates*100 {
+		t.Errorf("usage stats = %+v, want %d queries with no lost increments", info.UsageStats, updates)
+	}
+}
+
+func TestUpdateCacheInfo_LockTimeout(t *testing.T) {
+	defer func(d time.Duration) { cacheInfoLockTimeout = d }(cacheInfoLockTimeout)
+	cacheInfoLockTimeout = 100 * time.Millisecond
+
+	dir := t.TempDir()
+	infoFile := filepath.Join(dir, "hybrid_abc.json")
+	unlock, err := acquireCacheLock(context.Background(), dir, "abc.info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	start := time.Now()
+	err = updateCacheInfo(context.Background(), infoFile, func(info *CacheInfo) bool { return true })
+	if !errors.Is(err, ErrCacheLockTimeout) {
+		t.Fatalf("expected ErrCacheLockTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("gave up after %s, want about %s", elapsed, cacheInfoLockTimeout)
+	}
+	if _, err := os.Stat(infoFile); !os.IsNotExist(err) {
+		t.Error("nothing should be written when the lock times out")
+	}
+}
@@ -0,0 +1,15 @@
+package gemini
+
+import (
+	"context"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+)
+
+// loadPricingOverrides installs pricing.yml and gemini.pricing_overrides for
+// cost estimates. It runs once per process; invalid entries are skipped with a warning.
+func loadPricingOverrides(ctx context.Context, workDir string) {
+	if err := logging.LoadPricingOverrides(workDir); err != nil {
+		ulog.Warn("Ignoring invalid pricing overrides").Err(err).Log(ctx)
+	}
+}
@@ -0,0 +1,103 @@
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// QuotaError is returned when a request fails because a quota is exhausted
+// (HTTP 429 / RESOURCE_EXHAUSTED). It carries a human-readable summary and
+// wraps the original API error.
+type QuotaError struct {
+	Model      string
+	Daily      bool          // True when a per-day quota was exhausted (retrying won't help)
+	RetryAfter time.Duration // Retry delay suggested by the API, if any
+	ResetAt    time.Time     // Best estimate of when the quota resets, if known
+	Err        error
+}
+
+func (e *QuotaError) Error() string {
+	model := e.Model
+	if model == "" {
+		model = "this model"
+	}
+
+	if e.Daily {
+		reset := "tomorrow"
+		if !e.ResetAt.IsZero() {
+			reset = "at " + e.ResetAt.Local().Format("2006-01-02 15:04 MST")
+		}
+		return fmt.Sprintf("Daily quota exhausted for model %s; resets %s or upgrade your plan.", model, reset)
+	}
+
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("Quota exceeded for model %s; retry in %s or upgrade your plan.", model, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("Quota exceeded for model %s; try again later or upgrade your plan.", model)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return e.Err
+}
+
+// IsQuotaError checks if an error is a Google API quota exhaustion (429 / RESOURCE_EXHAUSTED) error
+func IsQuotaError(err error) bool {
+	var quotaErr *QuotaError
+	if errors.As(err, &quotaErr) {
+		return true
+	}
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 429 || apiErr.Status == "RESOURCE_EXHAUSTED"
+	}
+	return false
+}
+
+// newQuotaError builds a QuotaError from a quota exhaustion API error,
+// extracting the retry delay and quota period from the error details.
+func newQuotaError(model string, err error, now time.Time) *QuotaError {
+	qe := &QuotaError{Model: model, Err: err}
+
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return qe
+	}
+
+	for _, detail := range apiErr.Details {
+		detailType, _ := detail["@type"].(string)
+		switch {
+		case strings.HasSuffix(detailType, "google.rpc.RetryInfo"):
+			if delay, ok := detail["retryDelay"].(string); ok {
+				if d, err := time.ParseDuration(delay); err == nil {
+					qe.RetryAfter = d
+				}
+			}
+		case strings.HasSuffix(detailType, "google.rpc.QuotaFailure"):
+			violations, _ := detail["violations"].([]any)
+			for _, v := range violations {
+				violation, _ := v.(map[string]any)
+				quotaID, _ := violation["quotaId"].(string)
+				if strings.Contains(quotaID, "PerDay") {
+					qe.Daily = true
+				}
+			}
+		}
+	}
+
+	switch {
+	case qe.RetryAfter > 0:
+		qe.ResetAt = now.Add(qe.RetryAfter)
+	case qe.Daily:
+		// Daily quotas reset at midnight Pacific time
+		if loc, err := time.LoadLocation("America/Los_Angeles"); err == nil {
+			pt := now.In(loc)
+			qe.ResetAt = time.Date(pt.Year(), pt.Month(), pt.Day()+1, 0, 0, 0, 0, loc)
+		}
+	}
+
+	return qe
+}
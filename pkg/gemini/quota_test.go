@@ -0,0 +1,84 @@
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+func TestIsQuotaError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "429 code", err: genai.APIError{Code: 429}, expected: true},
+		{name: "resource exhausted status", err: genai.APIError{Status: "RESOURCE_EXHAUSTED"}, expected: true},
+		{name: "wrapped", err: fmt.Errorf("wrapped: %w", genai.APIError{Code: 429}), expected: true},
+		{name: "not found", err: genai.APIError{Code: 404}, expected: false},
+		{name: "plain error", err: errors.New("boom"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsQuotaError(tt.err); got != tt.expected {
+				t.Errorf("IsQuotaError() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewQuotaError_Daily(t *testing.T) {
+	apiErr := genai.APIError{
+		Code:   429,
+		Status: "RESOURCE_EXHAUSTED",
+		Details: []map[string]any{
+			{
+				"@type": "type.googleapis.com/google.rpc.QuotaFailure",
+				"violations": []any{
+					map[string]any{"quotaId": "GenerateRequestsPerDayPerProjectPerModel-FreeTier"},
+				},
+			},
+		},
+	}
+
+	qe := newQuotaError("gemini-2.5-pro", apiErr, time.Now())
+	if !qe.Daily {
+		t.Error("Expected daily quota to be detected")
+	}
+	if !strings.Contains(qe.Error(), "Daily quota exhausted for model gemini-2.5-pro") {
+		t.Errorf("Unexpected message: %s", qe.Error())
+	}
+	var unwrapped genai.APIError
+	if !errors.As(qe, &unwrapped) || unwrapped.Code != 429 {
+		t.Error("Expected QuotaError to wrap the API error")
+	}
+}
+
+func TestNewQuotaError_RetryDelay(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	apiErr := genai.APIError{
+		Code: 429,
+		Details: []map[string]any{
+			{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "34s"},
+		},
+	}
+
+	qe := newQuotaError("gemini-2.0-flash", apiErr, now)
+	if qe.Daily {
+		t.Error("Expected non-daily quota")
+	}
+	if qe.RetryAfter != 34*time.Second {
+		t.Errorf("Expected retry after 34s, got %s", qe.RetryAfter)
+	}
+	if !qe.ResetAt.Equal(now.Add(34 * time.Second)) {
+		t.Errorf("Unexpected reset time: %s", qe.ResetAt)
+	}
+	if !strings.Contains(qe.Error(), "retry in 34s") {
+		t.Errorf("Unexpected message: %s", qe.Error())
+	}
+}
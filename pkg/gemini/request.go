@@ -3,21 +3,30 @@ package gemini
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/grovetools/core/tui/theme"
 	grovecontext "github.com/grovetools/cx/pkg/context"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	ctxinfo "github.com/grovetools/grove-gemini/pkg/context"
+	"github.com/grovetools/grove-gemini/pkg/logging"
 	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"google.golang.org/genai"
 )
 
 // RequestOptions contains all the parameters for a request
 type RequestOptions struct {
 	Model            string
 	Prompt           string
+	PromptPrefix     string   // Text placed before the prompt (e.g., "Answer concisely.")
+	PromptSuffix     string   // Text placed after the prompt (e.g., "Cite files.")
 	PromptFiles      []string // Paths to files containing prompts (for display purposes)
 	WorkDir          string
 	CacheTTL         time.Duration
@@ -26,6 +35,10 @@ type RequestOptions struct {
 	Recache          bool
 	UseCache         string
 	ContextFiles     []string
+	Attachments      []string // Images and PDFs sent alongside the prompt
+	ContextURLs      []string // Documents fetched over HTTP(S) and attached as dynamic context
+	IncludeDiff      bool     // Attach the working tree `git diff` as dynamic context
+	DiffStaged       bool     // Use `git diff --staged` instead of the working tree diff
 	SkipConfirmation bool
 	APIKey           string // Explicitly pass API key to avoid context issues
 	// New fields for better logging context
@@ -37,6 +50,65 @@ type RequestOptions struct {
 	TopP            *float32
 	TopK            *int32
 	MaxOutputTokens *int32
+	CandidateCount  int32
+	StopSequences   []string
+	// Timeout bounds the whole request, including context uploads and streaming;
+	// zero waits indefinitely
+	Timeout time.Duration
+	// ContextURLTimeout bounds each ContextURLs fetch (defaults to DefaultContextURLTimeout)
+	ContextURLTimeout time.Duration
+	// FallbackModels are tried in order when the model is unavailable (e.g. 503 overloaded)
+	FallbackModels []string
+	// AutoContinue is the maximum number of follow-up turns when a response hits the output token limit
+	AutoContinue int
+	// Repetition penalties (only sent when set)
+	PresencePenalty  *float32
+	FrequencyPenalty *float32
+	// Labels attached to the request for usage attribution
+	Labels map[string]string
+	// Output options
+	ShowCostOnly bool
+	// ResponseMIMEType sets the response format; with JSONMIMEType a response that isn't valid JSON is an error
+	ResponseMIMEType string
+	// ResponseJSONSchema constrains the response to JSON matching this JSON Schema document
+	ResponseJSONSchema []byte
+	// ValidateResponse checks the response against ResponseJSONSchema, retrying once on mismatch
+	ValidateResponse bool
+	// DumpContentsDir, when set, receives the exact assembled request parts for debugging
+	DumpContentsDir string
+	// StreamWriter, when set, receives the response text as it is generated
+	StreamWriter io.Writer
+	// MaxRetries is how many times transient API errors (429/500/503) are retried with backoff
+	MaxRetries int
+	// DryRun resolves context and the cache decision and prints the plan without calling the API
+	DryRun bool
+	// CountCacheTokens sizes the cold context with the CountTokens API instead of the byte heuristic
+	CountCacheTokens bool
+	// MinCacheTokens overrides gemini.min_cache_tokens when positive
+	MinCacheTokens int
+	// MinCacheTTL is how long a reused cache must have left before it is warned
+	// about or extended (0 uses DefaultMinCacheTTL)
+	MinCacheTTL time.Duration
+	// AutoExtendCache refreshes the TTL of a reused cache that expires within MinCacheTTL
+	AutoExtendCache bool
+	// UploadConcurrency is how many attached files are uploaded at once (0 uses the default)
+	UploadConcurrency int
+	// SystemInstruction is sent separately from the prompt to steer the model
+	SystemInstruction string
+	// PromptTemplate is the path of the template the prompt was rendered from, for debug logging
+	PromptTemplate string
+	// SessionFile persists the conversation; earlier turns are sent with the prompt
+	// and the new exchange is appended after a successful response
+	SessionFile string
+	// ConversationHistory holds earlier turns kept by the caller, sent after any session turns
+	ConversationHistory []*genai.Content
+	// NoUploadCache bypasses the upload reuse index and always uploads attached files
+	NoUploadCache bool
+	// ResponseCache reuses a saved response for an identical request instead of
+	// calling the API; ResponseCacheTTL is how long saved responses stay valid
+	// (0 uses DefaultResponseCacheTTL)
+	ResponseCache    bool
+	ResponseCacheTTL time.Duration
 }
 
 // RequestRunner handles the orchestration of Gemini API requests with context management
@@ -54,14 +126,49 @@ func NewRequestRunner() *RequestRunner {
 
 // Run executes a request with the given options
 func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string, error) {
+	result, err := r.RunWithResult(ctx, options)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// RunWithResult executes a request with the given options and returns the
+// response together with its token usage, cost and latency
+func (r *RequestRunner) RunWithResult(ctx context.Context, options RequestOptions) (*GenerateResult, error) {
 	// Validate options
 	if options.Prompt == "" {
-		return "", fmt.Errorf("prompt cannot be empty")
+		return nil, fmt.Errorf("prompt cannot be empty")
+	}
+
+	if len(options.StopSequences) > MaxStopSequences {
+		return nil, fmt.Errorf("too many stop sequences: %d (maximum %d)", len(options.StopSequences), MaxStopSequences)
+	}
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	// Bracket the prompt with any prefix/suffix so they are sent, counted and logged as part of it
+	options.Prompt = WrapPrompt(options.PromptPrefix, options.Prompt, options.PromptSuffix)
+
+	// Load the conversation so far; the new exchange is saved once the request succeeds
+	var session *Session
+	if options.SessionFile != "" {
+		if options.CandidateCount > 1 {
+			return nil, fmt.Errorf("a session can't record multiple candidates")
+		}
+		var err error
+		if session, err = LoadSession(options.SessionFile); err != nil {
+			return nil, err
+		}
 	}
 
 	// Validate cache flags
 	if options.UseCache != "" && options.Recache {
-		return "", fmt.Errorf("UseCache and Recache are mutually exclusive")
+		return nil, fmt.Errorf("UseCache and Recache are mutually exclusive")
 	}
 
 	// Determine working directory
@@ -70,14 +177,14 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 		var err error
 		workDir, err = os.Getwd()
 		if err != nil {
-			return "", fmt.Errorf("getting current directory: %w", err)
+			return nil, fmt.Errorf("getting current directory: %w", err)
 		}
 	}
 
 	// Make workDir absolute
 	absWorkDir, err := filepath.Abs(workDir)
 	if err != nil {
-		return "", fmt.Errorf("resolving work directory: %w", err)
+		return nil, fmt.Errorf("resolving work directory: %w", err)
 	}
 	workDir = absWorkDir
 
@@ -126,7 +233,7 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 				r.logger.RulesFileContent(strings.TrimSpace(string(rulesContent)))
 			}
 		} else if !os.IsNotExist(err) {
-			return "", fmt.Errorf("checking rules file: %w", err)
+			return nil, fmt.Errorf("checking rules file: %w", err)
 		}
 	}
 
@@ -151,16 +258,35 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 
 			// Update context from rules
 			if err := ctxMgr.UpdateFromRules(); err != nil {
-				return "", fmt.Errorf("updating context from rules: %w", err)
+				return nil, fmt.Errorf("updating context from rules: %w", err)
 			}
 
-			// Generate context file
-			if err := ctxMgr.GenerateContext(true); err != nil {
-				return "", fmt.Errorf("generating context: %w", err)
+			// Compare the resolved files against the last generation so an
+			// unchanged file set doesn't pay for rebuilding the context
+			files, _ := ctxMgr.ReadFilesList(grovecontext.FilesListFile)
+			manifestPath := filepath.Join(ResolveGeminiCacheDir(workDir), contextManifestFile)
+			manifest := buildContextManifest(workDir, files)
+			prevManifest, err := loadContextManifest(manifestPath)
+			if err != nil {
+				r.logger.Warning(fmt.Sprintf("Ignoring unreadable context manifest: %v", err))
+			}
+			changed := manifest.changedFiles(prevManifest)
+
+			_, hotErr := os.Stat(hotContextFile)
+			if manifest.upToDate(prevManifest, options.RegenerateCtx, hotErr == nil) {
+				r.logger.Info(fmt.Sprintf("Context up to date (0 of %d files changed), skipping regeneration", len(files)))
+			} else {
+				// Generate context file
+				if err := ctxMgr.GenerateContext(true); err != nil {
+					return nil, fmt.Errorf("generating context: %w", err)
+				}
+				if err := manifest.save(manifestPath); err != nil {
+					r.logger.Warning(fmt.Sprintf("Failed to save context manifest: %v", err))
+				}
+				r.logger.Info(fmt.Sprintf("Regenerated context: %d of %d files changed", changed, len(files)))
 			}
 
 			// Display stats
-			files, _ := ctxMgr.ReadFilesList(grovecontext.FilesListFile)
 			stats, err := ctxMgr.GetStats("request", files, 10)
 			if err == nil {
 				r.logger.Blank()
@@ -170,7 +296,7 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 				r.logger.Field("Total Size", grovecontext.FormatBytes(int(stats.TotalSize)))
 
 				if stats.TotalTokens > 500000 {
-					return "", fmt.Errorf("context size exceeds limit: %d tokens (max 500,000)", stats.TotalTokens)
+					return nil, fmt.Errorf("%w: %d tokens (max 500,000)", ErrContextTooLarge, stats.TotalTokens)
 				}
 			}
 			r.logger.Blank()
@@ -182,14 +308,27 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 		r.logger.Blank()
 	}
 
-	// Initialize Gemini client
-	geminiClient, err := NewClient(ctx, options.APIKey)
-	if err != nil {
-		return "", fmt.Errorf("creating Gemini client: %w", err)
+	// Initialize Gemini client. A dry run never talks to the API, so it doesn't need one.
+	var geminiClient *Client
+	if !options.DryRun {
+		var err error
+		geminiClient, err = NewClientForWorkDir(ctx, workDir, options.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("creating Gemini client: %w", err)
+		}
 	}
 
 	// Initialize cache manager
 	cacheManager := NewCacheManager(workDir)
+	minCacheTokens := options.MinCacheTokens
+	if minCacheTokens <= 0 {
+		minCacheTokens = config.ResolveMinCacheTokens(workDir)
+	}
+	cacheManager.SetMinCacheTokens(minCacheTokens)
+	cacheManager.SetExpiryPolicy(options.MinCacheTTL, options.AutoExtendCache)
+	if config.ResolveSharedCache(workDir) {
+		cacheManager.SetSharedCacheDir(SharedCacheDir())
+	}
 
 	// Use provided TTL or default
 	ttl := options.CacheTTL
@@ -242,26 +381,49 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 		}
 	}
 
-	// Get or create cache for cold context (if it exists and caching is enabled)
+	// Get or create cache for cold context (if it exists and caching is enabled).
+	// Naming a cache explicitly (e.g. one made with 'cache create') counts as opting in.
 	var cacheInfo *CacheInfo
 	var isNewCache bool
-	if !options.NoCache && cachingEnabled {
+	cacheDecision := CachePlanDisabled
+	if !options.NoCache && (cachingEnabled || options.UseCache != "") {
+		cacheDecision = CachePlanNone
 		// Check if user specified a cache to use
 		if options.UseCache != "" {
 			r.logger.Info(fmt.Sprintf("Using specified cache: %s", options.UseCache))
 			var err error
-			cacheInfo, err = cacheManager.FindAndValidateCache(ctx, geminiClient, options.UseCache, disableExpiration)
+			if options.DryRun {
+				cacheInfo, err = cacheManager.planNamedCache(options.UseCache, disableExpiration)
+			} else {
+				cacheInfo, err = cacheManager.FindAndValidateCache(ctx, geminiClient, options.UseCache, disableExpiration)
+			}
 			if err != nil {
-				return "", fmt.Errorf("using specified cache: %w", err)
+				return nil, fmt.Errorf("using specified cache: %w", err)
+			}
+			if !cacheModelMatches(cacheInfo.Model, options.Model) {
+				return nil, fmt.Errorf("using specified cache: %w: '%s' holds context for %s, not %s (pass -m %s to use it)",
+					ErrCacheModelMismatch, options.UseCache, cacheInfo.Model, options.Model, cacheInfo.Model)
 			}
 			isNewCache = false
+			cacheDecision = CachePlanReuse
 		} else {
 			// Normal cache handling - create or find cache based on content
 			if info, err := os.Stat(coldContextFile); err == nil && info.Size() > 0 {
-				r.logger.Info(fmt.Sprintf("Cache settings: requestYes=%v, ignoreChanges=%v, disableExpiration=%v", options.SkipConfirmation, ignoreChanges, disableExpiration))
-				cacheInfo, isNewCache, err = cacheManager.GetOrCreateCache(ctx, geminiClient, options.Model, coldContextFile, ttl, ignoreChanges, disableExpiration, options.Recache, options.SkipConfirmation)
-				if err != nil {
-					return "", fmt.Errorf("managing cache: %w", err)
+				if options.DryRun {
+					cacheDecision, cacheInfo, err = cacheManager.PlanCache(options.Model, []string{coldContextFile}, ignoreChanges, disableExpiration, options.Recache)
+					if err != nil {
+						return nil, fmt.Errorf("planning cache: %w", err)
+					}
+				} else {
+					r.logger.Info(fmt.Sprintf("Cache settings: requestYes=%v, ignoreChanges=%v, disableExpiration=%v", options.SkipConfirmation, ignoreChanges, disableExpiration))
+					var estimator TokenEstimator = HeuristicEstimator{}
+					if options.CountCacheTokens {
+						estimator = APITokenEstimator{Client: geminiClient}
+					}
+					cacheInfo, isNewCache, err = cacheManager.GetOrCreateCache(ctx, geminiClient, options.Model, []string{coldContextFile}, ttl, ignoreChanges, disableExpiration, options.Recache, options.SkipConfirmation, estimator)
+					if err != nil {
+						return nil, fmt.Errorf("managing cache: %w", err)
+					}
 				}
 			} else if err == nil && info.Size() == 0 {
 				r.logger.Warning("Cold context file is empty, skipping cache")
@@ -296,15 +458,62 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 	for _, ctxFile := range options.ContextFiles {
 		absPath, err := filepath.Abs(ctxFile)
 		if err != nil {
-			return "", fmt.Errorf("resolving context file %s: %w", ctxFile, err)
+			return nil, fmt.Errorf("resolving context file %s: %w", ctxFile, err)
 		}
 		if _, err := os.Stat(absPath); err != nil {
-			return "", fmt.Errorf("context file not found: %s", ctxFile)
+			return nil, fmt.Errorf("context file not found: %s", ctxFile)
 		}
 		dynamicFiles = append(dynamicFiles, absPath)
 		r.logger.Info(fmt.Sprintf("Including additional context: %s", absPath))
 	}
 
+	// Attach images and PDFs; like context files they are uploaded and sent as file parts
+	for _, attachment := range options.Attachments {
+		absPath, err := filepath.Abs(attachment)
+		if err != nil {
+			return nil, fmt.Errorf("resolving attachment %s: %w", attachment, err)
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return nil, fmt.Errorf("attachment not found: %s", attachment)
+		}
+		mimeType := FileMIMEType(absPath)
+		if !IsAttachmentMIMEType(mimeType) {
+			return nil, fmt.Errorf("unsupported attachment %s (%s): expected a PNG, JPEG, WebP, HEIC or HEIF image or a PDF", attachment, mimeType)
+		}
+		dynamicFiles = append(dynamicFiles, absPath)
+		r.logger.Info(fmt.Sprintf("Attaching %s (%s)", absPath, mimeType))
+	}
+
+	// Fetch any remote documents and attach them like local context files
+	urlTimeout := options.ContextURLTimeout
+	if urlTimeout <= 0 {
+		urlTimeout = DefaultContextURLTimeout
+	}
+	for _, rawURL := range options.ContextURLs {
+		urlFile, err := FetchContextURL(ctx, rawURL, urlTimeout, MaxContextURLBytes)
+		if err != nil {
+			return nil, fmt.Errorf("including context URL: %w", err)
+		}
+		defer func() { _ = os.Remove(urlFile) }()
+		dynamicFiles = append(dynamicFiles, urlFile)
+		r.logger.Info(fmt.Sprintf("Including context from URL: %s", rawURL))
+	}
+
+	// Add the current git diff if requested
+	if options.IncludeDiff {
+		diffFile, err := writeGitDiffFile(workDir, options.DiffStaged)
+		if err != nil {
+			return nil, fmt.Errorf("including git diff: %w", err)
+		}
+		if diffFile == "" {
+			r.logger.WarningCtx(ctx, "No changes found in git diff, nothing to include")
+		} else {
+			defer func() { _ = os.Remove(diffFile) }()
+			dynamicFiles = append(dynamicFiles, diffFile)
+			r.logger.Info("Including git diff as dynamic context")
+		}
+	}
+
 	// Also check for CLAUDE.md in the working directory
 	claudePath := filepath.Join(workDir, "CLAUDE.md")
 	if _, err := os.Stat(claudePath); err == nil {
@@ -312,6 +521,10 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 		r.logger.Info(fmt.Sprintf("Including CLAUDE.md: %s", claudePath))
 	}
 
+	if options.DryRun {
+		return r.reportDryRun(ctx, options.Model, options.Prompt, cacheDecision, cacheInfo, dynamicFiles, cacheManager.MinCacheTokens())
+	}
+
 	// Determine cache ID
 	var cacheID string
 	if cacheInfo != nil {
@@ -327,22 +540,201 @@ func (r *RequestRunner) Run(ctx context.Context, options RequestOptions) (string
 	}
 
 	opts := &GenerateContentOptions{
-		WorkingDir:      workDir,
-		Caller:          caller,
-		IsNewCache:      isNewCache,
-		PromptFiles:     options.PromptFiles,
-		JobID:           options.JobID,
-		PlanName:        options.PlanName,
-		Temperature:     options.Temperature,
-		TopP:            options.TopP,
-		TopK:            options.TopK,
-		MaxOutputTokens: options.MaxOutputTokens,
-	}
-
-	response, err := geminiClient.GenerateContentWithCacheAndOptions(ctx, options.Model, options.Prompt, cacheID, dynamicFiles, opts)
+		WorkingDir:         workDir,
+		Caller:             caller,
+		IsNewCache:         isNewCache,
+		PromptFiles:        options.PromptFiles,
+		JobID:              options.JobID,
+		PlanName:           options.PlanName,
+		Temperature:        options.Temperature,
+		TopP:               options.TopP,
+		TopK:               options.TopK,
+		MaxOutputTokens:    options.MaxOutputTokens,
+		PresencePenalty:    options.PresencePenalty,
+		FrequencyPenalty:   options.FrequencyPenalty,
+		CandidateCount:     options.CandidateCount,
+		StopSequences:      options.StopSequences,
+		AutoContinue:       options.AutoContinue,
+		Labels:             options.Labels,
+		ShowCostOnly:       options.ShowCostOnly,
+		ResponseMIMEType:   options.ResponseMIMEType,
+		ResponseJSONSchema: options.ResponseJSONSchema,
+		DumpContentsDir:    options.DumpContentsDir,
+		StreamWriter:       options.StreamWriter,
+		MaxRetries:         options.MaxRetries,
+		UploadConcurrency:  options.UploadConcurrency,
+		NoUploadCache:      options.NoUploadCache,
+		SystemInstruction:  options.SystemInstruction,
+		PromptTemplate:     options.PromptTemplate,
+	}
+	opts.ConversationHistory = options.ConversationHistory
+	if session != nil {
+		opts.ConversationHistory = slices.Concat(session.Turns, options.ConversationHistory)
+	}
+
+	// Conversations send earlier turns the key doesn't cover, so they always go to the API
+	var responseCache *ResponseCache
+	var responseKey string
+	if options.ResponseCache && session == nil && len(opts.ConversationHistory) == 0 {
+		responseCache = NewResponseCache(ResolveGeminiCacheDir(workDir), options.ResponseCacheTTL)
+		responseKey, err = responseCacheKey(options.Model, options.Prompt, cacheID, dynamicFiles, opts)
+		if err != nil {
+			return nil, fmt.Errorf("computing response cache key: %w", err)
+		}
+		if entry, ok := responseCache.Get(responseKey); ok {
+			return r.replayCachedResponse(ctx, entry, options, opts)
+		}
+	}
+
+	model := options.Model
+	result, err := geminiClient.GenerateContentWithResult(ctx, model, options.Prompt, cacheID, dynamicFiles, opts)
+
+	// Fall back to the next model while the current one is unavailable
+	chain := []string{model}
+	for _, fallback := range options.FallbackModels {
+		if err == nil || !IsUnavailableError(err) {
+			break
+		}
+		r.logger.WarningCtx(ctx, fmt.Sprintf("Model %s is unavailable, falling back to %s: %v", model, fallback, err))
+		if cacheID != "" {
+			// Cached content is bound to the model it was created for, so send its files inline
+			dynamicFiles = append(cachedSourceFiles(cacheInfo), dynamicFiles...)
+			cacheID = ""
+			opts.IsNewCache = false
+		}
+		model = fallback
+		chain = append(chain, model)
+		r.logger.ModelCtx(ctx, model)
+		result, err = geminiClient.GenerateContentWithResult(ctx, model, options.Prompt, cacheID, dynamicFiles, opts)
+	}
+	if len(chain) > 1 {
+		ulog.Info("Model fallback").
+			Field("chain", chain).
+			Field("answered", err == nil).
+			Log(ctx)
+		if err == nil {
+			r.logger.InfoCtx(ctx, fmt.Sprintf("Answered by fallback model %s (tried %s)", model, strings.Join(chain, " -> ")))
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API request failed: %w", classifyAPIError(err))
+	}
+
+	// The model can still return non-conforming JSON in schema mode, so give it one more try
+	if options.ValidateResponse && len(options.ResponseJSONSchema) > 0 {
+		if err := ValidateJSONResponse(options.ResponseJSONSchema, result.Text); err != nil {
+			var schemaErr *SchemaValidationError
+			if !errors.As(err, &schemaErr) {
+				return nil, err
+			}
+			r.logger.WarningCtx(ctx, fmt.Sprintf("Response did not match JSON schema (%d violations), retrying once", len(schemaErr.Failures)))
+
+			result, err = geminiClient.GenerateContentWithResult(ctx, model, options.Prompt, cacheID, dynamicFiles, opts)
+			if err != nil {
+				return nil, fmt.Errorf("Gemini API request failed: %w", classifyAPIError(err))
+			}
+			if err := ValidateJSONResponse(options.ResponseJSONSchema, result.Text); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// JSON mode promises machine-readable output, so fail rather than pass anything else along
+	if options.ResponseMIMEType == JSONMIMEType {
+		if err := CheckJSONResponse(result.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	if session != nil {
+		session.AppendExchange(options.Prompt, result.Text)
+		if err := session.Save(options.SessionFile); err != nil {
+			r.logger.WarningCtx(ctx, fmt.Sprintf("Failed to save session: %v", err))
+		}
+	}
+
+	// Only save answers from the requested model; a fallback's response isn't what the key describes
+	if responseCache != nil && result.Model == options.Model {
+		entry := responseCacheEntry{Model: result.Model, CacheID: cacheID, Text: result.Text, CreatedAt: time.Now()}
+		if err := responseCache.Put(responseKey, entry); err != nil {
+			r.logger.WarningCtx(ctx, fmt.Sprintf("Failed to save response to the response cache: %v", err))
+		}
+	}
+
+	// Warn when the cache used for this request isn't paying off
+	if cacheInfo != nil {
+		r.warnOnLowHitRate(ctx, cacheManager, cacheInfo.CacheName, workDir)
+	}
+
+	return result, nil
+}
+
+// replayCachedResponse answers a request from the response cache: the saved
+// text is streamed if requested and the query is logged as a free local hit
+func (r *RequestRunner) replayCachedResponse(ctx context.Context, entry *responseCacheEntry, options RequestOptions, opts *GenerateContentOptions) (*GenerateResult, error) {
+	r.logger.InfoCtx(ctx, fmt.Sprintf("Using saved response from %s (no API call)", entry.CreatedAt.Local().Format("2006-01-02 15:04")))
+	if options.StreamWriter != nil {
+		if _, err := io.WriteString(options.StreamWriter, entry.Text); err != nil {
+			return nil, fmt.Errorf("writing saved response: %w", err)
+		}
+	}
+
+	contextInfo := ctxinfo.GetContextInfo(opts.WorkingDir)
+	logEntry := logging.QueryLog{
+		Timestamp:        time.Now(),
+		RequestID:        os.Getenv("GROVE_REQUEST_ID"),
+		Model:            entry.Model,
+		Method:           "GenerateContent",
+		CacheID:          entry.CacheID,
+		Success:          true,
+		ResponseCacheHit: true,
+		Caller:           opts.Caller,
+		Labels:           opts.Labels,
+		WorkingDir:       contextInfo.WorkingDir,
+		GitRepo:          contextInfo.GitRepo,
+		GitBranch:        contextInfo.GitBranch,
+		GitCommit:        contextInfo.GitCommit,
+	}
+	if err := logging.GetLogger().Log(logEntry); err != nil {
+		// Don't fail the request if logging fails
+		r.logger.WarningCtx(ctx, fmt.Sprintf("Failed to log query: %v", err))
+	}
+
+	return &GenerateResult{
+		Text:             entry.Text,
+		Model:            entry.Model,
+		CacheID:          entry.CacheID,
+		ResponseCacheHit: true,
+	}, nil
+}
+
+// WrapPrompt brackets a prompt with an optional prefix and suffix, separated by blank lines
+func WrapPrompt(prefix, prompt, suffix string) string {
+	parts := make([]string, 0, 3)
+	if p := strings.TrimSpace(prefix); p != "" {
+		parts = append(parts, p)
+	}
+	parts = append(parts, prompt)
+	if s := strings.TrimSpace(suffix); s != "" {
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// warnOnLowHitRate reloads the cache's usage stats and prints a warning if the
+// configured cache advice recommends disabling caching for this context
+func (r *RequestRunner) warnOnLowHitRate(ctx context.Context, cacheManager *CacheManager, cacheName, workDir string) {
+	advice := config.ResolveCacheAdvice(workDir)
+	if !advice.WarnAfterRequest {
+		return
+	}
+
+	info, err := LoadCacheInfo(filepath.Join(cacheManager.cacheDir, "hybrid_"+cacheName+".json"))
 	if err != nil {
-		return "", fmt.Errorf("Gemini API request failed: %w", err)
+		return
 	}
 
-	return response, nil
+	if rec := RecommendCacheUsage(info, advice.MinHitRate, advice.MinQueries); rec.DisableCaching {
+		r.logger.WarningCtx(ctx, rec.Reason)
+	}
 }
@@ -0,0 +1,182 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/pretty"
+)
+
+// runRequest runs the request flow for a project without rules against a
+// fake Gemini API and returns the generateContent request bodies it sent.
+// Each generateContent call is answered with the next of responses; one with
+// an "error" key is sent with its status code.
+func runRequest(t *testing.T, options RequestOptions, responses ...map[string]any) (*GenerateResult, []string) {
+	t.Helper()
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if !strings.HasSuffix(r.URL.Path, ":generateContent") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"totalTokens": 10})
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(data))
+		if len(bodies) > len(responses) {
+			t.Errorf("unexpected generateContent call %d", len(bodies))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp := responses[len(bodies)-1]
+		if apiErr, ok := resp["error"].(map[string]any); ok {
+			w.WriteHeader(apiErr["code"].(int))
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GOOGLE_GEMINI_BASE_URL", server.URL)
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("GROVE_GEMINI_BACKEND", "")
+	t.Setenv("GROVE_GEMINI_LOG_DIR", t.TempDir())
+
+	if options.WorkDir == "" {
+		options.WorkDir = t.TempDir()
+	}
+	if options.Model == "" {
+		options.Model = "gemini-2.0-flash"
+	}
+	var out bytes.Buffer
+	runner := &RequestRunner{logger: pretty.NewWithWriter(&out)}
+	result, err := runner.RunWithResult(context.Background(), options)
+	if err != nil {
+		t.Fatalf("RunWithResult() error = %v\n%s", err, out.String())
+	}
+	return result, bodies
+}
+
+// textResponse is a generateContent response with a single text candidate
+func textResponse(text, finishReason string) map[string]any {
+	return map[string]any{
+		"candidates": []any{map[string]any{
+			"content":      map[string]any{"role": "model", "parts": []any{map[string]any{"text": text}}},
+			"finishReason": finishReason,
+		}},
+		"usageMetadata": map[string]any{"promptTokenCount": 10, "candidatesTokenCount": 5, "totalTokenCount": 15},
+	}
+}
+
+func TestWrapPrompt(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		suffix   string
+		expected string
+	}{
+		{name: "no wrapper", expected: "Explain the cache."},
+		{name: "prefix only", prefix: "Answer concisely.", expected: "Answer concisely.\n\nExplain the cache."},
+		{name: "suffix only", suffix: "Cite files.\n", expected: "Explain the cache.\n\nCite files."},
+		{name: "both", prefix: "Answer concisely.", suffix: "Cite files.", expected: "Answer concisely.\n\nExplain the cache.\n\nCite files."},
+		{name: "whitespace only", prefix: "  ", suffix: "\n", expected: "Explain the cache."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WrapPrompt(tt.prefix, "Explain the cache.", tt.suffix); got != tt.expected {
+				t.Errorf("WrapPrompt() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunWithResult_TooManyStopSequences(t *testing.T) {
+	_, err := NewRequestRunner().RunWithResult(context.Background(), RequestOptions{
+		Prompt:        "Explain the cache.",
+		StopSequences: []string{"a", "b", "c", "d", "e", "f"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "too many stop sequences") {
+		t.Errorf("Expected stop sequence limit error, got %v", err)
+	}
+}
+
+func TestRunWithResult_SendsStopSequences(t *testing.T) {
+	_, bodies := runRequest(t, RequestOptions{
+		Prompt:        "List three colors.",
+		StopSequences: []string{"END", "\n\n"},
+	}, textResponse("red, green, blue", "STOP"))
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 generateContent call, got %d", len(bodies))
+	}
+	if !strings.Contains(bodies[0], `"stopSequences":["END","\n\n"]`) {
+		t.Errorf("request body missing stop sequences: %s", bodies[0])
+	}
+}
+
+func TestRunWithResult_SendsPenalties(t *testing.T) {
+	presence, frequency := float32(0.5), float32(0.25)
+	options := RequestOptions{
+		Prompt:           "Write a haiku.",
+		PresencePenalty:  &presence,
+		FrequencyPenalty: &frequency,
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		_, bodies := runRequest(t, options, textResponse("an old silent pond", "STOP"))
+		if len(bodies) != 1 {
+			t.Fatalf("expected 1 generateContent call, got %d", len(bodies))
+		}
+		for _, want := range []string{`"presencePenalty":0.5`, `"frequencyPenalty":0.25`} {
+			if !strings.Contains(bodies[0], want) {
+				t.Errorf("request body missing %s: %s", want, bodies[0])
+			}
+		}
+	})
+
+	t.Run("unsupported by the model", func(t *testing.T) {
+		rejected := map[string]any{"error": map[string]any{"code": 400, "message": "Penalty is not enabled for this model", "status": "INVALID_ARGUMENT"}}
+		result, bodies := runRequest(t, options, rejected, textResponse("an old silent pond", "STOP"))
+		if len(bodies) != 2 {
+			t.Fatalf("expected a retry without penalties, got %d generateContent calls", len(bodies))
+		}
+		if strings.Contains(bodies[1], "Penalty") {
+			t.Errorf("retry still sent penalties: %s", bodies[1])
+		}
+		if result.Text != "an old silent pond" {
+			t.Errorf("response = %q", result.Text)
+		}
+	})
+}
+
+func TestRunWithResult_AutoContinue(t *testing.T) {
+	result, bodies := runRequest(t, RequestOptions{
+		Prompt:       "Write a long story.",
+		AutoContinue: 2,
+	}, textResponse("Once upon a ", "MAX_TOKENS"), textResponse("time.", "STOP"))
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected a continuation turn, got %d generateContent calls", len(bodies))
+	}
+	for _, want := range []string{"Once upon a ", continuePrompt} {
+		if !strings.Contains(bodies[1], want) {
+			t.Errorf("continuation request missing %q: %s", want, bodies[1])
+		}
+	}
+	if result.Text != "Once upon a time." || result.Continuations != 1 {
+		t.Errorf("result text = %q with %d continuations, want the joined text after 1", result.Text, result.Continuations)
+	}
+}
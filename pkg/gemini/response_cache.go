@@ -0,0 +1,143 @@
+package gemini
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// responseCacheDirName is the directory under the gemini cache directory that
+// holds saved responses
+const responseCacheDirName = "responses"
+
+// DefaultResponseCacheTTL is how long a saved response is reused unless
+// --response-cache-ttl says otherwise
+const DefaultResponseCacheTTL = 24 * time.Hour
+
+// responseCacheEntry is a saved response, stored as <key>.json
+type responseCacheEntry struct {
+	Model     string    `json:"model"`
+	CacheID   string    `json:"cache_id,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ResponseCache saves full responses on disk so an identical request can be
+// answered without calling the API
+type ResponseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewResponseCache returns the response cache in cacheDir whose entries are
+// reused for ttl (DefaultResponseCacheTTL when ttl isn't positive)
+func NewResponseCache(cacheDir string, ttl time.Duration) *ResponseCache {
+	if ttl <= 0 {
+		ttl = DefaultResponseCacheTTL
+	}
+	return &ResponseCache{dir: filepath.Join(cacheDir, responseCacheDirName), ttl: ttl}
+}
+
+// responseCacheInputs is everything that can change a response. It is hashed
+// as JSON, so adding a field invalidates earlier entries rather than reusing
+// them for requests they didn't answer.
+type responseCacheInputs struct {
+	Model              string          `json:"model"`
+	Prompt             string          `json:"prompt"`
+	CacheID            string          `json:"cache_id,omitempty"`
+	SystemInstruction  string          `json:"system_instruction,omitempty"`
+	Files              []string        `json:"files,omitempty"` // Content hashes of the dynamic files, in order
+	Temperature        *float32        `json:"temperature,omitempty"`
+	TopP               *float32        `json:"top_p,omitempty"`
+	TopK               *int32          `json:"top_k,omitempty"`
+	MaxOutputTokens    *int32          `json:"max_output_tokens,omitempty"`
+	PresencePenalty    *float32        `json:"presence_penalty,omitempty"`
+	FrequencyPenalty   *float32        `json:"frequency_penalty,omitempty"`
+	CandidateCount     int32           `json:"candidate_count,omitempty"`
+	StopSequences      []string        `json:"stop_sequences,omitempty"`
+	AutoContinue       int             `json:"auto_continue,omitempty"`
+	ResponseMIMEType   string          `json:"response_mime_type,omitempty"`
+	ResponseJSONSchema json.RawMessage `json:"response_json_schema,omitempty"`
+}
+
+// responseCacheKey hashes the model, prompt, context cache, dynamic file
+// contents and generation parameters of a request. Files are identified by
+// content, so a temporary file with a new name but the same bytes still hits.
+func responseCacheKey(model, prompt, cacheID string, dynamicFiles []string, opts *GenerateContentOptions) (string, error) {
+	inputs := responseCacheInputs{Model: model, Prompt: prompt, CacheID: cacheID}
+	for _, f := range dynamicFiles {
+		hash, err := hashFile(f)
+		if err != nil {
+			return "", fmt.Errorf("hashing %s: %w", f, err)
+		}
+		inputs.Files = append(inputs.Files, hash)
+	}
+	if opts != nil {
+		inputs.SystemInstruction = opts.SystemInstruction
+		inputs.Temperature = opts.Temperature
+		inputs.TopP = opts.TopP
+		inputs.TopK = opts.TopK
+		inputs.MaxOutputTokens = opts.MaxOutputTokens
+		inputs.PresencePenalty = opts.PresencePenalty
+		inputs.FrequencyPenalty = opts.FrequencyPenalty
+		inputs.CandidateCount = opts.CandidateCount
+		inputs.StopSequences = opts.StopSequences
+		inputs.AutoContinue = opts.AutoContinue
+		inputs.ResponseMIMEType = opts.ResponseMIMEType
+		inputs.ResponseJSONSchema = opts.ResponseJSONSchema
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// path returns the file an entry for key is stored in
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Get returns the saved response for key if it is younger than the TTL. A
+// missing, unreadable or expired entry is a miss.
+func (c *ResponseCache) Get(key string) (*responseCacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key)) //nolint:gosec // path is inside the cache directory
+	if err != nil {
+		return nil, false
+	}
+	var entry responseCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if time.Since(entry.CreatedAt) > c.ttl {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put saves a response under key. The entry is written to a temporary file
+// and renamed into place so concurrent readers never see a partial entry.
+func (c *ResponseCache) Put(key string, entry responseCacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil { //nolint:gosec // cache dir needs to be traversable
+		return fmt.Errorf("creating response cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("writing response cache entry: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("saving response cache entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package gemini
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	hot := filepath.Join(dir, "hot.md")
+	if err := os.WriteFile(hot, []byte("context v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	temp := float32(0.2)
+
+	base, err := responseCacheKey("gemini-2.5-pro", "Summarize", "cachedContents/abc", []string{hot}, &GenerateContentOptions{Temperature: &temp})
+	if err != nil {
+		t.Fatalf("responseCacheKey() error = %v", err)
+	}
+	same, _ := responseCacheKey("gemini-2.5-pro", "Summarize", "cachedContents/abc", []string{hot}, &GenerateContentOptions{Temperature: &temp, Caller: "other"})
+	if same != base {
+		t.Error("key should ignore options that don't change the response")
+	}
+
+	otherTemp := float32(0.9)
+	variants := map[string]func() (string, error){
+		"model": func() (string, error) {
+			return responseCacheKey("gemini-2.5-flash", "Summarize", "cachedContents/abc", []string{hot}, &GenerateContentOptions{Temperature: &temp})
+		},
+		"prompt": func() (string, error) {
+			return responseCacheKey("gemini-2.5-pro", "Explain", "cachedContents/abc", []string{hot}, &GenerateContentOptions{Temperature: &temp})
+		},
+		"cache": func() (string, error) {
+			return responseCacheKey("gemini-2.5-pro", "Summarize", "cachedContents/def", []string{hot}, &GenerateContentOptions{Temperature: &temp})
+		},
+		"temperature": func() (string, error) {
+			return responseCacheKey("gemini-2.5-pro", "Summarize", "cachedContents/abc", []string{hot}, &GenerateContentOptions{Temperature: &otherTemp})
+		},
+		"file content": func() (string, error) {
+			if err := os.WriteFile(hot, []byte("context v2"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			return responseCacheKey("gemini-2.5-pro", "Summarize", "cachedContents/abc", []string{hot}, &GenerateContentOptions{Temperature: &temp})
+		},
+	}
+	for name, keyFn := range variants {
+		key, err := keyFn()
+		if err != nil {
+			t.Fatalf("%s: responseCacheKey() error = %v", name, err)
+		}
+		if key == base {
+			t.Errorf("changing the %s should change the key", name)
+		}
+	}
+
+	if _, err := responseCacheKey("m", "p", "", []string{filepath.Join(dir, "missing")}, nil); err == nil {
+		t.Error("expected an error for an unreadable dynamic file")
+	}
+}
+
+func TestResponseCacheGetPut(t *testing.T) {
+	cache := NewResponseCache(t.TempDir(), time.Hour)
+
+	if _, ok := cache.Get("abc"); ok {
+		t.Fatal("empty cache should miss")
+	}
+	if err := cache.Put("abc", responseCacheEntry{Model: "gemini-2.5-pro", Text: "answer", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	entry, ok := cache.Get("abc")
+	if !ok || entry.Text != "answer" {
+		t.Fatalf("Get() = %+v, %v, want the saved answer", entry, ok)
+	}
+
+	if err := cache.Put("old", responseCacheEntry{Text: "stale", CreatedAt: time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, ok := cache.Get("old"); ok {
+		t.Error("entry older than the TTL should miss")
+	}
+	if _, err := os.Stat(cache.path("abc") + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file should not remain after Put")
+	}
+}
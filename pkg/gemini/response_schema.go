@@ -0,0 +1,94 @@
+package gemini
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// JSONMIMEType is the response MIME type that puts the model in JSON mode
+const JSONMIMEType = "application/json"
+
+// ErrInvalidJSONResponse is returned in JSON mode when the response doesn't parse as JSON
+var ErrInvalidJSONResponse = errors.New("response is not valid JSON")
+
+// CheckJSONResponse checks that response is exactly one well-formed JSON value
+func CheckJSONResponse(response string) error {
+	decoder := json.NewDecoder(strings.NewReader(response))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSONResponse, err)
+	}
+	if err := decoder.Decode(&value); err != io.EOF {
+		return fmt.Errorf("%w: unexpected content after the JSON value", ErrInvalidJSONResponse)
+	}
+	return nil
+}
+
+// SchemaValidationError is returned when a response does not conform to the
+// requested JSON schema. Failures lists each violation as "location: message".
+type SchemaValidationError struct {
+	Failures []string
+}
+
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("response does not match JSON schema:\n  - %s", strings.Join(e.Failures, "\n  - "))
+}
+
+// ValidateJSONResponse checks that response is valid JSON conforming to the
+// given JSON Schema document. It returns a *SchemaValidationError describing
+// every violation, or a plain error if the schema itself can't be compiled.
+func ValidateJSONResponse(schemaData []byte, response string) error {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("response.schema.json", bytes.NewReader(schemaData)); err != nil {
+		return fmt.Errorf("loading response schema: %w", err)
+	}
+	schema, err := compiler.Compile("response.schema.json")
+	if err != nil {
+		return fmt.Errorf("compiling response schema: %w", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(response))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return &SchemaValidationError{Failures: []string{fmt.Sprintf("response is not valid JSON: %v", err)}}
+	}
+	// A valid first value followed by prose or a second value is still not a JSON response
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return &SchemaValidationError{Failures: []string{"response is not valid JSON: unexpected content after the JSON value"}}
+	}
+
+	err = schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return fmt.Errorf("validating response: %w", err)
+	}
+
+	// Report only the leaf violations; parent entries just summarize their causes
+	var failures []string
+	for _, e := range validationErr.BasicOutput().Errors {
+		if e.Error == "" || strings.HasPrefix(e.Error, "doesn't validate with") {
+			continue
+		}
+		location := e.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", location, e.Error))
+	}
+	if len(failures) == 0 {
+		failures = []string{validationErr.Error()}
+	}
+	return &SchemaValidationError{Failures: failures}
+}
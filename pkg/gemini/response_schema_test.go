@@ -0,0 +1,90 @@
+package gemini
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testResponseSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string"},
+		"count": {"type": "integer", "minimum": 0}
+	},
+	"required": ["name", "count"]
+}`
+
+func TestValidateJSONResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		response     string
+		wantFailures []string
+	}{
+		{name: "valid", response: `{"name": "cache", "count": 3}`},
+		{name: "missing property", response: `{"name": "cache"}`, wantFailures: []string{"count"}},
+		{name: "wrong type", response: `{"name": "cache", "count": "three"}`, wantFailures: []string{"/count"}},
+		{name: "not json", response: "Here is your JSON:", wantFailures: []string{"not valid JSON"}},
+		{name: "trailing content", response: `{"name": "cache", "count": 3} and some commentary`, wantFailures: []string{"after the JSON value"}},
+		{name: "second value", response: `{"name": "cache", "count": 3} {}`, wantFailures: []string{"after the JSON value"}},
+		{name: "trailing whitespace", response: "{\"name\": \"cache\", \"count\": 3}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSONResponse([]byte(testResponseSchema), tt.response)
+			if len(tt.wantFailures) == 0 {
+				if err != nil {
+					t.Fatalf("Expected valid response, got %v", err)
+				}
+				return
+			}
+
+			var schemaErr *SchemaValidationError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("Expected SchemaValidationError, got %v", err)
+			}
+			joined := strings.Join(schemaErr.Failures, "\n")
+			for _, want := range tt.wantFailures {
+				if !strings.Contains(joined, want) {
+					t.Errorf("Expected failures to mention %q, got:\n%s", want, joined)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateJSONResponse_InvalidSchema(t *testing.T) {
+	err := ValidateJSONResponse([]byte(`{"type": 5}`), `{}`)
+	if err == nil {
+		t.Fatal("Expected error for invalid schema")
+	}
+	var schemaErr *SchemaValidationError
+	if errors.As(err, &schemaErr) {
+		t.Error("Invalid schema should not be reported as a response validation failure")
+	}
+}
+
+func TestCheckJSONResponse(t *testing.T) {
+	tests := []struct {
+		response string
+		valid    bool
+	}{
+		{`{"answer": 42}`, true},
+		{"  [1, 2, 3]\n", true},
+		{`"just a string"`, true},
+		{"```json\n{\"answer\": 42}\n```", false},
+		{`{"answer": 42} and some commentary`, false},
+		{`{"answer": 42}}`, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		err := CheckJSONResponse(tt.response)
+		if tt.valid && err != nil {
+			t.Errorf("CheckJSONResponse(%q) = %v, want nil", tt.response, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidJSONResponse) {
+			t.Errorf("CheckJSONResponse(%q) = %v, want ErrInvalidJSONResponse", tt.response, err)
+		}
+	}
+}
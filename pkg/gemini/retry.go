@@ -0,0 +1,133 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genai"
+)
+
+// Defaults for retrying transient generate failures
+const (
+	DefaultMaxRetries     = 3
+	DefaultRetryBaseDelay = time.Second
+	DefaultMaxRetryWait   = time.Minute
+)
+
+// retryPolicy controls how transient generate failures are retried
+type retryPolicy struct {
+	maxRetries int           // Retries after the first attempt (0 disables retrying)
+	baseDelay  time.Duration // Backoff before the first retry, doubled for each one after
+	maxWait    time.Duration // Total time spent backing off before giving up
+}
+
+// newRetryPolicy builds the retry policy for a request, filling in defaults
+// for unset delays. Retrying is off unless opts.MaxRetries is positive.
+func newRetryPolicy(opts *GenerateContentOptions) retryPolicy {
+	policy := retryPolicy{baseDelay: DefaultRetryBaseDelay, maxWait: DefaultMaxRetryWait}
+	if opts == nil {
+		return policy
+	}
+	policy.maxRetries = opts.MaxRetries
+	if opts.RetryBaseDelay > 0 {
+		policy.baseDelay = opts.RetryBaseDelay
+	}
+	if opts.MaxRetryWait > 0 {
+		policy.maxWait = opts.MaxRetryWait
+	}
+	return policy
+}
+
+// isRetryableError reports whether a generate failure is transient: a rate
+// limit (429) or server error (500, 503). Exhausted daily quotas and streams
+// that already wrote part of the response are not retried.
+func isRetryableError(err error) bool {
+	var streamErr *StreamInterruptedError
+	if err == nil || errors.As(err, &streamErr) {
+		return false
+	}
+	var quotaErr *QuotaError
+	if errors.As(err, &quotaErr) && quotaErr.Daily {
+		return false
+	}
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case 429, 500, 503:
+		return true
+	}
+	switch apiErr.Status {
+	case "RESOURCE_EXHAUSTED", "INTERNAL", "UNAVAILABLE":
+		return true
+	}
+	return false
+}
+
+// backoffDelay returns the delay before retry number attempt (starting at 1):
+// exponential in the attempt with jitter over its upper half, so concurrent
+// clients don't retry in lockstep.
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if delay <= 0 {
+		// Shift overflowed
+		delay = DefaultMaxRetryWait
+	}
+	half := delay / 2
+	return half + rand.N(half+1) //nolint:gosec // jitter doesn't need a secure source
+}
+
+// retryableGenerate calls generate, retrying transient failures with
+// exponential backoff until the policy's attempts or total wait run out.
+// A rate limit that names its own retry delay is honored instead of the backoff.
+func retryableGenerate(ctx context.Context, model string, policy retryPolicy, generate func() (*genai.GenerateContentResponse, error)) (*genai.GenerateContentResponse, error) {
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		result, err := generate()
+		if err == nil || attempt > policy.maxRetries {
+			return result, err
+		}
+		var quotaErr *QuotaError
+		if IsQuotaError(err) {
+			quotaErr = newQuotaError(model, err, time.Now())
+		}
+		if !isRetryableError(err) || (quotaErr != nil && quotaErr.Daily) {
+			return nil, err
+		}
+
+		delay := backoffDelay(policy.baseDelay, attempt)
+		if quotaErr != nil && quotaErr.RetryAfter > 0 {
+			delay = quotaErr.RetryAfter
+		}
+		if waited+delay > policy.maxWait {
+			log.WithFields(logrus.Fields{
+				"model":   model,
+				"attempt": attempt,
+				"backoff": delay,
+				"waited":  waited,
+			}).Debug("Giving up on Gemini request: retry would exceed the maximum wait")
+			return nil, err
+		}
+
+		log.WithFields(logrus.Fields{
+			"model":       model,
+			"attempt":     attempt,
+			"max_retries": policy.maxRetries,
+			"backoff":     delay,
+			"error":       err.Error(),
+		}).Debug("Retrying Gemini request after transient error")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		waited += delay
+	}
+}
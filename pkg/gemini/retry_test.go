@@ -0,0 +1,126 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "rate limit", err: genai.APIError{Code: 429}, expected: true},
+		{name: "internal", err: genai.APIError{Code: 500}, expected: true},
+		{name: "unavailable", err: genai.APIError{Code: 503}, expected: true},
+		{name: "unavailable status", err: genai.APIError{Status: "UNAVAILABLE"}, expected: true},
+		{name: "bad request", err: genai.APIError{Code: 400}, expected: false},
+		{name: "forbidden", err: genai.APIError{Code: 403}, expected: false},
+		{name: "not found", err: genai.APIError{Code: 404}, expected: false},
+		{name: "daily quota", err: &QuotaError{Daily: true, Err: genai.APIError{Code: 429}}, expected: false},
+		{name: "interrupted stream", err: &StreamInterruptedError{Bytes: 5, Err: genai.APIError{Code: 503}}, expected: false},
+		{name: "plain error", err: errors.New("boom"), expected: false},
+		{name: "nil", err: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.expected {
+				t.Errorf("isRetryableError() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+	for attempt := 1; attempt <= 4; attempt++ {
+		upper := base << (attempt - 1)
+		for i := 0; i < 20; i++ {
+			if d := backoffDelay(base, attempt); d < upper/2 || d > upper {
+				t.Fatalf("backoffDelay(attempt %d) = %v, want within [%v, %v]", attempt, d, upper/2, upper)
+			}
+		}
+	}
+}
+
+// failingGenerate returns errs in order, then a successful response
+func failingGenerate(calls *int, errs ...error) func() (*genai.GenerateContentResponse, error) {
+	return func() (*genai.GenerateContentResponse, error) {
+		*calls++
+		if *calls <= len(errs) {
+			return nil, errs[*calls-1]
+		}
+		return &genai.GenerateContentResponse{}, nil
+	}
+}
+
+func TestRetryableGenerate(t *testing.T) {
+	policy := retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxWait: time.Second}
+	ctx := context.Background()
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		calls := 0
+		_, err := retryableGenerate(ctx, "m", policy, failingGenerate(&calls, genai.APIError{Code: 503}, genai.APIError{Code: 429}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("fails fast on non-retryable errors", func(t *testing.T) {
+		calls := 0
+		_, err := retryableGenerate(ctx, "m", policy, failingGenerate(&calls, genai.APIError{Code: 400}))
+		if err == nil || calls != 1 {
+			t.Errorf("calls = %d, err = %v; want a single failed call", calls, err)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		calls := 0
+		unavailable := genai.APIError{Code: 503}
+		_, err := retryableGenerate(ctx, "m", policy, failingGenerate(&calls, unavailable, unavailable, unavailable, unavailable, unavailable))
+		var apiErr genai.APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != 503 {
+			t.Errorf("err = %v, want the last 503", err)
+		}
+		if calls != 4 {
+			t.Errorf("calls = %d, want 4", calls)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		calls := 0
+		_, err := retryableGenerate(ctx, "m", retryPolicy{}, failingGenerate(&calls, genai.APIError{Code: 503}))
+		if err == nil || calls != 1 {
+			t.Errorf("calls = %d, err = %v; want a single failed call", calls, err)
+		}
+	})
+
+	t.Run("stops at max wait", func(t *testing.T) {
+		calls := 0
+		slow := retryPolicy{maxRetries: 3, baseDelay: time.Hour, maxWait: time.Second}
+		_, err := retryableGenerate(ctx, "m", slow, failingGenerate(&calls, genai.APIError{Code: 503}))
+		if err == nil || calls != 1 {
+			t.Errorf("calls = %d, err = %v; want to give up without waiting", calls, err)
+		}
+	})
+
+	t.Run("respects cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		calls := 0
+		slow := retryPolicy{maxRetries: 3, baseDelay: 100 * time.Millisecond, maxWait: time.Minute}
+		_, err := retryableGenerate(cancelled, "m", slow, failingGenerate(&calls, genai.APIError{Code: 503}))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+}
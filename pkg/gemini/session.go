@@ -0,0 +1,65 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// Session is a conversation persisted between requests. Only the text of each
+// turn is kept; attached context files are sent fresh with every request.
+type Session struct {
+	Turns     []*genai.Content `json:"turns"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// LoadSession reads a session file. A missing file is an empty session.
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // session path is user-provided
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Session{}, nil
+		}
+		return nil, fmt.Errorf("reading session: %w", err)
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("parsing session %s: %w", path, err)
+	}
+	return &session, nil
+}
+
+// AppendExchange adds a user prompt and the model's reply to the session
+func (s *Session) AppendExchange(prompt, response string) {
+	s.Turns = append(s.Turns,
+		genai.NewContentFromText(prompt, genai.RoleUser),
+		genai.NewContentFromText(response, genai.RoleModel),
+	)
+	s.UpdatedAt = time.Now()
+}
+
+// Save writes the session to path atomically
+func (s *Session) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling session: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // session dir is user-chosen
+			return fmt.Errorf("creating session directory: %w", err)
+		}
+	}
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return fmt.Errorf("writing session: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		_ = os.Remove(tempFile) // best-effort cleanup
+		return fmt.Errorf("saving session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package gemini
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions", "chat.json")
+
+	session, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession on a missing file: %v", err)
+	}
+	if len(session.Turns) != 0 {
+		t.Fatalf("expected an empty session, got %d turns", len(session.Turns))
+	}
+
+	session.AppendExchange("Where is the cache key computed?", "In generateCacheKey.")
+	session.AppendExchange("Why?", "So identical files share a cache.")
+	if err := session.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if len(loaded.Turns) != 4 {
+		t.Fatalf("expected 4 turns, got %d", len(loaded.Turns))
+	}
+	wantRoles := []string{genai.RoleUser, genai.RoleModel, genai.RoleUser, genai.RoleModel}
+	for i, turn := range loaded.Turns {
+		if turn.Role != wantRoles[i] {
+			t.Errorf("turn %d: role %q, want %q", i, turn.Role, wantRoles[i])
+		}
+	}
+	if got := loaded.Turns[3].Parts[0].Text; got != "So identical files share a cache." {
+		t.Errorf("last turn text = %q", got)
+	}
+}
+
+func TestLoadSessionInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chat.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSession(path); err == nil {
+		t.Error("expected an error for a corrupt session file")
+	}
+}
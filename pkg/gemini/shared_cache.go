@@ -0,0 +1,194 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SharedCacheEntry records a server cache that any project with the same cold
+// context and model can reuse. Projects lists the working directories that
+// have used it, so clearing it can be weighed against who still relies on it.
+type SharedCacheEntry struct {
+	CacheID    string    `json:"cache_id"`
+	CacheKey   string    `json:"cache_key"`
+	Model      string    `json:"model"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	TokenCount int       `json:"token_count,omitempty"`
+	Projects   []string  `json:"projects"`
+}
+
+// SharedCacheDir returns the directory of the cross-project cache index,
+// ~/.grove/gemini-cache, or an empty string if the home directory is unknown.
+func SharedCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".grove", "gemini-cache")
+}
+
+// sharedCacheName names the index entry for a content key and model
+func sharedCacheName(cacheKey, model string) string {
+	return "shared_" + cacheKey + "_" + strings.ReplaceAll(model, "/", "_")
+}
+
+// LoadSharedCacheEntry loads the shared index entry for a content key and model
+func LoadSharedCacheEntry(dir, cacheKey, model string) (*SharedCacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, sharedCacheName(cacheKey, model)+".json")) //nolint:gosec // path is built from the internal shared cache dir
+	if err != nil {
+		return nil, err
+	}
+	return parseSharedCacheEntry(data)
+}
+
+func parseSharedCacheEntry(data []byte) (*SharedCacheEntry, error) {
+	var entry SharedCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("parsing shared cache entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// findSharedCache returns the shared cache for the content key and model if it
+// is still live on the server. Entries for expired or deleted caches are removed.
+func (m *CacheManager) findSharedCache(ctx context.Context, client *Client, cacheKey, model string) (*SharedCacheEntry, error) {
+	entry, err := LoadSharedCacheEntry(m.sharedDir, cacheKey, model)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	live := time.Now().Before(entry.ExpiresAt)
+	if live {
+		if live, err = client.VerifyCacheExists(ctx, entry.CacheID); err != nil {
+			return nil, err
+		}
+	}
+	if !live {
+		_ = os.Remove(filepath.Join(m.sharedDir, sharedCacheName(cacheKey, model)+".json"))
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// registerSharedCache adds the cache to the shared index, or refreshes its
+// entry, and records this manager's working directory as one of its projects
+func (m *CacheManager) registerSharedCache(ctx context.Context, info *CacheInfo) error {
+	if err := os.MkdirAll(m.sharedDir, 0o755); err != nil { //nolint:gosec // cache dir needs to be traversable
+		return fmt.Errorf("creating shared cache directory: %w", err)
+	}
+	name := sharedCacheName(info.CacheName, info.Model)
+	unlock, err := acquireCacheLock(ctx, m.sharedDir, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entry, err := LoadSharedCacheEntry(m.sharedDir, info.CacheName, info.Model)
+	if err != nil || entry.CacheID != info.CacheID {
+		entry = &SharedCacheEntry{
+			CacheID:   info.CacheID,
+			CacheKey:  info.CacheName,
+			Model:     info.Model,
+			CreatedAt: info.CreatedAt,
+		}
+	}
+	entry.ExpiresAt = info.ExpiresAt
+	entry.TokenCount = info.TokenCount
+
+	project := sharedCacheProject(m.workingDir)
+	if !slices.Contains(entry.Projects, project) {
+		entry.Projects = append(entry.Projects, project)
+	}
+	return writeSharedCacheEntry(filepath.Join(m.sharedDir, name+".json"), entry)
+}
+
+// sharedCacheProject returns how workDir is recorded in a shared entry's projects
+func sharedCacheProject(workDir string) string {
+	if abs, err := filepath.Abs(workDir); err == nil {
+		return abs
+	}
+	return workDir
+}
+
+// writeSharedCacheEntry atomically writes a shared index entry to path
+func writeSharedCacheEntry(path string, entry *SharedCacheEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling shared cache entry: %w", err)
+	}
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o644); err != nil { //nolint:gosec // cache files need to be readable
+		return fmt.Errorf("writing shared cache entry: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		_ = os.Remove(tempFile) // best-effort cleanup
+		return fmt.Errorf("saving shared cache entry: %w", err)
+	}
+	return nil
+}
+
+// ReleaseCache drops workDir's use of the server cache in info. A shared
+// cache that other projects in the index in sharedDir still use is kept and
+// only this project's reference is removed; otherwise the server cache is
+// deleted. It returns the projects still using the cache, so an empty result
+// means the server cache was deleted.
+func ReleaseCache(ctx context.Context, client *Client, sharedDir, workDir string, info *CacheInfo) ([]string, error) {
+	if info.Shared {
+		if sharedDir == "" {
+			return nil, fmt.Errorf("cache %s is shared but the shared cache index can't be located", info.CacheID)
+		}
+		remaining, err := unregisterSharedCache(ctx, sharedDir, workDir, info)
+		if err != nil {
+			return nil, fmt.Errorf("updating the shared cache index: %w", err)
+		}
+		if len(remaining) > 0 {
+			return remaining, nil
+		}
+	}
+	return nil, client.DeleteCache(ctx, info.CacheID)
+}
+
+// unregisterSharedCache removes workDir from the projects of the shared entry
+// for info's cache and returns the projects left. The entry is removed with
+// its last project. An entry that now points at a different cache is left
+// alone, as nothing in the index refers to info's cache any more.
+func unregisterSharedCache(ctx context.Context, sharedDir, workDir string, info *CacheInfo) ([]string, error) {
+	name := sharedCacheName(info.CacheName, info.Model)
+	unlock, err := acquireCacheLock(ctx, sharedDir, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entry, err := LoadSharedCacheEntry(sharedDir, info.CacheName, info.Model)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if entry.CacheID != info.CacheID {
+		return nil, nil
+	}
+
+	project := sharedCacheProject(workDir)
+	entry.Projects = slices.DeleteFunc(entry.Projects, func(p string) bool { return p == project })
+	path := filepath.Join(sharedDir, name+".json")
+	if len(entry.Projects) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return entry.Projects, writeSharedCacheEntry(path, entry)
+}
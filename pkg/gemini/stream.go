@@ -0,0 +1,96 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// StreamInterruptedError is returned when a streamed response fails after
+// some of it was already written. The partial output can't be retracted, so
+// callers must not silently retry or fall back to another model.
+type StreamInterruptedError struct {
+	Bytes int // Bytes of response text written before the failure
+	Err   error
+}
+
+func (e *StreamInterruptedError) Error() string {
+	return fmt.Sprintf("response stream interrupted after %d bytes: %v", e.Bytes, e.Err)
+}
+
+func (e *StreamInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateContentStream generates content like GenerateContentWithResult but
+// writes the response text to w as it arrives. Token usage, cost and the query
+// log are recorded from the final chunk once the stream completes.
+func (c *Client) GenerateContentStream(ctx context.Context, model string, prompt string, cacheID string, dynamicFilePaths []string, opts *GenerateContentOptions, w io.Writer) (*GenerateResult, error) {
+	streamOpts := GenerateContentOptions{}
+	if opts != nil {
+		streamOpts = *opts
+	}
+	streamOpts.StreamWriter = w
+	return c.GenerateContentWithResult(ctx, model, prompt, cacheID, dynamicFilePaths, &streamOpts)
+}
+
+// generateContent makes a single generate call, streaming the text to w when it is set
+func (c *Client) generateContent(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig, w io.Writer) (*genai.GenerateContentResponse, error) {
+	if w == nil {
+		return c.client.Models.GenerateContent(ctx, model, contents, config)
+	}
+	return collectStream(c.client.Models.GenerateContentStream(ctx, model, contents, config), w)
+}
+
+// collectStream writes each chunk's text to w and reassembles the chunks into
+// a single response. The last chunk carries the finish reason, and the latest
+// usage metadata covers the whole response.
+func collectStream(seq iter.Seq2[*genai.GenerateContentResponse, error], w io.Writer) (*genai.GenerateContentResponse, error) {
+	var text strings.Builder
+	var last *genai.GenerateContentResponse
+	var usage *genai.GenerateContentResponseUsageMetadata
+
+	for chunk, err := range seq {
+		if err == nil && chunk != nil {
+			if chunkText := chunk.Text(); chunkText != "" {
+				text.WriteString(chunkText)
+				if _, werr := io.WriteString(w, chunkText); werr != nil {
+					err = fmt.Errorf("writing streamed response: %w", werr)
+				}
+			}
+		}
+		if err != nil {
+			if text.Len() > 0 {
+				return nil, &StreamInterruptedError{Bytes: text.Len(), Err: err}
+			}
+			return nil, err
+		}
+		last = chunk
+		if chunk.UsageMetadata != nil {
+			usage = chunk.UsageMetadata
+		}
+	}
+	if last == nil {
+		return nil, errors.New("response stream ended without any data")
+	}
+
+	response := &genai.GenerateContentResponse{
+		UsageMetadata:  usage,
+		PromptFeedback: last.PromptFeedback,
+		ModelVersion:   last.ModelVersion,
+		ResponseID:     last.ResponseID,
+	}
+	if text.Len() > 0 || len(last.Candidates) > 0 {
+		candidate := &genai.Candidate{Content: genai.NewContentFromText(text.String(), genai.RoleModel)}
+		if len(last.Candidates) > 0 {
+			candidate.FinishReason = last.Candidates[0].FinishReason
+		}
+		response.Candidates = []*genai.Candidate{candidate}
+	}
+	return response, nil
+}
@@ -0,0 +1,89 @@
+package gemini
+
+import (
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// chunkSeq yields the given chunks, then err if it is non-nil
+func chunkSeq(chunks []*genai.GenerateContentResponse, err error) iter.Seq2[*genai.GenerateContentResponse, error] {
+	return func(yield func(*genai.GenerateContentResponse, error) bool) {
+		for _, chunk := range chunks {
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func textChunk(text string, finish genai.FinishReason, usage *genai.GenerateContentResponseUsageMetadata) *genai.GenerateContentResponse {
+	return &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content:      genai.NewContentFromText(text, genai.RoleModel),
+			FinishReason: finish,
+		}},
+		UsageMetadata: usage,
+	}
+}
+
+func TestCollectStream(t *testing.T) {
+	usage := &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 12, TotalTokenCount: 112}
+	chunks := []*genai.GenerateContentResponse{
+		textChunk("Hello, ", "", &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100}),
+		textChunk("streaming ", "", nil),
+		textChunk("world.", genai.FinishReasonStop, usage),
+	}
+
+	var out strings.Builder
+	resp, err := collectStream(chunkSeq(chunks, nil), &out)
+	if err != nil {
+		t.Fatalf("collectStream() error = %v", err)
+	}
+	if out.String() != "Hello, streaming world." {
+		t.Errorf("written = %q", out.String())
+	}
+	if got := resp.Text(); got != "Hello, streaming world." {
+		t.Errorf("merged text = %q", got)
+	}
+	if resp.Candidates[0].FinishReason != genai.FinishReasonStop {
+		t.Errorf("finish reason = %q, want STOP", resp.Candidates[0].FinishReason)
+	}
+	if resp.UsageMetadata != usage {
+		t.Errorf("usage metadata = %+v, want the last chunk's", resp.UsageMetadata)
+	}
+}
+
+func TestCollectStreamInterrupted(t *testing.T) {
+	streamErr := genai.APIError{Code: 500, Message: "internal"}
+
+	var out strings.Builder
+	_, err := collectStream(chunkSeq([]*genai.GenerateContentResponse{textChunk("partial", "", nil)}, streamErr), &out)
+	var interrupted *StreamInterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("error = %v, want StreamInterruptedError", err)
+	}
+	if interrupted.Bytes != len("partial") {
+		t.Errorf("Bytes = %d, want %d", interrupted.Bytes, len("partial"))
+	}
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 500 {
+		t.Errorf("underlying API error not preserved: %v", err)
+	}
+
+	// A failure before any output is returned as-is so fallback can still apply
+	_, err = collectStream(chunkSeq(nil, streamErr), &out)
+	if errors.As(err, &interrupted) {
+		t.Errorf("error before output should not be StreamInterruptedError: %v", err)
+	}
+
+	if _, err := collectStream(chunkSeq(nil, nil), &out); err == nil {
+		t.Error("expected an error for an empty stream")
+	}
+}
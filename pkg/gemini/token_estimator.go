@@ -0,0 +1,83 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// TokenEstimator counts the tokens a model would see for a piece of content.
+// GetOrCreateCache uses it to decide whether cold context is large enough to cache.
+type TokenEstimator interface {
+	EstimateTokens(ctx context.Context, model string, content []byte) (int, error)
+}
+
+// HeuristicEstimator estimates ~1 token per 4 bytes without calling the API.
+// It is fast and free but can be far off for non-English text or dense code.
+type HeuristicEstimator struct{}
+
+// EstimateTokens returns the heuristic estimate for content
+func (HeuristicEstimator) EstimateTokens(_ context.Context, _ string, content []byte) (int, error) {
+	return estimateTokens(content), nil
+}
+
+// APITokenEstimator counts tokens exactly with the Gemini CountTokens API.
+// Each call costs a round trip.
+type APITokenEstimator struct {
+	Client *Client
+}
+
+// EstimateTokens asks the API how many tokens model counts for content
+func (e APITokenEstimator) EstimateTokens(ctx context.Context, model string, content []byte) (int, error) {
+	if e.Client == nil {
+		return 0, fmt.Errorf("counting tokens: no client")
+	}
+	contents := []*genai.Content{genai.NewContentFromText(string(content), genai.RoleUser)}
+	resp, err := e.Client.GetClient().Models.CountTokens(ctx, model, contents, nil)
+	if err != nil {
+		return 0, fmt.Errorf("counting tokens: %w", err)
+	}
+	return int(resp.TotalTokens), nil
+}
+
+// FileTokenCount is the number of tokens a file contributes to a prompt
+type FileTokenCount struct {
+	Path     string
+	MIMEType string
+	Tokens   int
+}
+
+// CountFileTokens uploads files and asks the API how many tokens each adds to
+// a prompt for model. Text, images and PDFs are all counted by the API, so the
+// result is exact where the byte heuristic would be meaningless. On Vertex AI
+// the files are sent inline instead of uploaded.
+func (c *Client) CountFileTokens(ctx context.Context, model string, files []string) ([]FileTokenCount, error) {
+	var parts []*genai.Part
+	if c.isVertex() {
+		inline, err := inlineFileParts(files)
+		if err != nil {
+			return nil, err
+		}
+		parts = inline
+	} else {
+		uploads, err := uploadFiles(ctx, c.client, nil, files, DefaultUploadConcurrency)
+		if err != nil {
+			return nil, err
+		}
+		for _, upload := range uploads {
+			parts = append(parts, genai.NewPartFromURI(upload.FileURI, upload.MIMEType))
+		}
+	}
+
+	counts := make([]FileTokenCount, 0, len(parts))
+	for i, part := range parts {
+		contents := []*genai.Content{genai.NewContentFromParts([]*genai.Part{part}, genai.RoleUser)}
+		resp, err := c.client.Models.CountTokens(ctx, model, contents, nil)
+		if err != nil {
+			return nil, fmt.Errorf("counting tokens for %s: %w", files[i], err)
+		}
+		counts = append(counts, FileTokenCount{Path: files[i], MIMEType: FileMIMEType(files[i]), Tokens: int(resp.TotalTokens)})
+	}
+	return counts, nil
+}
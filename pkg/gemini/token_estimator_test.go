@@ -0,0 +1,181 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/config"
+)
+
+// tokenFixtureDir holds sample files with token counts recorded from the CountTokens API.
+// Re-record them with:
+//
+//	GEMINI_API_KEY=... GEMINI_RECORD_TOKEN_COUNTS=1 go test ./pkg/gemini -run TestHeuristicEstimatorAccuracy
+const tokenFixtureDir = "testdata/tokens"
+
+type tokenFixtures struct {
+	Model  string         `json:"model"`
+	Counts map[string]int `json:"counts"`
+}
+
+func TestHeuristicEstimatorAccuracy(t *testing.T) {
+	countsPath := filepath.Join(tokenFixtureDir, "counts.json")
+	data, err := os.ReadFile(countsPath)
+	if err != nil {
+		t.Fatalf("reading fixture counts: %v", err)
+	}
+	var fixtures tokenFixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("parsing fixture counts: %v", err)
+	}
+
+	if os.Getenv("GEMINI_RECORD_TOKEN_COUNTS") != "" {
+		recordTokenCounts(t, countsPath, &fixtures)
+	}
+
+	for name, recorded := range fixtures.Counts {
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join(tokenFixtureDir, name))
+			if err != nil {
+				t.Fatalf("reading fixture: %v", err)
+			}
+			if recorded == 0 {
+				t.Skip("no recorded count; set GEMINI_RECORD_TOKEN_COUNTS=1 with an API key to record one")
+			}
+
+			estimate, _ := HeuristicEstimator{}.EstimateTokens(context.Background(), fixtures.Model, content)
+			ratio := float64(estimate) / float64(recorded)
+			t.Logf("heuristic %d vs recorded %d (ratio %.2f)", estimate, recorded, ratio)
+			// Loose bounds: this tracks drift, it doesn't demand precision
+			if ratio < 0.25 || ratio > 4 {
+				t.Errorf("heuristic estimate %d is more than 4x off the recorded count %d", estimate, recorded)
+			}
+		})
+	}
+}
+
+// recordTokenCounts refreshes the fixture counts from the live CountTokens API
+func recordTokenCounts(t *testing.T, countsPath string, fixtures *tokenFixtures) {
+	t.Helper()
+	ctx := context.Background()
+	client, err := NewClient(ctx, "")
+	if err != nil {
+		t.Fatalf("creating client to record token counts: %v", err)
+	}
+	estimator := APITokenEstimator{Client: client}
+	for name := range fixtures.Counts {
+		content, err := os.ReadFile(filepath.Join(tokenFixtureDir, name))
+		if err != nil {
+			t.Fatalf("reading fixture %s: %v", name, err)
+		}
+		count, err := estimator.EstimateTokens(ctx, fixtures.Model, content)
+		if err != nil {
+			t.Fatalf("counting tokens for %s: %v", name, err)
+		}
+		fixtures.Counts[name] = count
+	}
+	data, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(countsPath, append(data, '\n'), 0o644); err != nil { //nolint:gosec // test fixture
+		t.Fatalf("writing fixture counts: %v", err)
+	}
+}
+
+func TestAPITokenEstimator(t *testing.T) {
+	var path, body string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_ = json.NewEncoder(w).Encode(map[string]any{"totalTokens": 1234})
+	})
+
+	count, err := APITokenEstimator{Client: client}.EstimateTokens(context.Background(), "gemini-2.0-flash", []byte("hello world"))
+	if err != nil {
+		t.Fatalf("EstimateTokens: %v", err)
+	}
+	if count != 1234 {
+		t.Errorf("count = %d, want 1234", count)
+	}
+	if !strings.HasSuffix(path, "gemini-2.0-flash:countTokens") {
+		t.Errorf("request path = %q, want a countTokens call", path)
+	}
+	if !strings.Contains(body, "hello world") {
+		t.Errorf("request body %q does not contain the content", body)
+	}
+}
+
+func TestCountFileTokens(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	upload := fakeUploadHandler(&inFlight, &maxInFlight)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":countTokens") {
+			upload(w, r)
+			return
+		}
+		// Count one token per byte of the file URI so each file gets a distinct count
+		var req struct {
+			Contents []struct {
+				Parts []struct {
+					FileData struct {
+						FileURI string `json:"fileUri"`
+					} `json:"fileData"`
+				} `json:"parts"`
+			} `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		uri := req.Contents[0].Parts[0].FileData.FileURI
+		_ = json.NewEncoder(w).Encode(map[string]any{"totalTokens": len(uri)})
+	})
+
+	paths := writeUploadFiles(t, "short", "a-longer-file")
+	counts, err := client.CountFileTokens(context.Background(), "gemini-2.0-flash", paths)
+	if err != nil {
+		t.Fatalf("CountFileTokens: %v", err)
+	}
+	if len(counts) != 2 {
+		t.Fatalf("got %d counts, want 2", len(counts))
+	}
+	for i, content := range []string{"short", "a-longer-file"} {
+		want := len("https://files.example/" + content)
+		if counts[i].Path != paths[i] || counts[i].Tokens != want {
+			t.Errorf("count %d = %+v, want %s with %d tokens", i, counts[i], paths[i], want)
+		}
+	}
+}
+
+// fixedEstimator reports the same count for every file
+type fixedEstimator int
+
+func (f fixedEstimator) EstimateTokens(context.Context, string, []byte) (int, error) {
+	return int(f), nil
+}
+
+func TestGetOrCreateCacheUsesEstimator(t *testing.T) {
+	dir := t.TempDir()
+	cm := NewCacheManager(dir)
+
+	// Large enough for the heuristic, but the estimator says it's below the minimum
+	file := filepath.Join(dir, "cold.md")
+	if err := os.WriteFile(file, []byte(strings.Repeat("x", config.DefaultMinCacheTokens*8)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheInfo, created, err := cm.GetOrCreateCache(context.Background(), nil, "gemini-pro", []string{file}, time.Hour, false, false, false, true, fixedEstimator(100))
+	if err != nil {
+		t.Fatalf("GetOrCreateCache: %v", err)
+	}
+	if cacheInfo != nil || created {
+		t.Errorf("expected no cache when the estimator reports too few tokens, got %+v", cacheInfo)
+	}
+}
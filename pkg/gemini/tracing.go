@@ -0,0 +1,69 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans this package creates
+const tracerName = "github.com/grovetools/grove-gemini/pkg/gemini"
+
+// tracer creates spans for Gemini API operations. It starts out on the global
+// OpenTelemetry tracer provider, so spans are no-ops unless the host
+// application has configured one or the CLI called SetupTracing.
+var tracer = otel.Tracer(tracerName)
+
+// SetupTracing exports spans over OTLP/HTTP when OTEL_EXPORTER_OTLP_ENDPOINT
+// or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set. The exporter reads the rest of
+// the standard OTEL_* variables (headers, protocol options, OTEL_SERVICE_NAME)
+// itself. The returned shutdown flushes pending spans and must be called
+// before the process exits; it is a no-op when tracing isn't configured.
+func SetupTracing(ctx context.Context) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return noop, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", "grove-gemini")),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("creating trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	setTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// setTracerProvider makes provider the source of this package's spans and the
+// global provider. The package tracer is replaced directly because a tracer
+// taken from the global provider only follows the first provider installed.
+func setTracerProvider(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	tracer = provider.Tracer(tracerName)
+}
+
+// recordSpanError marks a span as failed with the given error
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
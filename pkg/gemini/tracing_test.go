@@ -0,0 +1,106 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// restoreTracer puts back the global provider and package tracer after the test
+func restoreTracer(t *testing.T) {
+	t.Helper()
+	prevGlobal, prevTracer := otel.GetTracerProvider(), tracer
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevGlobal)
+		tracer = prevTracer
+	})
+}
+
+// recordSpans routes this package's spans to an in-memory recorder for the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	restoreTracer(t)
+	recorder := tracetest.NewSpanRecorder()
+	setTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+}
+
+func TestGenerateRecordsSpan(t *testing.T) {
+	recorder := recordSpans(t)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []any{map[string]any{
+				"content":      map[string]any{"role": "model", "parts": []any{map[string]any{"text": "ok"}}},
+				"finishReason": "STOP",
+			}},
+		})
+	})
+
+	if _, _, err := client.Generate(context.Background(), "gemini-2.0-flash", "hi", nil); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "gemini.Generate" {
+		t.Fatalf("expected one gemini.Generate span, got %d", len(spans))
+	}
+	want := attribute.String("gemini.model", "gemini-2.0-flash")
+	found := false
+	for _, attr := range spans[0].Attributes() {
+		found = found || attr == want
+	}
+	if !found {
+		t.Errorf("span attributes %v missing %v", spans[0].Attributes(), want)
+	}
+}
+
+func TestSetupTracing(t *testing.T) {
+	t.Run("no endpoint", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+		t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+		prevTracer := tracer
+		shutdown, err := SetupTracing(context.Background())
+		if err != nil {
+			t.Fatalf("SetupTracing() error = %v", err)
+		}
+		if err := shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown() error = %v", err)
+		}
+		if tracer != prevTracer {
+			t.Error("expected the tracer to be left alone without an endpoint")
+		}
+	})
+
+	t.Run("exports on shutdown", func(t *testing.T) {
+		var exports atomic.Int32
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/traces" {
+				exports.Add(1)
+			}
+		}))
+		defer collector.Close()
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collector.URL)
+		restoreTracer(t)
+
+		shutdown, err := SetupTracing(context.Background())
+		if err != nil {
+			t.Fatalf("SetupTracing() error = %v", err)
+		}
+		_, span := tracer.Start(context.Background(), "test")
+		span.End()
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown() error = %v", err)
+		}
+		if exports.Load() == 0 {
+			t.Error("expected spans to be exported to the collector on shutdown")
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package gemini
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrashDirName is the directory inside the cache directory that holds cleared records
+const TrashDirName = ".trash"
+
+// TrashRetention is how long trashed records are kept before being purged
+const TrashRetention = 7 * 24 * time.Hour
+
+// TrashedCache is a cache record that was moved to the trash by 'cache clear --trash'
+type TrashedCache struct {
+	Path      string
+	Info      *CacheInfo
+	TrashedAt time.Time
+}
+
+// TrashDir returns the trash directory for a cache directory
+func TrashDir(cacheDir string) string {
+	return filepath.Join(cacheDir, TrashDirName)
+}
+
+// TrashCacheRecord moves a cache record into the trash instead of deleting it,
+// so it can be recovered with RestoreCacheRecord. The record's modification
+// time is bumped to mark when it was trashed.
+func TrashCacheRecord(cacheDir, path string) error {
+	trashDir := TrashDir(cacheDir)
+	if err := os.MkdirAll(trashDir, 0o755); err != nil { //nolint:gosec // cache dir needs to be traversable
+		return fmt.Errorf("creating trash directory: %w", err)
+	}
+
+	dest := filepath.Join(trashDir, filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil {
+		return fmt.Errorf("moving cache record to trash: %w", err)
+	}
+	now := time.Now()
+	if err := os.Chtimes(dest, now, now); err != nil {
+		return fmt.Errorf("stamping trashed cache record: %w", err)
+	}
+	return nil
+}
+
+// ListTrashedCaches returns the records in the trash, most recently trashed first.
+// Records that can't be read are skipped.
+func ListTrashedCaches(cacheDir string) ([]TrashedCache, error) {
+	trashDir := TrashDir(cacheDir)
+	entries, err := os.ReadDir(trashDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading trash directory: %w", err)
+	}
+
+	var trashed []TrashedCache
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "hybrid_") || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(trashDir, entry.Name())
+		stat, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		info, err := LoadCacheInfo(path)
+		if err != nil {
+			continue
+		}
+		trashed = append(trashed, TrashedCache{Path: path, Info: info, TrashedAt: stat.ModTime()})
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].TrashedAt.After(trashed[j].TrashedAt)
+	})
+	return trashed, nil
+}
+
+// RestoreCacheRecord moves a trashed record back into the cache directory.
+// It refuses to overwrite a record that has since been recreated.
+func RestoreCacheRecord(cacheDir, cacheName string) error {
+	fileName := "hybrid_" + cacheName + ".json"
+	src := filepath.Join(TrashDir(cacheDir), fileName)
+	if _, err := os.Stat(src); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("cache '%s' is not in the trash", cacheName)
+		}
+		return err
+	}
+
+	dest := filepath.Join(cacheDir, fileName)
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("cache '%s' already exists; remove it before restoring", cacheName)
+	}
+	if err := os.Rename(src, dest); err != nil {
+		return fmt.Errorf("restoring cache record: %w", err)
+	}
+	return nil
+}
+
+// PurgeTrash permanently deletes trashed records older than maxAge and
+// returns how many were removed.
+func PurgeTrash(cacheDir string, maxAge time.Duration) (int, error) {
+	trashed, err := ListTrashedCaches(cacheDir)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	cutoff := time.Now().Add(-maxAge)
+	for _, t := range trashed {
+		if t.TrashedAt.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(t.Path); err != nil {
+			return purged, fmt.Errorf("purging %s: %w", filepath.Base(t.Path), err)
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,89 @@
+package gemini
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrashAndRestoreCacheRecord(t *testing.T) {
+	cacheDir := t.TempDir()
+	path := filepath.Join(cacheDir, "hybrid_abc123.json")
+	if err := SaveCacheInfo(path, &CacheInfo{CacheName: "abc123", CacheID: "cachedContents/abc123", Model: "gemini-2.0-flash"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := TrashCacheRecord(cacheDir, path); err != nil {
+		t.Fatalf("TrashCacheRecord: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected record to leave the cache directory, stat err = %v", err)
+	}
+
+	trashed, err := ListTrashedCaches(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 1 || trashed[0].Info.CacheID != "cachedContents/abc123" {
+		t.Fatalf("Expected one trashed record, got %+v", trashed)
+	}
+	if time.Since(trashed[0].TrashedAt) > time.Minute {
+		t.Errorf("Expected trash time to be stamped, got %v", trashed[0].TrashedAt)
+	}
+
+	// A record recreated under the same name must not be overwritten
+	if err := SaveCacheInfo(path, &CacheInfo{CacheName: "abc123"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreCacheRecord(cacheDir, "abc123"); err == nil {
+		t.Error("Expected restore to refuse overwriting an existing record")
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RestoreCacheRecord(cacheDir, "abc123"); err != nil {
+		t.Fatalf("RestoreCacheRecord: %v", err)
+	}
+	info, err := LoadCacheInfo(path)
+	if err != nil {
+		t.Fatalf("Expected restored record to be readable: %v", err)
+	}
+	if info.CacheID != "cachedContents/abc123" {
+		t.Errorf("Restored wrong record: %+v", info)
+	}
+
+	if err := RestoreCacheRecord(cacheDir, "missing"); err == nil {
+		t.Error("Expected error restoring a record that isn't in the trash")
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	cacheDir := t.TempDir()
+	for _, name := range []string{"old", "new"} {
+		path := filepath.Join(cacheDir, "hybrid_"+name+".json")
+		if err := SaveCacheInfo(path, &CacheInfo{CacheName: name}); err != nil {
+			t.Fatal(err)
+		}
+		if err := TrashCacheRecord(cacheDir, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * TrashRetention)
+	if err := os.Chtimes(filepath.Join(TrashDir(cacheDir), "hybrid_old.json"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := PurgeTrash(cacheDir, TrashRetention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 purged record, got %d", purged)
+	}
+	trashed, _ := ListTrashedCaches(cacheDir)
+	if len(trashed) != 1 || trashed[0].Info.CacheName != "new" {
+		t.Errorf("Expected only the recent record to remain, got %+v", trashed)
+	}
+}
@@ -2,20 +2,29 @@ package gemini
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/grovetools/grove-gemini/pkg/pretty"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/genai"
 )
 
+// DefaultUploadConcurrency is how many files are uploaded at once when no limit is set
+const DefaultUploadConcurrency = 4
+
 // FileUploadResult contains information about an uploaded file
 type FileUploadResult struct {
 	FilePath   string
 	FileURI    string
 	MIMEType   string
 	DurationMs int64
+	Reused     bool // An earlier upload of identical content was reused
 }
 
 // uploadFile uploads a single file to the Gemini API and logs completion
@@ -39,7 +48,7 @@ func uploadFileQuiet(ctx context.Context, client *genai.Client, filePath string)
 		ctx,
 		filePath,
 		&genai.UploadFileConfig{
-			MIMEType: detectMIMEType(filePath),
+			MIMEType: FileMIMEType(filePath),
 		},
 	)
 	if err != nil {
@@ -49,10 +58,140 @@ func uploadFileQuiet(ctx context.Context, client *genai.Client, filePath string)
 	return f, time.Since(uploadStart), nil
 }
 
-// detectMIMEType returns appropriate MIME type for a file
+// inlineFileParts reads files into inline data parts, in order. Vertex AI has
+// no Files API, so files are sent with the request instead of uploaded; the
+// whole request must then fit within its inline size limit.
+func inlineFileParts(files []string) ([]*genai.Part, error) {
+	parts := make([]*genai.Part, 0, len(files))
+	for _, path := range files {
+		data, err := os.ReadFile(path) //nolint:gosec // path is a context file chosen by the user or rules
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		parts = append(parts, genai.NewPartFromBytes(data, FileMIMEType(path)))
+	}
+	return parts, nil
+}
+
+// uploadFiles uploads files with at most concurrency uploads in flight and
+// returns the results in the same order as files. Files already uploaded with
+// the same content are reused from index when it is non-nil. The first failure
+// cancels the uploads still running and is returned.
+func uploadFiles(ctx context.Context, client *genai.Client, index *UploadIndex, files []string, concurrency int) ([]FileUploadResult, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultUploadConcurrency
+	}
+
+	results := make([]FileUploadResult, len(files))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i, filePath := range files {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			f, duration, reused, err := uploadFileReusing(gctx, client, index, filePath)
+			if err != nil {
+				return fmt.Errorf("failed to upload file %s: %w", filePath, err)
+			}
+			// Each goroutine writes only its own slot
+			results[i] = FileUploadResult{
+				FilePath:   filePath,
+				FileURI:    f.URI,
+				MIMEType:   f.MIMEType,
+				DurationMs: duration.Milliseconds(),
+				Reused:     reused,
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// attachmentMIMETypes are the image and document formats Gemini accepts as attachments
+var attachmentMIMETypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/webp":      true,
+	"image/heic":      true,
+	"image/heif":      true,
+	"application/pdf": true,
+}
+
+// IsAttachmentMIMEType reports whether mimeType can be sent with --attach
+func IsAttachmentMIMEType(mimeType string) bool {
+	return attachmentMIMETypes[mimeType]
+}
+
+// isTextMIMEType reports whether files of mimeType hold text that token heuristics apply to
+func isTextMIMEType(mimeType string) bool {
+	switch mimeType {
+	case "application/json", "application/xml":
+		return true
+	}
+	return strings.HasPrefix(mimeType, "text/")
+}
+
+// FileMIMEType detects the MIME type of a file from its content, so images and
+// PDFs are recognised whatever their name, and falls back to the extension for
+// text formats that can't be told apart by content
+func FileMIMEType(filePath string) string {
+	byExt := detectMIMEType(filePath)
+
+	f, err := os.Open(filePath) //nolint:gosec // path was resolved for the request
+	if err != nil {
+		return byExt
+	}
+	defer func() { _ = f.Close() }()
+	head := make([]byte, 512) // http.DetectContentType considers at most 512 bytes
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return byExt
+	}
+
+	sniffed, _, _ := strings.Cut(http.DetectContentType(head[:n]), ";")
+	switch {
+	case !isTextMIMEType(sniffed) && sniffed != "application/octet-stream":
+		return sniffed
+	case !isTextMIMEType(byExt) && strings.HasPrefix(sniffed, "text/"):
+		// Named like a binary format but holds text
+		return "text/plain"
+	}
+	return byExt
+}
+
+// nonTextMIMETypes returns the MIME type of each file that isn't text, keyed by path
+func nonTextMIMETypes(files []string) map[string]string {
+	types := make(map[string]string)
+	for _, path := range files {
+		if mimeType := FileMIMEType(path); !isTextMIMEType(mimeType) {
+			types[path] = mimeType
+		}
+	}
+	return types
+}
+
+// detectMIMEType returns the MIME type for a file based on its extension
 func detectMIMEType(filePath string) string {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
+	case ".png":
+		return "image/png"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".webp":
+		return "image/webp"
+	case ".heic":
+		return "image/heic"
+	case ".heif":
+		return "image/heif"
+	case ".gif":
+		return "image/gif"
+	case ".pdf":
+		return "application/pdf"
 	case ".txt", ".text":
 		return "text/plain"
 	case ".md", ".markdown":
@@ -0,0 +1,168 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// uploadIndexFileName is the upload index stored in the gemini cache directory
+const uploadIndexFileName = "uploads.json"
+
+// uploadedFileTTL is how long the Files API keeps an upload when it doesn't report an expiry
+const uploadedFileTTL = 48 * time.Hour
+
+// uploadReuseMargin keeps entries that are about to expire from being reused,
+// so a file doesn't disappear between the lookup and the generate call
+const uploadReuseMargin = time.Hour
+
+// uploadIndexEntry records a server file uploaded for a particular content hash
+type uploadIndexEntry struct {
+	Name      string    `json:"name"`
+	URI       string    `json:"uri"`
+	MIMEType  string    `json:"mime_type"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UploadIndex maps file content hashes to files already uploaded to the Files API,
+// so unchanged context files can be reused instead of uploaded again.
+// It is safe for concurrent use.
+type UploadIndex struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]uploadIndexEntry
+	dirty   bool
+}
+
+// LoadUploadIndex reads the upload index in cacheDir. A missing or unreadable
+// index starts out empty.
+func LoadUploadIndex(cacheDir string) *UploadIndex {
+	index := &UploadIndex{
+		path:    filepath.Join(cacheDir, uploadIndexFileName),
+		entries: make(map[string]uploadIndexEntry),
+	}
+	data, err := os.ReadFile(index.path) //nolint:gosec // path is inside the cache directory
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, &index.entries); err != nil {
+		index.entries = make(map[string]uploadIndexEntry)
+	}
+	return index
+}
+
+// lookup returns the entry for hash if it won't expire within uploadReuseMargin
+func (x *UploadIndex) lookup(hash string) (uploadIndexEntry, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	entry, ok := x.entries[hash]
+	if !ok || time.Now().Add(uploadReuseMargin).After(entry.ExpiresAt) {
+		return uploadIndexEntry{}, false
+	}
+	return entry, true
+}
+
+// record stores a freshly uploaded file under hash
+func (x *UploadIndex) record(hash string, f *genai.File) {
+	expiresAt := f.ExpirationTime
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(uploadedFileTTL)
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries[hash] = uploadIndexEntry{Name: f.Name, URI: f.URI, MIMEType: f.MIMEType, ExpiresAt: expiresAt}
+	x.dirty = true
+}
+
+// forget drops the entry for hash, e.g. after the API rejected its file
+func (x *UploadIndex) forget(hash string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.entries[hash]; ok {
+		delete(x.entries, hash)
+		x.dirty = true
+	}
+}
+
+// Save writes the index back to disk if it changed, dropping expired entries
+func (x *UploadIndex) Save() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.dirty {
+		return nil
+	}
+	now := time.Now()
+	for hash, entry := range x.entries {
+		if now.After(entry.ExpiresAt) {
+			delete(x.entries, hash)
+		}
+	}
+
+	data, err := json.MarshalIndent(x.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling upload index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(x.path), 0o755); err != nil { //nolint:gosec // cache dir needs to be traversable
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	tempFile := x.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o644); err != nil { //nolint:gosec // cache files need to be readable
+		return fmt.Errorf("writing upload index: %w", err)
+	}
+	if err := os.Rename(tempFile, x.path); err != nil {
+		_ = os.Remove(tempFile) // best-effort cleanup
+		return fmt.Errorf("renaming upload index: %w", err)
+	}
+	x.dirty = false
+	return nil
+}
+
+// ClearUploadIndex deletes the upload index in cacheDir and returns how many
+// entries it held. Uploaded files are left to expire on the server.
+func ClearUploadIndex(cacheDir string) (int, error) {
+	index := LoadUploadIndex(cacheDir)
+	if err := os.Remove(index.path); err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("removing upload index: %w", err)
+	}
+	return len(index.entries), nil
+}
+
+// uploadFileReusing returns a previously uploaded file with the same content
+// when the index has one the API still accepts, and uploads the file otherwise.
+// The bool reports whether an earlier upload was reused. A nil index always uploads.
+func uploadFileReusing(ctx context.Context, client *genai.Client, index *UploadIndex, filePath string) (*genai.File, time.Duration, bool, error) {
+	if index == nil {
+		f, duration, err := uploadFileQuiet(ctx, client, filePath)
+		return f, duration, false, err
+	}
+
+	hash, err := hashFile(filePath)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	if entry, ok := index.lookup(hash); ok {
+		// The file may have been deleted server-side; only reuse it if the API still has it
+		f, err := client.Files.Get(ctx, entry.Name, nil)
+		if err == nil && f.State == genai.FileStateActive {
+			return &genai.File{Name: entry.Name, URI: entry.URI, MIMEType: entry.MIMEType, ExpirationTime: entry.ExpiresAt}, 0, true, nil
+		}
+		index.forget(hash)
+	}
+
+	f, duration, err := uploadFileQuiet(ctx, client, filePath)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	index.record(hash, f)
+	return f, duration, false, nil
+}
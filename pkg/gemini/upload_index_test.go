@@ -0,0 +1,139 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// fakeFilesAPI serves uploads and file lookups, tracking which files still exist
+type fakeFilesAPI struct {
+	uploads atomic.Int32
+	mu      sync.Mutex
+	files   map[string]bool
+}
+
+func (f *fakeFilesAPI) handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		name := r.URL.Path[strings.Index(r.URL.Path, "files/"):]
+		f.mu.Lock()
+		exists := f.files[name]
+		f.mu.Unlock()
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": name, "state": "ACTIVE"})
+		return
+	}
+
+	if strings.Contains(r.Header.Get("X-Goog-Upload-Command"), "start") {
+		w.Header().Set("X-Goog-Upload-URL", "http://"+r.Host+"/session")
+		w.Header().Set("X-Goog-Upload-Status", "active")
+		_, _ = w.Write([]byte("{}"))
+		return
+	}
+
+	_, _ = io.ReadAll(r.Body)
+	name := fmt.Sprintf("files/upload-%d", f.uploads.Add(1))
+	f.mu.Lock()
+	f.files[name] = true
+	f.mu.Unlock()
+	w.Header().Set("X-Goog-Upload-Status", "final")
+	_ = json.NewEncoder(w).Encode(map[string]any{"file": map[string]any{
+		"name":     name,
+		"uri":      "https://files.example/" + name,
+		"mimeType": "text/plain",
+	}})
+}
+
+func TestUploadFileReusing(t *testing.T) {
+	api := &fakeFilesAPI{files: make(map[string]bool)}
+	client := newTestClient(t, api.handler).GetClient()
+	ctx := context.Background()
+
+	cacheDir := t.TempDir()
+	path := filepath.Join(t.TempDir(), "hot.md")
+	if err := os.WriteFile(path, []byte("hot context"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	index := LoadUploadIndex(cacheDir)
+	first, _, reused, err := uploadFileReusing(ctx, client, index, path)
+	if err != nil || reused {
+		t.Fatalf("first upload: reused=%v err=%v", reused, err)
+	}
+	if err := index.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// A fresh process loads the index from disk and reuses the upload
+	index = LoadUploadIndex(cacheDir)
+	second, _, reused, err := uploadFileReusing(ctx, client, index, path)
+	if err != nil || !reused || second.URI != first.URI {
+		t.Fatalf("expected reuse of %s, got %+v reused=%v err=%v", first.URI, second, reused, err)
+	}
+	if n := api.uploads.Load(); n != 1 {
+		t.Errorf("uploads = %d, want 1", n)
+	}
+
+	// Changed content is uploaded again
+	if err := os.WriteFile(path, []byte("edited hot context"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, reused, err = uploadFileReusing(ctx, client, index, path); err != nil || reused {
+		t.Fatalf("changed content: reused=%v err=%v", reused, err)
+	}
+
+	// A file the API no longer has is forgotten and re-uploaded
+	api.mu.Lock()
+	api.files = make(map[string]bool)
+	api.mu.Unlock()
+	third, _, reused, err := uploadFileReusing(ctx, client, index, path)
+	if err != nil || reused {
+		t.Fatalf("rejected file: reused=%v err=%v", reused, err)
+	}
+	if n := api.uploads.Load(); n != 3 {
+		t.Errorf("uploads = %d, want 3", n)
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, ok := index.lookup(hash); !ok || entry.URI != third.URI {
+		t.Errorf("index should point at the new upload %s, got %+v", third.URI, entry)
+	}
+}
+
+func TestClearUploadIndex(t *testing.T) {
+	cacheDir := t.TempDir()
+	if removed, err := ClearUploadIndex(cacheDir); err != nil || removed != 0 {
+		t.Fatalf("empty index: removed=%d err=%v", removed, err)
+	}
+
+	index := LoadUploadIndex(cacheDir)
+	index.record("abc", &genai.File{Name: "files/a", URI: "https://files.example/a"})
+	index.record("def", &genai.File{Name: "files/b", URI: "https://files.example/b"})
+	if err := index.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := ClearUploadIndex(cacheDir)
+	if err != nil || removed != 2 {
+		t.Fatalf("removed=%d err=%v, want 2", removed, err)
+	}
+	if _, ok := LoadUploadIndex(cacheDir).lookup("abc"); ok {
+		t.Error("expected the index to be empty after cleaning")
+	}
+}
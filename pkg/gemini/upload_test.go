@@ -0,0 +1,137 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeUploadHandler serves the resumable upload protocol, naming each file
+// after its content. Content "fail" is rejected.
+func fakeUploadHandler(inFlight, maxInFlight *atomic.Int32) http.HandlerFunc {
+	var sessions atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("X-Goog-Upload-Command"), "start") {
+			w.Header().Set("X-Goog-Upload-URL", fmt.Sprintf("http://%s/session/%d", r.Host, sessions.Add(1)))
+			w.Header().Set("X-Goog-Upload-Status", "active")
+			_, _ = w.Write([]byte("{}"))
+			return
+		}
+
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Goog-Upload-Status", "final")
+		if string(body) == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "bad file"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"file": map[string]any{
+			"name":     "files/" + string(body),
+			"uri":      "https://files.example/" + string(body),
+			"mimeType": "text/plain",
+		}})
+	}
+}
+
+func writeUploadFiles(t *testing.T, contents ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, 0, len(contents))
+	for i, content := range contents {
+		path := filepath.Join(dir, fmt.Sprintf("%02d.txt", i))
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestUploadFilesPreservesOrder(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	client := newTestClient(t, fakeUploadHandler(&inFlight, &maxInFlight))
+
+	contents := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	paths := writeUploadFiles(t, contents...)
+
+	results, err := uploadFiles(context.Background(), client.GetClient(), nil, paths, 3)
+	if err != nil {
+		t.Fatalf("uploadFiles: %v", err)
+	}
+	if len(results) != len(paths) {
+		t.Fatalf("got %d results, want %d", len(results), len(paths))
+	}
+	for i, r := range results {
+		if r.FilePath != paths[i] || r.FileURI != "https://files.example/"+contents[i] {
+			t.Errorf("result %d = %+v, want %s uploaded as %s", i, r, paths[i], contents[i])
+		}
+	}
+	if peak := maxInFlight.Load(); peak > 3 {
+		t.Errorf("peak concurrent uploads = %d, want at most 3", peak)
+	} else if peak < 2 {
+		t.Errorf("peak concurrent uploads = %d, expected uploads to overlap", peak)
+	}
+}
+
+func TestUploadFilesReturnsFirstError(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	client := newTestClient(t, fakeUploadHandler(&inFlight, &maxInFlight))
+
+	paths := writeUploadFiles(t, "a", "fail", "c")
+	results, err := uploadFiles(context.Background(), client.GetClient(), nil, paths, 2)
+	if err == nil {
+		t.Fatalf("expected an error, got results %+v", results)
+	}
+	if !strings.Contains(err.Error(), paths[1]) {
+		t.Errorf("error %q should name the failing file %s", err, paths[1])
+	}
+}
+
+func TestFileMIMEType(t *testing.T) {
+	dir := t.TempDir()
+	pngHeader := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	tests := []struct {
+		name, content, want string
+	}{
+		{"shot.png", pngHeader, "image/png"},
+		{"shot.txt", pngHeader, "image/png"}, // content wins over a misleading name
+		{"doc.pdf", "%PDF-1.7\n", "application/pdf"},
+		{"photo.heic", "\x00\x00\x00\x18ftypheic", "image/heic"}, // not sniffable, so the extension decides
+		{"notes.png", "just some notes", "text/plain"},
+		{"main.go", "package main\n", "text/x-go"},
+	}
+	var paths []string
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+		if got := FileMIMEType(path); got != tt.want {
+			t.Errorf("FileMIMEType(%s) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	nonText := nonTextMIMETypes(paths)
+	if len(nonText) != 4 || nonText[paths[1]] != "image/png" {
+		t.Errorf("nonTextMIMETypes = %v, want the two PNGs, the PDF and the HEIC", nonText)
+	}
+}
@@ -0,0 +1,57 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
+)
+
+// TokenUsage is the token breakdown, estimated cost and cache hit rate of a
+// single Gemini response.
+type TokenUsage struct {
+	Model            string
+	CachedTokens     int     // Prompt tokens served from cached content
+	DynamicTokens    int     // Prompt tokens sent with the request (prompt minus cached)
+	PromptTokens     int     // All prompt tokens, cached and dynamic
+	CompletionTokens int     // Tokens across all returned candidates
+	TotalTokens      int     // Total reported by the API
+	CacheHitRate     float64 // Fraction of prompt tokens served from cache (0-1)
+	EstimatedCost    float64 // Estimated cost in USD
+}
+
+// ReportTokenUsage computes the token usage of a response generated by model.
+// It has no output side effects, so callers that make their own genai requests
+// can reuse the same accounting as this package; the usage is also recorded on
+// the span in ctx, if any. Cost estimates honour logging.SetPricingOverrides.
+func ReportTokenUsage(ctx context.Context, resp *genai.GenerateContentResponse, model string) (*TokenUsage, error) {
+	if resp == nil || resp.UsageMetadata == nil {
+		return nil, errors.New("response has no usage metadata")
+	}
+	meta := resp.UsageMetadata
+
+	usage := &TokenUsage{
+		Model:            model,
+		CachedTokens:     int(meta.CachedContentTokenCount),
+		PromptTokens:     int(meta.PromptTokenCount),
+		CompletionTokens: int(meta.CandidatesTokenCount),
+		TotalTokens:      int(meta.TotalTokenCount),
+		EstimatedCost:    logging.EstimateCostWithCache(model, meta.PromptTokenCount, meta.CandidatesTokenCount, meta.CachedContentTokenCount),
+	}
+	usage.DynamicTokens = usage.PromptTokens - usage.CachedTokens
+	if usage.PromptTokens > 0 {
+		usage.CacheHitRate = float64(usage.CachedTokens) / float64(usage.PromptTokens)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("gemini.cached_tokens", usage.CachedTokens),
+		attribute.Int("gemini.prompt_tokens", usage.PromptTokens),
+		attribute.Int("gemini.completion_tokens", usage.CompletionTokens),
+		attribute.Float64("gemini.cache_hit_rate", usage.CacheHitRate),
+	)
+
+	return usage, nil
+}
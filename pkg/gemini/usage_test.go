@@ -0,0 +1,70 @@
+package gemini
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/grovetools/grove-gemini/pkg/logging"
+	"google.golang.org/genai"
+)
+
+func TestReportTokenUsage(t *testing.T) {
+	logging.SetPricingOverrides(map[string]logging.ModelPricing{
+		"test-model": {InputPrice: 1.00, OutputPrice: 2.00, CachedDiscount: 0.75},
+	})
+	t.Cleanup(func() { logging.SetPricingOverrides(nil) })
+
+	resp := &genai.GenerateContentResponse{UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        100_000,
+		CachedContentTokenCount: 60_000,
+		CandidatesTokenCount:    10_000,
+		TotalTokenCount:         110_000,
+	}}
+
+	usage, err := ReportTokenUsage(context.Background(), resp, "test-model")
+	if err != nil {
+		t.Fatalf("ReportTokenUsage: %v", err)
+	}
+
+	if usage.CachedTokens != 60_000 || usage.DynamicTokens != 40_000 || usage.PromptTokens != 100_000 {
+		t.Errorf("Unexpected prompt split: cached=%d dynamic=%d prompt=%d", usage.CachedTokens, usage.DynamicTokens, usage.PromptTokens)
+	}
+	if usage.CompletionTokens != 10_000 || usage.TotalTokens != 110_000 {
+		t.Errorf("Unexpected completion/total: %d/%d", usage.CompletionTokens, usage.TotalTokens)
+	}
+	if usage.CacheHitRate != 0.6 {
+		t.Errorf("CacheHitRate = %v, want 0.6", usage.CacheHitRate)
+	}
+
+	// 60K cached at 25% of $1/M + 40K dynamic at $1/M + 10K output at $2/M
+	want := 0.015 + 0.04 + 0.02
+	if math.Abs(usage.EstimatedCost-want) > 1e-9 {
+		t.Errorf("EstimatedCost = %v, want %v", usage.EstimatedCost, want)
+	}
+}
+
+func TestReportTokenUsage_NoCache(t *testing.T) {
+	resp := &genai.GenerateContentResponse{UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     500,
+		CandidatesTokenCount: 50,
+		TotalTokenCount:      550,
+	}}
+
+	usage, err := ReportTokenUsage(context.Background(), resp, "gemini-2.0-flash")
+	if err != nil {
+		t.Fatalf("ReportTokenUsage: %v", err)
+	}
+	if usage.DynamicTokens != 500 || usage.CacheHitRate != 0 {
+		t.Errorf("Expected all prompt tokens to be dynamic, got dynamic=%d hit rate=%v", usage.DynamicTokens, usage.CacheHitRate)
+	}
+}
+
+func TestReportTokenUsage_NoMetadata(t *testing.T) {
+	if _, err := ReportTokenUsage(context.Background(), nil, "gemini-2.0-flash"); err == nil {
+		t.Error("Expected error for nil response")
+	}
+	if _, err := ReportTokenUsage(context.Background(), &genai.GenerateContentResponse{}, "gemini-2.0-flash"); err == nil {
+		t.Error("Expected error for response without usage metadata")
+	}
+}
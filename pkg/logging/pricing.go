@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/grovetools/grove-gemini/pkg/config"
+)
+
+var pricingOnce sync.Once
+
+// LoadPricingOverrides installs user-supplied prices for cost estimates: the
+// pricing table file (see config.ResolvePricingFile), then
+// gemini.pricing_overrides from the grove.yml that applies to workDir, which
+// wins for the same model pattern. It runs once per process; invalid entries
+// are skipped and reported in the returned error.
+func LoadPricingOverrides(workDir string) error {
+	var loadErr error
+	pricingOnce.Do(func() {
+		fileOverrides, fileErr := config.LoadPricingFile(config.ResolvePricingFile())
+		configOverrides, configErr := config.ResolvePricingOverrides(workDir)
+		loadErr = errors.Join(fileErr, configErr)
+
+		pricing := make(map[string]ModelPricing, len(fileOverrides)+len(configOverrides))
+		for _, overrides := range []map[string]config.PricingOverride{fileOverrides, configOverrides} {
+			for model, override := range overrides {
+				pricing[model] = modelPricingFrom(override)
+			}
+		}
+		if len(pricing) > 0 {
+			SetPricingOverrides(pricing)
+		}
+	})
+	return loadErr
+}
+
+// ResetPricing removes all pricing overrides and lets LoadPricingOverrides
+// load them again. It is meant for tests.
+func ResetPricing() {
+	SetPricingOverrides(nil)
+	pricingOnce = sync.Once{}
+}
+
+// modelPricingFrom converts a configured override into the prices used for estimates
+func modelPricingFrom(override config.PricingOverride) ModelPricing {
+	pricing := ModelPricing{
+		InputPrice:     override.Input,
+		OutputPrice:    override.Output,
+		CachedDiscount: override.Discount(),
+		StoragePrice:   override.Storage,
+	}
+	if lc := override.LongContext; lc != nil {
+		pricing.LongContextThreshold = int32(lc.Threshold) //nolint:gosec // thresholds are far below int32 limits
+		pricing.LongInputPrice = lc.Input
+		pricing.LongOutputPrice = lc.Output
+	}
+	return pricing
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/grovetools/core/pkg/paths"
+	"github.com/grovetools/grove-gemini/pkg/config"
+	"github.com/grovetools/grove-gemini/pkg/models"
 )
 
 // QueryLog represents a single API query log entry
@@ -24,11 +26,20 @@ type QueryLog struct {
 	CompletionTokens int32     `json:"completion_tokens"`
 	TotalTokens      int32     `json:"total_tokens"`
 	CacheHitRate     float64   `json:"cache_hit_rate"`
-	ResponseTime     float64   `json:"response_time_seconds"`
+	ResponseTime     float64   `json:"response_time_seconds"`         // Generation time, excluding file uploads
+	UploadTime       float64   `json:"upload_time_seconds,omitempty"` // Wall-clock time uploading files before generation
 	EstimatedCost    float64   `json:"estimated_cost_usd"`
 	Error            string    `json:"error,omitempty"`
 	CacheID          string    `json:"cache_id,omitempty"`
 	Success          bool      `json:"success"`
+	CandidateCount   int32     `json:"candidate_count,omitempty"`    // Number of candidates requested when more than one
+	Continuations    int       `json:"continuations,omitempty"`      // Auto-continue turns included in the token counts
+	ResponseCacheHit bool      `json:"response_cache_hit,omitempty"` // Answered from the local response cache; no API call or cost
+
+	// Cache creation is a one-time cost, tracked apart from EstimatedCost so
+	// steady-state query spend isn't skewed by the request that built the cache
+	IsNewCache        bool    `json:"is_new_cache,omitempty"`
+	CacheCreationCost float64 `json:"cache_creation_cost_usd,omitempty"`
 
 	// Context information
 	WorkingDir string `json:"working_dir,omitempty"`
@@ -36,12 +47,15 @@ type QueryLog struct {
 	GitBranch  string `json:"git_branch,omitempty"`
 	GitCommit  string `json:"git_commit,omitempty"`
 	Caller     string `json:"caller,omitempty"` // e.g., "grove-flow", "grove-gemini-request", "grove-gemini-count-tokens"
+
+	// User-defined labels for attributing usage (e.g., team=platform)
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // QueryLogger handles logging of API queries
 type QueryLogger struct {
 	mu       sync.Mutex
-	logFile  string
+	dir      string
 	disabled bool
 }
 
@@ -50,37 +64,56 @@ var (
 	once          sync.Once
 )
 
-// GetLogger returns the singleton query logger instance
+// GetLogger returns the singleton query logger instance. Its directory is
+// resolved once: GROVE_GEMINI_LOG_DIR, then gemini.log_dir in grove.yml,
+// then the grove state directory.
 func GetLogger() *QueryLogger {
 	once.Do(func() {
-		logPath, err := getLogPath()
+		logger, err := NewQueryLoggerAt(getLogDir())
 		if err != nil {
-			// If we can't create the log path, create a disabled logger
+			// If we can't create the log directory, create a disabled logger
 			defaultLogger = &QueryLogger{disabled: true}
 			return
 		}
-		defaultLogger = &QueryLogger{
-			logFile: logPath,
-		}
+		defaultLogger = logger
 	})
 	return defaultLogger
 }
 
-// getLogPath returns the path to the query log file
-func getLogPath() (string, error) {
+// NewQueryLoggerAt returns a query logger that writes to and reads from dir,
+// creating it if needed. Most callers should use GetLogger; this is for tests
+// and programs embedding grove-gemini that keep their own logs.
+func NewQueryLoggerAt(dir string) (*QueryLogger, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("could not determine query log directory")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil { //nolint:gosec // query log directory
+		return nil, err
+	}
+	return &QueryLogger{dir: dir}, nil
+}
+
+// getLogDir returns the configured query log directory, falling back to the
+// grove state directory
+func getLogDir() string {
+	if dir := config.ResolveLogDir(""); dir != "" {
+		return dir
+	}
 	stateDir := paths.StateDir()
 	if stateDir == "" {
-		return "", fmt.Errorf("could not determine grove state directory")
+		return ""
 	}
+	return filepath.Join(stateDir, "logs", "gemini")
+}
 
-	groveDir := filepath.Join(stateDir, "logs", "gemini")
-	if err := os.MkdirAll(groveDir, 0o750); err != nil { //nolint:gosec // grove state directory
-		return "", err
-	}
+// Dir returns the directory the logger reads and writes
+func (ql *QueryLogger) Dir() string {
+	return ql.dir
+}
 
-	// Use date-based log files for easy rotation
-	today := time.Now().Format("2006-01-02")
-	return filepath.Join(groveDir, fmt.Sprintf("query-log-%s.jsonl", today)), nil
+// logFileFor returns the log file for the given day; logs are split by date for easy rotation
+func (ql *QueryLogger) logFileFor(day time.Time) string {
+	return filepath.Join(ql.dir, fmt.Sprintf("query-log-%s.jsonl", day.Format("2006-01-02")))
 }
 
 // Log adds a new query log entry
@@ -93,7 +126,7 @@ func (ql *QueryLogger) Log(entry QueryLog) error {
 	defer ql.mu.Unlock()
 
 	// Open file in append mode
-	file, err := os.OpenFile(ql.logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644) //nolint:gosec // log files need to be readable
+	file, err := os.OpenFile(ql.logFileFor(time.Now()), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644) //nolint:gosec // log files need to be readable
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
 	}
@@ -122,8 +155,7 @@ func (ql *QueryLogger) ReadLogs(startTime, endTime time.Time) ([]QueryLog, error
 	// Check multiple days if time range spans multiple days
 	// Use date.Before(endTime.AddDate(0, 0, 1)) to include the end date
 	for date := startTime; date.Before(endTime.AddDate(0, 0, 1)); date = date.AddDate(0, 0, 1) {
-		dayStr := date.Format("2006-01-02")
-		logFile := filepath.Join(filepath.Dir(ql.logFile), fmt.Sprintf("query-log-%s.jsonl", dayStr))
+		logFile := ql.logFileFor(date)
 
 		if _, err := os.Stat(logFile); os.IsNotExist(err) {
 			continue
@@ -153,6 +185,60 @@ func (ql *QueryLogger) ReadLogs(startTime, endTime time.Time) ([]QueryLog, error
 	return allLogs, nil
 }
 
+// ModelPricing holds prices in USD per million tokens. CachedDiscount is the
+// fraction taken off the input price for cached tokens.
+type ModelPricing struct {
+	InputPrice     float64
+	OutputPrice    float64
+	CachedDiscount float64
+	// Prompts longer than LongContextThreshold tokens are billed at the long
+	// context prices instead (0 disables the tier; a zero price keeps the regular one)
+	LongContextThreshold int32
+	LongInputPrice       float64
+	LongOutputPrice      float64
+	// StoragePrice is the cache storage price per million tokens per hour
+	// (0 keeps the built-in rate)
+	StoragePrice float64
+}
+
+var (
+	pricingMu        sync.RWMutex
+	pricingOverrides map[string]ModelPricing
+)
+
+// SetPricingOverrides replaces the per-model prices consulted by
+// EstimateCostWithCache before its built-in table. Keys match a model name
+// exactly or, failing that, as a substring; the longest matching key wins.
+func SetPricingOverrides(overrides map[string]ModelPricing) {
+	pricingMu.Lock()
+	defer pricingMu.Unlock()
+	pricingOverrides = make(map[string]ModelPricing, len(overrides))
+	for model, pricing := range overrides {
+		pricingOverrides[strings.ToLower(model)] = pricing
+	}
+}
+
+// PricingOverrideFor returns the configured override that applies to model, if any
+func PricingOverrideFor(model string) (ModelPricing, bool) {
+	pricingMu.RLock()
+	defer pricingMu.RUnlock()
+
+	modelLower := strings.ToLower(model)
+	if pricing, ok := pricingOverrides[modelLower]; ok {
+		return pricing, true
+	}
+	best := ""
+	for key := range pricingOverrides {
+		if strings.Contains(modelLower, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return ModelPricing{}, false
+	}
+	return pricingOverrides[best], true
+}
+
 // EstimateCost calculates the estimated cost based on token usage
 // Cached tokens get a 75% discount on input pricing
 func EstimateCost(model string, promptTokens, completionTokens int32) float64 {
@@ -160,24 +246,31 @@ func EstimateCost(model string, promptTokens, completionTokens int32) float64 {
 }
 
 // EstimateCostWithCache calculates the estimated cost accounting for cached token discounts
-// Cached tokens get a 75% discount on input pricing
+// Cached tokens get a 75% discount on input pricing. Prices set with
+// SetPricingOverrides take precedence over the built-in table.
 func EstimateCostWithCache(model string, promptTokens, completionTokens, cachedTokens int32) float64 {
-	// Pricing as of Jan 2025 (per million tokens)
-	// GCP has different pricing for "long context" (>128K tokens) vs "short context"
+	if pricing, ok := PricingOverrideFor(model); ok {
+		return computeCost(pricing, promptTokens, completionTokens, cachedTokens)
+	}
+
+	// Known models are priced from the model table, including their long context tier
+	if inputPrice, outputPrice, ok := models.PricingFor(model, promptTokens); ok {
+		return computeCost(ModelPricing{InputPrice: inputPrice, OutputPrice: outputPrice, CachedDiscount: 0.75}, promptTokens, completionTokens, cachedTokens)
+	}
+
+	// Fall back to matching model name patterns (per million tokens)
 	var inputPrice, outputPrice float64
 
 	modelLower := strings.ToLower(model)
 
-	// Determine if this is a long context request (>200K tokens for Gemini 3.x pricing)
-	// We use total prompt tokens (including cached) for this determination
-	totalPromptTokens := promptTokens
-	isLongContext := totalPromptTokens > 128000
-	isLongContextGemini3 := totalPromptTokens > 200000
+	// Long context pricing applies when the whole prompt, cached tokens
+	// included, exceeds the threshold
+	isLongContext := promptTokens > models.LongContextThreshold
 
 	switch {
 	// Gemini 3.1 Pro models
 	case contains(modelLower, "gemini-3.1-pro"):
-		if isLongContextGemini3 {
+		if isLongContext {
 			inputPrice = 4.00
 			outputPrice = 18.00
 		} else {
@@ -187,7 +280,7 @@ func EstimateCostWithCache(model string, promptTokens, completionTokens, cachedT
 
 	// Gemini 3 Pro models
 	case contains(modelLower, "gemini-3-pro"):
-		if isLongContextGemini3 {
+		if isLongContext {
 			inputPrice = 4.00
 			outputPrice = 18.00
 		} else {
@@ -203,7 +296,7 @@ func EstimateCostWithCache(model string, promptTokens, completionTokens, cachedT
 	// Gemini 2.5 Pro models
 	case contains(modelLower, "gemini-2.5-pro"):
 		if isLongContext {
-			// Long context pricing (observed from GCP billing)
+			// Long context pricing
 			inputPrice = 2.50
 			outputPrice = 15.00
 		} else {
@@ -244,16 +337,60 @@ func EstimateCostWithCache(model string, promptTokens, completionTokens, cachedT
 		outputPrice = 0.40
 	}
 
-	// Calculate costs with cache discount
 	// Cached tokens get 75% discount
-	const cacheDiscount = 0.25 // Pay only 25% of the price for cached tokens
+	return computeCost(ModelPricing{InputPrice: inputPrice, OutputPrice: outputPrice, CachedDiscount: 0.75}, promptTokens, completionTokens, cachedTokens)
+}
+
+// EstimateCacheCreationCost estimates the one-time cost of ingesting cachedTokens
+// into a new cache, billed at the model's full input price
+func EstimateCacheCreationCost(model string, cachedTokens int32) float64 {
+	return EstimateCostWithCache(model, cachedTokens, 0, 0)
+}
+
+// DefaultStoragePrice is the cache storage price per million tokens per hour
+// used for models without a known rate
+const DefaultStoragePrice = 1.00
+
+// StoragePriceFor returns the cache storage price per million tokens per hour
+// for model: a pricing override's storage price, then the model table, then
+// DefaultStoragePrice
+func StoragePriceFor(model string) float64 {
+	if pricing, ok := PricingOverrideFor(model); ok && pricing.StoragePrice > 0 {
+		return pricing.StoragePrice
+	}
+	if price, ok := models.StoragePriceFor(model); ok {
+		return price
+	}
+	return DefaultStoragePrice
+}
+
+// EstimateCacheStorageCost estimates the cost of keeping cachedTokens in a
+// cache for duration. Gemini bills cache storage per token-hour, on top of
+// the one-time creation cost and the discounted reads.
+func EstimateCacheStorageCost(model string, cachedTokens int32, duration time.Duration) float64 {
+	if cachedTokens <= 0 || duration <= 0 {
+		return 0
+	}
+	return float64(cachedTokens) / 1_000_000 * StoragePriceFor(model) * duration.Hours()
+}
+
+// computeCost applies per-million-token prices to a request's token counts
+func computeCost(pricing ModelPricing, promptTokens, completionTokens, cachedTokens int32) float64 {
+	if pricing.LongContextThreshold > 0 && promptTokens > pricing.LongContextThreshold {
+		if pricing.LongInputPrice > 0 {
+			pricing.InputPrice = pricing.LongInputPrice
+		}
+		if pricing.LongOutputPrice > 0 {
+			pricing.OutputPrice = pricing.LongOutputPrice
+		}
+	}
 
 	// Separate dynamic tokens from cached tokens
 	dynamicTokens := promptTokens - cachedTokens
 
-	cachedCost := float64(cachedTokens) / 1_000_000 * inputPrice * cacheDiscount
-	dynamicCost := float64(dynamicTokens) / 1_000_000 * inputPrice
-	outputCost := float64(completionTokens) / 1_000_000 * outputPrice
+	cachedCost := float64(cachedTokens) / 1_000_000 * pricing.InputPrice * (1 - pricing.CachedDiscount)
+	dynamicCost := float64(dynamicTokens) / 1_000_000 * pricing.InputPrice
+	outputCost := float64(completionTokens) / 1_000_000 * pricing.OutputPrice
 
 	return cachedCost + dynamicCost + outputCost
 }
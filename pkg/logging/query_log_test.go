@@ -0,0 +1,227 @@
+package logging
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/grove-gemini/pkg/config"
+)
+
+func TestEstimateCostWithPricingOverrides(t *testing.T) {
+	defer SetPricingOverrides(nil)
+
+	builtIn := EstimateCostWithCache("gemini-2.5-pro", 100_000, 100_000, 0)
+	if math.Abs(builtIn-1.125) > 1e-9 {
+		t.Fatalf("Expected built-in gemini-2.5-pro cost 1.125, got %f", builtIn)
+	}
+
+	SetPricingOverrides(map[string]ModelPricing{
+		"gemini-2.5-pro":         {InputPrice: 2.00, OutputPrice: 20.00, CachedDiscount: 0.5},
+		"gemini-2.5-pro-preview": {InputPrice: 3.00, OutputPrice: 30.00, CachedDiscount: 0.5},
+	})
+
+	// 500K cached at half price + 500K dynamic + 1M output
+	got := EstimateCostWithCache("gemini-2.5-pro", 1_000_000, 1_000_000, 500_000)
+	if math.Abs(got-(0.5+1.0+20.0)) > 1e-9 {
+		t.Errorf("Expected override cost 21.5, got %f", got)
+	}
+
+	// The longest matching key wins for substring matches
+	pricing, ok := PricingOverrideFor("models/Gemini-2.5-Pro-Preview-05-06")
+	if !ok || pricing.InputPrice != 3.00 {
+		t.Errorf("Expected preview override, got %+v (ok=%v)", pricing, ok)
+	}
+
+	if _, ok := PricingOverrideFor("gemini-2.0-flash"); ok {
+		t.Error("Expected no override for gemini-2.0-flash")
+	}
+}
+
+func TestEstimateCacheCreationCost(t *testing.T) {
+	// 100K tokens ingested at the full gemini-2.5-pro input price of $1.25/M
+	got := EstimateCacheCreationCost("gemini-2.5-pro", 100_000)
+	if math.Abs(got-0.125) > 1e-9 {
+		t.Errorf("Expected creation cost 0.125, got %f", got)
+	}
+
+	// The same tokens served from the cache cost a quarter of that
+	served := EstimateCostWithCache("gemini-2.5-pro", 100_000, 0, 100_000)
+	if math.Abs(got-4*served) > 1e-9 {
+		t.Errorf("Expected creation cost to be 4x the cached cost, got %f vs %f", got, served)
+	}
+}
+
+func TestEstimateCacheStorageCost(t *testing.T) {
+	defer SetPricingOverrides(nil)
+
+	tests := []struct {
+		model    string
+		tokens   int32
+		duration time.Duration
+		want     float64
+	}{
+		// 1M tokens for 1h at the gemini-2.5-pro rate of $4.50 per million token-hours
+		{"gemini-2.5-pro", 1_000_000, time.Hour, 4.50},
+		// 200K tokens for 30m at $1.00
+		{"gemini-2.5-flash", 200_000, 30 * time.Minute, 0.10},
+		// Unknown models use the default rate
+		{"unknown-model", 500_000, 4 * time.Hour, 2.00},
+		{"gemini-2.5-pro", 0, time.Hour, 0},
+		{"gemini-2.5-pro", 1_000_000, 0, 0},
+	}
+	for _, tt := range tests {
+		got := EstimateCacheStorageCost(tt.model, tt.tokens, tt.duration)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCacheStorageCost(%q, %d, %s) = %f, want %f", tt.model, tt.tokens, tt.duration, got, tt.want)
+		}
+	}
+
+	// A storage price in an override replaces the table rate; without one the table applies
+	SetPricingOverrides(map[string]ModelPricing{
+		"gemini-2.5-pro":   {InputPrice: 1.25, OutputPrice: 10.00, StoragePrice: 2.00},
+		"gemini-2.5-flash": {InputPrice: 0.30, OutputPrice: 2.50},
+	})
+	if got := EstimateCacheStorageCost("gemini-2.5-pro", 1_000_000, 2*time.Hour); math.Abs(got-4.00) > 1e-9 {
+		t.Errorf("Expected override storage cost 4.00, got %f", got)
+	}
+	if got := StoragePriceFor("gemini-2.5-flash"); got != 1.00 {
+		t.Errorf("Expected table storage price 1.00 without an override rate, got %f", got)
+	}
+}
+
+func TestQueryLoggerAt(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	logger, err := NewQueryLoggerAt(dir)
+	if err != nil {
+		t.Fatalf("NewQueryLoggerAt failed: %v", err)
+	}
+	if logger.Dir() != dir {
+		t.Errorf("Expected dir %s, got %s", dir, logger.Dir())
+	}
+
+	now := time.Now()
+	if err := logger.Log(QueryLog{Timestamp: now, Model: "gemini-2.0-flash", Success: true}); err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("query-log-%s.jsonl", now.Format("2006-01-02")))); err != nil {
+		t.Errorf("Expected a dated log file in the configured directory: %v", err)
+	}
+
+	logs, err := logger.ReadLogs(now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ReadLogs failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Model != "gemini-2.0-flash" {
+		t.Errorf("Expected to read back the logged entry, got %+v", logs)
+	}
+}
+
+func TestGetLogDir_EnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.LogDirEnvVar, dir)
+	if got := getLogDir(); got != dir {
+		t.Errorf("Expected %s from %s, got %s", dir, config.LogDirEnvVar, got)
+	}
+}
+
+func TestEstimateCostLongContextTier(t *testing.T) {
+	defer SetPricingOverrides(nil)
+
+	SetPricingOverrides(map[string]ModelPricing{
+		"gemini-2.5-pro": {InputPrice: 1.00, OutputPrice: 10.00, LongContextThreshold: 200_000, LongInputPrice: 2.00, LongOutputPrice: 15.00},
+	})
+
+	short := EstimateCostWithCache("gemini-2.5-pro", 100_000, 100_000, 0)
+	if math.Abs(short-(0.1+1.0)) > 1e-9 {
+		t.Errorf("Expected regular prices below the threshold, got %f", short)
+	}
+	long := EstimateCostWithCache("gemini-2.5-pro", 1_000_000, 100_000, 0)
+	if math.Abs(long-(2.0+1.5)) > 1e-9 {
+		t.Errorf("Expected long context prices above the threshold, got %f", long)
+	}
+}
+
+func TestEstimateCostWithCache_LongContext(t *testing.T) {
+	// 1M output tokens so the output price shows up directly in the cost
+	tests := []struct {
+		name   string
+		model  string
+		prompt int32
+		want   float64
+	}{
+		{"2.5 pro at threshold", "gemini-2.5-pro", 200_000, 0.25 + 10.00},
+		{"2.5 pro above threshold", "gemini-2.5-pro", 400_000, 1.00 + 15.00},
+		{"3 pro below threshold", "gemini-3-pro-preview", 100_000, 0.20 + 12.00},
+		{"3 pro above threshold", "gemini-3-pro-preview", 400_000, 1.60 + 18.00},
+		{"3.1 pro below threshold", "gemini-3.1-pro-preview", 100_000, 0.20 + 12.00},
+		{"3.1 pro above threshold", "gemini-3.1-pro-preview", 400_000, 1.60 + 18.00},
+		{"flash has one tier", "gemini-2.5-flash", 400_000, 0.12 + 2.50},
+		// Names the model table doesn't know fall back to pattern matching
+		{"pattern below threshold", "publishers/google/gemini-2.5-pro", 200_000, 0.25 + 10.00},
+		{"pattern above threshold", "publishers/google/gemini-2.5-pro", 400_000, 1.00 + 15.00},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateCostWithCache(tt.model, tt.prompt, 1_000_000, 0)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EstimateCostWithCache(%s, %d) = %f, want %f", tt.model, tt.prompt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPricingOverrides(t *testing.T) {
+	ResetPricing()
+	defer ResetPricing()
+
+	pricingFile := filepath.Join(t.TempDir(), "pricing.yml")
+	table := `gemini-2.5-pro:
+  input: 1.5
+  output: 12
+  long_context:
+    threshold: 200000
+    input: 3
+broken-model:
+  input: -1
+`
+	if err := os.WriteFile(pricingFile, []byte(table), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.PricingFileEnvVar, pricingFile)
+
+	err := LoadPricingOverrides(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "broken-model") {
+		t.Errorf("Expected the invalid entry to be reported, got %v", err)
+	}
+
+	pricing, ok := PricingOverrideFor("gemini-2.5-pro")
+	if !ok || pricing.InputPrice != 1.5 || pricing.LongInputPrice != 3 || pricing.LongContextThreshold != 200_000 {
+		t.Errorf("Expected pricing from the file, got %+v (ok=%v)", pricing, ok)
+	}
+	if pricing.CachedDiscount != config.DefaultCachedDiscount {
+		t.Errorf("Expected the default cached discount, got %f", pricing.CachedDiscount)
+	}
+	if _, ok := PricingOverrideFor("broken-model"); ok {
+		t.Error("Expected the invalid entry to be skipped")
+	}
+
+	// Loading again is a no-op until reset
+	if err := os.WriteFile(pricingFile, []byte("gemini-2.5-pro:\n  input: 9\n  output: 9\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadPricingOverrides(""); err != nil {
+		t.Errorf("Expected no error from a repeated load, got %v", err)
+	}
+	if pricing, _ := PricingOverrideFor("gemini-2.5-pro"); pricing.InputPrice != 1.5 {
+		t.Errorf("Expected overrides to be loaded once, got %+v", pricing)
+	}
+	ResetPricing()
+	if _, ok := PricingOverrideFor("gemini-2.5-pro"); ok {
+		t.Error("Expected ResetPricing to clear overrides")
+	}
+}
@@ -1,6 +1,15 @@
 // Package models provides centralized model definitions for Google Gemini models.
 package models
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownModel is returned by ResolveModel for a name that matches no known model
+var ErrUnknownModel = errors.New("unknown model")
+
 // Model represents an LLM model with its metadata.
 type Model struct {
 	ID       string  // Full API model ID (e.g., "gemini-2.5-pro")
@@ -10,6 +19,19 @@ type Model struct {
 	Input    float64 // Input price per million tokens (short context)
 	Output   float64 // Output price per million tokens
 	Legacy   bool    // Whether this is a legacy model
+
+	// Prices per million tokens for prompts over LongContextThreshold tokens;
+	// zero when the model has a single price tier
+	InputLong  float64
+	OutputLong float64
+
+	// InputTokenLimit is the model's context window, which also bounds how
+	// many tokens a single cache can hold
+	InputTokenLimit int32
+
+	// Storage is the context caching storage price per million tokens per
+	// hour; zero when the model doesn't support caching
+	Storage float64
 }
 
 // DefaultModel is the recommended default model to use.
@@ -25,89 +47,112 @@ func Models() []Model {
 	return []Model{
 		// Gemini 3.1 models (preview)
 		{
-			ID:       "gemini-3.1-pro-preview",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Latest intelligent multimodal and agentic model",
-			Input:    2.00,  // $2.00 <=200k, $4.00 >200k
-			Output:   12.00, // $12.00 <=200k, $18.00 >200k
-			Legacy:   false,
+			ID:              "gemini-3.1-pro-preview",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Latest intelligent multimodal and agentic model",
+			Input:           2.00,
+			Output:          12.00,
+			InputLong:       4.00,
+			OutputLong:      18.00,
+			Legacy:          false,
+			InputTokenLimit: 1_048_576,
+			Storage:         4.50,
 		},
 		// Gemini 3 models (preview)
 		{
-			ID:       "gemini-3-pro-preview",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Most intelligent multimodal and agentic model",
-			Input:    2.00,  // $2.00 <=200k, $4.00 >200k
-			Output:   12.00, // $12.00 <=200k, $18.00 >200k
-			Legacy:   false,
+			ID:              "gemini-3-pro-preview",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Most intelligent multimodal and agentic model",
+			Input:           2.00,
+			Output:          12.00,
+			InputLong:       4.00,
+			OutputLong:      18.00,
+			Legacy:          false,
+			InputTokenLimit: 1_048_576,
+			Storage:         4.50,
 		},
 		{
-			ID:       "gemini-3-flash-preview",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Fastest intelligent model with search/grounding",
-			Input:    0.50,
-			Output:   3.00,
-			Legacy:   false,
+			ID:              "gemini-3-flash-preview",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Fastest intelligent model with search/grounding",
+			Input:           0.50,
+			Output:          3.00,
+			Legacy:          false,
+			InputTokenLimit: 1_048_576,
+			Storage:         1.00,
 		},
 		// Gemini 2.5 models (current stable)
 		{
-			ID:       "gemini-2.5-pro",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Advanced thinking model for complex problems",
-			Input:    1.25,  // $1.25 <=200k, $2.50 >200k
-			Output:   10.00, // $10.00 <=200k, $15.00 >200k
-			Legacy:   false,
+			ID:              "gemini-2.5-pro",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Advanced thinking model for complex problems",
+			Input:           1.25,
+			Output:          10.00,
+			InputLong:       2.50,
+			OutputLong:      15.00,
+			Legacy:          false,
+			InputTokenLimit: 1_048_576,
+			Storage:         4.50,
 		},
 		{
-			ID:       "gemini-2.5-flash",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Best price-performance, large scale processing",
-			Input:    0.30,
-			Output:   2.50,
-			Legacy:   false,
+			ID:              "gemini-2.5-flash",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Best price-performance, large scale processing",
+			Input:           0.30,
+			Output:          2.50,
+			Legacy:          false,
+			InputTokenLimit: 1_048_576,
+			Storage:         1.00,
 		},
 		{
-			ID:       "gemini-2.5-flash-lite",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Ultra-fast, cost-efficient, high throughput",
-			Input:    0.10,
-			Output:   0.40,
-			Legacy:   false,
+			ID:              "gemini-2.5-flash-lite",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Ultra-fast, cost-efficient, high throughput",
+			Input:           0.10,
+			Output:          0.40,
+			Legacy:          false,
+			InputTokenLimit: 1_048_576,
+			Storage:         1.00,
 		},
 		// Embedding models
 		{
-			ID:       "gemini-embedding-001",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Text embedding model, 3072 dimensions",
-			Input:    0.00, // Free tier / usage-based
-			Output:   0.00,
-			Legacy:   false,
+			ID:              "gemini-embedding-001",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Text embedding model, 3072 dimensions",
+			Input:           0.00, // Free tier / usage-based
+			Output:          0.00,
+			Legacy:          false,
+			InputTokenLimit: 2_048,
 		},
 		// Gemini 2.0 models (legacy)
 		{
-			ID:       "gemini-2.0-flash",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Second gen workhorse model (legacy)",
-			Input:    0.10,
-			Output:   0.40,
-			Legacy:   true,
+			ID:              "gemini-2.0-flash",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Second gen workhorse model (legacy)",
+			Input:           0.10,
+			Output:          0.40,
+			Legacy:          true,
+			InputTokenLimit: 1_048_576,
+			Storage:         1.00,
 		},
 		{
-			ID:       "gemini-2.0-flash-lite",
-			Alias:    "",
-			Provider: "Google",
-			Note:     "Second gen fast model (legacy)",
-			Input:    0.075,
-			Output:   0.30,
-			Legacy:   true,
+			ID:              "gemini-2.0-flash-lite",
+			Alias:           "",
+			Provider:        "Google",
+			Note:            "Second gen fast model (legacy)",
+			Input:           0.075,
+			Output:          0.30,
+			Legacy:          true,
+			InputTokenLimit: 1_048_576,
+			Storage:         1.00,
 		},
 	}
 }
@@ -157,3 +202,139 @@ func GetPricing(model string) (input, output float64) {
 	// Default to Pro pricing
 	return 1.25, 10.00
 }
+
+// PricingFor returns the input and output price per million tokens for a
+// request to model with promptTokens prompt tokens, applying the long context
+// tier above LongContextThreshold. Versioned IDs match the longest known model
+// ID they start with. ok is false for unknown models.
+func PricingFor(model string, promptTokens int32) (input, output float64, ok bool) {
+	model = strings.TrimPrefix(ResolveAlias(model), "models/")
+
+	var match *Model
+	all := Models()
+	for i := range all {
+		if strings.HasPrefix(model, all[i].ID) && (match == nil || len(all[i].ID) > len(match.ID)) {
+			match = &all[i]
+		}
+	}
+	if match == nil {
+		return 0, 0, false
+	}
+
+	input, output = match.Input, match.Output
+	if promptTokens > LongContextThreshold {
+		if match.InputLong > 0 {
+			input = match.InputLong
+		}
+		if match.OutputLong > 0 {
+			output = match.OutputLong
+		}
+	}
+	return input, output, true
+}
+
+// StoragePriceFor returns the cache storage price per million tokens per hour
+// for model. Versioned IDs match the longest known model ID they start with.
+// ok is false for unknown models and models without a storage price.
+func StoragePriceFor(model string) (price float64, ok bool) {
+	model = strings.TrimPrefix(ResolveAlias(model), "models/")
+
+	best := ""
+	for _, m := range Models() {
+		if strings.HasPrefix(model, m.ID) && len(m.ID) > len(best) {
+			best, price = m.ID, m.Storage
+		}
+	}
+	return price, price > 0
+}
+
+// MaxCacheTokens returns the largest number of tokens a cache can hold for a
+// model. Versioned IDs such as "gemini-2.5-pro-preview-05-06" match the
+// longest known model ID they start with. ok is false for unknown models.
+func MaxCacheTokens(model string) (limit int32, ok bool) {
+	model = strings.TrimPrefix(ResolveAlias(model), "models/")
+
+	best := ""
+	for _, m := range Models() {
+		if m.InputTokenLimit > 0 && strings.HasPrefix(model, m.ID) && len(m.ID) > len(best) {
+			best, limit = m.ID, m.InputTokenLimit
+		}
+	}
+	return limit, best != ""
+}
+
+// ModelsWithLargerContext returns current models whose context window is larger than limit
+func ModelsWithLargerContext(limit int32) []string {
+	var larger []string
+	for _, m := range CurrentModels() {
+		if m.InputTokenLimit > limit && !strings.Contains(m.ID, "embedding") {
+			larger = append(larger, m.ID)
+		}
+	}
+	return larger
+}
+
+// ResolveModel returns the known model for name, which may be an alias, carry
+// a "models/" prefix or be a versioned ID of a known model. For anything else
+// it returns ErrUnknownModel, suggesting the closest known ID when one is near.
+func ResolveModel(name string) (Model, error) {
+	id := strings.TrimPrefix(ResolveAlias(name), "models/")
+
+	var match *Model
+	all := Models()
+	for i := range all {
+		if strings.HasPrefix(id, all[i].ID) && (match == nil || len(all[i].ID) > len(match.ID)) {
+			match = &all[i]
+		}
+	}
+	if match != nil {
+		return *match, nil
+	}
+
+	if suggestion := closestModel(id); suggestion != "" {
+		return Model{}, fmt.Errorf("%w %q: did you mean %s?", ErrUnknownModel, name, suggestion)
+	}
+	return Model{}, fmt.Errorf("%w %q (run 'grove-gemini models list' to see known models)", ErrUnknownModel, name)
+}
+
+// closestModel returns the known model ID nearest to id by edit distance, or
+// "" when none is close enough to be a plausible typo. A known ID that id is
+// the start of, such as a preview model missing its suffix, wins outright.
+func closestModel(id string) string {
+	for _, m := range Models() {
+		if id != "" && strings.HasPrefix(m.ID, id) {
+			return m.ID
+		}
+	}
+	maxDistance := max(2, len(id)/4)
+	best, bestDistance := "", maxDistance+1
+	for _, m := range Models() {
+		if d := levenshtein(id, m.ID); d < bestDistance {
+			best, bestDistance = m.ID, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the number of single character insertions, deletions
+// and substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaxCacheTokens(t *testing.T) {
+	tests := []struct {
+		model  string
+		want   int32
+		wantOK bool
+	}{
+		{"gemini-2.5-pro", 1_048_576, true},
+		{"models/gemini-2.5-flash-lite", 1_048_576, true},
+		{"gemini-2.5-pro-preview-05-06", 1_048_576, true},
+		{"gemini-embedding-001", 2_048, true},
+		{"unknown-model", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := MaxCacheTokens(tt.model)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("MaxCacheTokens(%q) = %d, %v; want %d, %v", tt.model, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPricingFor(t *testing.T) {
+	tests := []struct {
+		model      string
+		tokens     int32
+		wantInput  float64
+		wantOutput float64
+		wantOK     bool
+	}{
+		{"gemini-2.5-pro", LongContextThreshold, 1.25, 10.00, true},
+		{"gemini-2.5-pro", LongContextThreshold + 1, 2.50, 15.00, true},
+		{"models/gemini-2.5-pro-preview-05-06", LongContextThreshold + 1, 2.50, 15.00, true},
+		{"gemini-3-pro-preview", 100_000, 2.00, 12.00, true},
+		{"gemini-3-pro-preview", 500_000, 4.00, 18.00, true},
+		{"gemini-3.1-pro-preview", 100_000, 2.00, 12.00, true},
+		{"gemini-3.1-pro-preview", 500_000, 4.00, 18.00, true},
+		// Single-tier models keep their price above the threshold
+		{"gemini-2.5-flash", 500_000, 0.30, 2.50, true},
+		{"gemini-2.5-flash-lite", 500_000, 0.10, 0.40, true},
+		{"unknown-model", 500_000, 0, 0, false},
+	}
+	for _, tt := range tests {
+		input, output, ok := PricingFor(tt.model, tt.tokens)
+		if input != tt.wantInput || output != tt.wantOutput || ok != tt.wantOK {
+			t.Errorf("PricingFor(%q, %d) = %v, %v, %v; want %v, %v, %v",
+				tt.model, tt.tokens, input, output, ok, tt.wantInput, tt.wantOutput, tt.wantOK)
+		}
+	}
+}
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantID      string
+		wantErr     bool
+		wantSuggest string
+	}{
+		{"gemini-2.5-pro", "gemini-2.5-pro", false, ""},
+		{"models/gemini-2.5-flash", "gemini-2.5-flash", false, ""},
+		{"gemini-2.5-pro-preview-05-06", "gemini-2.5-pro", false, ""},
+		{"gemini-2.5-flash-lite-001", "gemini-2.5-flash-lite", false, ""},
+		{"gemini-2.5-prro", "", true, "gemini-2.5-pro"},
+		{"gemni-2.5-flash", "", true, "gemini-2.5-flash"},
+		{"gemini-3-pro", "", true, "gemini-3-pro-preview"},
+		{"llama-3-70b", "", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ResolveModel(tt.name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownModel) {
+					t.Fatalf("ResolveModel(%q) error = %v, want ErrUnknownModel", tt.name, err)
+				}
+				hasSuggestion := strings.Contains(err.Error(), "did you mean")
+				if tt.wantSuggest == "" && hasSuggestion {
+					t.Errorf("ResolveModel(%q) suggested a model: %v", tt.name, err)
+				}
+				if tt.wantSuggest != "" && !strings.Contains(err.Error(), "did you mean "+tt.wantSuggest+"?") {
+					t.Errorf("ResolveModel(%q) error = %v, want suggestion %s", tt.name, err, tt.wantSuggest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveModel(%q) error = %v", tt.name, err)
+			}
+			if m.ID != tt.wantID {
+				t.Errorf("ResolveModel(%q) = %s, want %s", tt.name, m.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"gemini-2.5-pro", "gemini-2.5-pro", 0},
+		{"gemini-2.5-prro", "gemini-2.5-pro", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStoragePriceFor(t *testing.T) {
+	tests := []struct {
+		model  string
+		want   float64
+		wantOK bool
+	}{
+		{"gemini-2.5-pro", 4.50, true},
+		{"models/gemini-2.5-pro-preview-05-06", 4.50, true},
+		{"gemini-2.5-flash", 1.00, true},
+		{"gemini-2.5-flash-lite", 1.00, true},
+		{"gemini-3-pro-preview", 4.50, true},
+		// Models without caching have no storage price
+		{"gemini-embedding-001", 0, false},
+		{"unknown-model", 0, false},
+	}
+	for _, tt := range tests {
+		price, ok := StoragePriceFor(tt.model)
+		if price != tt.want || ok != tt.wantOK {
+			t.Errorf("StoragePriceFor(%q) = %v, %v; want %v, %v", tt.model, price, ok, tt.want, tt.wantOK)
+		}
+	}
+}
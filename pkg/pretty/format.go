@@ -0,0 +1,108 @@
+package pretty
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultThousandsSeparator groups digits in token counts and large costs
+const DefaultThousandsSeparator = ","
+
+var thousandsSeparator = DefaultThousandsSeparator
+
+// SetThousandsSeparator sets the digit grouping separator used by FormatTokens
+// and FormatCost, e.g. "." or " " for other locales. An empty string disables grouping.
+func SetThousandsSeparator(sep string) {
+	thousandsSeparator = sep
+}
+
+// integer covers the token count types used across the API and query logs
+type integer interface {
+	~int | ~int32 | ~int64
+}
+
+// FormatTokens formats a token count with digit grouping, e.g. 1,234,567
+func FormatTokens[T integer](n T) string {
+	return groupDigits(strconv.FormatInt(int64(n), 10))
+}
+
+// FormatTokensCompact formats a token count with a K or M suffix for narrow
+// columns, e.g. 1.23M or 45.6K. Counts under 1,000 are shown exactly.
+func FormatTokensCompact[T integer](n T) string {
+	v := float64(n)
+	switch abs := math.Abs(v); {
+	case abs >= 1_000_000:
+		return trimZeros(fmt.Sprintf("%.2f", v/1_000_000)) + "M"
+	case abs >= 1_000:
+		return trimZeros(fmt.Sprintf("%.1f", v/1_000)) + "K"
+	default:
+		return strconv.FormatInt(int64(n), 10)
+	}
+}
+
+// FormatCost formats a USD amount with precision suited to its magnitude:
+// cents for amounts of a dollar or more, and more decimal places for the
+// fractions of a cent typical of a single request.
+func FormatCost(cost float64) string {
+	var s string
+	switch abs := math.Abs(cost); {
+	case abs == 0:
+		return "$0.00"
+	case abs >= 1:
+		s = fmt.Sprintf("%.2f", cost)
+	case abs >= 0.01:
+		s = fmt.Sprintf("%.4f", cost)
+	default:
+		s = fmt.Sprintf("%.6f", cost)
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	return sign + "$" + groupDigits(whole) + "." + frac
+}
+
+// groupDigits inserts the thousands separator into a string of digits
+func groupDigits(digits string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if thousandsSeparator == "" || len(digits) <= 3 {
+		return sign + digits
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if i > 0 {
+			b.WriteString(thousandsSeparator)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// FormatLatency splits a request's wall-clock time into its file upload and
+// generation phases, e.g. "Upload: 0.42s / Generation: 3.10s / Total: 3.52s"
+func FormatLatency(upload, generation time.Duration) string {
+	return fmt.Sprintf("Upload: %.2fs / Generation: %.2fs / Total: %.2fs",
+		upload.Seconds(), generation.Seconds(), (upload + generation).Seconds())
+}
+
+// trimZeros drops trailing zeros after a decimal point, e.g. "1.50" -> "1.5"
+func trimZeros(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
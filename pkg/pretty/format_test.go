@@ -0,0 +1,86 @@
+package pretty
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTokens(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1234567, "1,234,567"},
+		{-45678, "-45,678"},
+	}
+	for _, tt := range tests {
+		if got := FormatTokens(tt.n); got != tt.want {
+			t.Errorf("FormatTokens(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+
+	defer SetThousandsSeparator(DefaultThousandsSeparator)
+	SetThousandsSeparator(".")
+	if got := FormatTokens(int32(1234567)); got != "1.234.567" {
+		t.Errorf("FormatTokens with '.' separator = %q", got)
+	}
+	SetThousandsSeparator("")
+	if got := FormatTokens(1234567); got != "1234567" {
+		t.Errorf("FormatTokens without grouping = %q", got)
+	}
+}
+
+func TestFormatTokensCompact(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{512, "512"},
+		{1000, "1K"},
+		{45600, "45.6K"},
+		{1234567, "1.23M"},
+		{2000000, "2M"},
+	}
+	for _, tt := range tests {
+		if got := FormatTokensCompact(tt.n); got != tt.want {
+			t.Errorf("FormatTokensCompact(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		cost float64
+		want string
+	}{
+		{0, "$0.00"},
+		{0.000123, "$0.000123"},
+		{0.0456, "$0.0456"},
+		{3.5, "$3.50"},
+		{12345.678, "$12,345.68"},
+		{-0.25, "-$0.2500"},
+	}
+	for _, tt := range tests {
+		if got := FormatCost(tt.cost); got != tt.want {
+			t.Errorf("FormatCost(%v) = %q, want %q", tt.cost, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLatency(t *testing.T) {
+	tests := []struct {
+		upload, generation time.Duration
+		want               string
+	}{
+		{0, 1500 * time.Millisecond, "Upload: 0.00s / Generation: 1.50s / Total: 1.50s"},
+		{420 * time.Millisecond, 3100 * time.Millisecond, "Upload: 0.42s / Generation: 3.10s / Total: 3.52s"},
+	}
+	for _, tt := range tests {
+		if got := FormatLatency(tt.upload, tt.generation); got != tt.want {
+			t.Errorf("FormatLatency(%v, %v) = %q, want %q", tt.upload, tt.generation, got, tt.want)
+		}
+	}
+}
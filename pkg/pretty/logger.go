@@ -29,7 +29,9 @@ type TokenFields struct {
 	CompletionTokens  int     `json:"completion_tokens" verbosity:"0"`   // metrics
 	UserPromptTokens  int     `json:"user_prompt_tokens" verbosity:"0"`  // metrics
 	TotalPromptTokens int     `json:"total_prompt_tokens" verbosity:"0"` // metrics
-	ResponseTimeMs    int64   `json:"response_time_ms" verbosity:"0"`    // metrics
+	ResponseTimeMs    int64   `json:"response_time_ms" verbosity:"0"`    // metrics - upload plus generation
+	UploadTimeMs      int64   `json:"upload_time_ms" verbosity:"0"`      // metrics
+	GenerationTimeMs  int64   `json:"generation_time_ms" verbosity:"0"`  // metrics
 	CacheHitRate      float64 `json:"cache_hit_rate" verbosity:"0"`      // metrics - percentage (0-100)
 	IsNewCache        bool    `json:"is_new_cache" verbosity:"0"`        // metrics
 }
@@ -39,6 +41,20 @@ type ModelFields struct {
 	Model string `json:"model" verbosity:"3"` // metrics
 }
 
+// quiet is set by --quiet. Output is already discarded through the global
+// writer; this additionally stops interactive prompts from waiting for input.
+var quiet bool
+
+// SetQuiet enables or disables quiet mode
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// IsQuiet reports whether quiet mode is enabled
+func IsQuiet() bool {
+	return quiet
+}
+
 // New creates a new Gemini-specific pretty logger.
 func New() *Logger {
 	return &Logger{
@@ -162,8 +178,9 @@ func (l *Logger) GeneratingResponse() {
 		Log(context.Background())
 }
 
-// FilesIncludedCtx displays the list of files that will be included in the request to the writer from the context
-func (l *Logger) FilesIncludedCtx(ctx context.Context, files []string) {
+// FilesIncludedCtx displays the list of files that will be included in the request to the writer from the context.
+// attachments maps the paths of non-text files (images, PDFs) to their MIME type so they stand out.
+func (l *Logger) FilesIncludedCtx(ctx context.Context, files []string, attachments map[string]string) {
 	if len(files) == 0 {
 		return
 	}
@@ -186,7 +203,9 @@ func (l *Logger) FilesIncludedCtx(ctx context.Context, files []string) {
 
 		// Show full path if it's a special file or prompt file
 		var displayItem string
-		if displayName == "CLAUDE.md" || displayName == "context" || displayName == "cached-context" {
+		if mimeType, ok := attachments[file]; ok {
+			displayItem = pathStyle.Render(displayName) + " " + l.theme.Info.Render("("+mimeType+")")
+		} else if displayName == "CLAUDE.md" || displayName == "context" || displayName == "cached-context" {
 			displayItem = pathStyle.Render(file)
 		} else if isPromptFile {
 			displayItem = pathStyle.Render(file) + " " + promptStyle.Render("(prompt)")
@@ -199,17 +218,45 @@ func (l *Logger) FilesIncludedCtx(ctx context.Context, files []string) {
 	l.ulog.Info("Files attached to request").
 		Field("files", files).
 		Field("count", len(files)).
+		Field("attachments", attachments).
 		Pretty(strings.Join(prettyLines, "\n")).
 		Log(ctx)
 }
 
 // FilesIncluded displays the list of files that will be included in the request
-func (l *Logger) FilesIncluded(files []string) {
-	l.FilesIncludedCtx(context.Background(), files)
+func (l *Logger) FilesIncluded(files []string, attachments map[string]string) {
+	l.FilesIncludedCtx(context.Background(), files, attachments)
 }
 
-// TokenUsageCtx displays token usage statistics in a styled box to the writer from the context
-func (l *Logger) TokenUsageCtx(ctx context.Context, cached, dynamic, completion, promptTokens int, responseTime time.Duration, isNewCache bool) {
+// TokenUsageCtx displays token usage statistics in a styled box to the writer from the context.
+// uploadTime is the wall-clock time spent uploading files (zero if none were
+// uploaded) and generationTime the time spent waiting on the model.
+func (l *Logger) TokenUsageCtx(ctx context.Context, cached, dynamic, completion, promptTokens int, uploadTime, generationTime time.Duration, isNewCache bool) {
+	totalPrompt := cached + dynamic
+	cacheHitRate := 0.0
+	if totalPrompt > 0 {
+		cacheHitRate = float64(cached) / float64(totalPrompt) * 100
+	}
+	box := l.TokenUsageBox(cached, dynamic, completion, promptTokens, uploadTime, generationTime, isNewCache)
+
+	l.ulog.Info("Gemini Response & Token Summary").
+		Field("cached_tokens", cached).
+		Field("dynamic_tokens", dynamic).
+		Field("completion_tokens", completion).
+		Field("user_prompt_tokens", promptTokens).
+		Field("total_prompt_tokens", totalPrompt).
+		Field("response_time_ms", (uploadTime+generationTime).Milliseconds()).
+		Field("upload_time_ms", uploadTime.Milliseconds()).
+		Field("generation_time_ms", generationTime.Milliseconds()).
+		Field("cache_hit_rate", cacheHitRate).
+		Field("is_new_cache", isNewCache).
+		Pretty(fmt.Sprintf("%s Token usage:\n%s", theme.IconChart, box)).
+		Log(ctx)
+}
+
+// TokenUsageBox renders the token usage box without logging it, for views
+// that draw it themselves
+func (l *Logger) TokenUsageBox(cached, dynamic, completion, promptTokens int, uploadTime, generationTime time.Duration, isNewCache bool) string {
 	// Calculate cache hit rate
 	totalPrompt := cached + dynamic
 	cacheHitRate := 0.0
@@ -235,17 +282,17 @@ func (l *Logger) TokenUsageCtx(ctx context.Context, cached, dynamic, completion,
 	content := []string{
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render(cachedLabel),
-			cachedStyle.Render(fmt.Sprintf("%d tokens", cached))),
+			cachedStyle.Render(FormatTokens(cached)+" tokens")),
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render("Hot (Dynamic):"),
-			l.theme.Normal.Render(fmt.Sprintf("%d tokens", dynamic))),
+			l.theme.Normal.Render(FormatTokens(dynamic)+" tokens")),
 	}
 
 	// Add user prompt tokens if available
 	if promptTokens > 0 {
 		content = append(content, fmt.Sprintf("%s %s",
 			l.theme.Muted.Render("User Prompt:"),
-			l.theme.Normal.Render(fmt.Sprintf("%d tokens", promptTokens))))
+			l.theme.Normal.Render(FormatTokens(promptTokens)+" tokens")))
 	}
 
 	divider := l.theme.Muted.Render(strings.Repeat("─", 32))
@@ -253,20 +300,18 @@ func (l *Logger) TokenUsageCtx(ctx context.Context, cached, dynamic, completion,
 		divider,
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render("Total Prompt:"),
-			l.theme.Normal.Render(fmt.Sprintf("%d tokens", totalPrompt))),
+			l.theme.Normal.Render(FormatTokens(totalPrompt)+" tokens")),
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render("Completion:"),
-			l.theme.Normal.Render(fmt.Sprintf("%d tokens", completion))),
+			l.theme.Normal.Render(FormatTokens(completion)+" tokens")),
 		divider,
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render("Total API Usage:"),
-			l.theme.Normal.Render(fmt.Sprintf("%d tokens", totalAPIUsage))),
+			l.theme.Normal.Render(FormatTokens(totalAPIUsage)+" tokens")),
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render(cacheHitRateLabel),
 			l.theme.Success.Render(fmt.Sprintf("%.1f%%", cacheHitRate))),
-		fmt.Sprintf("%s %s",
-			l.theme.Muted.Render("Response Time:"),
-			l.theme.Muted.Render(fmt.Sprintf("%.2fs", responseTime.Seconds()))),
+		l.theme.Muted.Render(FormatLatency(uploadTime, generationTime)),
 	}...)
 
 	// Join with newlines and apply box styling using theme
@@ -275,24 +320,61 @@ func (l *Logger) TokenUsageCtx(ctx context.Context, cached, dynamic, completion,
 		BorderForeground(l.theme.Colors.Violet).
 		Padding(0, 1)
 
-	box := tokenBox.Render(strings.Join(content, "\n"))
+	return tokenBox.Render(strings.Join(content, "\n"))
+}
 
-	l.ulog.Info("Gemini Response & Token Summary").
-		Field("cached_tokens", cached).
-		Field("dynamic_tokens", dynamic).
+// TokenUsage displays token usage statistics in a styled box
+func (l *Logger) TokenUsage(cached, dynamic, completion, promptTokens int, uploadTime, generationTime time.Duration, isNewCache bool) {
+	l.TokenUsageCtx(context.Background(), cached, dynamic, completion, promptTokens, uploadTime, generationTime, isNewCache)
+}
+
+// CandidatesCtx logs how many candidates were generated and their combined completion tokens
+func (l *Logger) CandidatesCtx(ctx context.Context, count, completion int) {
+	l.ulog.Info("Multiple candidates generated").
+		Field("candidate_count", count).
 		Field("completion_tokens", completion).
-		Field("user_prompt_tokens", promptTokens).
-		Field("total_prompt_tokens", totalPrompt).
-		Field("response_time_ms", responseTime.Milliseconds()).
-		Field("cache_hit_rate", cacheHitRate).
-		Field("is_new_cache", isNewCache).
-		Pretty(fmt.Sprintf("%s Token usage:\n%s", theme.IconChart, box)).
+		Pretty(fmt.Sprintf("%s Generated %d candidates (%d completion tokens across all candidates)", theme.IconSparkle, count, completion)).
 		Log(ctx)
 }
 
-// TokenUsage displays token usage statistics in a styled box
-func (l *Logger) TokenUsage(cached, dynamic, completion, promptTokens int, responseTime time.Duration, isNewCache bool) {
-	l.TokenUsageCtx(context.Background(), cached, dynamic, completion, promptTokens, responseTime, isNewCache)
+// CheckStatus is the outcome of one diagnostic check
+type CheckStatus string
+
+// Diagnostic check outcomes
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// CheckCtx prints one line of a diagnostic checklist. A hint, shown under
+// checks that warn or fail, says how to fix the problem.
+func (l *Logger) CheckCtx(ctx context.Context, status CheckStatus, name, detail, hint string) {
+	icon, style := theme.IconSuccess, l.theme.Success
+	switch status {
+	case CheckWarn:
+		icon, style = theme.IconWarning, l.theme.Warning
+	case CheckFail:
+		icon, style = theme.IconError, l.theme.Error
+	}
+
+	text := fmt.Sprintf("%s %s %s", style.Render(icon), l.theme.Bold.Render(fmt.Sprintf("%-18s", name)), detail)
+	if hint != "" && status != CheckPass {
+		text += "\n   " + l.theme.Muted.Render(theme.IconArrow+" "+hint)
+	}
+	l.ulog.Info("Diagnostic check").
+		Field("check", name).
+		Field("status", string(status)).
+		Field("detail", detail).
+		Field("hint", hint).
+		Pretty(text).
+		Log(ctx)
+}
+
+// CostLine writes a single concise cost summary line to the logger's output (stderr by default).
+// It is used instead of the token usage box when cost-only output is requested.
+func (l *Logger) CostLine(cost float64, totalTokens int, cacheHitRate float64) {
+	fmt.Fprintf(l.writer, "cost=$%.4f tokens=%d cache_hit=%.0f%%\n", cost, totalTokens, cacheHitRate*100)
 }
 
 // CacheInfo logs cache-related information
@@ -356,6 +438,24 @@ func (l *Logger) CacheExpired(at time.Time) {
 		l.theme.Muted.Render(relativeTime))
 }
 
+// CacheExpiringSoon logs that a reused cache expires soon enough that a request could outlive it
+func (l *Logger) CacheExpiringSoon(at time.Time) {
+	relativeTime := formatRelativeTime(at)
+	_, _ = fmt.Fprintf(l.writer, "%s (%s; %s)\n",
+		l.theme.Warning.Render(theme.IconClock+" Cache expires soon"),
+		l.theme.Muted.Render("expires "+relativeTime),
+		l.theme.Muted.Render("use --auto-extend to refresh its TTL"))
+}
+
+// CacheExtended logs that a cache's TTL was refreshed
+func (l *Logger) CacheExtended(until time.Time) {
+	relativeTime := formatRelativeTime(until)
+	_, _ = fmt.Fprintf(l.writer, "%s (%s %s)\n",
+		l.theme.Success.Render(theme.IconClock+" Extended cache TTL"),
+		l.theme.Muted.Render("expires"),
+		l.theme.Muted.Render(relativeTime))
+}
+
 // CacheFrozen logs when cache is frozen
 func (l *Logger) CacheFrozen() {
 	_, _ = fmt.Fprintf(l.writer, "%s\n",
@@ -415,7 +515,7 @@ func (l *Logger) CacheWarning() {
 func (l *Logger) EstimatedTokens(count int) {
 	_, _ = fmt.Fprintf(l.writer, "   %s %s\n",
 		l.theme.Muted.Render("Estimated tokens:"),
-		l.theme.Normal.Render(fmt.Sprintf("%d", count)))
+		l.theme.Normal.Render(FormatTokens(count)))
 }
 
 // ResponseWritten logs successful response write
@@ -465,7 +565,12 @@ func (l *Logger) ContextSummary(cold, hot int) {
 }
 
 // CacheCreationPrompt shows cache creation details and prompts for confirmation
+// In quiet mode the prompt can't be seen, so it is declined without reading input.
 func (l *Logger) CacheCreationPrompt(tokens int, sizeBytes int64, ttl time.Duration) bool {
+	if quiet {
+		return false
+	}
+
 	// Create a prominent box for the cache creation warning using theme
 	warningBox := l.theme.Box.
 		BorderForeground(l.theme.Colors.Yellow).
@@ -482,7 +587,7 @@ func (l *Logger) CacheCreationPrompt(tokens int, sizeBytes int64, ttl time.Durat
 		"",
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render("Cache size:"),
-			l.theme.Normal.Render(fmt.Sprintf("%d tokens (%s)", tokens, sizeStr))),
+			l.theme.Normal.Render(fmt.Sprintf("%s tokens (%s)", FormatTokens(tokens), sizeStr))),
 		fmt.Sprintf("%s %s",
 			l.theme.Muted.Render("Expires:"),
 			l.theme.Muted.Render(relativeTime)),
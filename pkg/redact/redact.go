@@ -0,0 +1,102 @@
+// Package redact masks secrets such as API keys and tokens in text before it
+// is written to debug logs or shown in error output.
+package redact
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// Placeholder replaces every redacted secret
+const Placeholder = "[REDACTED]"
+
+// DefaultPatterns match common API key and token shapes. A pattern with a
+// named group "secret" only has that group replaced, so the surrounding
+// key name stays readable.
+var DefaultPatterns = []string{
+	`AIza[0-9A-Za-z_\-]{35}`,                               // Google API keys
+	`\bsk-[A-Za-z0-9_\-]{20,}`,                             // OpenAI/Anthropic-style secret keys
+	`\bgh[pousr]_[A-Za-z0-9]{36,}`,                         // GitHub tokens
+	`\bgithub_pat_[A-Za-z0-9_]{22,}`,                       // GitHub fine-grained tokens
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,                        // AWS access key IDs
+	`\bxox[abprs]-[A-Za-z0-9\-]{10,}`,                      // Slack tokens
+	`\bya29\.[0-9A-Za-z_\-]{20,}`,                          // Google OAuth access tokens
+	`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]{20,}=*)`, // Authorization headers
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	`(?i)\b(?:api[_-]?key|secret|token|password|passwd)\b["']?\s*[:=]\s*["']?(?P<secret>[^\s"',;]{8,})`,
+}
+
+// Redactor replaces matches of a set of patterns with Placeholder.
+// A nil *Redactor leaves text unchanged.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New compiles a Redactor from the default patterns (unless includeDefaults is
+// false) followed by any extra user-supplied patterns.
+func New(extra []string, includeDefaults bool) (*Redactor, error) {
+	var sources []string
+	if includeDefaults {
+		sources = append(sources, DefaultPatterns...)
+	}
+	sources = append(sources, extra...)
+
+	r := &Redactor{patterns: make([]*regexp.Regexp, 0, len(sources))}
+	for _, source := range sources {
+		re, err := regexp.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", source, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Default returns a Redactor using only DefaultPatterns
+func Default() *Redactor {
+	r, _ := New(nil, true) // default patterns are known to compile
+	return r
+}
+
+// Redact returns s with every secret matched by the redactor's patterns masked
+func (r *Redactor) Redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	for _, re := range r.patterns {
+		group := re.SubexpIndex("secret")
+		if group < 0 {
+			s = re.ReplaceAllString(s, Placeholder)
+			continue
+		}
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			loc := re.FindStringSubmatchIndex(match)
+			if loc == nil || loc[2*group] < 0 {
+				return Placeholder
+			}
+			return match[:loc[2*group]] + Placeholder + match[loc[2*group+1]:]
+		})
+	}
+	return s
+}
+
+// writer redacts everything written through it
+type writer struct {
+	w io.Writer
+	r *Redactor
+}
+
+// NewWriter wraps w so each write is redacted before reaching it. Secrets
+// split across separate writes are not detected, so it suits line-oriented
+// output such as error messages.
+func NewWriter(w io.Writer, r *Redactor) io.Writer {
+	return &writer{w: w, r: r}
+}
+
+func (rw *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, rw.r.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}